@@ -1,10 +1,13 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
+	"ultimate-sdd-framework/internal/agents"
 	"ultimate-sdd-framework/internal/cli"
+	"ultimate-sdd-framework/internal/config"
 
 	"github.com/spf13/cobra"
 )
@@ -66,6 +69,26 @@ Available commands:`)
 		fmt.Println()
 	}
 
+	var noRedact bool
+	var keepInjections bool
+	var debugTranscript bool
+	var profile string
+	var mockResponses string
+	rootCmd.PersistentFlags().BoolVar(&noRedact, "no-redact", false, "Send file content to AI providers without masking likely secrets")
+	rootCmd.PersistentFlags().BoolVar(&keepInjections, "keep-injections", false, "Keep likely prompt-injection phrases in ingested content (it is still fenced as data)")
+	rootCmd.PersistentFlags().BoolVar(&debugTranscript, "debug-transcript", false, "Write every agent prompt and response, secrets masked, to .sdd/debug/<track>/<phase>.log")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Config profile to use for this command (overrides VIKI_PROFILE)")
+	rootCmd.PersistentFlags().StringVar(&mockResponses, "mock-responses", "", "Answer agent calls from a YAML file of canned responses keyed by phase, without network calls (lists are answered in order; use --parallel 1 with execute)")
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		agents.RedactSecrets = !noRedact
+		agents.StripInjections = !keepInjections
+		agents.DebugTranscript = debugTranscript
+		agents.MockResponsesPath = mockResponses
+		if profile != "" {
+			os.Setenv(config.ProfileEnvVar, profile)
+		}
+	}
+
 	// Core SDD commands
 	rootCmd.AddCommand(cli.NewInitCmd())
 	rootCmd.AddCommand(cli.NewDiscoveryCmd())
@@ -73,6 +96,7 @@ Available commands:`)
 	rootCmd.AddCommand(cli.NewPlanCmd())
 	rootCmd.AddCommand(cli.NewTaskCmd())
 	rootCmd.AddCommand(cli.NewExecuteCmd())
+	rootCmd.AddCommand(cli.NewValidateCmd())
 	rootCmd.AddCommand(cli.NewAnalyzeCmd())
 	rootCmd.AddCommand(cli.NewReviewCmd())
 	rootCmd.AddCommand(cli.NewPairCmd())
@@ -82,7 +106,12 @@ Available commands:`)
 	rootCmd.AddCommand(cli.NewPerformanceCmd())
 	rootCmd.AddCommand(cli.NewEvolveCmd())
 	rootCmd.AddCommand(cli.NewStatusCmd())
+	rootCmd.AddCommand(cli.NewDoctorCmd())
+	rootCmd.AddCommand(cli.NewMigrateCmd())
+	rootCmd.AddCommand(cli.NewExplainCmd())
+	rootCmd.AddCommand(cli.NewReportsCmd())
 	rootCmd.AddCommand(cli.NewApproveCmd())
+	rootCmd.AddCommand(cli.NewRejectCmd())
 	rootCmd.AddCommand(cli.NewMCPCommand())
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newGuideCmd())
@@ -90,12 +119,14 @@ Available commands:`)
 	// v2.0 commands
 	rootCmd.AddCommand(cli.NewChatCmd())      // Interactive chat mode
 	rootCmd.AddCommand(cli.NewUndoCmd())      // Undo file changes
+	rootCmd.AddCommand(cli.NewRedoCmd())      // Redo undone changes
 	rootCmd.AddCommand(cli.NewSecretsCmd())   // Secrets management
 	rootCmd.AddCommand(cli.NewNewCmd())       // Project templates
 	rootCmd.AddCommand(cli.NewDashboardCmd()) // Web dashboard
 	rootCmd.AddCommand(cli.NewConfigCmd())    // Global config
 	rootCmd.AddCommand(cli.NewPluginCmd())    // Plugin management
 	rootCmd.AddCommand(cli.NewIndexCmd())     // Codebase indexing
+	rootCmd.AddCommand(cli.NewLSPCmd())       // Editor language server
 
 	// v3.0 commands - Enhanced with competitor features
 	rootCmd.AddCommand(cli.NewSessionCmd())      // Session management (from OpenCode)
@@ -106,8 +137,19 @@ Available commands:`)
 	rootCmd.AddCommand(cli.NewClarifyCmd())      // Clarify specs (from Spec-Kit)
 	rootCmd.AddCommand(cli.NewChecklistCmd())    // Quality checklists (from Spec-Kit)
 
+	// Commands provided by installed plugins
+	cli.AddPluginCommands(rootCmd)
+
+	// Suggest the command meant when one is mistyped; main prints the error
+	cli.EnableSuggestions(rootCmd)
+	rootCmd.SilenceErrors = true
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		var exitErr *cli.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,239 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// DecisionRecordInstructions ask the designer to list its architecture
+// decisions in a block ExportDecisions can turn into ADRs
+const DecisionRecordInstructions = "\n\n## ARCHITECTURE DECISIONS\n" +
+	"End the document with every significant architecture decision in a fenced `decisions` block of YAML, one entry per decision:\n" +
+	"```decisions\n" +
+	"- title: Use PostgreSQL for persistence\n" +
+	"  status: Accepted\n" +
+	"  context: Why a decision was needed and the forces at play\n" +
+	"  decision: What was decided\n" +
+	"  consequences: What becomes easier or harder as a result\n" +
+	"```\n"
+
+// Decision is an architecture decision taken from a design document
+type Decision struct {
+	Title        string `yaml:"title"`
+	Status       string `yaml:"status"`
+	Context      string `yaml:"context"`
+	Decision     string `yaml:"decision"`
+	Consequences string `yaml:"consequences"`
+}
+
+var (
+	decisionsBlock = regexp.MustCompile("(?s)```decisions\\s*\\n(.*?)```")
+	// adrHeading matches freeform decision headings such as
+	// "### ADR-001: Use PostgreSQL" or "## Decision 2 - REST over gRPC"
+	adrHeading     = regexp.MustCompile(`(?i)^(#{2,4})\s+(?:adr[- ]?\d*|decision\s*\d*)\s*[:.\-–]\s*(.+)$`)
+	headingLine    = regexp.MustCompile(`^(#{1,6})\s`)
+	decisionField  = regexp.MustCompile(`(?i)^\s*(?:[-*]\s*)?\**(status|context|decision|consequences)\**\s*:\**\s*(.*)$`)
+	adrFileNumber  = regexp.MustCompile(`^(\d{4})-(.+)\.md$`)
+	slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// ADRDir returns the directory holding the project's decision records
+func ADRDir(projectRoot string) string {
+	return filepath.Join(projectRoot, "docs", "adr")
+}
+
+// ParseDecisions extracts the decisions of a design document from its
+// decisions block, or else from headings like "### ADR-1: Title" with
+// Status/Context/Decision/Consequences paragraphs
+func ParseDecisions(document string) ([]Decision, error) {
+	if match := decisionsBlock.FindStringSubmatch(document); match != nil {
+		var decisions []Decision
+		if err := yaml.Unmarshal([]byte(match[1]), &decisions); err != nil {
+			return nil, fmt.Errorf("failed to parse decisions block: %w", err)
+		}
+		return completeDecisions(decisions), nil
+	}
+	return completeDecisions(headingDecisions(document)), nil
+}
+
+// headingDecisions reads decisions written as markdown sections
+func headingDecisions(document string) []Decision {
+	var decisions []Decision
+	var current *Decision
+	var field *string
+	level := 0
+
+	for _, line := range strings.Split(document, "\n") {
+		if match := adrHeading.FindStringSubmatch(line); match != nil {
+			decisions = append(decisions, Decision{Title: strings.TrimSpace(match[2])})
+			current, level = &decisions[len(decisions)-1], len(match[1])
+			field = &current.Decision
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		// A heading of the same or a higher level ends the decision
+		if match := headingLine.FindStringSubmatch(line); match != nil && len(match[1]) <= level {
+			current = nil
+			continue
+		}
+
+		if match := decisionField.FindStringSubmatch(line); match != nil {
+			switch strings.ToLower(match[1]) {
+			case "status":
+				field = &current.Status
+			case "context":
+				field = &current.Context
+			case "decision":
+				field = &current.Decision
+			case "consequences":
+				field = &current.Consequences
+			}
+			line = match[2]
+		}
+		if *field != "" || strings.TrimSpace(line) != "" {
+			*field += line + "\n"
+		}
+	}
+	return decisions
+}
+
+// completeDecisions trims the decisions, drops untitled ones and marks those
+// without a status as proposed
+func completeDecisions(decisions []Decision) []Decision {
+	var complete []Decision
+	for _, decision := range decisions {
+		decision.Title = strings.TrimSpace(decision.Title)
+		if decision.Title == "" {
+			continue
+		}
+		decision.Status = strings.TrimSpace(decision.Status)
+		if decision.Status == "" {
+			decision.Status = "Proposed"
+		}
+		decision.Context = strings.TrimSpace(decision.Context)
+		decision.Decision = strings.TrimSpace(decision.Decision)
+		decision.Consequences = strings.TrimSpace(decision.Consequences)
+		complete = append(complete, decision)
+	}
+	return complete
+}
+
+// WriteADRs writes each decision to docs/adr/NNNN-title.md, numbering new
+// records after the existing ones. A decision whose title already has a
+// record rewrites it under its number, so regenerating a design updates
+// its records instead of duplicating them. It returns the written paths.
+func WriteADRs(projectRoot, source string, decisions []Decision) ([]string, error) {
+	dir := ADRDir(projectRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create ADR directory: %w", err)
+	}
+
+	existing, last, err := existingADRs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, decision := range decisions {
+		slug := adrSlug(decision.Title)
+		number, ok := existing[slug]
+		if !ok {
+			last++
+			number = last
+			existing[slug] = number
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("%04d-%s.md", number, slug))
+		if err := os.WriteFile(path, []byte(formatADR(number, source, decision)), 0644); err != nil {
+			return paths, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// existingADRs maps the slugs of the records in dir to their numbers and
+// returns the highest number
+func existingADRs(dir string) (map[string]int, int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read ADR directory: %w", err)
+	}
+
+	slugs := make(map[string]int)
+	last := 0
+	for _, entry := range entries {
+		match := adrFileNumber.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		number, _ := strconv.Atoi(match[1])
+		slugs[match[2]] = number
+		last = max(last, number)
+	}
+	return slugs, last, nil
+}
+
+// adrSlug turns a decision title into a file name part
+func adrSlug(title string) string {
+	slug := strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	if slug == "" {
+		slug = "decision"
+	}
+	return slug
+}
+
+// formatADR renders a decision as a Nygard-style decision record
+func formatADR(number int, source string, decision Decision) string {
+	section := func(text string) string {
+		if text == "" {
+			return "_Not recorded._"
+		}
+		return text
+	}
+
+	var adr strings.Builder
+	adr.WriteString(fmt.Sprintf("# %d. %s\n\n", number, decision.Title))
+	adr.WriteString(fmt.Sprintf("Date: %s\n", time.Now().Format("2006-01-02")))
+	if source != "" {
+		adr.WriteString(fmt.Sprintf("Source: %s\n", source))
+	}
+	adr.WriteString(fmt.Sprintf("\n## Status\n\n%s\n", decision.Status))
+	adr.WriteString(fmt.Sprintf("\n## Context\n\n%s\n", section(decision.Context)))
+	adr.WriteString(fmt.Sprintf("\n## Decision\n\n%s\n", section(decision.Decision)))
+	adr.WriteString(fmt.Sprintf("\n## Consequences\n\n%s\n", section(decision.Consequences)))
+	return adr.String()
+}
+
+// ExportDecisions writes the decisions of a design document as ADRs and
+// reports them; a document without decisions writes nothing
+func (as *AgentService) ExportDecisions(source, document string) {
+	decisions, err := ParseDecisions(document)
+	if err != nil {
+		fmt.Printf("⚠️  No ADRs exported: %v\n", err)
+		return
+	}
+	if len(decisions) == 0 {
+		return
+	}
+
+	paths, err := WriteADRs(as.projectRoot, source, decisions)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to export ADRs: %v\n", err)
+	}
+	if len(paths) > 0 {
+		fmt.Printf("📐 Exported %d architecture decision record(s) to %s\n", len(paths), ADRDir(as.projectRoot))
+	}
+}
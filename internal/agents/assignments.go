@@ -0,0 +1,87 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// phaseAssignments is the on-disk form of .sdd/agents.yaml
+type phaseAssignments struct {
+	Phases map[string]string `yaml:"phases"` // phase -> agent id
+}
+
+// AssignmentsPath returns the file recording which agent serves each phase
+func AssignmentsPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "agents.yaml")
+}
+
+// LoadAssignments reads the phase to agent assignments of a project
+func LoadAssignments(projectRoot string) (map[string]string, error) {
+	assignments := phaseAssignments{Phases: make(map[string]string)}
+
+	data, err := os.ReadFile(AssignmentsPath(projectRoot))
+	if os.IsNotExist(err) {
+		return assignments.Phases, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent assignments: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &assignments); err != nil {
+		return nil, fmt.Errorf("failed to parse agent assignments: %w", err)
+	}
+	if assignments.Phases == nil {
+		assignments.Phases = make(map[string]string)
+	}
+	return assignments.Phases, nil
+}
+
+// AssignPhase records that agentID serves phase; an empty agentID restores
+// the default role for the phase
+func AssignPhase(projectRoot, phase, agentID string) error {
+	if !slices.Contains(WorkflowPhases, phase) {
+		return UnknownPhaseError(phase)
+	}
+
+	phases, err := LoadAssignments(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	if agentID == "" {
+		delete(phases, phase)
+	} else {
+		phases[phase] = agentID
+	}
+
+	data, err := yaml.Marshal(phaseAssignments{Phases: phases})
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent assignments: %w", err)
+	}
+
+	return os.WriteFile(AssignmentsPath(projectRoot), data, 0644)
+}
+
+// agentFromExtended adapts a built-in agent so it can serve a workflow phase
+func agentFromExtended(extended *ExtendedAgent) *Agent {
+	content := fmt.Sprintf("%s\n\nRemember to be %s and use a %s tone.\nFocus on: %s\n",
+		extended.Prompt, extended.Personality, extended.Tone, strings.Join(extended.Focus, ", "))
+
+	return &Agent{
+		Name:        extended.Name,
+		Role:        extended.ID,
+		Expertise:   strings.Join(extended.Expertise, ", "),
+		Personality: extended.Personality,
+		Tone:        extended.Tone,
+		Focus:       strings.Join(extended.Focus, ", "),
+		Category:    extended.Category,
+		Content:     content,
+		IsRaw:       true,
+		Temperature: extended.Temperature,
+	}
+}
@@ -0,0 +1,223 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"ultimate-sdd-framework/internal/editor"
+)
+
+// MaxBuildFixAttempts bounds the fix-up passes the builder gets when the
+// code it wrote does not compile
+const MaxBuildFixAttempts = 2
+
+// buildTimeout bounds a single compile run
+const buildTimeout = 5 * time.Minute
+
+// maxCompilerOutput is how much compiler output is fed back to the builder
+const maxCompilerOutput = 6000
+
+// BuildCheck is the command that compiles a project in its language
+type BuildCheck struct {
+	Language string
+	Command  []string
+}
+
+// BuildResult is the outcome of compiling the code the builder wrote
+type BuildResult struct {
+	Check    *BuildCheck
+	Passed   bool
+	Attempts int // fix-up passes made
+	Output   string
+}
+
+// DetectBuildCheck picks the compile command for the project, or for the
+// written files when the project has no build manifest. It returns nil when
+// there is nothing it knows how to compile.
+func DetectBuildCheck(projectRoot string, files []string) *BuildCheck {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(projectRoot, name))
+		return err == nil
+	}
+
+	switch {
+	case exists("go.mod"):
+		return &BuildCheck{Language: "go", Command: []string{"go", "build", "./..."}}
+	case exists("tsconfig.json"):
+		return &BuildCheck{Language: "typescript", Command: []string{"npx", "--no-install", "tsc", "--noEmit"}}
+	case exists("Cargo.toml"):
+		return &BuildCheck{Language: "rust", Command: []string{"cargo", "check", "--quiet"}}
+	}
+
+	var python []string
+	for _, file := range files {
+		if strings.HasSuffix(file, ".py") {
+			python = append(python, file)
+		}
+	}
+	if len(python) > 0 {
+		return &BuildCheck{Language: "python", Command: append([]string{"python3", "-m", "py_compile"}, python...)}
+	}
+	return nil
+}
+
+// RunBuildCheck compiles the project, returning whether it built and the
+// compiler output
+func RunBuildCheck(projectRoot string, check *BuildCheck) (bool, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), buildTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, check.Command[0], check.Command[1:]...)
+	cmd.Dir = projectRoot
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return true, string(output), nil
+	}
+	if _, ok := err.(*exec.ExitError); ok && ctx.Err() == nil {
+		return false, string(output), nil
+	}
+	return false, string(output), fmt.Errorf("failed to run %s: %w", strings.Join(check.Command, " "), err)
+}
+
+// writeCodeBlocks writes the files of a builder response, given as fenced
+// blocks tagged with their path, and returns the paths written
+func (as *AgentService) writeCodeBlocks(response string) ([]string, error) {
+	ed := editor.NewEditor(as.projectRoot)
+
+	var blocks []editor.CodeBlock
+	for _, block := range ed.ParseCodeBlocks(response) {
+		if path, ok := projectPath(block.Filename); ok {
+			block.Filename = path
+			blocks = append(blocks, block)
+		}
+	}
+	for _, block := range blocks {
+		if err := as.trackWrite(block.Filename); err != nil {
+			return nil, err
+		}
+	}
+	return ed.CreateFromBlocks(blocks)
+}
+
+// beginUndo starts recording the files an execute run writes as one
+// operation of the undo stack
+func (as *AgentService) beginUndo(command string) {
+	as.undo = editor.NewUndoStack(as.projectRoot)
+	as.undoOp = as.undo.Begin(command)
+}
+
+// trackWrite snapshots a file, relative to the project root, before the
+// running execute changes it
+func (as *AgentService) trackWrite(path string) error {
+	if as.undoOp == nil {
+		return nil
+	}
+	return as.undo.Track(as.undoOp, path)
+}
+
+// commitUndo pushes the operation recorded since beginUndo onto the undo
+// stack, only warning when it cannot be saved since the files are written
+func (as *AgentService) commitUndo() {
+	if as.undoOp == nil {
+		return
+	}
+	if err := as.undo.Commit(as.undoOp); err != nil {
+		fmt.Printf("⚠️ Warning: could not record undo history: %v\n", err)
+	}
+	as.undo, as.undoOp = nil, nil
+}
+
+// projectPath accepts a relative file path inside the project, rejecting
+// the first code line the block pattern can mistake for a filename
+func projectPath(name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, " \t(){};=\"'") || filepath.IsAbs(name) || filepath.Ext(name) == "" {
+		return "", false
+	}
+	clean := filepath.Clean(name)
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) || strings.HasPrefix(clean, ".sdd") {
+		return "", false
+	}
+	return clean, true
+}
+
+// VerifyBuild compiles the project after the builder wrote files and, while
+// it fails, has the builder fix the files using the compiler output
+func (as *AgentService) VerifyBuild(files []string) (*BuildResult, error) {
+	check := DetectBuildCheck(as.projectRoot, files)
+	if check == nil {
+		return nil, nil
+	}
+	result := &BuildResult{Check: check}
+	roleName, _, _, skill := as.getPhaseConfig("execute")
+
+	for {
+		fmt.Printf("🔨 Compiling: %s\n", strings.Join(check.Command, " "))
+		passed, output, err := RunBuildCheck(as.projectRoot, check)
+		if err != nil {
+			return nil, err
+		}
+		result.Passed, result.Output = passed, output
+		if passed || result.Attempts == MaxBuildFixAttempts {
+			return result, nil
+		}
+
+		result.Attempts++
+		fmt.Printf("🔧 Build failed, builder fix-up pass %d/%d...\n", result.Attempts, MaxBuildFixAttempts)
+
+		response, err := as.GetAgentResponse(roleName, "execute", buildFixInstructions(check, output), as.writtenFilesContext(files), skill)
+		if err != nil {
+			return nil, err
+		}
+		fixed, err := as.writeCodeBlocks(response)
+		if err != nil {
+			return nil, err
+		}
+		files = mergeFiles(files, fixed)
+	}
+}
+
+// buildFixInstructions asks the builder to fix the compiler errors
+func buildFixInstructions(check *BuildCheck, output string) string {
+	if len(output) > maxCompilerOutput {
+		output = output[:maxCompilerOutput] + "\n... (truncated)"
+	}
+	return fmt.Sprintf(`The code you wrote does not compile. '%s' failed with:
+
+%s
+
+Fix these errors. Reply with the COMPLETE corrected content of every file you change, each in a fenced block whose opening line is `+"```%s:path/to/file"+`.`,
+		strings.Join(check.Command, " "), strings.TrimSpace(output), check.Language)
+}
+
+// writtenFilesContext gives the builder the current content of the files
+// it wrote
+func (as *AgentService) writtenFilesContext(files []string) string {
+	var written strings.Builder
+	written.WriteString("\n\n## FILES YOU WROTE\n")
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(as.projectRoot, file))
+		if err != nil {
+			continue
+		}
+		masked, _ := as.RedactContent(string(content))
+		fenced, _ := as.untrusted(file, masked)
+		written.WriteString(fmt.Sprintf("\n### %s\n%s\n", file, fenced))
+	}
+	return written.String()
+}
+
+// mergeFiles appends the files of more not already in files
+func mergeFiles(files, more []string) []string {
+	for _, file := range more {
+		if !slices.Contains(files, file) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
@@ -0,0 +1,52 @@
+package agents
+
+import (
+	"fmt"
+
+	"ultimate-sdd-framework/internal/mcp"
+)
+
+// MockResponsesPath, when set, makes new agent services answer from canned
+// responses keyed by phase instead of calling a provider (set by
+// --mock-responses)
+var MockResponsesPath = ""
+
+// ChatClient sends the messages of a phase to a model, returning the
+// response and the name of the provider that served it
+type ChatClient interface {
+	Chat(phase string, messages []mcp.Message, options map[string]interface{}) (*mcp.ChatResponse, string, error)
+}
+
+// providerClient is the ChatClient of the configured providers, falling back
+// along the provider chain
+type providerClient struct {
+	mcpMgr *mcp.MCPManager
+}
+
+// Chat sends messages to the default provider, or the next in the chain
+// when it is unavailable. The provider is only announced when a fallback
+// served the response.
+func (pc *providerClient) Chat(phase string, messages []mcp.Message, options map[string]interface{}) (*mcp.ChatResponse, string, error) {
+	fellBack := false
+	response, provider, err := pc.mcpMgr.ChatWithFallback(messages, options, func(failed string, err error, next string) {
+		fellBack = true
+		fmt.Printf("⚠️  Provider '%s' unavailable (%v), falling back to '%s'\n", failed, err, next)
+	})
+	if err == nil && fellBack {
+		fmt.Printf("🤖 Response served by '%s'\n", provider)
+	}
+	return response, provider, err
+}
+
+// SetChatClient replaces the client agent calls are sent through, e.g. with
+// an mcp.MockClient for deterministic runs
+func (as *AgentService) SetChatClient(client ChatClient) {
+	as.client = client
+}
+
+// Mocked reports whether agent calls are answered from canned responses
+// rather than a provider
+func (as *AgentService) Mocked() bool {
+	_, ok := as.client.(*mcp.MockClient)
+	return ok
+}
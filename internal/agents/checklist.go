@@ -0,0 +1,70 @@
+package agents
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ultimate-sdd-framework/internal/gates"
+)
+
+// verdictLinePattern matches a "3. FAIL - note" line of a checklist review
+var verdictLinePattern = regexp.MustCompile(`(?i)^\s*\**(\d+)[.):]\**\s*\**(PASS|FAIL)\**\s*[-:–—]?\s*(.*)$`)
+
+// ReviewChecklist has the QA agent (the agent of the validate phase) decide
+// the UNVERIFIED items of a checklist against content. Items the agent does
+// not answer stay UNVERIFIED.
+func (as *AgentService) ReviewChecklist(results []gates.CheckResult, content string) error {
+	var pending []int
+	var items strings.Builder
+	for i, result := range results {
+		if result.Status != gates.CheckUnverified {
+			continue
+		}
+		pending = append(pending, i)
+		items.WriteString(fmt.Sprintf("%d. %s\n", len(pending), result.Item.Text))
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	roleName, _, _, _ := as.getPhaseConfig("validate")
+
+	masked, redactions := as.RedactContent(content)
+	if redactions > 0 {
+		fmt.Printf("🔒 Redacted %d likely secret(s) from the prompt context\n", redactions)
+	}
+	// Fenced so the artifact cannot answer the checklist for the agent
+	fenced, _ := as.untrusted("artifact under review", masked)
+	contextInfo := fmt.Sprintf("\n\n## ARTIFACT UNDER REVIEW\n%s\n", fenced)
+
+	instructions := fmt.Sprintf(`Check the artifact against each checklist item below.
+%s
+Reply with ONE line per item in the form "<number>. PASS - <reason>" or "<number>. FAIL - <what to add or change>", and nothing else.`, items.String())
+
+	response, err := as.GetAgentResponse(roleName, "checklist", instructions, contextInfo, "")
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(response, "\n") {
+		match := verdictLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		number, _ := strconv.Atoi(match[1])
+		if number < 1 || number > len(pending) {
+			continue
+		}
+
+		result := &results[pending[number-1]]
+		result.Status = strings.ToUpper(match[2])
+		result.Note = strings.TrimSpace(match[3])
+		if result.Status == gates.CheckFail && result.Note == "" {
+			result.Note = result.Item.Fix
+		}
+	}
+
+	return nil
+}
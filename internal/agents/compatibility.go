@@ -0,0 +1,64 @@
+package agents
+
+import (
+	"fmt"
+	"slices"
+)
+
+// phaseFit lists the agent categories that usually do well in each of the
+// WorkflowPhases and the built-in agent to suggest when a poor fit is
+// selected
+var phaseFit = map[string]struct {
+	categories []string
+	suggest    string
+}{
+	"discover": {[]string{"core", "product"}, "business_analyst"},
+	"specify":  {[]string{"core", "product"}, "pm"},
+	"design":   {[]string{"core", "engineering"}, "architect"},
+	"audit":    {[]string{"engineering", "quality"}, "security"},
+	"task":     {[]string{"core", "product"}, "scrum_master"},
+	"execute":  {[]string{"core", "engineering"}, "developer"},
+	"validate": {[]string{"core", "quality"}, "qa"},
+	"review":   {[]string{"core", "quality"}, "qa"},
+	"evolve":   {[]string{"operations"}, "documentation"},
+}
+
+// agentCategories returns the categories of the named agent: those of the
+// built-in agent with the same id, else the category its role file declares
+func (am *AgentManager) agentCategories(name string) []string {
+	if extended := GetAgentByID(name); extended != nil {
+		return append([]string{extended.Category}, extended.AlsoIn...)
+	}
+	if agent, ok := am.agents[name]; ok && agent.Category != "" {
+		return []string{agent.Category}
+	}
+	return nil
+}
+
+// CheckPhaseFit returns a warning when the named agent's category does not
+// match the categories that usually handle phase, suggesting a better-fit
+// agent. Agents without a known category are not checked.
+func (am *AgentManager) CheckPhaseFit(name, phase string) string {
+	fit, ok := phaseFit[phase]
+	if !ok {
+		return ""
+	}
+
+	categories := am.agentCategories(name)
+	if len(categories) == 0 {
+		return ""
+	}
+	for _, category := range categories {
+		if slices.Contains(fit.categories, category) {
+			return ""
+		}
+	}
+
+	suggestion := fit.suggest
+	if suggested := GetAgentByID(fit.suggest); suggested != nil {
+		suggestion = fmt.Sprintf("%s (%s)", fit.suggest, suggested.Name)
+	}
+
+	return fmt.Sprintf("⚠️  Agent '%s' (%s) is not a typical fit for the %s phase; consider using %s instead",
+		name, categories[0], phase, suggestion)
+}
@@ -0,0 +1,174 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"ultimate-sdd-framework/internal/performance"
+)
+
+// Risk ratings of an estimated task
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// Token budget of a builder call on top of the target files it reads
+const (
+	estimatePromptTokens = 1500 // role, skill and task instructions
+	estimateOutputTokens = 1200 // generated code and notes
+)
+
+// taskFilePattern matches file paths named in a task's title or description
+var taskFilePattern = regexp.MustCompile(`(?:[\w.-]+/)*[\w-]+\.(?:go|ts|tsx|js|jsx|py|rs|java|rb|sql|css|html|yaml|yml|toml|md)\b`)
+
+// TaskEstimate forecasts the work and risk of a single gsd.json task
+type TaskEstimate struct {
+	ID                 string
+	Title              string
+	Done               bool
+	Files              []string // files the task touches
+	NewFiles           int      // of which do not exist yet
+	MaxComplexity      int      // highest cyclomatic complexity in the existing files
+	MinMaintainability float64  // lowest maintainability index, 0 if none measured
+	Risk               string
+	Reasons            []string
+	AICalls            int
+	Tokens             int
+}
+
+// TrackEstimate forecasts the execution of a track's task breakdown
+type TrackEstimate struct {
+	TrackID string
+	Tasks   []TaskEstimate
+	Files   int // distinct files to be touched
+	AICalls int
+	Tokens  int
+}
+
+// EstimateTrack forecasts executing a track's gsd.json without calling an AI
+// provider: the files each task touches, a risk rating from the complexity
+// of those that already exist, and the AI calls and tokens it will take.
+// Completed tasks are listed but cost nothing.
+func EstimateTrack(projectRoot, trackID string) (*TrackEstimate, error) {
+	tasks, err := LoadGSDTasks(projectRoot, trackID)
+	if err != nil {
+		return nil, err
+	}
+
+	profiler := performance.NewPerformanceProfiler(projectRoot)
+	estimate := &TrackEstimate{TrackID: trackID}
+	touched := make(map[string]bool)
+
+	for i, task := range tasks {
+		taskEstimate := estimateTask(projectRoot, profiler, task)
+		taskEstimate.ID = TaskID(task, i)
+
+		for _, file := range taskEstimate.Files {
+			touched[file] = true
+		}
+		estimate.AICalls += taskEstimate.AICalls
+		estimate.Tokens += taskEstimate.Tokens
+		estimate.Tasks = append(estimate.Tasks, taskEstimate)
+	}
+	estimate.Files = len(touched)
+
+	return estimate, nil
+}
+
+// estimateTask rates one task from the files it names
+func estimateTask(projectRoot string, profiler *performance.PerformanceProfiler, task GSDTask) TaskEstimate {
+	estimate := TaskEstimate{Title: task.Title, Done: task.Done, Files: taskFiles(task)}
+
+	contextTokens, existing, largest := 0, 0, 0
+	for _, file := range estimate.Files {
+		data, err := os.ReadFile(filepath.Join(projectRoot, file))
+		if err != nil {
+			estimate.NewFiles++
+			continue
+		}
+		existing++
+		contextTokens += len(data) / 4
+		largest = max(largest, strings.Count(string(data), "\n"))
+
+		if !strings.HasSuffix(file, ".go") {
+			continue
+		}
+		functions, err := profiler.FileFunctionMetrics(filepath.Join(projectRoot, file))
+		if err != nil {
+			continue
+		}
+		for _, fn := range functions {
+			estimate.MaxComplexity = max(estimate.MaxComplexity, fn.Complexity)
+			if estimate.MinMaintainability == 0 || fn.Maintainability < estimate.MinMaintainability {
+				estimate.MinMaintainability = fn.Maintainability
+			}
+		}
+	}
+
+	score := 0
+	switch {
+	case estimate.MaxComplexity >= 20:
+		score += 2
+		estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("edits a function with complexity %d", estimate.MaxComplexity))
+	case estimate.MaxComplexity >= 10:
+		score++
+		estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("edits a function with complexity %d", estimate.MaxComplexity))
+	}
+	if estimate.MinMaintainability > 0 && estimate.MinMaintainability < performance.LowMaintainabilityThreshold {
+		score++
+		estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("maintainability index %.0f", estimate.MinMaintainability))
+	}
+	if existing > 3 {
+		score++
+		estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("touches %d existing files", existing))
+	}
+	if largest > 500 {
+		score++
+		estimate.Reasons = append(estimate.Reasons, fmt.Sprintf("edits a %d-line file", largest))
+	}
+	if len(estimate.Files) == 0 {
+		score++
+		estimate.Reasons = append(estimate.Reasons, "names no target files")
+	}
+
+	switch {
+	case score >= 2:
+		estimate.Risk = RiskHigh
+	case score == 1:
+		estimate.Risk = RiskMedium
+	default:
+		estimate.Risk = RiskLow
+	}
+
+	if task.Done {
+		return estimate
+	}
+
+	// One builder call per task, plus an expected revision for risky ones
+	estimate.AICalls = 1
+	if estimate.Risk == RiskHigh {
+		estimate.AICalls++
+	}
+	estimate.Tokens = estimate.AICalls * (estimatePromptTokens + contextTokens + estimateOutputTokens)
+
+	return estimate
+}
+
+// taskFiles returns the files a task declares or names in its text
+func taskFiles(task GSDTask) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, file := range append(append([]string{}, task.Files...), taskFilePattern.FindAllString(task.Title+" "+task.Description, -1)...) {
+		file = filepath.ToSlash(filepath.Clean(file))
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	return files
+}
@@ -0,0 +1,363 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"ultimate-sdd-framework/internal/gates"
+)
+
+// DefaultParallelism bounds how many tasks the builder runs at once
+const DefaultParallelism = 3
+
+// Outcomes of an executed task
+const (
+	TaskSucceeded = "succeeded"
+	TaskFailed    = "failed"
+	TaskSkipped   = "skipped" // a prerequisite did not succeed
+	TaskDone      = "done"    // already completed in gsd.json
+)
+
+// TaskResult is the outcome of one gsd.json task
+type TaskResult struct {
+	ID       string
+	Title    string
+	Status   string
+	Output   string
+	Files    []string // files the builder wrote
+	Err      error
+	Started  time.Time
+	Finished time.Time
+}
+
+// TaskRunner executes a single task and returns its output
+type TaskRunner func(id string, task GSDTask) (string, error)
+
+// TaskID returns the ID of the task at index: its "id", else T1, T2, ...
+func TaskID(task GSDTask, index int) string {
+	if task.ID != "" {
+		return task.ID
+	}
+	return fmt.Sprintf("T%d", index+1)
+}
+
+// taskGraph resolves the prerequisites of every task, rejecting unknown
+// IDs and dependency cycles
+func taskGraph(tasks []GSDTask) ([]string, map[string][]string, error) {
+	ids := make([]string, len(tasks))
+	known := make(map[string]bool)
+	for i, task := range tasks {
+		ids[i] = TaskID(task, i)
+		if known[ids[i]] {
+			return nil, nil, fmt.Errorf("duplicate task id %s", ids[i])
+		}
+		known[ids[i]] = true
+	}
+
+	deps := make(map[string][]string)
+	for i, task := range tasks {
+		for _, dep := range task.DependsOn {
+			if !known[dep] {
+				return nil, nil, fmt.Errorf("task %s depends on unknown task %s", ids[i], dep)
+			}
+			deps[ids[i]] = append(deps[ids[i]], dep)
+		}
+	}
+
+	// Depth-first search for a path back to a task still being visited
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var visit func(id string, path []string) error
+	visit = func(id string, path []string) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("task dependency cycle: %s", strings.Join(append(path, id), " → "))
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		for _, dep := range deps[id] {
+			if err := visit(dep, append(path, id)); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		return nil
+	}
+	for _, id := range ids {
+		if err := visit(id, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return ids, deps, nil
+}
+
+// RunTaskGraph runs tasks with at most parallelism at a time, starting each
+// once all its prerequisites have succeeded. Dependents of a failed task are
+// skipped. onResult, if set, is called as each task finishes. Results are
+// returned in gsd.json order.
+func RunTaskGraph(tasks []GSDTask, parallelism int, run TaskRunner, onResult func(TaskResult)) ([]TaskResult, error) {
+	ids, deps, err := taskGraph(tasks)
+	if err != nil {
+		return nil, err
+	}
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	index := make(map[string]int, len(ids))
+	finished := make(map[string]chan struct{}, len(ids))
+	for i, id := range ids {
+		index[id] = i
+		finished[id] = make(chan struct{})
+	}
+
+	results := make([]TaskResult, len(tasks))
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, task := range tasks {
+		wg.Add(1)
+		go func(i int, task GSDTask) {
+			defer wg.Done()
+			id := ids[i]
+			result := TaskResult{ID: id, Title: task.Title}
+
+			// A task's result is final once its channel is closed
+			defer func() {
+				results[i] = result
+				close(finished[id])
+				if onResult != nil {
+					onResult(result)
+				}
+			}()
+
+			for _, dep := range deps[id] {
+				<-finished[dep]
+				if status := results[index[dep]].Status; status != TaskSucceeded && status != TaskDone {
+					result.Status = TaskSkipped
+					result.Err = fmt.Errorf("prerequisite %s %s", dep, status)
+					return
+				}
+			}
+
+			if task.Done {
+				result.Status = TaskDone
+				return
+			}
+
+			slots <- struct{}{}
+			result.Started = time.Now()
+			result.Output, result.Err = run(id, task)
+			result.Finished = time.Now()
+			<-slots
+
+			result.Status = TaskSucceeded
+			if result.Err != nil {
+				result.Status = TaskFailed
+			}
+		}(i, task)
+	}
+
+	wg.Wait()
+	return results, nil
+}
+
+// ExecuteTasks has the builder implement the pending tasks of a track's
+// gsd.json, or its approved spec when the workflow has no task phase,
+// running independent tasks concurrently and writing the files of its
+// responses. The project must then compile, with fix-up passes fed the
+// compiler output, before succeeded tasks are marked done in gsd.json. All
+// outputs are merged into execution.md.
+func (as *AgentService) ExecuteTasks(trackID string, parallelism int, onResult func(TaskResult)) ([]TaskResult, *BuildResult, error) {
+	roleName, prevArtifact, _, skill := as.getPhaseConfig("execute")
+	if as.workflow != nil && !as.workflow.Includes("execute") {
+		return nil, nil, fmt.Errorf("the execute phase is not part of the %s workflow", as.workflow.Name)
+	}
+
+	lock, err := as.lockTrack(trackID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer lock.Release()
+	as.transcriptTrack = trackID
+
+	approved, err := as.checkGateApproval(trackID, prevArtifact)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gate check failed: %w", err)
+	}
+	if !approved {
+		return nil, nil, fmt.Errorf("403 FORBIDDEN: Previous gate artifact '%s' is missing or not APPROVED", prevArtifact)
+	}
+
+	// The run is one undo operation, including files written before a failure
+	as.beginUndo("execute tasks " + trackID)
+	defer as.commitUndo()
+	trackDir := filepath.Join(".sdd", "tracks", trackID)
+
+	// Without a task phase there is no gsd.json: the builder implements the
+	// approved gate artifact as one task
+	planned := as.workflow == nil || as.workflow.Includes("task")
+	tasks := []GSDTask{{ID: "1", Title: fmt.Sprintf("Implement %s", prevArtifact)}}
+	if planned {
+		if tasks, err = LoadGSDTasks(as.projectRoot, trackID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	contextInfo, err := as.prepareContext("execute", trackID, prevArtifact)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to prepare context: %w", err)
+	}
+
+	// Tasks run concurrently but write their files one at a time
+	var writeMu sync.Mutex
+	written := make(map[string][]string)
+
+	run := func(id string, task GSDTask) (string, error) {
+		instructions := fmt.Sprintf("Implement ONLY task %s: %s", id, task.Title)
+		if task.Description != "" {
+			instructions += "\n" + task.Description
+		}
+		if len(task.Files) > 0 {
+			instructions += "\nFiles: " + strings.Join(task.Files, ", ")
+		}
+		instructions += "\nWrite each file in full in a fenced block whose opening line is ```<language>:<path>."
+
+		response, err := as.GetAgentResponse(roleName, "execute", instructions, contextInfo, skill)
+		if err != nil {
+			return "", err
+		}
+
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		files, err := as.writeCodeBlocks(response)
+		written[id] = files
+		return response, err
+	}
+
+	results, err := RunTaskGraph(tasks, parallelism, run, onResult)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []string
+	for i := range results {
+		results[i].Files = written[results[i].ID]
+		files = mergeFiles(files, results[i].Files)
+	}
+
+	var build *BuildResult
+	if len(files) > 0 {
+		if build, err = as.VerifyBuild(files); err != nil {
+			return results, nil, fmt.Errorf("build check failed: %w", err)
+		}
+	}
+
+	var completed []int
+	var merged strings.Builder
+	merged.WriteString(fmt.Sprintf("# Execution: %s\n", trackID))
+	for i, result := range results {
+		merged.WriteString(fmt.Sprintf("\n## %s %s [%s]\n\n", result.ID, result.Title, result.Status))
+		switch result.Status {
+		case TaskSucceeded:
+			completed = append(completed, i)
+			merged.WriteString(result.Output + "\n")
+		case TaskFailed, TaskSkipped:
+			merged.WriteString(fmt.Sprintf("%v\n", result.Err))
+		}
+	}
+
+	if build != nil {
+		verdict := "PASSED"
+		if !build.Passed {
+			verdict = "FAILED"
+		}
+		merged.WriteString(fmt.Sprintf("\n## Build [%s]\n\n`%s` after %d fix-up pass(es)\n",
+			verdict, strings.Join(build.Check.Command, " "), build.Attempts))
+		if !build.Passed {
+			merged.WriteString(fmt.Sprintf("\n```\n%s\n```\n", strings.TrimSpace(build.Output)))
+		}
+	}
+
+	if err := as.trackWrite(filepath.Join(trackDir, "execution.md")); err != nil {
+		return results, build, err
+	}
+	if err := as.SaveArtifact(trackID, "execution.md", merged.String(), gates.ArtifactPending); err != nil {
+		return results, build, fmt.Errorf("failed to save execution results: %w", err)
+	}
+
+	// Code that does not compile must not reach the validation gate
+	if build != nil && !build.Passed {
+		return results, build, fmt.Errorf("generated code does not compile after %d fix-up passes:\n%s",
+			build.Attempts, strings.TrimSpace(build.Output))
+	}
+	if planned {
+		if err := as.trackWrite(filepath.Join(trackDir, as.workflow.ArtifactFor("task"))); err != nil {
+			return results, build, err
+		}
+		if err := markTasksDone(as.projectRoot, trackID, completed); err != nil {
+			return results, build, err
+		}
+	}
+
+	return results, build, nil
+}
+
+// markTasksDone sets "done" on the tasks at the given indexes of a track's
+// gsd.json, keeping its frontmatter and any fields it does not know
+func markTasksDone(projectRoot, trackID string, indexes []int) error {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(gates.TracksDir(projectRoot), trackID, ArtifactName(projectRoot, "task"))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+
+	// The JSON follows the frontmatter, which also starts and ends with "---"
+	bodyStart := 0
+	if strings.HasPrefix(content, "---") {
+		if end := strings.Index(content[3:], "\n---"); end >= 0 {
+			bodyStart = end + 3 + len("\n---")
+		}
+	}
+	start := strings.Index(content[bodyStart:], "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < bodyStart+start {
+		return fmt.Errorf("%s contains no task JSON", path)
+	}
+	start += bodyStart
+
+	var checklist map[string]interface{}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &checklist); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	tasks, _ := checklist["tasks"].([]interface{})
+	for _, i := range indexes {
+		if i < len(tasks) {
+			if task, ok := tasks[i].(map[string]interface{}); ok {
+				task["done"] = true
+			}
+		}
+	}
+
+	updated, err := json.MarshalIndent(checklist, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content[:start]+string(updated)+content[end+1:]), 0644)
+}
@@ -0,0 +1,60 @@
+package agents
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PhaseExplanation describes what a workflow phase does, as configured for
+// the project
+type PhaseExplanation struct {
+	Phase     string
+	Role      string
+	Skill     string
+	Input     string   // artifact the agent consumes, "" when it starts from scratch
+	Output    string   // artifact the phase produces
+	Gate      string   // artifact that must be APPROVED before the phase runs
+	GatePhase string   // phase producing Gate
+	Context   []string // further context the agent is given
+	Included  bool     // whether the project's workflow profile runs the phase
+}
+
+// ExplainPhase describes a phase from the same configuration the
+// orchestrator runs it with
+func (as *AgentService) ExplainPhase(phase string) (*PhaseExplanation, error) {
+	if !slices.Contains(WorkflowPhases, phase) {
+		return nil, UnknownPhaseError(phase)
+	}
+
+	role, prev, curr, skill := as.getPhaseConfig(phase)
+	explanation := &PhaseExplanation{
+		Phase:    phase,
+		Role:     role,
+		Skill:    skill,
+		Input:    prev,
+		Output:   curr,
+		Included: as.workflow == nil || as.workflow.Includes(phase),
+	}
+	// source_code is approved implicitly, so it gates nothing
+	if prev != "" && prev != "source_code" {
+		explanation.Gate = prev
+		explanation.GatePhase = as.workflow.ProducerOf(prev)
+	}
+
+	// Mirrors the context prepareContext injects for the phase
+	if phase == "execute" {
+		for _, constraint := range builderConstraints {
+			explanation.Context = append(explanation.Context, fmt.Sprintf("%s — %s", as.workflow.ArtifactFor(constraint.Phase), strings.ToLower(constraint.Heading)))
+		}
+	}
+	if phase == "specify" || phase == "design" {
+		explanation.Context = append(explanation.Context, "the project vision, when one is set")
+	}
+	if phase == "discover" {
+		explanation.Context = append(explanation.Context, "brownfield constraints, when the codebase has been analyzed")
+	}
+	explanation.Context = append(explanation.Context, "the persistent project context in .sdd/context")
+
+	return explanation, nil
+}
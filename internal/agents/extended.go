@@ -11,8 +11,21 @@ type ExtendedAgent struct {
 	Focus       []string `json:"focus"`
 	Prompt      string   `json:"prompt"`
 	Category    string   `json:"category"` // core, product, engineering, quality, operations
+	// AlsoIn lists further categories the agent is listed under
+	AlsoIn []string `json:"also_in,omitempty"`
+	// Temperature is the sampling temperature the agent runs at; nil leaves
+	// it to the caller's default
+	Temperature *float64 `json:"temperature,omitempty"`
 }
 
+// temperature returns a pointer to t for ExtendedAgent.Temperature
+func temperature(t float64) *float64 {
+	return &t
+}
+
+// AgentCategories lists the extended agent categories in display order
+var AgentCategories = []string{"core", "product", "engineering", "quality", "operations", "creative"}
+
 // AllExtendedAgents returns all 21+ specialized agents inspired by BMAD
 func AllExtendedAgents() []*ExtendedAgent {
 	return []*ExtendedAgent{
@@ -77,6 +90,7 @@ func AllExtendedAgents() []*ExtendedAgent {
 			Tone:        "Precise, constructive, detail-focused",
 			Focus:       []string{"test coverage", "edge cases", "regression", "security"},
 			Category:    "core",
+			AlsoIn:      []string{"quality"},
 			Prompt: `You are the Quality Assurance agent. Your role is to:
 - Design comprehensive testing strategies
 - Identify edge cases and potential failure points
@@ -166,6 +180,7 @@ func AllExtendedAgents() []*ExtendedAgent {
 			Tone:        "Direct, serious, protective",
 			Focus:       []string{"vulnerabilities", "threat vectors", "compliance", "secure coding"},
 			Category:    "engineering",
+			Temperature: temperature(0.0),
 			Prompt: `You are the Security Analyst agent. Your role is to:
 - Identify and mitigate security vulnerabilities
 - Perform threat modeling and risk assessment
@@ -342,6 +357,7 @@ func AllExtendedAgents() []*ExtendedAgent {
 			Tone:        "Enthusiastic, exploratory, inspiring",
 			Focus:       []string{"new ideas", "possibilities", "experimentation", "trends"},
 			Category:    "creative",
+			Temperature: temperature(0.9),
 			Prompt: `You are the Innovation Catalyst agent. Your role is to:
 - Generate creative solutions to problems
 - Explore new technologies and approaches
@@ -397,11 +413,24 @@ func GetAgentByID(id string) *ExtendedAgent {
 	return nil
 }
 
+// InCategory reports whether the agent is listed under category
+func (a *ExtendedAgent) InCategory(category string) bool {
+	if a.Category == category {
+		return true
+	}
+	for _, c := range a.AlsoIn {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
 // GetAgentsByCategory returns agents filtered by category
 func GetAgentsByCategory(category string) []*ExtendedAgent {
 	var agents []*ExtendedAgent
 	for _, agent := range AllExtendedAgents() {
-		if agent.Category == category {
+		if agent.InCategory(category) {
 			agents = append(agents, agent)
 		}
 	}
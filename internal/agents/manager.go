@@ -2,32 +2,49 @@ package agents
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
+	"ultimate-sdd-framework/internal/mcp"
+
 	"github.com/goccy/go-yaml"
 )
 
 // Agent represents a BMAD-style persona agent
 type Agent struct {
+	Name       string `yaml:"name"`
 	Role       string `yaml:"role"`
 	Expertise  string `yaml:"expertise"`
 	Personality string `yaml:"personality"`
 	Tone       string `yaml:"tone"`
+	Focus      string `yaml:"focus"`
+	Category   string `yaml:"category"` // one of AgentCategories, used for phase fit checks
+	// Phases maps the workflow phases this agent serves to its
+	// instructions for each; custom roles take over the phases they declare
+	Phases map[string]string `yaml:"phases"`
+	// Temperature is the sampling temperature the agent prefers, declared
+	// in its role file; nil means the caller's default is used
+	Temperature *float64 `yaml:"temperature"`
 	Content    string `yaml:"-"`
 	IsRaw      bool   `yaml:"-"`
 }
 
 // AgentManager handles loading and managing agents
 type AgentManager struct {
+	projectRoot  string
 	agentsDir    string
 	agents       map[string]*Agent
+	assignments  map[string]string // phase -> agent id, from .sdd/agents.yaml
 }
 
 // NewAgentManager creates a new agent manager
 func NewAgentManager(projectRoot string) *AgentManager {
 	return &AgentManager{
+		projectRoot:  projectRoot,
 		// Moved from .agents to .sdd/role
 		agentsDir:    filepath.Join(projectRoot, ".sdd", "role"),
 		agents:       make(map[string]*Agent),
@@ -44,6 +61,34 @@ func (am *AgentManager) LoadAgents() error {
 		return fmt.Errorf("role directory .sdd/role not found")
 	}
 
+	assignments, err := LoadAssignments(am.projectRoot)
+	if err != nil {
+		return err
+	}
+	am.assignments = assignments
+
+	// Built-in agents assigned to a phase are usable without a role file.
+	// A bad entry only loses its assignment, so the phase keeps its default
+	// role and the other agents still load.
+	for _, phase := range slices.Sorted(maps.Keys(assignments)) {
+		name := assignments[phase]
+		if !slices.Contains(WorkflowPhases, phase) {
+			fmt.Printf("⚠️  Ignoring %s: %v\n", AssignmentsPath(am.projectRoot), UnknownPhaseError(phase))
+			delete(assignments, phase)
+			continue
+		}
+		if _, exists := am.agents[name]; exists {
+			continue
+		}
+		extended := GetAgentByID(name)
+		if extended == nil {
+			fmt.Printf("⚠️  Ignoring %s: phase %s is assigned to unknown agent '%s'\n", AssignmentsPath(am.projectRoot), phase, name)
+			delete(assignments, phase)
+			continue
+		}
+		am.agents[name] = agentFromExtended(extended)
+	}
+
 	return nil
 }
 
@@ -89,6 +134,10 @@ func (am *AgentManager) loadFromDir(dir string, isRaw bool) error {
 func (am *AgentManager) GetAgent(name string) (*Agent, error) {
 	agent, exists := am.agents[name]
 	if !exists {
+		// Built-in agents are usable by ID without a role file
+		if extended := GetAgentByID(name); extended != nil {
+			return agentFromExtended(extended), nil
+		}
 		return nil, fmt.Errorf("agent not found: %s", name)
 	}
 	return agent, nil
@@ -103,8 +152,47 @@ func (am *AgentManager) ListAgents() []string {
 	return names
 }
 
+// CustomAgentForPhase returns the agent assigned to the phase in
+// .sdd/agents.yaml or, failing that, a custom role that declares the phase
+// in its frontmatter, choosing the first by name when several do
+func (am *AgentManager) CustomAgentForPhase(phase string) (string, bool) {
+	if name, ok := am.assignments[phase]; ok {
+		return name, true
+	}
+
+	names := am.ListAgents()
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, builtIn := DefaultRoles[name+".md"]; builtIn {
+			continue
+		}
+		if _, ok := am.agents[name].Phases[phase]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
 // GetAgentForPhase returns the appropriate agent for a given phase
 func (am *AgentManager) GetAgentForPhase(phase string) (*Agent, error) {
+	agentName, err := am.AgentNameForPhase(phase)
+	if err != nil {
+		return nil, err
+	}
+	return am.GetAgent(agentName)
+}
+
+// AgentNameForPhase returns the name of the agent that runs a phase: the
+// custom agent assigned to it, or the built-in one
+func (am *AgentManager) AgentNameForPhase(phase string) (string, error) {
+	if name, ok := am.CustomAgentForPhase(phase); ok {
+		if warning := am.CheckPhaseFit(name, phase); warning != "" {
+			fmt.Println(warning)
+		}
+		return name, nil
+	}
+
 	var agentName string
 	switch phase {
 	case "discover":
@@ -124,10 +212,30 @@ func (am *AgentManager) GetAgentForPhase(phase string) (*Agent, error) {
 	case "deploy":
 		agentName = "sre"
 	default:
-		return nil, fmt.Errorf("no agent defined for phase: %s", phase)
+		return "", fmt.Errorf("no agent defined for phase: %s", phase)
 	}
 
-	return am.GetAgent(agentName)
+	return agentName, nil
+}
+
+// RoleFileErrors parses every role file in .sdd/role and returns the
+// errors of those that fail, keyed by file name
+func (am *AgentManager) RoleFileErrors() (map[string]error, error) {
+	files, err := os.ReadDir(am.agentsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	failures := make(map[string]error)
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".md") {
+			continue
+		}
+		if _, err := am.loadRawAgent(filepath.Join(am.agentsDir, file.Name())); err != nil {
+			failures[file.Name()] = err
+		}
+	}
+	return failures, nil
 }
 
 // loadAgent loads a single agent from a markdown file with frontmatter
@@ -164,11 +272,31 @@ func (am *AgentManager) loadRawAgent(filePath string) (*Agent, error) {
 
 	name := strings.TrimSuffix(filepath.Base(filePath), ".md")
 
-	return &Agent{
-		Content: string(content),
-		IsRaw:   true,
-		Role:    name, // Default to filename
-	}, nil
+	agent := &Agent{}
+
+	// Role files keep their frontmatter in the prompt, but it also carries
+	// tuning such as the preferred temperature
+	if parts := strings.SplitN(string(content), "---", 3); len(parts) == 3 && strings.TrimSpace(parts[0]) == "" {
+		if err := yaml.Unmarshal([]byte(parts[1]), agent); err != nil {
+			return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+		}
+	}
+
+	agent.Content = string(content)
+	agent.IsRaw = true
+	agent.Role = name // Default to filename
+
+	return agent, nil
+}
+
+// TemperatureOptions returns the request options setting the temperature
+// declared in the agent's role file. An agent declaring none gets fallback
+// as the default, which the active config profile's temperature overrides.
+func (a *Agent) TemperatureOptions(fallback float64) map[string]interface{} {
+	if a.Temperature == nil {
+		return map[string]interface{}{mcp.DefaultTemperatureOption: fallback}
+	}
+	return map[string]interface{}{"temperature": *a.Temperature}
 }
 
 // GetSystemPrompt generates a system prompt for the agent
@@ -192,7 +320,9 @@ Always respond in character and maintain your role throughout the interaction.`,
 func (a *Agent) GetPhasePrompt(phase, context string) string {
 	// With the new role-based prompts, the instructions are often baked into the system prompt or skill.
 	// We can keep this generic wrapper.
-	return fmt.Sprintf(`Current Phase: %s
-Context: %s
-`, strings.Title(phase), context)
+	prompt := fmt.Sprintf("Current Phase: %s\n", strings.Title(phase))
+	if instructions := a.Phases[phase]; instructions != "" {
+		prompt += fmt.Sprintf("Phase Instructions: %s\n", instructions)
+	}
+	return prompt + fmt.Sprintf("Context: %s\n", context)
 }
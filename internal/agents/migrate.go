@@ -0,0 +1,48 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LegacyContextPath returns where older versions kept the brownfield
+// context, .sdd/CONTEXT.md
+func LegacyContextPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "CONTEXT.md")
+}
+
+// BrownfieldContextPath returns the brownfield context whose presence makes
+// agents load the legacy patterns and constraints of the codebase
+func BrownfieldContextPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "context", "current_state.md")
+}
+
+// HasLegacyContext reports whether the project still uses .sdd/CONTEXT.md
+func HasLegacyContext(projectRoot string) bool {
+	_, err := os.Stat(LegacyContextPath(projectRoot))
+	return err == nil
+}
+
+// MigrateLegacyContext moves .sdd/CONTEXT.md to the brownfield context in
+// .sdd/context/, keeping its content. An existing brownfield context is only
+// replaced with force.
+func MigrateLegacyContext(projectRoot string, force bool) (string, error) {
+	from := LegacyContextPath(projectRoot)
+	to := BrownfieldContextPath(projectRoot)
+
+	if !HasLegacyContext(projectRoot) {
+		return "", fmt.Errorf("no legacy context to migrate: %s does not exist", from)
+	}
+	if _, err := os.Stat(to); err == nil && !force {
+		return "", fmt.Errorf("%s already exists; compare it with %s and rerun with --force to replace it", to, from)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return "", fmt.Errorf("failed to create context directory: %w", err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return "", fmt.Errorf("failed to move %s: %w", from, err)
+	}
+	return to, nil
+}
@@ -0,0 +1,121 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/goccy/go-yaml"
+)
+
+// WorkflowPhases lists the phases a custom role can declare, in workflow order
+var WorkflowPhases = []string{"discover", "specify", "design", "audit", "task", "execute", "review", "validate", "evolve"}
+
+var agentIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// RoleSpec describes a custom agent role to scaffold
+type RoleSpec struct {
+	ID          string
+	Name        string
+	Role        string
+	Expertise   string
+	Personality string
+	Tone        string
+	Focus       string
+	Category    string
+	Temperature *float64
+	Phases      map[string]string // phase -> instructions
+}
+
+// roleFrontmatter is the frontmatter written for a scaffolded role file
+type roleFrontmatter struct {
+	Name        string            `yaml:"name"`
+	Role        string            `yaml:"role"`
+	Expertise   string            `yaml:"expertise,omitempty"`
+	Personality string            `yaml:"personality,omitempty"`
+	Tone        string            `yaml:"tone,omitempty"`
+	Focus       string            `yaml:"focus,omitempty"`
+	Category    string            `yaml:"category,omitempty"`
+	Temperature *float64          `yaml:"temperature,omitempty"`
+	Phases      map[string]string `yaml:"phases,omitempty"`
+}
+
+// ValidateAgentID checks that id is a well-formed agent id not already used
+// by a role file in the project or a built-in agent
+func ValidateAgentID(projectRoot, id string) error {
+	if !agentIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid agent id '%s': use lowercase letters, digits, '-' or '_', starting with a letter", id)
+	}
+
+	rolePath := filepath.Join(projectRoot, ".sdd", "role", id+".md")
+	if _, err := os.Stat(rolePath); err == nil {
+		return fmt.Errorf("agent '%s' already exists at %s", id, rolePath)
+	}
+	if GetAgentByID(id) != nil {
+		return fmt.Errorf("agent id '%s' is already used by a built-in agent", id)
+	}
+
+	return nil
+}
+
+// CreateRoleFile validates spec and writes it to .sdd/role/<id>.md, returning
+// the path of the new role file
+func CreateRoleFile(projectRoot string, spec RoleSpec) (string, error) {
+	if err := ValidateAgentID(projectRoot, spec.ID); err != nil {
+		return "", err
+	}
+	if spec.Role == "" {
+		return "", fmt.Errorf("role cannot be empty")
+	}
+	if spec.Category != "" && !slices.Contains(AgentCategories, spec.Category) {
+		return "", fmt.Errorf("unknown category '%s'", spec.Category)
+	}
+	for phase := range spec.Phases {
+		if !slices.Contains(WorkflowPhases, phase) {
+			return "", UnknownPhaseError(phase)
+		}
+	}
+	if spec.Name == "" {
+		spec.Name = spec.ID
+	}
+
+	frontmatter, err := yaml.Marshal(roleFrontmatter{
+		Name:        spec.Name,
+		Role:        spec.Role,
+		Expertise:   spec.Expertise,
+		Personality: spec.Personality,
+		Tone:        spec.Tone,
+		Focus:       spec.Focus,
+		Category:    spec.Category,
+		Temperature: spec.Temperature,
+		Phases:      spec.Phases,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal role frontmatter: %w", err)
+	}
+
+	body := fmt.Sprintf("You are the %s. Act as a %s", spec.Name, spec.Role)
+	if spec.Expertise != "" {
+		body += fmt.Sprintf(" with expertise in %s", spec.Expertise)
+	}
+	body += "."
+	if spec.Focus != "" {
+		body += fmt.Sprintf(" Focus on %s.", spec.Focus)
+	}
+
+	content := fmt.Sprintf("---\n%s---\n%s\n", frontmatter, body)
+
+	roleDir := filepath.Join(projectRoot, ".sdd", "role")
+	if err := os.MkdirAll(roleDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create role directory: %w", err)
+	}
+
+	rolePath := filepath.Join(roleDir, spec.ID+".md")
+	if err := os.WriteFile(rolePath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write role file: %w", err)
+	}
+
+	return rolePath, nil
+}
@@ -4,33 +4,72 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
+	"ultimate-sdd-framework/internal/editor"
+	"ultimate-sdd-framework/internal/gates"
 	"ultimate-sdd-framework/internal/lsp"
 	"ultimate-sdd-framework/internal/mcp"
-
-	"github.com/goccy/go-yaml"
+	"ultimate-sdd-framework/internal/metrics"
+	"ultimate-sdd-framework/internal/secrets"
 )
 
+// RedactSecrets controls whether new agent services mask likely secrets in
+// file content before it is sent to a provider (disabled by --no-redact)
+var RedactSecrets = true
+
 // AgentService provides high-level agent operations with context awareness
 type AgentService struct {
 	agentMgr             *AgentManager
 	mcpMgr               *mcp.MCPManager
+	client               ChatClient // where agent calls are sent
 	lspContext           *lsp.CodebaseContext
 	brownfieldCtx        *lsp.BrownfieldContext
 	projectRoot          string
 	hasBrownfieldContext bool
+	redact               bool
+	stripInjections      bool
+	modelsChecked        sync.Once // the configured models are checked before the first call
+	transcript           bool      // write prompts and responses to .sdd/debug
+	transcriptTrack      string    // track whose phase is running, for the transcript
+	transcriptsAnnounced map[string]bool
+	workflow             *WorkflowProfile
+	undo                 *editor.UndoStack
+	undoOp               *editor.Operation // records the files the running execute writes, for 'viki undo'
 }
 
 // NewAgentService creates a new agent service
 func NewAgentService(projectRoot string) *AgentService {
+	mcpMgr := mcp.NewMCPManager(projectRoot)
 	return &AgentService{
-		agentMgr:    NewAgentManager(projectRoot),
-		mcpMgr:      mcp.NewMCPManager(projectRoot),
-		projectRoot: projectRoot,
+		agentMgr:             NewAgentManager(projectRoot),
+		mcpMgr:               mcpMgr,
+		client:               &providerClient{mcpMgr: mcpMgr},
+		projectRoot:          projectRoot,
+		redact:               RedactSecrets,
+		stripInjections:      StripInjections,
+		transcript:           DebugTranscript,
+		transcriptsAnnounced: make(map[string]bool),
 	}
 }
 
+// SetRedaction enables or disables secret redaction for this service
+func (as *AgentService) SetRedaction(enabled bool) {
+	as.redact = enabled
+}
+
+// RedactContent masks likely secrets in content unless redaction is disabled,
+// returning the content and the number of redactions made
+func (as *AgentService) RedactContent(content string) (string, int) {
+	if !as.redact {
+		return content, 0
+	}
+	return secrets.Redact(content)
+}
+
 // Initialize loads all components
 func (as *AgentService) Initialize() error {
 	// Load agents
@@ -43,9 +82,25 @@ func (as *AgentService) Initialize() error {
 		return fmt.Errorf("failed to load MCP config: %w", err)
 	}
 
-	// Check for brownfield context (CONTEXT.md) in .sdd/context/
-	// (migrated from .sdd/CONTEXT.md based on new structure, but keeping logic resilient)
-	contextPath := filepath.Join(as.projectRoot, ".sdd", "context", "current_state.md")
+	// Answer agent calls from canned responses when running mocked
+	if MockResponsesPath != "" {
+		mock, err := mcp.LoadMockClient(MockResponsesPath)
+		if err != nil {
+			return err
+		}
+		as.SetChatClient(mock)
+	}
+
+	// Load the workflow profile the orchestrator follows
+	workflow, err := LoadWorkflow(as.projectRoot)
+	if err != nil {
+		return err
+	}
+	as.workflow = workflow
+
+	// Check for brownfield context in .sdd/context/; older projects kept it
+	// in .sdd/CONTEXT.md until 'viki migrate' moves it
+	contextPath := BrownfieldContextPath(as.projectRoot)
 	if _, err := os.Stat(contextPath); err == nil {
 		// Brownfield context exists - use it
 		as.brownfieldCtx = lsp.NewBrownfieldContext(as.projectRoot)
@@ -57,6 +112,10 @@ func (as *AgentService) Initialize() error {
 		// Still initialize regular LSP context for compatibility
 		as.lspContext = &as.brownfieldCtx.CodebaseContext
 	} else {
+		if HasLegacyContext(as.projectRoot) {
+			fmt.Printf("⚠️  Brownfield context in %s is not loaded; run 'viki migrate' to move it\n", LegacyContextPath(as.projectRoot))
+		}
+
 		// No brownfield context - use regular LSP analysis
 		as.lspContext = lsp.NewCodebaseContext(as.projectRoot)
 		if err := as.lspContext.AnalyzeProject(); err != nil {
@@ -65,15 +124,49 @@ func (as *AgentService) Initialize() error {
 		as.hasBrownfieldContext = false
 	}
 
+	contextConfig, err := lsp.LoadContextConfig(as.projectRoot)
+	if err != nil {
+		return err
+	}
+	as.lspContext.SetKeyFileLimits(contextConfig.KeyFiles)
+
 	return nil
 }
 
+// TrackLockWait is how long a phase waits for another viki invocation
+// working on the same track before giving up
+var TrackLockWait = 10 * time.Minute
+
+// lockTrack takes the lock of a track for the duration of a phase, so
+// concurrent invocations on one track run one after the other
+func (as *AgentService) lockTrack(trackID string) (*gates.TrackLock, error) {
+	return gates.LockTrack(as.projectRoot, trackID, TrackLockWait, func(pid int, since time.Time) {
+		fmt.Printf("⏳ Track %s is in use by another viki process (pid %d, since %s); waiting...\n",
+			trackID, pid, since.Format("15:04:05"))
+	})
+}
+
 // Orchestrate handles the 7-Gate SDD Workflow
 func (as *AgentService) Orchestrate(phase string, trackID string, userInput string) (string, error) {
 	// 1. Identify Role and Artifacts based on Phase
 	roleName, prevArtifact, currentArtifact, skill := as.getPhaseConfig(phase)
+	if as.workflow != nil && !as.workflow.Includes(phase) {
+		return "", fmt.Errorf("the %s phase is not part of the %s workflow (phases: %s)",
+			phase, as.workflow.Name, strings.Join(as.workflow.Phases, " → "))
+	}
 
-	// 2. Gatekeeper Check: Ensure previous phase artifact is APPROVED
+	lock, err := as.lockTrack(trackID)
+	if err != nil {
+		return "", err
+	}
+	defer lock.Release()
+	as.transcriptTrack = trackID
+
+	// 2. Gatekeeper Check: the workflow state machine rejects jumps past
+	// phases that have not run, then the previous phase artifact must be APPROVED
+	if err := NewWorkflowStateMachine(as.workflow).CheckTransition(phase, as.trackHasArtifact(trackID)); err != nil {
+		return "", err
+	}
 	if prevArtifact != "" {
 		approved, err := as.checkGateApproval(trackID, prevArtifact)
 		if err != nil {
@@ -92,24 +185,164 @@ func (as *AgentService) Orchestrate(phase string, trackID string, userInput stri
 
 	// 4. Special Handling for Security Gate (Guardian)
 	if phase == "audit" {
-		return as.runSecurityGate(trackID, contextInfo)
+		return as.runSecurityGate(roleName, trackID, contextInfo)
+	}
+
+	// 5. Validation runs the test suite before the inspector weighs in
+	if phase == "validate" {
+		return as.runValidation(roleName, trackID, userInput, contextInfo, skill, currentArtifact)
 	}
 
-	// 5. Get Agent Response
+	// 6. Get Agent Response
 	response, err := as.GetAgentResponse(roleName, phase, userInput, contextInfo, skill)
 	if err != nil {
 		return "", err
 	}
 
-	// 6. Save Artifact (Draft)
+	// 7. Save Artifact (Draft)
 	if err := as.SaveArtifact(trackID, currentArtifact, response, "PENDING"); err != nil {
 		return "", fmt.Errorf("failed to save artifact: %w", err)
 	}
 
+	if phase == "specify" || phase == "design" {
+		as.WarnVisionDivergence(currentArtifact, response)
+	}
+	if phase == "design" {
+		as.ExportDecisions(filepath.Join(".sdd", "tracks", trackID, currentArtifact), response)
+	}
+
 	return response, nil
 }
 
+// Revise re-runs a phase for a track, feeding the agent its previous draft
+// together with the reviewer's rejection feedback
+func (as *AgentService) Revise(phase string, trackID string, userInput string) (string, error) {
+	roleName, prevArtifact, currentArtifact, skill := as.getPhaseConfig(phase)
+	if roleName == "" {
+		return "", UnknownPhaseError(phase)
+	}
+
+	lock, err := as.lockTrack(trackID)
+	if err != nil {
+		return "", err
+	}
+	defer lock.Release()
+	as.transcriptTrack = trackID
+
+	draft, err := gates.LoadArtifact(as.projectRoot, trackID, currentArtifact)
+	if err != nil {
+		return "", fmt.Errorf("no previous draft of %s to revise: %w", currentArtifact, err)
+	}
+	if draft.Status != gates.ArtifactRejected {
+		return "", fmt.Errorf("%s is %s; only REJECTED artifacts can be revised", currentArtifact, draft.Status)
+	}
+
+	contextInfo, err := as.prepareContext(phase, trackID, prevArtifact)
+	if err != nil {
+		return "", fmt.Errorf("failed to prepare context: %w", err)
+	}
+
+	masked, redactions := as.RedactContent(draft.Body)
+	if redactions > 0 {
+		fmt.Printf("🔒 Redacted %d likely secret(s) from the previous draft\n", redactions)
+	}
+	previous, _ := as.untrusted(currentArtifact, masked)
+	contextInfo += fmt.Sprintf("\n\n## PREVIOUS DRAFT (%s, REJECTED)\n%s\n", currentArtifact, previous)
+	contextInfo += fmt.Sprintf("\n\n## REVIEWER FEEDBACK (MUST ADDRESS)\n%s\n", draft.Feedback)
+	contextInfo += "\nProduce a complete revised version of the artifact that resolves every point of the feedback while keeping what was already correct.\n"
+
+	// The audit reruns the security gate, now with the rejected report and
+	// the feedback on it in its context
+	if phase == "audit" {
+		if strings.TrimSpace(userInput) != "" {
+			contextInfo += fmt.Sprintf("\n## REVISION REQUEST\n%s\n", userInput)
+		}
+		return as.runSecurityGate(roleName, trackID, contextInfo)
+	}
+
+	response, err := as.GetAgentResponse(roleName, phase, userInput, contextInfo, skill)
+	if err != nil {
+		return "", err
+	}
+
+	if err := as.SaveArtifact(trackID, currentArtifact, response, gates.ArtifactPending); err != nil {
+		return "", fmt.Errorf("failed to save artifact: %w", err)
+	}
+	if phase == "design" {
+		as.ExportDecisions(filepath.Join(".sdd", "tracks", trackID, currentArtifact), response)
+	}
+
+	return response, nil
+}
+
+// Clarify asks the agent of the specify phase for the open questions that
+// most need answering before a spec can be designed against, as a numbered
+// list from most to least important
+func (as *AgentService) Clarify(spec string) (string, error) {
+	roleName, _, _, _ := as.getPhaseConfig("specify")
+
+	masked, redactions := as.RedactContent(spec)
+	if redactions > 0 {
+		fmt.Printf("🔒 Redacted %d likely secret(s) from the prompt context\n", redactions)
+	}
+	fenced, _ := as.untrusted("specification", masked)
+	contextInfo := fmt.Sprintf("\n\n## SPECIFICATION\n%s\n", fenced)
+
+	instructions := `Review the specification and list the questions that must be answered before it can be designed and built.
+Look for missing acceptance criteria, undefined terms, unhandled edge cases and unstated constraints (scale, security, integrations).
+Reply with ONLY a numbered list of at most 10 questions, most important first. End each question with the gap it closes in brackets, e.g. "[acceptance criteria]".`
+
+	return as.GetAgentResponse(roleName, "clarify", instructions, contextInfo, "")
+}
+
+// RejectArtifact marks a track artifact REJECTED with feedback for its agent
+func (as *AgentService) RejectArtifact(trackID, artifact, feedback string) error {
+	return gates.RejectArtifact(as.projectRoot, trackID, artifact, feedback)
+}
+
+// getPhaseConfig returns the role, input artifact, output artifact and skill
+// of a phase. A custom role declaring the phase replaces the default role,
+// and the workflow profile decides which artifact gates the phase and how
+// artifacts are named.
 func (as *AgentService) getPhaseConfig(phase string) (role, prev, curr, skill string) {
+	defaultRole, _, _, skill := defaultPhaseConfig(phase)
+	prev, curr = as.workflow.PhaseArtifacts(phase)
+	if role = as.phaseRole(phase); role != defaultRole {
+		if warning := as.agentMgr.CheckPhaseFit(role, phase); warning != "" {
+			fmt.Println(warning)
+		}
+	}
+	return role, prev, curr, skill
+}
+
+// phaseRole returns the role that runs a phase: a custom role declaring the
+// phase, or else the default role
+func (as *AgentService) phaseRole(phase string) string {
+	role, _, _, _ := defaultPhaseConfig(phase)
+	if custom, ok := as.agentMgr.CustomAgentForPhase(phase); ok && role != "" {
+		return custom
+	}
+	return role
+}
+
+// RequiredAgents returns the roles that run the phases of the project's
+// workflow, each once, in phase order
+func (as *AgentService) RequiredAgents() []string {
+	phases := WorkflowPhases
+	if as.workflow != nil {
+		phases = as.workflow.Phases
+	}
+
+	var roles []string
+	for _, phase := range phases {
+		if role := as.phaseRole(phase); role != "" && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func defaultPhaseConfig(phase string) (role, prev, curr, skill string) {
 	switch phase {
 	case "discover":
 		return "scout", "", "0_discovery.md", "research-codebase"
@@ -123,6 +356,8 @@ func (as *AgentService) getPhaseConfig(phase string) (role, prev, curr, skill st
 		return "taskmaster", "2_architecture.md", "gsd.json", "plan-feature"
 	case "execute":
 		return "builder", "gsd.json", "source_code", "gsd-execute" // Builder follows GSD checklist
+	case "review":
+		return "guardian", "source_code", "4_code_review.md", "code-review" // Enterprise code review gate
 	case "validate":
 		return "inspector", "source_code", "5_validation_report.md", "piv-validate"
 	case "evolve":
@@ -132,6 +367,20 @@ func (as *AgentService) getPhaseConfig(phase string) (role, prev, curr, skill st
 	}
 }
 
+// RequiredSkills returns the skills the workflow phases equip, each once
+func RequiredSkills() []string {
+	var skills []string
+	for _, phase := range WorkflowPhases {
+		if _, _, _, skill := defaultPhaseConfig(phase); skill != "" && !slices.Contains(skills, skill) {
+			skills = append(skills, skill)
+		}
+	}
+	return skills
+}
+
+// checkGateApproval reports whether an artifact is APPROVED, either by a
+// reviewer or by the project's gate policy for the phase that produced it.
+// Phases the policy assigns several approvers need that many distinct ones.
 func (as *AgentService) checkGateApproval(trackID, artifactName string) (bool, error) {
 	// For "source_code", we assume implicit approval if validation is running,
 	// or we might check git status. For now, skip file check for source_code.
@@ -139,8 +388,7 @@ func (as *AgentService) checkGateApproval(trackID, artifactName string) (bool, e
 		return true, nil
 	}
 
-	path := filepath.Join(as.projectRoot, ".sdd", "tracks", trackID, artifactName)
-	content, err := os.ReadFile(path)
+	artifact, err := gates.LoadArtifact(as.projectRoot, trackID, artifactName)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return false, nil
@@ -148,34 +396,87 @@ func (as *AgentService) checkGateApproval(trackID, artifactName string) (bool, e
 		return false, err
 	}
 
-	// Parse frontmatter
-	parts := strings.SplitN(string(content), "---", 3)
-	if len(parts) < 3 {
-		return false, nil // No frontmatter
+	phase := as.workflow.ProducerOf(artifactName)
+	policy, err := gates.LoadPolicy(as.projectRoot)
+	if err != nil {
+		return false, err
 	}
 
-	var metadata map[string]interface{}
-	if err := yaml.Unmarshal([]byte(parts[1]), &metadata); err != nil {
-		return false, err
+	// Gates that need several reviewers open only once enough distinct
+	// users signed off; neither the status alone nor the policy suffices
+	if required := policy.RequiredApprovers(phase); required > 1 {
+		if approvals := len(artifact.Approvers); approvals < required {
+			fmt.Printf("ℹ️  %s has %d of %d required approvals\n", artifactName, approvals, required)
+			return false, nil
+		}
+		return artifact.Status == gates.ArtifactApproved, nil
 	}
 
-	status, ok := metadata["status"].(string)
-	if !ok {
+	if artifact.Status == gates.ArtifactApproved {
+		return true, nil
+	}
+
+	// Fall back to the gate policy of the producing phase
+	if phase == "" {
+		return false, nil
+	}
+	if _, ok := policy.AutoApprove[phase]; !ok {
 		return false, nil
 	}
 
-	return strings.ToUpper(status) == "APPROVED", nil
+	approved, reason := policy.AutoApproves(phase, artifact)
+	if !approved {
+		fmt.Printf("ℹ️  Gate policy did not approve %s: %s\n", artifactName, reason)
+		return false, nil
+	}
+
+	if err := gates.SetArtifactStatus(as.projectRoot, trackID, artifactName, gates.ArtifactApproved); err != nil {
+		return false, err
+	}
+	fmt.Printf("✅ %s auto-approved by gate policy (%s)\n", artifactName, reason)
+	return true, nil
+}
+
+// producingPhase returns the workflow phase whose output is artifactName
+func producingPhase(artifactName string) string {
+	for _, phase := range WorkflowPhases {
+		if _, _, curr, _ := defaultPhaseConfig(phase); curr == artifactName {
+			return phase
+		}
+	}
+	return ""
+}
+
+// builderConstraints are the phases whose artifacts the builder is given on
+// top of the GSD checklist, with the heading each is injected under
+var builderConstraints = []struct {
+	Phase   string
+	Heading string
+}{
+	{"design", "ARCHITECTURE SPECIFICATION"},
+	{"audit", "SECURITY CONSTRAINTS (MANDATORY)"},
 }
 
 func (as *AgentService) prepareContext(phase, trackID, prevArtifact string) (string, error) {
 	var contextBuilder strings.Builder
+	redactions, injections := 0, 0
+
+	// ingest masks secrets in injected file content and fences it as
+	// untrusted data before it reaches the prompt
+	ingest := func(source string, content []byte) string {
+		masked, n := as.RedactContent(string(content))
+		redactions += n
+		fenced, stripped := as.untrusted(source, masked)
+		injections += stripped
+		return fenced
+	}
 
 	// 1. Ingest previous artifact if exists
 	if prevArtifact != "" && prevArtifact != "source_code" {
 		path := filepath.Join(as.projectRoot, ".sdd", "tracks", trackID, prevArtifact)
 		content, err := os.ReadFile(path)
 		if err == nil {
-			contextBuilder.WriteString(fmt.Sprintf("\n\n## INPUT ARTIFACT (%s)\n%s\n", prevArtifact, string(content)))
+			contextBuilder.WriteString(fmt.Sprintf("\n\n## INPUT ARTIFACT (%s)\n%s\n", prevArtifact, ingest(prevArtifact, content)))
 		}
 	}
 
@@ -184,20 +485,28 @@ func (as *AgentService) prepareContext(phase, trackID, prevArtifact string) (str
 		// Already handled by prevArtifact="0_discovery.md"
 	}
 
+	// Keep requirements and design anchored to the project vision
+	if phase == "specify" {
+		contextBuilder.WriteString(as.CodebaseContext(phase))
+	}
+	if phase == "specify" || phase == "design" {
+		contextBuilder.WriteString(as.VisionContext())
+	}
+	if phase == "design" {
+		contextBuilder.WriteString(DecisionRecordInstructions)
+	}
+
 	// 3. Add Builder Constraints (Blind to PRD, sees GSD + Arch Spec + Security Report)
 	if phase == "execute" {
 		// GSD is in prevArtifact.
 		// Need to inject Arch Spec and Security Report as well.
-		archPath := filepath.Join(as.projectRoot, ".sdd", "tracks", trackID, "2_architecture.md")
-		archContent, err := os.ReadFile(archPath)
-		if err == nil {
-			contextBuilder.WriteString(fmt.Sprintf("\n\n## ARCHITECTURE SPECIFICATION\n%s\n", string(archContent)))
-		}
-
-		secPath := filepath.Join(as.projectRoot, ".sdd", "tracks", trackID, "3_security_report.md")
-		secContent, err := os.ReadFile(secPath)
-		if err == nil {
-			contextBuilder.WriteString(fmt.Sprintf("\n\n## SECURITY CONSTRAINTS (MANDATORY)\n%s\n", string(secContent)))
+		for _, constraint := range builderConstraints {
+			artifact := as.workflow.ArtifactFor(constraint.Phase)
+			path := filepath.Join(as.projectRoot, ".sdd", "tracks", trackID, artifact)
+			content, err := os.ReadFile(path)
+			if err == nil {
+				contextBuilder.WriteString(fmt.Sprintf("\n\n## %s\n%s\n", constraint.Heading, ingest(artifact, content)))
+			}
 		}
 	}
 
@@ -209,18 +518,35 @@ func (as *AgentService) prepareContext(phase, trackID, prevArtifact string) (str
 	}
 
 	// 5. Inject Conductor Context
-	contextBuilder.WriteString(as.getConductorContext())
+	if conductor := as.getConductorContext(); conductor != "" {
+		contextBuilder.WriteString("\n\n" + ingest(".sdd/context", []byte(conductor)))
+	}
+
+	if redactions > 0 {
+		fmt.Printf("🔒 Redacted %d likely secret(s) from the prompt context\n", redactions)
+	}
+	if injections > 0 {
+		fmt.Printf("🛡️  Removed %d likely prompt-injection phrase(s) from the prompt context\n", injections)
+	}
 
 	return contextBuilder.String(), nil
 }
 
-// runSecurityGate is the specialized logic for the Guardian
-func (as *AgentService) runSecurityGate(trackID, contextInfo string) (string, error) {
+// PhaseContext returns the context the agent of a phase is given for a
+// track, e.g. for an editor asking what the current file is being built
+// against
+func (as *AgentService) PhaseContext(phase, trackID string) (string, error) {
+	_, prevArtifact, _, _ := as.getPhaseConfig(phase)
+	return as.prepareContext(phase, trackID, prevArtifact)
+}
+
+// runSecurityGate is the specialized logic for the Guardian, or the custom
+// role that took over the audit phase
+func (as *AgentService) runSecurityGate(agentName, trackID, contextInfo string) (string, error) {
 	fmt.Println("🛡️  Gate 3: Security Guardian is auditing the design...")
 
 	// The contextInfo already contains the ARCH_SPEC (prevArtifact)
 
-	agentName := "guardian"
 	skill := "architecture-audit"
 
 	// Get Agent
@@ -232,20 +558,16 @@ func (as *AgentService) runSecurityGate(trackID, contextInfo string) (string, er
 	systemPrompt := agent.GetSystemPrompt()
 	// Add skill instruction
 	systemPrompt += fmt.Sprintf("\n\n[SYSTEM]: You have equipped the skill '%s'. Use it to perform your task.", skill)
+	systemPrompt = withUntrustedNote(systemPrompt, contextInfo)
 
 	prompt := fmt.Sprintf("%s\n\nCONTEXT:\n%s\n\nINSTRUCTIONS: Perform a deep security audit. Find at least one risk. Issue a PASS/FAIL verdict.", systemPrompt, contextInfo)
 
 	// Call AI
-	client, err := as.mcpMgr.GetClient("")
-	if err != nil {
-		return "", err
-	}
-
 	messages := []mcp.Message{
 		{Role: "user", Content: prompt},
 	}
 
-	resp, err := client.Chat(messages, map[string]interface{}{"temperature": 0.0}) // Low temp for audit
+	resp, err := as.chat("audit", messages, agent.TemperatureOptions(0.0)) // Low temp for audit
 	if err != nil {
 		return "", err
 	}
@@ -254,21 +576,23 @@ func (as *AgentService) runSecurityGate(trackID, contextInfo string) (string, er
 	}
 
 	report := resp.Choices[0].Message.Content
+	reportName := as.workflow.ArtifactFor("audit")
 
 	// Check for FAIL (Gate Blocking)
 	if strings.Contains(report, "[STATUS: FAIL]") {
 		fmt.Println("❌ SECURITY GATE BLOCKED: Implementation cannot proceed.")
 		// We still save the report so the Architect sees it
-		as.SaveArtifact(trackID, "3_security_report.md", report, "REJECTED")
+		as.SaveArtifact(trackID, reportName, report, "REJECTED")
+		metrics.NewStore(as.projectRoot).Add(metrics.GateRejections, metrics.Labels{"artifact": reportName}, 1)
 		return report, nil // Return report but user needs to revise Arch Spec
 	}
 
 	fmt.Println("✅ SECURITY GATE PASSED.")
-	as.SaveArtifact(trackID, "3_security_report.md", report, "APPROVED") // Auto-approve if passed? Or wait for human?
+	as.SaveArtifact(trackID, reportName, report, "APPROVED") // Auto-approve if passed? Or wait for human?
 	// Prompt says: "Human approves the security hardening."
 	// But Guardian output says "Status: PASS".
 	// Let's set it to PENDING so human can confirm.
-	as.SaveArtifact(trackID, "3_security_report.md", report, "PENDING")
+	as.SaveArtifact(trackID, reportName, report, "PENDING")
 
 	return report, nil
 }
@@ -296,29 +620,23 @@ func (as *AgentService) GetAgentResponse(agentName, phase, userInput, contextInf
 		}
 	}
 
+	systemPrompt = withUntrustedNote(systemPrompt, contextInfo)
+
 	// Phase Prompt (Agent's internal logic)
 	phasePrompt := agent.GetPhasePrompt(phase, contextInfo)
 
 	// Combine with user input
 	prompt := fmt.Sprintf("%s\n\n%s\n\nUser Input: %s", systemPrompt, phasePrompt, userInput)
 
-	// Get MCP client
-	client, err := as.mcpMgr.GetClient("")
-	if err != nil {
-		return "", fmt.Errorf("no MCP client available: %w", err)
-	}
-
 	// Send to AI model
 	messages := []mcp.Message{
 		{Role: "user", Content: prompt},
 	}
 
-	options := map[string]interface{}{
-		"temperature": 0.7,
-		"max_tokens":  4000,
-	}
+	options := agent.TemperatureOptions(0.7)
+	options["max_tokens"] = 4000
 
-	response, err := client.Chat(messages, options)
+	response, err := as.chat(phase, messages, options)
 	if err != nil {
 		return "", fmt.Errorf("AI request failed: %w", err)
 	}
@@ -330,16 +648,42 @@ func (as *AgentService) GetAgentResponse(agentName, phase, userInput, contextInf
 	return response.Choices[0].Message.Content, nil
 }
 
-// SaveArtifact writes content to the track folder with frontmatter
+// chat sends messages for a phase through the service's chat client (the
+// provider fallback chain unless mocked) and logs which provider served the
+// response
+func (as *AgentService) chat(phase string, messages []mcp.Message, options map[string]interface{}) (*mcp.ChatResponse, error) {
+	if !as.Mocked() {
+		as.modelsChecked.Do(func() {
+			for _, unknown := range as.mcpMgr.UnknownModels() {
+				fmt.Printf("⚠️  %s\n", unknown)
+			}
+		})
+	}
+
+	response, provider, err := as.client.Chat(phase, messages, options)
+	if as.transcript {
+		as.logTranscript(phase, provider, messages, response, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// SaveArtifact writes content to the track folder with frontmatter,
+// archiving any previous version under the track's history directory
 func (as *AgentService) SaveArtifact(trackID, filename, content, status string) error {
-	dir := filepath.Join(as.projectRoot, ".sdd", "tracks", trackID)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := gates.ValidateArtifactStatus(as.projectRoot, status); err != nil {
 		return err
 	}
 
-	fullContent := fmt.Sprintf("---\nstatus: %s\nphase: %s\n---\n\n%s", status, strings.TrimSuffix(filename, ".md"), content)
+	// Keep the previous revision so changes can be reviewed with 'viki workflow diff'
+	if err := gates.ArchiveArtifact(as.projectRoot, trackID, filename, content); err != nil {
+		return fmt.Errorf("failed to archive previous version: %w", err)
+	}
 
-	return os.WriteFile(filepath.Join(dir, filename), []byte(fullContent), 0644)
+	fm := &gates.Frontmatter{Status: status, Phase: strings.TrimSuffix(filename, ".md")}
+	return gates.WriteArtifact(as.projectRoot, trackID, filename, fm, content)
 }
 
 // getConductorContext reads files from .sdd/context/ to inject persistent context
@@ -429,16 +773,36 @@ func (as *AgentService) GetBrownfieldSummary() string {
 	return summary.String()
 }
 
+// ProviderLimits returns the requests per minute and concurrent calls the
+// default provider allows
+func (as *AgentService) ProviderLimits() (requestsPerMinute, maxConcurrent int) {
+	return as.mcpMgr.Limits()
+}
+
 // GetAgentForPhase returns the appropriate agent for a phase
 func (as *AgentService) GetAgentForPhase(phase string) (*Agent, error) {
 	return as.agentMgr.GetAgentForPhase(phase)
 }
 
+// AgentNameForPhase returns the name of the agent that runs a phase
+func (as *AgentService) AgentNameForPhase(phase string) (string, error) {
+	return as.agentMgr.AgentNameForPhase(phase)
+}
+
 // ListAgents returns available agents
 func (as *AgentService) ListAgents() []string {
 	return as.agentMgr.ListAgents()
 }
 
+// CodebaseContext returns what a phase should know about the existing
+// codebase, "" before the codebase was analyzed
+func (as *AgentService) CodebaseContext(phase string) string {
+	if as.lspContext == nil {
+		return ""
+	}
+	return "\n\n" + as.lspContext.GetContextForPhase(phase)
+}
+
 // GetCodebaseSummary returns a summary of the codebase
 func (as *AgentService) GetCodebaseSummary() string {
 	if as.lspContext == nil {
@@ -498,27 +862,21 @@ func (as *AgentService) loadContext(filename string) string {
 func (as *AgentService) ValidateSetup() []string {
 	var issues []string
 
-	// Check agents
+	// Check the agents the workflow's phases need
 	agents := as.agentMgr.ListAgents()
-	requiredAgents := []string{"strategist", "designer", "builder", "inspector", "scout", "guardian", "librarian"}
-	for _, required := range requiredAgents {
-		found := false
-		for _, agent := range agents {
-			if agent == required {
-				found = true
-				break
-			}
-		}
-		if !found {
+	for _, required := range as.RequiredAgents() {
+		if !slices.Contains(agents, required) {
 			issues = append(issues, fmt.Sprintf("Required agent '%s' not found in .sdd/role/ directory", required))
 		}
 	}
 
-	// Check MCP configuration
+	// Check MCP configuration; mocked runs need no provider
 	providers := as.mcpMgr.ListProviders()
-	if len(providers) == 0 {
-		issues = append(issues, "No AI providers configured. Run 'sdd mcp add <name> --provider <provider>'")
-	} else {
+	switch {
+	case as.Mocked():
+	case len(providers) == 0:
+		issues = append(issues, "No AI providers configured. Run 'viki mcp add <name> --provider <provider>'")
+	default:
 		enabledProviders := 0
 		for _, config := range providers {
 			if config.Enabled {
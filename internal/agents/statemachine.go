@@ -0,0 +1,149 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ultimate-sdd-framework/internal/gates"
+)
+
+// StateStart is the state of a track before any phase has run
+const StateStart = "start"
+
+// PhaseTransition is an allowed move of a track into a workflow phase. From
+// is the phase producing Guard, the artifact that must be approved before
+// the move; transitions from StateStart may have no guard.
+type PhaseTransition struct {
+	From  string
+	To    string
+	Guard string
+}
+
+// WorkflowStateMachine models a workflow profile as a state machine: its
+// phases are the states a track moves through, and a phase can be entered
+// once every phase its gate artifact depends on has run
+type WorkflowStateMachine struct {
+	phases      []string
+	transitions map[string]PhaseTransition // keyed by the phase entered
+	artifacts   map[string]string          // phase -> artifact it produces
+}
+
+// NewWorkflowStateMachine builds the state machine of a workflow profile,
+// of the full workflow when workflow is nil
+func NewWorkflowStateMachine(workflow *WorkflowProfile) *WorkflowStateMachine {
+	phases := WorkflowPhases
+	if workflow != nil {
+		phases = workflow.Phases
+	}
+
+	sm := &WorkflowStateMachine{
+		phases:      phases,
+		transitions: make(map[string]PhaseTransition),
+		artifacts:   make(map[string]string),
+	}
+	for _, phase := range phases {
+		sm.artifacts[phase] = workflow.ArtifactFor(phase)
+	}
+	for _, phase := range phases {
+		guard, _ := workflow.PhaseArtifacts(phase)
+		from := sm.producerOf(guard)
+		if from == "" {
+			from = StateStart
+		}
+		sm.transitions[phase] = PhaseTransition{From: from, To: phase, Guard: guard}
+	}
+	return sm
+}
+
+// States returns the phases of the workflow in order
+func (sm *WorkflowStateMachine) States() []string {
+	return sm.phases
+}
+
+// Transitions returns the transition into each phase, in phase order
+func (sm *WorkflowStateMachine) Transitions() []PhaseTransition {
+	transitions := make([]PhaseTransition, 0, len(sm.phases))
+	for _, phase := range sm.phases {
+		transitions = append(transitions, sm.transitions[phase])
+	}
+	return transitions
+}
+
+// Prerequisites returns the phases that must have run before phase, nearest
+// first, following the chain of guards back to the start
+func (sm *WorkflowStateMachine) Prerequisites(phase string) []string {
+	var chain []string
+	seen := map[string]bool{phase: true}
+	for {
+		transition, ok := sm.transitions[phase]
+		if !ok || transition.From == StateStart || seen[transition.From] {
+			return chain
+		}
+		phase = transition.From
+		seen[phase] = true
+		chain = append(chain, phase)
+	}
+}
+
+// CurrentState returns the latest phase of the workflow whose artifact the
+// track has, StateStart when it has none. exists reports whether the track
+// has an artifact; phases producing no file are never the current state.
+func (sm *WorkflowStateMachine) CurrentState(exists func(artifact string) bool) string {
+	for i := len(sm.phases) - 1; i >= 0; i-- {
+		if artifact := sm.artifacts[sm.phases[i]]; isFileArtifact(artifact) && exists(artifact) {
+			return sm.phases[i]
+		}
+	}
+	return StateStart
+}
+
+// CheckTransition returns an error when a track cannot move to target
+// because a phase it depends on has not run, naming the earliest such
+// phase. Approval of the guard artifact is checked by the gatekeeper.
+func (sm *WorkflowStateMachine) CheckTransition(target string, exists func(artifact string) bool) error {
+	if _, ok := sm.transitions[target]; !ok {
+		return fmt.Errorf("cannot transition to %s: not a phase of the workflow (phases: %s)",
+			target, strings.Join(sm.phases, " → "))
+	}
+
+	missing := ""
+	for _, phase := range sm.Prerequisites(target) {
+		if artifact := sm.artifacts[phase]; isFileArtifact(artifact) && !exists(artifact) {
+			missing = phase
+		}
+	}
+	if missing == "" {
+		return nil
+	}
+	return fmt.Errorf("cannot transition %s→%s, %s required", sm.CurrentState(exists), target, missing)
+}
+
+// producerOf returns the phase of the workflow producing artifact, or ""
+func (sm *WorkflowStateMachine) producerOf(artifact string) string {
+	if artifact == "" {
+		return ""
+	}
+	for _, phase := range sm.phases {
+		if sm.artifacts[phase] == artifact {
+			return phase
+		}
+	}
+	return ""
+}
+
+// isFileArtifact reports whether a phase output is a file of the track
+// directory rather than changes to the project itself
+func isFileArtifact(artifact string) bool {
+	return artifact != "" && artifact != "source_code" && artifact != "context_update"
+}
+
+// trackHasArtifact returns a function reporting whether the track has an
+// artifact, for the workflow state machine
+func (as *AgentService) trackHasArtifact(trackID string) func(artifact string) bool {
+	return func(artifact string) bool {
+		_, err := os.Stat(filepath.Join(gates.TracksDir(as.projectRoot), trackID, artifact))
+		return err == nil
+	}
+}
@@ -0,0 +1,130 @@
+package agents
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// hasArtifacts reports the artifacts of a track holding the given files
+func hasArtifacts(files ...string) func(string) bool {
+	return func(artifact string) bool {
+		return slices.Contains(files, artifact)
+	}
+}
+
+func TestWorkflowStateMachineTransitions(t *testing.T) {
+	profiles := DefaultWorkflowProfiles()
+
+	tests := []struct {
+		name     string
+		workflow *WorkflowProfile
+		phase    string
+		want     PhaseTransition
+	}{
+		{"first phase starts", nil, "discover", PhaseTransition{From: StateStart, To: "discover"}},
+		{"full workflow", nil, "design", PhaseTransition{From: "specify", To: "design", Guard: "1_prd.md"}},
+		{"audit does not gate task by default", nil, "task", PhaseTransition{From: "design", To: "task", Guard: "2_architecture.md"}},
+		{"quick skips design and task", profiles["quick"], "execute", PhaseTransition{From: "specify", To: "execute", Guard: "1_prd.md"}},
+		{"enterprise audit gates task", profiles["enterprise"], "task", PhaseTransition{From: "audit", To: "task", Guard: "3_security_report.md"}},
+		{"enterprise review gates validate", profiles["enterprise"], "validate", PhaseTransition{From: "review", To: "validate", Guard: "4_code_review.md"}},
+		{
+			name:     "renamed artifacts",
+			workflow: &WorkflowProfile{Phases: []string{"discover", "specify"}, Artifacts: map[string]string{"discover": "context.md"}},
+			phase:    "specify",
+			want:     PhaseTransition{From: "discover", To: "specify", Guard: "context.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := NewWorkflowStateMachine(tt.workflow)
+			got, ok := sm.transitions[tt.phase]
+			if !ok || got != tt.want {
+				t.Errorf("transition into %s = %+v, want %+v", tt.phase, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowStateMachinePrerequisites(t *testing.T) {
+	profiles := DefaultWorkflowProfiles()
+
+	tests := []struct {
+		name     string
+		workflow *WorkflowProfile
+		phase    string
+		want     []string
+	}{
+		{"first phase", nil, "discover", nil},
+		{"nearest first", nil, "task", []string{"design", "specify", "discover"}},
+		{"audit is off the path to task", profiles["standard"], "execute", []string{"task", "design", "specify", "discover"}},
+		{"quick", profiles["quick"], "execute", []string{"specify", "discover"}},
+		{"not a phase", profiles["quick"], "design", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewWorkflowStateMachine(tt.workflow).Prerequisites(tt.phase); !slices.Equal(got, tt.want) {
+				t.Errorf("Prerequisites(%s) = %v, want %v", tt.phase, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowStateMachineCurrentState(t *testing.T) {
+	tests := []struct {
+		name   string
+		exists func(string) bool
+		want   string
+	}{
+		{"no artifacts", hasArtifacts(), StateStart},
+		{"latest artifact wins", hasArtifacts("0_discovery.md", "1_prd.md"), "specify"},
+		{"gaps do not matter", hasArtifacts("0_discovery.md", "gsd.json"), "task"},
+		{"code is never a state", hasArtifacts("source_code"), StateStart},
+	}
+
+	sm := NewWorkflowStateMachine(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sm.CurrentState(tt.exists); got != tt.want {
+				t.Errorf("CurrentState() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowStateMachineCheckTransition(t *testing.T) {
+	profiles := DefaultWorkflowProfiles()
+
+	tests := []struct {
+		name     string
+		workflow *WorkflowProfile
+		target   string
+		exists   func(string) bool
+		wantErr  string
+	}{
+		{"first phase", nil, "discover", hasArtifacts(), ""},
+		{"prerequisites present", nil, "design", hasArtifacts("0_discovery.md", "1_prd.md"), ""},
+		{"names the earliest missing phase", nil, "design", hasArtifacts(), "cannot transition start→design, discover required"},
+		{"names the nearer missing phase", nil, "task", hasArtifacts("0_discovery.md", "1_prd.md"), "cannot transition specify→task, design required"},
+		{"code needs no file", nil, "validate", hasArtifacts("0_discovery.md", "1_prd.md", "2_architecture.md", "gsd.json"), ""},
+		{"quick reaches execute from the spec", profiles["quick"], "execute", hasArtifacts("0_discovery.md", "1_prd.md"), ""},
+		{"phase outside the workflow", profiles["quick"], "design", hasArtifacts(), "not a phase of the workflow (phases: discover → specify → execute)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewWorkflowStateMachine(tt.workflow).CheckTransition(tt.target, tt.exists)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckTransition(%s) error = %v", tt.target, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckTransition(%s) error = %v, want %q", tt.target, err, tt.wantErr)
+			}
+		})
+	}
+}
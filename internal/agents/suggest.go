@@ -0,0 +1,68 @@
+package agents
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSuggestionDistance is the most edits a typo may be from a name for the
+// name to be suggested
+const maxSuggestionDistance = 2
+
+// ClosestMatch returns the candidate a mistyped input most likely meant:
+// the nearest by edit distance, within maxSuggestionDistance, or else the
+// only candidate the input is a prefix of. It returns "" when none is close.
+func ClosestMatch(input string, candidates []string) string {
+	input = strings.ToLower(input)
+	best, bestDistance := "", maxSuggestionDistance+1
+	var prefixed []string
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		if lower == input {
+			return candidate
+		}
+		if distance := levenshtein(input, lower); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+		if len(input) >= 2 && strings.HasPrefix(lower, input) {
+			prefixed = append(prefixed, candidate)
+		}
+	}
+	if best == "" && len(prefixed) == 1 {
+		return prefixed[0]
+	}
+	return best
+}
+
+// levenshtein returns the number of single-character insertions, deletions
+// and substitutions that turn a into b
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		current[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(t)]
+}
+
+// UnknownPhaseError reports a phase that is not part of the workflow,
+// suggesting the phase it most likely meant
+func UnknownPhaseError(phase string) error {
+	if suggestion := ClosestMatch(phase, WorkflowPhases); suggestion != "" {
+		return fmt.Errorf("unknown phase '%s' - did you mean '%s'? (valid: %s)", phase, suggestion, strings.Join(WorkflowPhases, ", "))
+	}
+	return fmt.Errorf("unknown phase '%s' (valid: %s)", phase, strings.Join(WorkflowPhases, ", "))
+}
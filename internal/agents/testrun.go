@@ -0,0 +1,372 @@
+package agents
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ultimate-sdd-framework/internal/gates"
+)
+
+// testTimeout bounds a run of the project's test suite
+const testTimeout = 15 * time.Minute
+
+// maxTestOutputLines is how much failure output a validation report quotes
+const maxTestOutputLines = 60
+
+var (
+	goCoveragePattern     = regexp.MustCompile(`coverage: (\d+(?:\.\d+)?)% of statements`)
+	summaryCountPattern   = regexp.MustCompile(`(\d+) (passed|passing|failed|failing|skipped|pending|errors?)\b`)
+	pytestFailurePattern  = regexp.MustCompile(`(?m)^(?:FAILED|ERROR) (\S+)`)
+	pytestCoveragePattern = regexp.MustCompile(`(?m)^TOTAL\s.*?(\d+(?:\.\d+)?)%\s*$`)
+	jestFailurePattern    = regexp.MustCompile(`(?m)^\s*● (.+?)\s*$`)
+	jestCoveragePattern   = regexp.MustCompile(`(?m)^All files\s*\|\s*(\d+(?:\.\d+)?)`)
+	cargoFailurePattern   = regexp.MustCompile(`(?m)^test (\S+) \.\.\. FAILED`)
+	cargoSummaryPattern   = regexp.MustCompile(`test result: \w+\. (\d+) passed; (\d+) failed; (\d+) ignored`)
+)
+
+// TestRun is the outcome of running a project's test suite
+type TestRun struct {
+	Command  string
+	ExitCode int
+	Passed   int
+	Failed   int
+	Skipped  int
+	Failures []string // names of the failing tests
+	Coverage float64  // statement coverage percent, -1 when not reported
+	Output   string   // output of the failures
+}
+
+// DetectTestCommand returns the command running the project's test suite,
+// or "" when there is none it recognizes
+func DetectTestCommand(projectRoot string) string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(projectRoot, name))
+		return err == nil
+	}
+
+	switch {
+	case exists("go.mod"):
+		return "go test -json -cover ./..."
+	case exists("Cargo.toml"):
+		return "cargo test"
+	case exists("package.json"):
+		var manifest struct {
+			Scripts map[string]string `json:"scripts"`
+		}
+		data, _ := os.ReadFile(filepath.Join(projectRoot, "package.json"))
+		if json.Unmarshal(data, &manifest) == nil && manifest.Scripts["test"] != "" {
+			return "npm test --silent"
+		}
+	case exists("pytest.ini"), exists("pyproject.toml"), exists("setup.cfg"), exists("tests"):
+		return "python3 -m pytest -q -rfE"
+	}
+	return ""
+}
+
+// RunTests runs command in the project and parses its results
+func RunTests(projectRoot, command string) (*TestRun, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = projectRoot
+	output, err := cmd.CombinedOutput()
+
+	run := &TestRun{Command: command, Coverage: -1}
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to run tests (%s): %w", command, err)
+		}
+		run.ExitCode = exitErr.ExitCode()
+	}
+
+	if strings.Contains(command, "go test") && strings.Contains(command, "-json") {
+		parseGoTestJSON(run, string(output))
+	} else {
+		parseTestSummary(run, string(output))
+	}
+	return run, nil
+}
+
+// parseGoTestJSON counts the test events of 'go test -json'
+func parseGoTestJSON(run *TestRun, output string) {
+	testOutput := make(map[string]*strings.Builder)
+	var coverage []float64
+	var other strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		var event struct {
+			Action  string
+			Package string
+			Test    string
+			Output  string
+		}
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &event) != nil {
+			// Compile errors are printed outside the JSON stream
+			other.WriteString(line + "\n")
+			continue
+		}
+
+		key := event.Package
+		if event.Test != "" {
+			key += "." + event.Test
+		}
+
+		switch event.Action {
+		case "output":
+			if testOutput[key] == nil {
+				testOutput[key] = &strings.Builder{}
+			}
+			testOutput[key].WriteString(event.Output)
+			if match := goCoveragePattern.FindStringSubmatch(event.Output); match != nil && event.Test == "" {
+				value, _ := strconv.ParseFloat(match[1], 64)
+				coverage = append(coverage, value)
+			}
+		case "pass":
+			if event.Test != "" {
+				run.Passed++
+			}
+		case "skip":
+			if event.Test != "" {
+				run.Skipped++
+			}
+		case "fail":
+			if event.Test != "" {
+				run.Failed++
+				run.Failures = append(run.Failures, key)
+			} else if out := testOutput[key]; out != nil && strings.Contains(out.String(), "[build failed]") {
+				run.Failed++
+				run.Failures = append(run.Failures, event.Package+" (build failed)")
+			}
+		}
+	}
+
+	var failureOutput strings.Builder
+	for _, name := range run.Failures {
+		if out := testOutput[strings.TrimSuffix(name, " (build failed)")]; out != nil {
+			failureOutput.WriteString(out.String())
+		}
+	}
+	failureOutput.WriteString(other.String())
+	run.Output = lastLines(failureOutput.String(), maxTestOutputLines)
+
+	if len(coverage) > 0 {
+		total := 0.0
+		for _, value := range coverage {
+			total += value
+		}
+		run.Coverage = total / float64(len(coverage))
+	}
+}
+
+// parseTestSummary reads the summary lines of pytest, jest, mocha and cargo
+func parseTestSummary(run *TestRun, output string) {
+	if matches := cargoSummaryPattern.FindAllStringSubmatch(output, -1); matches != nil {
+		for _, match := range matches {
+			passed, _ := strconv.Atoi(match[1])
+			failed, _ := strconv.Atoi(match[2])
+			ignored, _ := strconv.Atoi(match[3])
+			run.Passed += passed
+			run.Failed += failed
+			run.Skipped += ignored
+		}
+	} else {
+		// The last summary line wins: pytest and jest print theirs at the end
+		for _, line := range strings.Split(output, "\n") {
+			counts := summaryCountPattern.FindAllStringSubmatch(line, -1)
+			if counts == nil {
+				continue
+			}
+			run.Passed, run.Failed, run.Skipped = 0, 0, 0
+			for _, count := range counts {
+				n, _ := strconv.Atoi(count[1])
+				switch count[2] {
+				case "passed", "passing":
+					run.Passed = n
+				case "failed", "failing", "error", "errors":
+					run.Failed += n
+				case "skipped", "pending":
+					run.Skipped = n
+				}
+			}
+		}
+	}
+
+	for _, pattern := range []*regexp.Regexp{pytestFailurePattern, jestFailurePattern, cargoFailurePattern} {
+		for _, match := range pattern.FindAllStringSubmatch(output, -1) {
+			if !slices.Contains(run.Failures, match[1]) {
+				run.Failures = append(run.Failures, match[1])
+			}
+		}
+	}
+
+	for _, pattern := range []*regexp.Regexp{pytestCoveragePattern, jestCoveragePattern} {
+		if match := pattern.FindStringSubmatch(output); match != nil {
+			run.Coverage, _ = strconv.ParseFloat(match[1], 64)
+		}
+	}
+
+	if run.ExitCode != 0 {
+		run.Output = lastLines(output, maxTestOutputLines)
+	}
+}
+
+// EvaluateTests decides whether a test run meets the policy, returning the
+// reasons it does not
+func EvaluateTests(run *TestRun, policy gates.TestPolicy) (bool, []string) {
+	var reasons []string
+
+	total := run.Passed + run.Failed
+	if total == 0 {
+		if run.ExitCode != 0 {
+			reasons = append(reasons, fmt.Sprintf("the test command exited with status %d", run.ExitCode))
+		}
+	} else if rate := float64(run.Passed) / float64(total); rate < policy.PassRate() {
+		reasons = append(reasons, fmt.Sprintf("%d of %d tests passed (%.0f%%, %.0f%% required)",
+			run.Passed, total, rate*100, policy.PassRate()*100))
+	}
+
+	if policy.MinCoverage > 0 {
+		switch {
+		case run.Coverage < 0:
+			reasons = append(reasons, fmt.Sprintf("coverage was not reported (%.0f%% required)", policy.MinCoverage))
+		case run.Coverage < policy.MinCoverage:
+			reasons = append(reasons, fmt.Sprintf("coverage %.1f%% is below the required %.0f%%", run.Coverage, policy.MinCoverage))
+		}
+	}
+
+	return len(reasons) == 0, reasons
+}
+
+// TestReport renders a test run and its verdict as the head of a
+// validation report
+func TestReport(run *TestRun, policy gates.TestPolicy, passed bool, reasons []string) string {
+	var report strings.Builder
+
+	verdict := "PASS"
+	if !passed {
+		verdict = "FAIL"
+	}
+	report.WriteString(fmt.Sprintf("# Validation Report\n\n[STATUS: %s]\n\n## Test Results (executed)\n\n", verdict))
+	report.WriteString(fmt.Sprintf("- Command: `%s` (exit status %d)\n", run.Command, run.ExitCode))
+	report.WriteString(fmt.Sprintf("- Passed: %d, Failed: %d, Skipped: %d\n", run.Passed, run.Failed, run.Skipped))
+	if run.Coverage >= 0 {
+		report.WriteString(fmt.Sprintf("- Coverage: %.1f%%\n", run.Coverage))
+	} else {
+		report.WriteString("- Coverage: not reported\n")
+	}
+	requirement := fmt.Sprintf("%.0f%% of tests passing", policy.PassRate()*100)
+	if policy.MinCoverage > 0 {
+		requirement += fmt.Sprintf(", coverage of at least %.0f%%", policy.MinCoverage)
+	}
+	report.WriteString(fmt.Sprintf("- Requirement: %s\n", requirement))
+
+	if len(reasons) > 0 {
+		report.WriteString("\n### Why it fails\n\n")
+		for _, reason := range reasons {
+			report.WriteString(fmt.Sprintf("- %s\n", reason))
+		}
+	}
+	if len(run.Failures) > 0 {
+		report.WriteString("\n### Failing Tests\n\n")
+		for _, name := range run.Failures {
+			report.WriteString(fmt.Sprintf("- %s\n", name))
+		}
+	}
+	if !passed && run.Output != "" {
+		report.WriteString(fmt.Sprintf("\n### Output\n\n```\n%s\n```\n", strings.TrimSpace(run.Output)))
+	}
+
+	return report.String()
+}
+
+// testFailureFeedback is the rejection feedback of a failing test run: why
+// it failed and which tests did
+func testFailureFeedback(run *TestRun, reasons []string) string {
+	feedback := "Tests failed"
+	if len(reasons) > 0 {
+		feedback += ": " + strings.Join(reasons, "; ")
+	}
+	if len(run.Failures) > 0 {
+		feedback += "\nFailing tests: " + strings.Join(run.Failures, ", ")
+	}
+	return feedback
+}
+
+// runValidation runs the project's tests for the validate phase. When they
+// fail the report states FAIL with the failing tests and no AI review;
+// otherwise the inspector reviews the implementation knowing the results.
+func (as *AgentService) runValidation(roleName, trackID, userInput, contextInfo, skill, artifact string) (string, error) {
+	policy, err := gates.LoadPolicy(as.projectRoot)
+	if err != nil {
+		return "", err
+	}
+
+	command := policy.Tests.Command
+	if command == "" {
+		command = DetectTestCommand(as.projectRoot)
+	}
+
+	var testReport string
+	if command != "" {
+		fmt.Printf("🧪 Running tests: %s\n", command)
+		run, err := RunTests(as.projectRoot, command)
+		if err != nil {
+			return "", err
+		}
+		passed, reasons := EvaluateTests(run, policy.Tests)
+		testReport = TestReport(run, policy.Tests, passed, reasons)
+
+		if !passed {
+			// A failing suite is rejected outright so neither the gate
+			// policy nor a manual approval can let it through
+			if err := as.SaveArtifact(trackID, artifact, testReport, gates.ArtifactPending); err != nil {
+				return "", fmt.Errorf("failed to save artifact: %w", err)
+			}
+			if err := gates.RejectArtifact(as.projectRoot, trackID, artifact, testFailureFeedback(run, reasons)); err != nil {
+				return "", fmt.Errorf("failed to reject artifact: %w", err)
+			}
+			return testReport, nil
+		}
+		contextInfo += fmt.Sprintf("\n\n## TEST RESULTS (ALREADY EXECUTED, DO NOT CONTRADICT)\n%s\n", testReport)
+	} else {
+		fmt.Println("⚠️  No test suite detected; the validation verdict comes from the inspector's review")
+	}
+
+	response, err := as.GetAgentResponse(roleName, "validate", userInput, contextInfo, skill)
+	if err != nil {
+		return "", err
+	}
+
+	report := response
+	if testReport != "" {
+		report = testReport + "\n## Inspector Review\n\n" + response
+	}
+	if err := as.SaveArtifact(trackID, artifact, report, gates.ArtifactPending); err != nil {
+		return "", fmt.Errorf("failed to save artifact: %w", err)
+	}
+	return report, nil
+}
+
+// lastLines returns at most n trailing lines of text
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = append([]string{"..."}, lines[len(lines)-n:]...)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,280 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ultimate-sdd-framework/internal/gates"
+)
+
+var (
+	requirementIDPattern = regexp.MustCompile(`\b((?:FR|NFR|REQ|US|UC|R)-?\d+(?:\.\d+)?)\b`)
+	listItemPattern      = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?(.+)$`)
+	requirementHeading   = regexp.MustCompile(`(?i)requirement|user stor|feature|acceptance|functional|capabilit|scope`)
+	skippedHeading       = regexp.MustCompile(`(?i)non[- ]?functional|out of scope|non[- ]?goal|open question|risk|assumption|glossary`)
+	decisionHeading      = regexp.MustCompile(`(?i)decision|adr|technolog|tech stack|component|architecture`)
+)
+
+// taskStopWords are verbs every task starts with, left out when matching
+var taskStopWords = map[string]bool{
+	"add": true, "create": true, "implement": true, "update": true, "setup": true, "set": true,
+	"write": true, "test": true, "ensure": true, "handle": true, "refactor": true, "define": true,
+	"new": true, "using": true,
+}
+
+// TraceItem is a requirement, architecture decision or task
+type TraceItem struct {
+	ID   string
+	Text string
+	Refs []string // requirement or decision IDs the item cites explicitly
+
+	words map[string]bool
+}
+
+// Traceability links a track's PRD requirements and architecture decisions
+// to the tasks of its gsd.json
+type Traceability struct {
+	Requirements []TraceItem
+	Decisions    []TraceItem
+	Tasks        []TraceItem
+	Coverage     map[string][]string // requirement or decision ID -> task IDs
+	Backing      map[string][]string // task ID -> requirement and decision IDs
+}
+
+// GSDTask is a task of a gsd.json checklist
+type GSDTask struct {
+	ID           string   `json:"id,omitempty"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description,omitempty"`
+	Done         bool     `json:"done"`
+	Requirements []string `json:"requirements,omitempty"`
+	Files        []string `json:"files,omitempty"`
+	DependsOn    []string `json:"depends_on,omitempty"` // IDs of tasks that must finish first
+}
+
+// AnalyzeTraceability cross-checks the PRD, architecture and task
+// breakdown of a track. A task covers a requirement when it cites the
+// requirement's ID or the two share key terms.
+func AnalyzeTraceability(projectRoot, trackID string) (*Traceability, error) {
+	prd, err := gates.LoadArtifact(projectRoot, trackID, ArtifactName(projectRoot, "specify"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the PRD of track %s: %w", trackID, err)
+	}
+
+	trace := &Traceability{
+		Requirements: parseTraceItems(prd.Body, "R", requirementHeading),
+		Coverage:     make(map[string][]string),
+		Backing:      make(map[string][]string),
+	}
+
+	if arch, err := gates.LoadArtifact(projectRoot, trackID, ArtifactName(projectRoot, "design")); err == nil {
+		trace.Decisions = parseTraceItems(arch.Body, "D", decisionHeading)
+	}
+
+	tasks, err := LoadGSDTasks(projectRoot, trackID)
+	if err != nil {
+		return nil, err
+	}
+	trace.Tasks = gsdTraceItems(tasks)
+
+	for _, task := range trace.Tasks {
+		for _, group := range [][]TraceItem{trace.Requirements, trace.Decisions} {
+			for _, item := range group {
+				if traces(task, item) {
+					trace.Coverage[item.ID] = append(trace.Coverage[item.ID], task.ID)
+					trace.Backing[task.ID] = append(trace.Backing[task.ID], item.ID)
+				}
+			}
+		}
+	}
+
+	return trace, nil
+}
+
+// UnimplementedRequirements returns the requirements no task covers
+func (t *Traceability) UnimplementedRequirements() []TraceItem {
+	var missing []TraceItem
+	for _, requirement := range t.Requirements {
+		if len(t.Coverage[requirement.ID]) == 0 {
+			missing = append(missing, requirement)
+		}
+	}
+	return missing
+}
+
+// OrphanTasks returns the tasks backed by no requirement or decision
+func (t *Traceability) OrphanTasks() []TraceItem {
+	var orphans []TraceItem
+	for _, task := range t.Tasks {
+		if len(t.Backing[task.ID]) == 0 {
+			orphans = append(orphans, task)
+		}
+	}
+	return orphans
+}
+
+// Matrix renders the coverage matrix and the gaps as markdown
+func (t *Traceability) Matrix() string {
+	var matrix strings.Builder
+
+	matrix.WriteString("## Coverage Matrix\n\n")
+	matrix.WriteString("| Requirement | Tasks |\n|---|---|\n")
+	for _, requirement := range t.Requirements {
+		tasks := "⚠️ unimplemented requirement"
+		if covering := t.Coverage[requirement.ID]; len(covering) > 0 {
+			tasks = strings.Join(covering, ", ")
+		}
+		matrix.WriteString(fmt.Sprintf("| %s %s | %s |\n", requirement.ID, escapeCell(requirement.Text), tasks))
+	}
+
+	if len(t.Decisions) > 0 {
+		matrix.WriteString("\n| Decision | Tasks |\n|---|---|\n")
+		for _, decision := range t.Decisions {
+			tasks := "-"
+			if covering := t.Coverage[decision.ID]; len(covering) > 0 {
+				tasks = strings.Join(covering, ", ")
+			}
+			matrix.WriteString(fmt.Sprintf("| %s %s | %s |\n", decision.ID, escapeCell(decision.Text), tasks))
+		}
+	}
+
+	matrix.WriteString("\n## Tasks\n\n")
+	for _, task := range t.Tasks {
+		backing := "⚠️ orphan task"
+		if ids := t.Backing[task.ID]; len(ids) > 0 {
+			backing = strings.Join(ids, ", ")
+		}
+		matrix.WriteString(fmt.Sprintf("- %s %s (%s)\n", task.ID, task.Text, backing))
+	}
+
+	return matrix.String()
+}
+
+func escapeCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// parseTraceItems collects the top-level list items under matching headings,
+// and any line citing a requirement ID, numbering items without an ID
+func parseTraceItems(body, prefix string, heading *regexp.Regexp) []TraceItem {
+	var items []TraceItem
+	inSection := false
+
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			inSection = heading.MatchString(trimmed) && !skippedHeading.MatchString(trimmed)
+			continue
+		}
+
+		match := listItemPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		text := strings.TrimSpace(strings.Trim(match[2], "*_ "))
+
+		// Nested items add detail to the item they belong to
+		if len(match[1]) >= 2 && len(items) > 0 && inSection {
+			items[len(items)-1].Text += "; " + text
+			continue
+		}
+
+		id := requirementIDPattern.FindString(text)
+		if !inSection && (prefix != "R" || id == "" || !strings.HasPrefix(text, id)) {
+			continue
+		}
+
+		if id != "" && strings.HasPrefix(text, id) {
+			text = strings.TrimSpace(strings.TrimLeft(strings.TrimPrefix(text, id), ":.-–*) "))
+		} else {
+			id = fmt.Sprintf("%s%d", prefix, len(items)+1)
+		}
+		items = append(items, TraceItem{ID: id, Text: text})
+	}
+
+	for i := range items {
+		items[i].words = traceWords(items[i].Text)
+	}
+	return items
+}
+
+// LoadGSDTasks reads the task checklist (gsd.json) of a track
+func LoadGSDTasks(projectRoot, trackID string) ([]GSDTask, error) {
+	path := filepath.Join(gates.TracksDir(projectRoot), trackID, ArtifactName(projectRoot, "task"))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the task breakdown of track %s: %w", trackID, err)
+	}
+
+	_, body, err := gates.ParseFrontmatter(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Agents sometimes wrap the JSON in a code fence or prose
+	start, end := strings.Index(body, "{"), strings.LastIndex(body, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("%s contains no task JSON", path)
+	}
+
+	var checklist struct {
+		Tasks []GSDTask `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(body[start:end+1]), &checklist); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return checklist.Tasks, nil
+}
+
+// gsdTraceItems converts tasks to trace items with the IDs they cite
+func gsdTraceItems(checklist []GSDTask) []TraceItem {
+	tasks := make([]TraceItem, len(checklist))
+	for i, task := range checklist {
+		text := task.Title
+		if task.Description != "" {
+			text += ": " + task.Description
+		}
+		refs := append([]string{}, task.Requirements...)
+		refs = append(refs, requirementIDPattern.FindAllString(text, -1)...)
+
+		tasks[i] = TraceItem{
+			ID:    TaskID(task, i),
+			Text:  task.Title,
+			Refs:  refs,
+			words: traceWords(text),
+		}
+	}
+	return tasks
+}
+
+// traces reports whether task implements item: by citing its ID or, when
+// the task cites nothing, by sharing key terms with it
+func traces(task, item TraceItem) bool {
+	if len(task.Refs) > 0 {
+		return slices.ContainsFunc(task.Refs, func(ref string) bool { return strings.EqualFold(ref, item.ID) })
+	}
+
+	shared := 0
+	for word := range task.words {
+		if item.words[word] {
+			shared++
+		}
+	}
+	return shared >= 2 || (shared == 1 && min(len(task.words), len(item.words)) <= 2)
+}
+
+// traceWords returns the key terms of a requirement or task
+func traceWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range keywords(text) {
+		if !taskStopWords[word] && !requirementIDPattern.MatchString(strings.ToUpper(word)) {
+			words[word] = true
+		}
+	}
+	return words
+}
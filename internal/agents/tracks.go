@@ -0,0 +1,101 @@
+package agents
+
+import (
+	"os"
+	"sort"
+
+	"ultimate-sdd-framework/internal/gates"
+)
+
+// TrackStatus summarizes where a track stands in the workflow
+type TrackStatus struct {
+	ID               string
+	Phase            string // latest phase with an artifact, "" if none
+	Artifact         string // that phase's artifact
+	Status           string // PENDING, APPROVED or REJECTED
+	NextPhase        string // "" once the workflow is finished
+	AwaitingApproval bool
+	Blocked          bool // rejected and waiting for a revision
+	Approved         int  // approved artifacts in the track
+}
+
+// TrackStatuses returns the status of every track in .sdd/tracks, following
+// the phases of the project's workflow profile
+func TrackStatuses(projectRoot string) ([]TrackStatus, error) {
+	workflow, err := LoadWorkflow(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(gates.TracksDir(projectRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var statuses []TrackStatus
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		statuses = append(statuses, trackStatus(projectRoot, entry.Name(), workflow))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ID < statuses[j].ID })
+	return statuses, nil
+}
+
+// trackStatus finds the latest phase of the workflow whose artifact exists
+// in the track and what it is waiting on
+func trackStatus(projectRoot, trackID string, workflow *WorkflowProfile) TrackStatus {
+	status := TrackStatus{ID: trackID}
+	current := -1
+
+	for i, phase := range workflow.Phases {
+		curr := workflow.ArtifactFor(phase)
+		artifact, err := gates.LoadArtifact(projectRoot, trackID, curr)
+		if err != nil {
+			continue
+		}
+
+		current = i
+		status.Phase = phase
+		status.Artifact = curr
+		status.Status = artifact.Status
+		if artifact.Status == gates.ArtifactApproved {
+			status.Approved++
+		}
+	}
+
+	switch {
+	case current < 0:
+		status.NextPhase = workflow.Phases[0]
+	case current+1 < len(workflow.Phases):
+		status.NextPhase = workflow.Phases[current+1]
+	}
+
+	status.AwaitingApproval = gates.AwaitingApproval(status.Status)
+	status.Blocked = status.Status == gates.ArtifactRejected
+	return status
+}
+
+// ApproveArtifact records approver's sign-off on a track artifact. The
+// artifact becomes APPROVED once as many distinct users as the gate policy
+// requires for the phase producing it have approved. It returns the
+// approvals so far and the number required.
+func ApproveArtifact(projectRoot, trackID, name, approver string) (approvals, required int, err error) {
+	workflow, err := LoadWorkflow(projectRoot)
+	if err != nil {
+		return 0, 0, err
+	}
+	policy, err := gates.LoadPolicy(projectRoot)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	required = policy.RequiredApprovers(workflow.ProducerOf(name))
+	approvals, err = gates.ApproveArtifact(projectRoot, trackID, name, approver, required)
+	return approvals, required, err
+}
@@ -0,0 +1,82 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"ultimate-sdd-framework/internal/mcp"
+	"ultimate-sdd-framework/internal/secrets"
+)
+
+// DebugTranscript controls whether new agent services write every prompt
+// and response to .sdd/debug/<track>/<phase>.log (enabled by
+// --debug-transcript)
+var DebugTranscript = false
+
+// untrackedTranscript is the directory of calls made outside a track
+const untrackedTranscript = "untracked"
+
+// transcriptMu serializes appends, as tasks of a track run in parallel
+var transcriptMu sync.Mutex
+
+// TranscriptPath returns the debug transcript of a phase of a track
+func TranscriptPath(projectRoot, trackID, phase string) string {
+	return filepath.Join(projectRoot, ".sdd", "debug", trackID, phase+".log")
+}
+
+// logTranscript appends a call to the transcript of its phase, secrets
+// masked whether or not prompts are redacted
+func (as *AgentService) logTranscript(phase, provider string, messages []mcp.Message, response *mcp.ChatResponse, callErr error) {
+	trackID := as.transcriptTrack
+	if trackID == "" {
+		trackID = untrackedTranscript
+	}
+	if phase == "" {
+		phase = "agent"
+	}
+
+	var entry strings.Builder
+	entry.WriteString(fmt.Sprintf("===== %s · provider %s =====\n", time.Now().Format(time.RFC3339), provider))
+	for _, message := range messages {
+		entry.WriteString(fmt.Sprintf("--- PROMPT (%s) ---\n%s\n", message.Role, message.Content))
+	}
+	switch {
+	case callErr != nil:
+		entry.WriteString(fmt.Sprintf("--- ERROR ---\n%v\n", callErr))
+	case response != nil && len(response.Choices) > 0:
+		entry.WriteString(fmt.Sprintf("--- RESPONSE ---\n%s\n", response.Choices[0].Message.Content))
+	default:
+		entry.WriteString("--- RESPONSE ---\n(empty)\n")
+	}
+	entry.WriteString("\n")
+	masked, _ := secrets.Redact(entry.String())
+
+	path := TranscriptPath(as.projectRoot, trackID, phase)
+
+	transcriptMu.Lock()
+	defer transcriptMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fmt.Printf("⚠️  Failed to write debug transcript: %v\n", err)
+		return
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to write debug transcript: %v\n", err)
+		return
+	}
+	defer file.Close()
+	if _, err := file.WriteString(masked); err != nil {
+		fmt.Printf("⚠️  Failed to write debug transcript: %v\n", err)
+		return
+	}
+
+	if !as.transcriptsAnnounced[path] {
+		as.transcriptsAnnounced[path] = true
+		fmt.Printf("📝 Debug transcript: %s\n", path)
+	}
+}
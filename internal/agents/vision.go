@@ -0,0 +1,216 @@
+package agents
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// minVisionOverlap is the share of a document's key terms that should also
+// appear in the vision before the document is considered aligned with it
+const minVisionOverlap = 0.1
+
+var (
+	wordPattern        = regexp.MustCompile(`[a-z][a-z0-9-]+`)
+	nonGoalHeading     = regexp.MustCompile(`(?i)^#+\s*(non[- ]?goals?|out of scope|anti[- ]?goals?|what we will not|not doing)`)
+	negatedGoalPattern = regexp.MustCompile(`(?i)^\s*(?:[-*]\s*)?(?:we\s+)?(?:will\s+not|won't|never|no|not|avoid|don't|do\s+not)\b\s+(.+)$`)
+)
+
+// visionStopWords are left out of keyword comparisons
+var visionStopWords = map[string]bool{
+	"about": true, "after": true, "also": true, "and": true, "are": true, "based": true, "be": true,
+	"been": true, "before": true, "build": true, "but": true, "can": true, "could": true, "each": true,
+	"for": true, "from": true, "have": true, "into": true, "its": true, "more": true, "most": true,
+	"must": true, "need": true, "needs": true, "not": true, "only": true, "other": true, "our": true,
+	"over": true, "should": true, "such": true, "than": true, "that": true, "the": true, "their": true,
+	"them": true, "then": true, "there": true, "these": true, "they": true, "this": true, "those": true,
+	"through": true, "use": true, "used": true, "user": true, "users": true, "very": true, "want": true,
+	"well": true, "what": true, "when": true, "where": true, "which": true, "while": true, "will": true,
+	"with": true, "within": true, "without": true, "would": true, "your": true, "system": true,
+	"feature": true, "features": true, "project": true, "product": true, "support": true, "make": true,
+	"provide": true, "allow": true, "allows": true, "shall": true, "any": true,
+	"all": true, "has": true, "was": true, "were": true, "you": true, "via": true, "etc": true,
+}
+
+// VisionPath returns the file holding the project's vision
+func VisionPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "vision.md")
+}
+
+// LoadVision returns the recorded project vision, or "" if there is none
+func LoadVision(projectRoot string) (string, error) {
+	data, err := os.ReadFile(VisionPath(projectRoot))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read vision: %w", err)
+	}
+	return string(data), nil
+}
+
+// SaveVision records the project vision
+func SaveVision(projectRoot, content string) error {
+	if err := os.MkdirAll(filepath.Join(projectRoot, ".sdd"), 0755); err != nil {
+		return fmt.Errorf("failed to create .sdd directory: %w", err)
+	}
+	return os.WriteFile(VisionPath(projectRoot), []byte(strings.TrimSpace(content)+"\n"), 0644)
+}
+
+// CheckVisionAlignment compares a document, usually a PRD, with the project
+// vision and returns a note for each sign that it diverges: mentioning
+// something the vision rules out, or sharing few of its key terms
+func CheckVisionAlignment(vision, document string) []string {
+	if strings.TrimSpace(vision) == "" || strings.TrimSpace(document) == "" {
+		return nil
+	}
+
+	var notes []string
+	docWords := keywordSet(document)
+
+	for _, nonGoal := range visionNonGoals(vision) {
+		terms := keywords(nonGoal)
+		if len(terms) == 0 {
+			continue
+		}
+		matched := true
+		for _, term := range terms {
+			if !docWords[term] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			notes = append(notes, fmt.Sprintf("may conflict with project vision: mentions %q, which the vision rules out (%q)",
+				strings.Join(terms, " "), nonGoal))
+		}
+	}
+
+	visionWords := keywordSet(vision)
+	docTerms := topKeywords(document, 25)
+	if len(docTerms) >= 5 {
+		shared := 0
+		for _, term := range docTerms {
+			if visionWords[term] {
+				shared++
+			}
+		}
+		if overlap := float64(shared) / float64(len(docTerms)); overlap < minVisionOverlap {
+			notes = append(notes, fmt.Sprintf("may conflict with project vision: only %d of its %d key terms appear in the vision (%.0f%% overlap)",
+				shared, len(docTerms), overlap*100))
+		}
+	}
+
+	return notes
+}
+
+// visionNonGoals returns the things the vision rules out: items under a
+// non-goals style heading and negated statements such as "No mobile app"
+func visionNonGoals(vision string) []string {
+	var nonGoals []string
+	inNonGoals := false
+
+	for _, line := range strings.Split(vision, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			inNonGoals = nonGoalHeading.MatchString(trimmed)
+			continue
+		}
+
+		item := strings.TrimSpace(strings.TrimLeft(trimmed, "-*0123456789. "))
+		if item == "" {
+			continue
+		}
+
+		if match := negatedGoalPattern.FindStringSubmatch(trimmed); match != nil {
+			nonGoals = append(nonGoals, strings.TrimRight(match[1], ". "))
+		} else if inNonGoals {
+			nonGoals = append(nonGoals, strings.TrimRight(item, ". "))
+		}
+	}
+
+	return nonGoals
+}
+
+// keywords returns the significant words of text, singularized, in order
+func keywords(text string) []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, word := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		word = singular(word)
+		if len(word) < 3 || visionStopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	return words
+}
+
+func keywordSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range keywords(text) {
+		set[word] = true
+	}
+	return set
+}
+
+// topKeywords returns up to n of the most frequent significant words
+func topKeywords(text string, n int) []string {
+	counts := make(map[string]int)
+	var order []string
+	for _, word := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		word = singular(word)
+		if len(word) < 4 || visionStopWords[word] {
+			continue
+		}
+		if counts[word] == 0 {
+			order = append(order, word)
+		}
+		counts[word]++
+	}
+
+	// Stable by first appearance among equally frequent words
+	for i := 1; i < len(order); i++ {
+		for j := i; j > 0 && counts[order[j]] > counts[order[j-1]]; j-- {
+			order[j], order[j-1] = order[j-1], order[j]
+		}
+	}
+	if len(order) > n {
+		order = order[:n]
+	}
+	return order
+}
+
+// singular strips a plural "s" so "apps" and "app" compare equal
+func singular(word string) string {
+	if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// VisionContext returns the recorded project vision as guiding context for
+// the specify and plan phases, or "" when none is recorded
+func (as *AgentService) VisionContext() string {
+	vision, err := LoadVision(as.projectRoot)
+	if err != nil || strings.TrimSpace(vision) == "" {
+		return ""
+	}
+	masked, _ := as.RedactContent(vision)
+	return fmt.Sprintf("\n\n## PROJECT VISION (GUIDING CONTEXT)\nStay consistent with this vision and call out any requirement that departs from it.\n%s\n", masked)
+}
+
+// WarnVisionDivergence prints a note for each sign that a generated
+// document diverges from the project vision
+func (as *AgentService) WarnVisionDivergence(name, document string) {
+	vision, err := LoadVision(as.projectRoot)
+	if err != nil {
+		return
+	}
+	for _, note := range CheckVisionAlignment(vision, document) {
+		fmt.Printf("⚠️  %s %s\n", name, note)
+	}
+}
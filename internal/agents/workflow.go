@@ -0,0 +1,264 @@
+package agents
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// DefaultScale is the workflow profile used when a project has not chosen one
+const DefaultScale = "standard"
+
+// WorkflowProfile is a named set of phases the orchestrator runs, from a
+// quick fix to an enterprise feature
+type WorkflowProfile struct {
+	Name        string            `yaml:"-"`
+	Description string            `yaml:"description,omitempty"`
+	Phases      []string          `yaml:"phases"`
+	Gates       map[string]string `yaml:"gates,omitempty"`     // phase -> artifact that must be approved before it
+	Artifacts   map[string]string `yaml:"artifacts,omitempty"` // phase -> file name of the artifact it produces
+}
+
+// workflowFile is the on-disk form of .sdd/workflow.yaml
+type workflowFile struct {
+	Scale    string                      `yaml:"scale"`
+	Profiles map[string]*WorkflowProfile `yaml:"profiles,omitempty"`
+}
+
+// DefaultWorkflowProfiles returns the built-in workflow profiles
+func DefaultWorkflowProfiles() map[string]*WorkflowProfile {
+	return map[string]*WorkflowProfile{
+		"quick": {
+			Name:        "quick",
+			Description: "Bug fixes and small changes: straight from spec to code",
+			Phases:      []string{"discover", "specify", "execute"},
+		},
+		"standard": {
+			Name:        "standard",
+			Description: "Products and platforms: the full gated workflow",
+			Phases:      []string{"discover", "specify", "design", "audit", "task", "execute", "validate", "evolve"},
+		},
+		"enterprise": {
+			Name:        "enterprise",
+			Description: "Compliance-heavy systems: the full workflow with extra audit and review gates",
+			Phases:      []string{"discover", "specify", "design", "audit", "task", "execute", "review", "validate", "evolve"},
+			Gates: map[string]string{
+				"task":     "3_security_report.md",
+				"validate": "4_code_review.md",
+			},
+		},
+	}
+}
+
+// WorkflowPath returns the file recording the project's workflow profile
+func WorkflowPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "workflow.yaml")
+}
+
+// loadWorkflowFile reads .sdd/workflow.yaml, merging project profiles over
+// the built-in ones
+func loadWorkflowFile(projectRoot string) (*workflowFile, map[string]*WorkflowProfile, error) {
+	file := &workflowFile{}
+	profiles := DefaultWorkflowProfiles()
+
+	data, err := os.ReadFile(WorkflowPath(projectRoot))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read workflow config: %w", err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, file); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse workflow config: %w", err)
+		}
+	}
+
+	for name, profile := range file.Profiles {
+		if profile == nil || len(profile.Phases) == 0 {
+			return nil, nil, fmt.Errorf("workflow profile '%s' has no phases", name)
+		}
+		for _, phase := range profile.Phases {
+			if !slices.Contains(WorkflowPhases, phase) {
+				return nil, nil, fmt.Errorf("workflow profile '%s': %w", name, UnknownPhaseError(phase))
+			}
+		}
+		if err := validateArtifactNames(profile.Artifacts); err != nil {
+			return nil, nil, fmt.Errorf("workflow profile '%s': %w", name, err)
+		}
+		profile.Name = name
+		profiles[name] = profile
+	}
+
+	return file, profiles, nil
+}
+
+// WorkflowProfiles returns the built-in profiles together with any defined
+// in the project's workflow config
+func WorkflowProfiles(projectRoot string) (map[string]*WorkflowProfile, error) {
+	_, profiles, err := loadWorkflowFile(projectRoot)
+	return profiles, err
+}
+
+// LoadWorkflow returns the workflow profile selected for the project
+func LoadWorkflow(projectRoot string) (*WorkflowProfile, error) {
+	file, profiles, err := loadWorkflowFile(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := file.Scale
+	if scale == "" {
+		scale = DefaultScale
+	}
+
+	profile, ok := profiles[scale]
+	if !ok {
+		return nil, fmt.Errorf("unknown workflow scale '%s' (available: %s)", scale, strings.Join(profileNames(profiles), ", "))
+	}
+	return profile, nil
+}
+
+// SetWorkflowScale selects the workflow profile the orchestrator follows
+func SetWorkflowScale(projectRoot, scale string) error {
+	file, profiles, err := loadWorkflowFile(projectRoot)
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles[scale]; !ok {
+		return fmt.Errorf("unknown workflow scale '%s' (available: %s)", scale, strings.Join(profileNames(profiles), ", "))
+	}
+
+	file.Scale = scale
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow config: %w", err)
+	}
+
+	return os.WriteFile(WorkflowPath(projectRoot), data, 0644)
+}
+
+// Includes reports whether phase is part of the workflow
+func (wp *WorkflowProfile) Includes(phase string) bool {
+	return slices.Contains(wp.Phases, phase)
+}
+
+// GateFor returns the artifact that must be approved before phase runs.
+// defaultGate is the gate of the full workflow; when the phase producing it
+// is skipped by this profile, the output of the nearest earlier phase of the
+// profile gates the phase instead. Gates follow the profile's artifact names.
+func (wp *WorkflowProfile) GateFor(phase, defaultGate string) string {
+	if gate, ok := wp.Gates[phase]; ok {
+		return wp.renamed(gate)
+	}
+	if defaultGate == "" {
+		return ""
+	}
+	if producer := producingPhase(defaultGate); producer == "" || wp.Includes(producer) {
+		return wp.renamed(defaultGate)
+	}
+
+	if previous := wp.Previous(phase); previous != "" {
+		return wp.ArtifactFor(previous)
+	}
+	return ""
+}
+
+// Previous returns the phase the workflow runs before phase, "" for its
+// first phase or one it does not include. A nil profile is the full
+// workflow.
+func (wp *WorkflowProfile) Previous(phase string) string {
+	phases := WorkflowPhases
+	if wp != nil {
+		phases = wp.Phases
+	}
+	if index := slices.Index(phases, phase); index > 0 {
+		return phases[index-1]
+	}
+	return ""
+}
+
+// ArtifactFor returns the file name of the artifact phase produces, the
+// profile's own name for it or the default one
+func (wp *WorkflowProfile) ArtifactFor(phase string) string {
+	if wp != nil {
+		if name, ok := wp.Artifacts[phase]; ok {
+			return name
+		}
+	}
+	_, _, curr, _ := defaultPhaseConfig(phase)
+	return curr
+}
+
+// PhaseArtifacts returns the artifact that gates phase and the one it
+// produces, under the profile's gates and names or the defaults when wp is
+// nil
+func (wp *WorkflowProfile) PhaseArtifacts(phase string) (gate, artifact string) {
+	_, gate, artifact, _ = defaultPhaseConfig(phase)
+	if wp != nil {
+		gate = wp.GateFor(phase, gate)
+		artifact = wp.ArtifactFor(phase)
+	}
+	return gate, artifact
+}
+
+// ProducerOf returns the phase whose artifact is named name, "" when no
+// phase produces it
+func (wp *WorkflowProfile) ProducerOf(name string) string {
+	for _, phase := range WorkflowPhases {
+		if wp.ArtifactFor(phase) == name {
+			return phase
+		}
+	}
+	return ""
+}
+
+// renamed maps a default artifact name to the profile's name for it
+func (wp *WorkflowProfile) renamed(defaultName string) string {
+	if producer := producingPhase(defaultName); producer != "" {
+		return wp.ArtifactFor(producer)
+	}
+	return defaultName
+}
+
+// ArtifactName returns the file name of the artifact phase produces in the
+// project's workflow, the default name when the workflow cannot be loaded
+func ArtifactName(projectRoot, phase string) string {
+	workflow, _ := LoadWorkflow(projectRoot)
+	return workflow.ArtifactFor(phase)
+}
+
+// validateArtifactNames checks a profile's artifact names are distinct
+// files of the track directory, for phases producing a file
+func validateArtifactNames(artifacts map[string]string) error {
+	seen := make(map[string]string)
+	for _, phase := range slices.Sorted(maps.Keys(artifacts)) {
+		name := artifacts[phase]
+		if !slices.Contains(WorkflowPhases, phase) {
+			return fmt.Errorf("artifacts: %w", UnknownPhaseError(phase))
+		}
+		if _, _, curr, _ := defaultPhaseConfig(phase); !isFileArtifact(curr) {
+			return fmt.Errorf("artifacts: the %s phase does not produce a named artifact", phase)
+		}
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("artifacts: '%s' for the %s phase must be a file name in the track directory", name, phase)
+		}
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("artifacts: the %s and %s phases both produce '%s'", other, phase, name)
+		}
+		seen[name] = phase
+	}
+	return nil
+}
+
+func profileNames(profiles map[string]*WorkflowProfile) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -67,6 +67,7 @@ type CodeAnalyzer struct {
 	RootPath string
 	Metrics  CodeMetrics
 	Issues   []QualityIssue
+	Filter   *PathFilter // nil analyzes every source file
 }
 
 // NewCodeAnalyzer creates a new code analyzer
@@ -81,7 +82,7 @@ func NewCodeAnalyzer(rootPath string) *CodeAnalyzer {
 // Analyze performs comprehensive code quality analysis
 func (ca *CodeAnalyzer) Analyze() (*QualityReport, error) {
 	// Walk through all source files
-	err := filepath.Walk(ca.RootPath, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(ca.RootPath, ca.Filter.Filtered(ca.RootPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -92,7 +93,7 @@ func (ca *CodeAnalyzer) Analyze() (*QualityReport, error) {
 		}
 
 		return ca.analyzeFile(path)
-	})
+	}))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze codebase: %w", err)
@@ -0,0 +1,187 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PathFilter narrows analysis to part of a project. A path is analyzed when
+// it matches an include glob, or there are none, and matches no exclude
+// glob. Globs are relative to the project root; "*" stays within a path
+// segment, "**" spans segments, and a glob without a "/" matches any single
+// segment, e.g. "testdata" or "*_gen.go". A nil filter selects everything.
+type PathFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// NewPathFilter returns a filter for the given globs, or nil when there are
+// none
+func NewPathFilter(include, exclude []string) *PathFilter {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil
+	}
+	return &PathFilter{Include: include, Exclude: exclude}
+}
+
+// Matches reports whether a project-relative file path is selected
+func (pf *PathFilter) Matches(path string) bool {
+	if pf == nil {
+		return true
+	}
+	path = cleanRelPath(path)
+
+	for _, pattern := range pf.Exclude {
+		if globMatchesPath(pattern, path) {
+			return false
+		}
+	}
+	if len(pf.Include) == 0 {
+		return true
+	}
+	for _, pattern := range pf.Include {
+		if globMatchesPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipsDir reports whether nothing under a project-relative directory can
+// be selected, so a walk need not enter it
+func (pf *PathFilter) SkipsDir(dir string) bool {
+	if pf == nil {
+		return false
+	}
+	dir = cleanRelPath(dir)
+	if dir == "." || dir == "" {
+		return false
+	}
+
+	for _, pattern := range pf.Exclude {
+		if globMatchesPath(pattern, dir) {
+			return true
+		}
+	}
+	if len(pf.Include) == 0 {
+		return false
+	}
+	for _, pattern := range pf.Include {
+		// Patterns that can match at any depth never rule a directory out
+		prefix := literalPrefix(pattern)
+		if prefix == "" || globMatchesPath(pattern, dir) ||
+			prefix == dir || strings.HasPrefix(prefix, dir+"/") || strings.HasPrefix(dir, prefix+"/") {
+			return false
+		}
+	}
+	return true
+}
+
+// Filtered wraps a walk function so it only sees the files the filter
+// selects, and does not descend into directories it rules out
+func (pf *PathFilter) Filtered(root string, fn filepath.WalkFunc) filepath.WalkFunc {
+	if pf == nil {
+		return fn
+	}
+	return func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == root {
+			return fn(path, info, err)
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return fn(path, info, err)
+		}
+		if info.IsDir() {
+			if pf.SkipsDir(rel) {
+				return filepath.SkipDir
+			}
+		} else if !pf.Matches(rel) {
+			return nil
+		}
+		return fn(path, info, err)
+	}
+}
+
+// globMatchesPath matches a glob against a path, or against each of its
+// segments when the glob has no "/". A glob also matches everything under
+// a directory it matches.
+func globMatchesPath(pattern, path string) bool {
+	pattern = strings.TrimSuffix(cleanRelPath(pattern), "/")
+	if !strings.Contains(pattern, "/") {
+		for _, segment := range strings.Split(path, "/") {
+			if MatchGlob(pattern, segment) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for candidate := path; ; {
+		if MatchGlob(pattern, candidate) {
+			return true
+		}
+		parent := filepath.ToSlash(filepath.Dir(candidate))
+		if parent == "." || parent == candidate {
+			return false
+		}
+		candidate = parent
+	}
+}
+
+// literalPrefix returns the directories of a glob before its first
+// wildcard, "" when the glob can match at any depth
+func literalPrefix(pattern string) string {
+	pattern = strings.TrimSuffix(cleanRelPath(pattern), "/")
+	if !strings.Contains(pattern, "/") {
+		return ""
+	}
+
+	var literal []string
+	for _, segment := range strings.Split(pattern, "/") {
+		if strings.ContainsAny(segment, "*?") {
+			break
+		}
+		literal = append(literal, segment)
+	}
+	return strings.Join(literal, "/")
+}
+
+// cleanRelPath normalizes a relative path to slash form without a leading
+// "./"
+func cleanRelPath(path string) string {
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./")
+}
+
+// MatchGlob matches a glob where "*" and "?" stay within a path segment and
+// "**" spans segments
+func MatchGlob(pattern, name string) bool {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					// "**/" also matches no directory at all
+					i++
+					expr.WriteString("(?:.*/)?")
+				} else {
+					expr.WriteString(".*")
+				}
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	matched, err := regexp.MatchString(expr.String(), name)
+	return err == nil && matched
+}
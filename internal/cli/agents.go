@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ultimate-sdd-framework/internal/agents"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+func newAgentsCreateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create <id>",
+		Short: "Scaffold a custom agent role file",
+		Long: `Create a custom agent in .sdd/role/<id>.md.
+
+You are asked for the agent's name, role, expertise, focus, tone and
+category, and for instructions for each workflow phase it should handle. Phases given
+instructions are served by the new agent instead of the default role.
+
+Examples:
+  viki agents create data-steward`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			if err := agents.ValidateAgentID(".", id); err != nil {
+				return err
+			}
+
+			reader := bufio.NewReader(os.Stdin)
+			ask := func(label, defaultValue string) string {
+				if defaultValue != "" {
+					fmt.Printf("%s (%s): ", label, defaultValue)
+				} else {
+					fmt.Printf("%s: ", label)
+				}
+				input, _ := reader.ReadString('\n')
+				if input = strings.TrimSpace(input); input == "" {
+					return defaultValue
+				}
+				return input
+			}
+
+			spec := agents.RoleSpec{ID: id}
+			spec.Name = ask("Name", id)
+			spec.Role = ask("Role", "")
+			spec.Expertise = ask("Expertise", "")
+			spec.Focus = ask("Focus", "")
+			spec.Personality = ask("Personality", "")
+			spec.Tone = ask("Tone", "")
+			spec.Category = ask("Category ("+strings.Join(agents.AgentCategories, ", ")+")", "")
+			if temp := ask("Temperature (0-2, blank for default)", ""); temp != "" {
+				value, err := strconv.ParseFloat(temp, 64)
+				if err != nil || value < 0 || value > 2 {
+					return fmt.Errorf("invalid temperature '%s' (expected a number from 0 to 2)", temp)
+				}
+				spec.Temperature = &value
+			}
+
+			fmt.Println()
+			fmt.Println("Phase instructions (leave blank to skip a phase):")
+			spec.Phases = make(map[string]string)
+			for _, phase := range agents.WorkflowPhases {
+				if prompt := ask("  "+phase, ""); prompt != "" {
+					spec.Phases[phase] = prompt
+				}
+			}
+
+			path, err := agents.CreateRoleFile(".", spec)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println()
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Created agent %s at %s", id, path)))
+			if len(spec.Phases) > 0 {
+				phases := make([]string, 0, len(spec.Phases))
+				for phase := range spec.Phases {
+					phases = append(phases, phase)
+				}
+				sort.Strings(phases)
+				fmt.Printf("  Handles: %s\n", strings.Join(phases, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+func newAgentsListCmd() *cobra.Command {
+	var category string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List agents, optionally filtered by category",
+		Long: `List the built-in agents with their expertise and focus.
+
+Categories: core, product, engineering, quality, operations, creative
+
+Examples:
+  viki agents list
+  viki agents list --category engineering`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			categories := agents.AgentCategories
+			if category != "" {
+				category = strings.ToLower(category)
+				if !slices.Contains(agents.AgentCategories, category) {
+					return fmt.Errorf("unknown category '%s' (valid: %s)", category, strings.Join(agents.AgentCategories, ", "))
+				}
+				categories = []string{category}
+			}
+
+			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+			categoryStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("220"))
+			agentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+			descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("249"))
+
+			fmt.Println(titleStyle.Render("👥 Agents"))
+
+			for _, c := range categories {
+				fmt.Println()
+				fmt.Println(categoryStyle.Render(fmt.Sprintf("### %s", strings.Title(c))))
+
+				for _, agent := range agents.GetAgentsByCategory(c) {
+					fmt.Printf("  %s (%s)\n", agentStyle.Render(agent.Name), agent.ID)
+					fmt.Printf("    Expertise: %s\n", descStyle.Render(strings.Join(agent.Expertise, ", ")))
+					fmt.Printf("    Focus:     %s\n", descStyle.Render(strings.Join(agent.Focus, ", ")))
+				}
+			}
+
+			fmt.Println()
+			fmt.Println(descStyle.Render("Use 'viki agents show <id>' for full detail"))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&category, "category", "c", "", "Only list agents in this category")
+
+	return cmd
+}
+
+func newAgentsShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show full detail for an agent",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			agent := agents.GetAgentByID(args[0])
+			if agent == nil {
+				return fmt.Errorf("agent '%s' not found; run 'viki agents list' to see available agents", args[0])
+			}
+
+			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+			labelStyle := lipgloss.NewStyle().Bold(true)
+			descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("249"))
+
+			categories := append([]string{agent.Category}, agent.AlsoIn...)
+
+			fmt.Println(titleStyle.Render(fmt.Sprintf("%s (%s)", agent.Name, agent.ID)))
+			fmt.Println(strings.Repeat("─", 50))
+			fmt.Printf("%s %s\n", labelStyle.Render("Role:       "), agent.Role)
+			fmt.Printf("%s %s\n", labelStyle.Render("Category:   "), strings.Join(categories, ", "))
+			fmt.Printf("%s %s\n", labelStyle.Render("Expertise:  "), strings.Join(agent.Expertise, ", "))
+			fmt.Printf("%s %s\n", labelStyle.Render("Focus:      "), strings.Join(agent.Focus, ", "))
+			fmt.Printf("%s %s\n", labelStyle.Render("Personality:"), agent.Personality)
+			fmt.Printf("%s %s\n", labelStyle.Render("Tone:       "), agent.Tone)
+			fmt.Println()
+			fmt.Println(labelStyle.Render("Prompt:"))
+			fmt.Println(descStyle.Render(agent.Prompt))
+			return nil
+		},
+	}
+}
+
+func newAgentsAssignCmd() *cobra.Command {
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "assign <phase> [id]",
+		Short: "Choose which agent serves a workflow phase",
+		Long: `Assign a built-in agent or project role to a workflow phase.
+
+The assignment is saved in .sdd/agents.yaml and takes precedence over the
+default role and any role file declaring the phase. A warning suggests a
+better-fit agent when the chosen one's category rarely suits the phase.
+
+Examples:
+  viki agents assign design architect
+  viki agents assign design --clear`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			phase := args[0]
+			if !slices.Contains(agents.WorkflowPhases, phase) {
+				return agents.UnknownPhaseError(phase)
+			}
+
+			if clear {
+				if err := agents.AssignPhase(".", phase, ""); err != nil {
+					return err
+				}
+				fmt.Println(successStyle.Render(fmt.Sprintf("✓ The %s phase uses its default agent again", phase)))
+				return nil
+			}
+
+			if len(args) < 2 {
+				return fmt.Errorf("specify an agent id, or --clear to restore the default")
+			}
+			id := args[1]
+
+			am := agents.NewAgentManager(".")
+			if err := am.LoadAgents(); err != nil {
+				return err
+			}
+			if _, err := am.GetAgent(id); err != nil && agents.GetAgentByID(id) == nil {
+				return fmt.Errorf("agent '%s' not found; run 'viki agents list' to see available agents", id)
+			}
+
+			if err := agents.AssignPhase(".", phase, id); err != nil {
+				return err
+			}
+
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ The %s phase is now handled by %s", phase, id)))
+			if warning := am.CheckPhaseFit(id, phase); warning != "" {
+				fmt.Println(warning)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&clear, "clear", false, "Restore the default agent for the phase")
+
+	return cmd
+}
+
+// printProjectRoles lists the role files loaded from .sdd/role and the
+// phases each one serves
+func printProjectRoles() {
+	am := agents.NewAgentManager(".")
+	if err := am.LoadAgents(); err != nil {
+		return
+	}
+
+	names := am.ListAgents()
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	categoryStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("220"))
+	agentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	descStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("249"))
+
+	fmt.Println()
+	fmt.Println(categoryStyle.Render("### Project Roles (.sdd/role)"))
+
+	for _, name := range names {
+		agent, _ := am.GetAgent(name)
+
+		var phases []string
+		for _, phase := range agents.WorkflowPhases {
+			if custom, ok := am.CustomAgentForPhase(phase); ok && custom == name {
+				phases = append(phases, phase)
+			}
+		}
+
+		label := name
+		if agent.Name != "" && agent.Name != name {
+			label = fmt.Sprintf("%s (%s)", agent.Name, name)
+		}
+		fmt.Printf("  %s\n", agentStyle.Render(label))
+		if len(phases) > 0 {
+			fmt.Printf("    Phases: %s\n", descStyle.Render(strings.Join(phases, ", ")))
+		}
+	}
+}
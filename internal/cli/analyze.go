@@ -3,12 +3,19 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
 	"ultimate-sdd-framework/internal/analysis"
+	"ultimate-sdd-framework/internal/gates"
 )
 
 func NewAnalyzeCmd() *cobra.Command {
+	var analyzeInclude, analyzeExclude []string
+
 	cmd := &cobra.Command{
 		Use:   "analyze",
 		Short: "Analyze codebase quality and generate reports",
@@ -19,7 +26,12 @@ func NewAnalyzeCmd() *cobra.Command {
 - Maintainability assessment
 - Test coverage evaluation
 
-Generates detailed reports with actionable recommendations.`,
+Generates detailed reports with actionable recommendations.
+
+--include and --exclude (repeatable globs relative to the project root)
+limit the analysis to part of the tree.
+
+Use 'viki analyze trace' to cross-check a track's spec, plan and tasks.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectRoot := "."
 
@@ -27,6 +39,7 @@ Generates detailed reports with actionable recommendations.`,
 
 			// Create analyzer
 			analyzer := analysis.NewCodeAnalyzer(projectRoot)
+			analyzer.Filter = analysis.NewPathFilter(analyzeInclude, analyzeExclude)
 
 			// Perform analysis
 			report, err := analyzer.Analyze()
@@ -52,9 +65,89 @@ Generates detailed reports with actionable recommendations.`,
 		},
 	}
 
+	cmd.Flags().StringArrayVar(&analyzeInclude, "include", nil, "Only analyze paths matching this glob (repeatable)")
+	cmd.Flags().StringArrayVar(&analyzeExclude, "exclude", nil, "Skip paths matching this glob (repeatable)")
+
+	cmd.AddCommand(newAnalyzeTraceSubCmd())
+
 	return cmd
 }
 
+func newAnalyzeTraceSubCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "trace [trackID]",
+		Short: "Cross-check a track's requirements, decisions and tasks",
+		Long: `Parse the requirements of the track's PRD (1_prd.md), the decisions of its
+architecture (2_architecture.md) and the tasks of its gsd.json, then report
+requirements no task implements and tasks no requirement or decision backs.
+
+A task covers a requirement when it cites its ID (e.g. FR-2, in its title or a
+"requirements" list) or shares its key terms. The coverage matrix is saved to
+.sdd/tracks/<trackID>/traceability.md.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trackID := "feature-implementation"
+			if len(args) > 0 {
+				trackID = args[0]
+			} else if state, err := gates.NewStateManager(".").LoadState(); err == nil && state.Metadata != nil {
+				if t, ok := state.Metadata["current_track"].(string); ok && t != "" {
+					trackID = t
+				}
+			}
+
+			trace, err := agents.AnalyzeTraceability(".", trackID)
+			if err != nil {
+				return fmt.Errorf("traceability analysis failed: %w", err)
+			}
+
+			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+			okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+			warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+			errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+			dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+			fmt.Println(titleStyle.Render(fmt.Sprintf("🔗 Traceability: %s", trackID)))
+			fmt.Println(dimStyle.Render(fmt.Sprintf("%d requirements, %d decisions, %d tasks",
+				len(trace.Requirements), len(trace.Decisions), len(trace.Tasks))))
+			fmt.Println()
+
+			for _, requirement := range trace.Requirements {
+				tasks := trace.Coverage[requirement.ID]
+				if len(tasks) == 0 {
+					fmt.Printf("  %s %s\n", errorStyle.Render("✗ "+requirement.ID), requirement.Text)
+					continue
+				}
+				fmt.Printf("  %s %s %s\n", okStyle.Render("✓ "+requirement.ID), requirement.Text,
+					dimStyle.Render("→ "+strings.Join(tasks, ", ")))
+			}
+
+			missing, orphans := trace.UnimplementedRequirements(), trace.OrphanTasks()
+			if len(missing) > 0 || len(orphans) > 0 {
+				fmt.Println()
+			}
+			for _, requirement := range missing {
+				fmt.Println(errorStyle.Render(fmt.Sprintf("❌ unimplemented requirement %s: %s", requirement.ID, requirement.Text)))
+			}
+			for _, task := range orphans {
+				fmt.Println(warnStyle.Render(fmt.Sprintf("⚠️  orphan task %s: %s (no backing requirement or decision)", task.ID, task.Text)))
+			}
+
+			report := fmt.Sprintf("# Traceability: %s\n\n%s", trackID, trace.Matrix())
+			reportPath := filepath.Join(gates.TracksDir("."), trackID, "traceability.md")
+			if err := os.WriteFile(reportPath, []byte(report), 0644); err != nil {
+				fmt.Printf("Warning: Failed to save report to file: %v\n", err)
+			} else {
+				fmt.Printf("\n📄 Coverage matrix saved to: %s\n", reportPath)
+			}
+
+			if len(missing) == 0 && len(orphans) == 0 {
+				fmt.Println(okStyle.Render("✅ Every requirement has a task and every task has a requirement"))
+			}
+			return nil
+		},
+	}
+}
+
 func showAnalysisRecommendations(report *analysis.QualityReport) {
 	fmt.Println("\n🎯 Recommendations:")
 
@@ -7,21 +7,70 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/config"
 	"ultimate-sdd-framework/internal/gates"
+	"ultimate-sdd-framework/internal/tui"
 )
 
 func NewApproveCmd() *cobra.Command {
-	var comments string
+	var (
+		comments    string
+		interactive bool
+		approver    string
+	)
 
 	cmd := &cobra.Command{
-		Use:   "approve",
+		Use:   "approve [trackID artifact]",
 		Short: "Approve the current phase to proceed",
 		Long: `Approve the current phase for transition to the next phase.
 
 Some phases require explicit approval before proceeding:
 - Plan phase must be approved before creating tasks
-- Review phase must be approved to complete the feature`,
+- Review phase must be approved to complete the feature
+
+Track artifacts can skip manual approval through a gate policy in
+.sdd/gates.yaml that auto-approves chosen phases when their artifact passes
+its checks, e.g. a PASS security verdict or a minimum validation score:
+
+  auto_approve:
+    discover: {}
+    audit:
+      verdict: PASS
+    validate:
+      min_score: 80
+
+Use --interactive to review every pending track artifact in a terminal UI
+and approve or reject each one with a keypress, or name a track artifact to
+approve it directly.
+
+Gates can require sign-off from several people. List the phases and how
+many distinct approvers their artifacts need in .sdd/gates.yaml; such an
+artifact stays blocked until that many users have approved it with --as:
+
+  approvers:
+    design: 2
+
+  viki approve user-auth 2_architecture.md --as alice
+  viki approve user-auth 2_architecture.md --as bob`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 && len(args) != 2 {
+				return fmt.Errorf("expected a track ID and artifact, or no arguments")
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if approver == "" {
+				approver = config.ResolveUser(".")
+			}
+
+			if interactive {
+				return tui.RunApproveTUI(".", approver)
+			}
+			if len(args) == 2 {
+				return approveArtifact(args[0], args[1], approver)
+			}
+
 			// Check project state
 			stateMgr := gates.NewStateManager(".")
 			state, err := stateMgr.LoadState()
@@ -49,12 +98,6 @@ Some phases require explicit approval before proceeding:
 				}
 			}
 
-			// Get approver name (in real implementation, this would be from git config or auth)
-			approver := "developer" // Default for demo
-			if user := os.Getenv("USER"); user != "" {
-				approver = user
-			}
-
 			// Approve the phase
 			if err := stateMgr.ApprovePhase(approver, comments); err != nil {
 				return fmt.Errorf("failed to approve phase: %w", err)
@@ -76,6 +119,25 @@ Some phases require explicit approval before proceeding:
 	}
 
 	cmd.Flags().StringVarP(&comments, "comments", "c", "", "Approval comments")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Review and approve pending artifacts interactively")
+	cmd.Flags().StringVar(&approver, "as", "", "Approve as this user (default: $VIKI_USER, the configured user, git user.email or $USER)")
 
 	return cmd
-}
\ No newline at end of file
+}
+
+// approveArtifact records an approval of a track artifact and reports
+// whether its gate is open yet
+func approveArtifact(trackID, name, approver string) error {
+	approvals, required, err := agents.ApproveArtifact(".", trackID, name, approver)
+	if err != nil {
+		return fmt.Errorf("failed to approve artifact: %w", err)
+	}
+
+	if approvals < required {
+		fmt.Printf("✅ %s/%s approved by %s (%d of %d approvals)\n", trackID, name, approver, approvals, required)
+		fmt.Printf("🚦 The gate stays blocked until %d more user(s) approve\n", required-approvals)
+		return nil
+	}
+	fmt.Printf("✅ %s/%s → %s (approved by %s)\n", trackID, name, gates.ArtifactApproved, approver)
+	return nil
+}
@@ -154,10 +154,20 @@ Viki includes 21+ specialized agents covering:
 - Engineering: DevOps, Security, Tech Lead, Data Architect, API Designer
 - Quality: Test Automation, Performance
 - Operations: SRE, Documentation
-- Creative: Innovator, Reviewer, Debugger`,
+- Creative: Innovator, Reviewer, Debugger
+
+Browse by category with 'viki agents list --category <name>' and see an
+agent's full profile with 'viki agents show <id>'. Project roles in
+.sdd/role are listed too; scaffold a new one with 'viki agents create <id>'
+and pick the agent for a phase with 'viki agents assign <phase> <id>'.`,
 		Run: runAgentList,
 	}
 
+	cmd.AddCommand(newAgentsListCmd())
+	cmd.AddCommand(newAgentsShowCmd())
+	cmd.AddCommand(newAgentsCreateCmd())
+	cmd.AddCommand(newAgentsAssignCmd())
+
 	return cmd
 }
 
@@ -233,6 +243,8 @@ func runAgentList(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	printProjectRoles()
+
 	fmt.Println()
 	fmt.Println(descStyle.Render("Use agents in chat: /agent <id>"))
 	fmt.Println()
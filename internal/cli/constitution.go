@@ -7,6 +7,9 @@ import (
 	"strings"
 	"time"
 
+	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/gates"
+
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
@@ -307,40 +310,71 @@ func mustGetwd() string {
 // NewClarifyCmd creates the clarify command
 func NewClarifyCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "clarify",
+		Use:   "clarify [trackID]",
 		Short: "❓ Clarify specifications with structured Q&A",
-		Long: `Analyze current specifications and identify areas that need clarification.
+		Long: `Analyze the current specification and list the questions that need answers.
 
-Generates structured questions to fill gaps in:
-- Requirements completeness
+The strategist agent reads the track's PRD (or .sdd/spec.md) and produces a
+prioritized, numbered list of questions about gaps in:
+- Acceptance criteria
+- Undefined terms
 - Edge cases
 - Technical constraints
-- User flows
-- Error handling`,
-		Run: runClarify,
+- Error handling
+
+The questions are saved to .sdd/tracks/<trackID>/clarifications.md. Without
+a configured AI provider, a checklist of common gaps is used instead.
+
+Examples:
+  viki clarify
+  viki clarify user-auth`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runClarify,
 	}
 }
 
-func runClarify(cmd *cobra.Command, args []string) {
-	fmt.Println("🔍 Analyzing specifications for gaps...")
-
-	specPath := filepath.Join(".sdd", "spec.md")
-	planPath := filepath.Join(".sdd", "plan.md")
+func runClarify(cmd *cobra.Command, args []string) error {
+	trackID := "feature-implementation"
+	if len(args) > 0 {
+		trackID = args[0]
+	} else if state, err := gates.NewStateManager(".").LoadState(); err == nil && state.Metadata != nil {
+		if t, ok := state.Metadata["current_track"].(string); ok && t != "" {
+			trackID = t
+		}
+	}
 
-	// Check if spec exists
-	if _, err := os.Stat(specPath); os.IsNotExist(err) {
-		fmt.Println("❌ No specification found. Run 'viki specify' first.")
-		return
+	// Prefer the track's PRD, falling back to the legacy spec
+	specPath := ""
+	for _, candidate := range []string{
+		filepath.Join(gates.TracksDir("."), trackID, agents.ArtifactName(".", "specify")),
+		filepath.Join(".sdd", "spec.md"),
+	} {
+		if _, err := os.Stat(candidate); err == nil {
+			specPath = candidate
+			break
+		}
+	}
+	if specPath == "" {
+		return fmt.Errorf("no specification found. Run 'viki specify' first")
 	}
 
 	specContent, err := os.ReadFile(specPath)
 	if err != nil {
-		fmt.Printf("❌ Error reading spec: %v\n", err)
-		return
+		return fmt.Errorf("error reading spec: %w", err)
 	}
 
-	// Generate clarification questions
-	questions := generateClarificationQuestions(string(specContent))
+	fmt.Printf("🔍 Analyzing %s for gaps...\n", specPath)
+
+	questions, err := askClarificationQuestions(string(specContent))
+	if err != nil {
+		fmt.Printf("⚠️  AI clarification unavailable (%v); using the built-in gap checklist\n", err)
+
+		var list strings.Builder
+		for i, q := range generateClarificationQuestions(string(specContent)) {
+			list.WriteString(fmt.Sprintf("%d. %s [%s]\n", i+1, q.Question, strings.ReplaceAll(q.Category, "_", " ")))
+		}
+		questions = list.String()
+	}
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -348,28 +382,42 @@ func runClarify(cmd *cobra.Command, args []string) {
 
 	fmt.Println(titleStyle.Render("\n❓ Clarification Questions"))
 	fmt.Println(strings.Repeat("─", 50))
+	fmt.Println(questions)
 
-	for i, q := range questions {
-		fmt.Printf("\n%d. %s\n", i+1, q.Question)
-		fmt.Printf("   📝 Why: %s\n", q.Reason)
-		if q.Suggestion != "" {
-			fmt.Printf("   💡 Suggestion: %s\n", q.Suggestion)
-		}
+	// Questions raised after the plan exists may invalidate it
+	if _, err := os.Stat(filepath.Join(".sdd", "plan.md")); err == nil {
+		fmt.Println("\n⚠️  Plan already exists. Address these before proceeding.")
 	}
 
-	// Save clarification report
-	reportPath := filepath.Join(".sdd", "clarifications.md")
-	if planPath != "" {
-		// Check plan status
-		_, planErr := os.Stat(planPath)
-		if planErr == nil {
-			fmt.Println("\n⚠️  Plan already exists. Address these before proceeding.")
-		}
+	trackDir := filepath.Join(gates.TracksDir("."), trackID)
+	if err := os.MkdirAll(trackDir, 0755); err != nil {
+		return fmt.Errorf("failed to create track directory: %w", err)
+	}
+
+	reportPath := filepath.Join(trackDir, "clarifications.md")
+	report := fmt.Sprintf("# Clarifications\n\nGenerated: %s\nSource: %s\n\n%s\n",
+		time.Now().Format("2006-01-02 15:04"), specPath, questions)
+	if err := os.WriteFile(reportPath, []byte(report), 0644); err != nil {
+		return fmt.Errorf("failed to save clarifications: %w", err)
 	}
 
-	report := generateClarificationReport(questions)
-	os.WriteFile(reportPath, []byte(report), 0644)
-	fmt.Printf("\n📄 Report saved to: %s\n", reportPath)
+	fmt.Printf("\n📄 Questions saved to: %s\n", reportPath)
+	return nil
+}
+
+// askClarificationQuestions has the strategist agent list the open
+// questions of a spec
+func askClarificationQuestions(spec string) (string, error) {
+	agentSvc := agents.NewAgentService(".")
+	if err := agentSvc.Initialize(); err != nil {
+		return "", err
+	}
+
+	questions, err := agentSvc.Clarify(spec)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(questions), nil
 }
 
 type ClarificationQuestion struct {
@@ -440,50 +488,55 @@ func generateClarificationQuestions(specContent string) []ClarificationQuestion
 	return questions
 }
 
-func generateClarificationReport(questions []ClarificationQuestion) string {
-	var sb strings.Builder
-
-	sb.WriteString("# Clarification Report\n\n")
-	sb.WriteString(fmt.Sprintf("Generated: %s\n\n", time.Now().Format("2006-01-02 15:04")))
-	sb.WriteString("---\n\n")
-
-	// Group by category
-	categories := map[string][]ClarificationQuestion{}
-	for _, q := range questions {
-		categories[q.Category] = append(categories[q.Category], q)
-	}
-
-	for category, qs := range categories {
-		sb.WriteString(fmt.Sprintf("## %s\n\n", strings.Title(strings.ReplaceAll(category, "_", " "))))
-		for _, q := range qs {
-			sb.WriteString(fmt.Sprintf("### %s\n\n", q.Question))
-			sb.WriteString(fmt.Sprintf("**Reason**: %s\n\n", q.Reason))
-			if q.Suggestion != "" {
-				sb.WriteString(fmt.Sprintf("**Suggestion**: %s\n\n", q.Suggestion))
-			}
-			sb.WriteString("**Answer**: _[To be filled]_\n\n")
-			sb.WriteString("---\n\n")
-		}
-	}
-
-	return sb.String()
-}
-
 // NewChecklistCmd creates the checklist command
 func NewChecklistCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "checklist",
+	var (
+		file string
+		noAI bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "checklist [name] [trackID]",
 		Short: "✅ Generate quality checklist",
-		Long: `Generate a quality checklist for the current specification and plan.
+		Long: `Generate quality checklists, or validate an artifact against one.
 
-Creates checklists for:
+Without arguments, checklists are written to .sdd/checklists for:
 - Requirements completeness
 - Technical readiness
 - Security considerations
 - Testing strategy
-- Documentation needs`,
-		Run: runChecklist,
+- Documentation needs
+
+With a checklist name, the track artifact named in the checklist (e.g. the
+PRD for spec-quality) is checked item by item. Items with a pattern are
+checked with it; the others are judged by the QA agent. Failed items come
+with remediation notes, and the report is saved to the track.
+
+Built-in checklists: spec-quality, architecture-quality. Templates in
+.sdd/checklists/<name>.md override them.
+
+Examples:
+  viki checklist
+  viki checklist spec-quality user-auth
+  viki checklist spec-quality --file docs/spec.md --no-ai`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				runChecklist(cmd, args)
+				return nil
+			}
+			trackID := ""
+			if len(args) > 1 {
+				trackID = args[1]
+			}
+			return runChecklistValidation(args[0], trackID, file, noAI)
+		},
 	}
+
+	cmd.Flags().StringVar(&file, "file", "", "Check this file instead of the track artifact")
+	cmd.Flags().BoolVar(&noAI, "no-ai", false, "Only run pattern checks; leave other items unverified")
+
+	return cmd
 }
 
 func runChecklist(cmd *cobra.Command, args []string) {
@@ -505,8 +558,112 @@ func runChecklist(cmd *cobra.Command, args []string) {
 		fmt.Printf("  ✅ Created: %s\n", path)
 	}
 
+	// Validation templates are only written once so local edits survive
+	for _, name := range gates.BuiltinChecklistNames() {
+		path := filepath.Join(".sdd", "checklists", name+".md")
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		content, _ := gates.BuiltinChecklist(name)
+		os.WriteFile(path, []byte(content), 0644)
+		fmt.Printf("  ✅ Created: %s\n", path)
+	}
+
 	fmt.Println("\n✅ Checklists generated!")
 	fmt.Println("💡 Review each checklist and mark items as complete [X] before proceeding.")
+	fmt.Println("💡 Validate an artifact with 'viki checklist spec-quality <trackID>'.")
+}
+
+// runChecklistValidation checks a track artifact, or file, against a checklist
+func runChecklistValidation(name, trackID, file string, noAI bool) error {
+	checklist, err := gates.LoadChecklist(".", name)
+	if err != nil {
+		return err
+	}
+
+	if trackID == "" {
+		trackID = "feature-implementation"
+		if state, err := gates.NewStateManager(".").LoadState(); err == nil && state.Metadata != nil {
+			if t, ok := state.Metadata["current_track"].(string); ok && t != "" {
+				trackID = t
+			}
+		}
+	}
+
+	target := file
+	if target == "" {
+		if checklist.Artifact == "" {
+			return fmt.Errorf("checklist '%s' names no artifact; use --file", checklist.Name)
+		}
+		target = filepath.Join(gates.TracksDir("."), trackID, checklist.Artifact)
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", target, err)
+	}
+	_, content, err := gates.ParseFrontmatter(string(data))
+	if err != nil {
+		content = string(data)
+	}
+
+	fmt.Printf("📋 Checking %s against %s...\n", target, checklist.Name)
+
+	results := checklist.CheckPatterns(content)
+	if !noAI {
+		if err := reviewChecklistWithAgent(results, content); err != nil {
+			fmt.Printf("⚠️  QA agent unavailable (%v); AI-judged items stay unverified\n", err)
+		}
+	}
+
+	passStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	failed := 0
+	fmt.Println()
+	for _, result := range results {
+		switch result.Status {
+		case gates.CheckPass:
+			fmt.Printf("%s %s\n", passStyle.Render("✅ PASS"), result.Item.Text)
+		case gates.CheckFail:
+			failed++
+			fmt.Printf("%s %s\n", failStyle.Render("❌ FAIL"), result.Item.Text)
+		default:
+			fmt.Printf("%s %s\n", dimStyle.Render("❔ ----"), result.Item.Text)
+		}
+		if result.Note != "" {
+			fmt.Printf("   %s\n", dimStyle.Render("→ "+result.Note))
+		}
+	}
+
+	report := gates.ChecklistReport(checklist, target, results)
+	reportDir := filepath.Join(gates.TracksDir("."), trackID)
+	if err := os.MkdirAll(reportDir, 0755); err != nil {
+		return fmt.Errorf("failed to create track directory: %w", err)
+	}
+	reportPath := filepath.Join(reportDir, "checklist-"+checklist.Name+".md")
+	if err := os.WriteFile(reportPath, []byte(report), 0644); err != nil {
+		return fmt.Errorf("failed to save checklist report: %w", err)
+	}
+
+	fmt.Printf("\n%d of %d item(s) failed\n", failed, len(results))
+	fmt.Printf("📄 Report saved to: %s\n", reportPath)
+	return nil
+}
+
+// reviewChecklistWithAgent has the QA agent judge the items patterns can't
+func reviewChecklistWithAgent(results []gates.CheckResult, content string) error {
+	for _, result := range results {
+		if result.Status == gates.CheckUnverified {
+			agentSvc := agents.NewAgentService(".")
+			if err := agentSvc.Initialize(); err != nil {
+				return err
+			}
+			return agentSvc.ReviewChecklist(results, content)
+		}
+	}
+	return nil
 }
 
 func generateRequirementsChecklist() string {
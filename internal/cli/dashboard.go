@@ -1,11 +1,12 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"runtime"
 
-	"ultimate-sdd-framework/web"
+	"ultimate-sdd-framework/internal/web"
 
 	"github.com/spf13/cobra"
 )
@@ -13,6 +14,7 @@ import (
 func NewDashboardCmd() *cobra.Command {
 	var port int
 	var noBrowser bool
+	var enableMetrics bool
 
 	cmd := &cobra.Command{
 		Use:   "dashboard",
@@ -20,28 +22,59 @@ func NewDashboardCmd() *cobra.Command {
 		Long: `Start the Viki web dashboard for a visual, beginner-friendly experience.
 
 The dashboard provides:
-• Visual workflow pipeline (Idea → Spec → Plan → Tasks → Code)
-• Click-based navigation instead of CLI commands
-• Live AI chat sidebar
-• Agent selector (21+ AI personas)
-• Real-time progress tracking
+• Visual workflow pipeline (Init → Specify → Plan → Task → Execute → Review)
+• Project stats: files indexed, symbols found and task progress
+• Activity log and one-click phase approval
+• Buttons running init, specify, plan, task, execute and review
+• Chat with any agent about the current phase
+• Real-time updates over Server-Sent Events that resync after a reconnect
 
-Perfect for beginners and visual thinkers!`,
+Perfect for beginners and visual thinkers!
+
+Ctrl+C disconnects open browsers and shuts the server down gracefully.
+
+With --metrics the dashboard also serves Prometheus-format counters for AI
+calls, tokens used, phases completed and gate rejections on /metrics.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			server := web.NewDashboardServer(port)
+			url, err := server.Listen()
+			if err != nil {
+				// The default port is only a preference; pick any free port
+				// unless the user asked for this one explicitly
+				server.Shutdown(context.Background())
+				if cmd.Flags().Changed("port") {
+					return err
+				}
+				server = web.NewDashboardServer(0)
+				if url, err = server.Listen(); err != nil {
+					return err
+				}
+				fmt.Printf("⚠️  Port %d is in use, using %s instead\n", port, url)
+			}
+
+			if err := server.LoadState("."); err != nil {
+				return fmt.Errorf("failed to load project state: %w", err)
+			}
+			server.Watch(".")
+
+			if enableMetrics {
+				server.EnableMetrics(".")
+				fmt.Printf("📈 Prometheus metrics at %s/metrics\n", url)
+			}
+
 			// Open browser unless --no-browser flag
 			if !noBrowser {
-				url := fmt.Sprintf("http://localhost:%d", port)
 				go openBrowser(url)
 			}
 
 			// Start web server
-			server := web.NewServer(port)
 			return server.Start()
 		},
 	}
 
-	cmd.Flags().IntVarP(&port, "port", "p", 3000, "Port to run dashboard on")
+	cmd.Flags().IntVarP(&port, "port", "p", 3000, "Port to run dashboard on (0 picks a free port)")
 	cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Don't open browser automatically")
+	cmd.Flags().BoolVar(&enableMetrics, "metrics", false, "Serve Prometheus metrics on /metrics")
 
 	return cmd
 }
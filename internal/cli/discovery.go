@@ -4,24 +4,49 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/analysis"
 	"ultimate-sdd-framework/internal/lsp"
 )
 
 func NewDiscoveryCmd() *cobra.Command {
 	var deepAnalysis bool
+	var showSkipped bool
+	var churn bool
+	var failOnSecrets bool
+	var maxFiles int
+	var include, exclude []string
 
 	cmd := &cobra.Command{
 		Use:   "discovery",
 		Short: "Analyze existing codebase and generate system context",
 		Long: `Perform comprehensive brownfield analysis of the existing codebase.
 
-This command creates a CONTEXT.md file that serves as the source of truth
+This command creates .sdd/context/current_state.md, the source of truth
 for the current system state, helping AI agents understand legacy patterns,
 forbidden practices, integration points, and technical debt.
 
-Use --deep flag for thorough analysis including code patterns and dependencies.`,
+Use --deep flag for thorough analysis including code patterns and dependencies.
+Projects with more than --max-files analyzable files are refused; run from a
+subdirectory or use --include and --exclude (repeatable globs relative to the
+project root) to narrow the scope.
+
+Files that cannot be analyzed (Go files that do not parse, files over 1 MB,
+unreadable files and unsupported types) are skipped and counted in the
+summary; --show-skipped lists each with its reason.
+
+With --churn, the git history ranks complex files that change often as the
+top refactor candidates of the technical debt.
+
+Files tracked by git are scanned for committed secrets: well-known credential
+formats, and credential entries with real-looking values in .env files. They
+are listed as file:line and recorded as a forbidden pattern; with
+--fail-on-secrets, discovery exits with status 1 when any are found.
+
+Run 'discovery verify' to check a stored context against the current code.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectRoot := "."
 
@@ -29,6 +54,10 @@ Use --deep flag for thorough analysis including code patterns and dependencies.`
 
 			// Create brownfield context analyzer
 			bfc := lsp.NewBrownfieldContext(projectRoot)
+			bfc.SetMaxFiles(maxFiles)
+			bfc.SetProgress(printAnalysisProgress)
+			bfc.SetPathFilter(analysis.NewPathFilter(include, exclude))
+			bfc.SetChurn(churn)
 
 			// Perform analysis
 			if err := bfc.AnalyzeBrownfield(); err != nil {
@@ -36,18 +65,19 @@ Use --deep flag for thorough analysis including code patterns and dependencies.`
 			}
 
 			fmt.Printf("✅ Analyzed %d files\n", len(bfc.Files))
+			if churn && bfc.Churn == nil {
+				fmt.Println("⚠️  No git history found; refactor candidates by churn are left out")
+			}
+			showCommittedSecrets(bfc)
 
-			// Generate CONTEXT.md
+			// Generate the system context
 			contextContent := bfc.GenerateCONTEXTFile()
 
-			// Ensure .sdd directory exists
-			sddDir := filepath.Join(projectRoot, ".sdd")
-			if err := os.MkdirAll(sddDir, 0755); err != nil {
-				return fmt.Errorf("failed to create .sdd directory: %w", err)
+			// Save it where agents load the brownfield context from
+			contextPath := agents.BrownfieldContextPath(projectRoot)
+			if err := os.MkdirAll(filepath.Dir(contextPath), 0755); err != nil {
+				return fmt.Errorf("failed to create context directory: %w", err)
 			}
-
-			// Save CONTEXT.md
-			contextPath := filepath.Join(sddDir, "CONTEXT.md")
 			if err := os.WriteFile(contextPath, []byte(contextContent), 0644); err != nil {
 				return fmt.Errorf("failed to save context file: %w", err)
 			}
@@ -56,21 +86,251 @@ Use --deep flag for thorough analysis including code patterns and dependencies.`
 
 			// Show summary
 			showDiscoverySummary(bfc)
+			showSkippedFiles(bfc, showSkipped)
 
 			fmt.Println("\n🎯 Next steps:")
-			fmt.Println("  1. Review CONTEXT.md to understand system constraints")
+			fmt.Printf("  1. Review %s to understand system constraints\n", contextPath)
 			fmt.Println("  2. Run: nexus specify \"your feature description\"")
 			fmt.Println("  3. The system will now validate requests against legacy patterns")
 
+			if failOnSecrets && len(bfc.Secrets) > 0 {
+				return &ExitError{Code: 1, Err: fmt.Errorf("%d committed secret(s) found", len(bfc.Secrets))}
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&deepAnalysis, "deep", false, "Perform deep analysis including code patterns and dependencies")
+	cmd.Flags().BoolVar(&churn, "churn", false, "Rank complex files that change often in git history as top refactor candidates")
+	cmd.Flags().BoolVar(&showSkipped, "show-skipped", false, "List the files left out of the analysis and why")
+	cmd.Flags().BoolVar(&failOnSecrets, "fail-on-secrets", false, "Exit with status 1 when git-tracked files hold committed secrets")
+	cmd.Flags().IntVar(&maxFiles, "max-files", lsp.DefaultMaxFiles, "Refuse to analyze more files than this (0 for no limit)")
+	cmd.Flags().StringArrayVar(&include, "include", nil, "Only analyze paths matching this glob (repeatable)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "Skip paths matching this glob (repeatable)")
+
+	cmd.AddCommand(newDiscoveryGraphCmd())
+	cmd.AddCommand(newDiscoveryVerifyCmd())
+
+	return cmd
+}
+
+func newDiscoveryGraphCmd() *cobra.Command {
+	var format, output string
+	var include, exclude []string
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export the package/file dependency graph",
+		Long: `Export the project's internal dependency graph as Graphviz DOT or Mermaid.
+
+Go files are grouped by package and JS/TS files are shown individually.
+Packages or files that import each other in a cycle are drawn in red.
+
+Writing to a .md file wraps Mermaid output in a fenced block so it renders
+directly on GitHub and in most Markdown viewers.
+
+Examples:
+  viki discovery graph --format mermaid
+  viki discovery graph --format mermaid --output docs/dependencies.md
+  viki discovery graph --format dot | dot -Tsvg > deps.svg`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cc := lsp.NewCodebaseContext(".")
+			cc.SetPathFilter(analysis.NewPathFilter(include, exclude))
+			if err := cc.AnalyzeProject(); err != nil {
+				return fmt.Errorf("failed to analyze codebase: %w", err)
+			}
+
+			graph, err := cc.GenerateDependencyGraph(format)
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				fmt.Print(graph)
+				return nil
+			}
+
+			if strings.EqualFold(format, "mermaid") && strings.EqualFold(filepath.Ext(output), ".md") {
+				graph = "```mermaid\n" + graph + "```\n"
+			}
+			if err := os.WriteFile(output, []byte(graph), 0644); err != nil {
+				return fmt.Errorf("failed to write graph: %w", err)
+			}
+
+			fmt.Printf("📄 Dependency graph written to %s (%d nodes)\n", output, len(cc.Dependencies))
+			if cycles := cc.DependencyCycles(); len(cycles) > 0 {
+				fmt.Printf("🔁 %d import cycle(s), highlighted in red:\n", len(cycles))
+				for _, cycle := range cycles {
+					fmt.Printf("  • %s\n", strings.Join(cycle, " ⇄ "))
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "mermaid", "Output format: mermaid or dot")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the graph to a file instead of stdout")
+	cmd.Flags().StringArrayVar(&include, "include", nil, "Only graph paths matching this glob (repeatable)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "Skip paths matching this glob (repeatable)")
 
 	return cmd
 }
 
+func newDiscoveryVerifyCmd() *cobra.Command {
+	var maxFiles int
+	var include, exclude []string
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the stored system context against the current code",
+		Long: `Re-analyze the codebase and report where .sdd/context/current_state.md
+no longer matches it:
+
+  • integration points the context lists that are no longer present, and
+    new ones it does not list
+  • forbidden-pattern occurrences the context does not list
+  • technical debt that has been resolved or newly introduced
+
+Pass the same --include and --exclude globs used for discovery, or files left
+out of the check are reported as drift. Exits with status 1 when the context
+is stale; run 'viki discovery' to regenerate it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectRoot := "."
+
+			contextPath := agents.BrownfieldContextPath(projectRoot)
+			content, err := os.ReadFile(contextPath)
+			if os.IsNotExist(err) {
+				return fmt.Errorf("no system context at %s - run 'viki discovery' first", contextPath)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read context file: %w", err)
+			}
+			stored := lsp.ParseCONTEXTFile(string(content))
+
+			fmt.Println("🔍 Re-analyzing codebase...")
+			bfc := lsp.NewBrownfieldContext(projectRoot)
+			bfc.SetMaxFiles(maxFiles)
+			bfc.SetProgress(printAnalysisProgress)
+			bfc.SetPathFilter(analysis.NewPathFilter(include, exclude))
+			if err := bfc.AnalyzeBrownfield(); err != nil {
+				return fmt.Errorf("failed to analyze codebase: %w", err)
+			}
+
+			drift := bfc.VerifyAgainst(stored)
+			if !drift.HasDrift() {
+				fmt.Printf("✅ %s matches the current code\n", contextPath)
+				return nil
+			}
+
+			showContextDrift(drift)
+			cmd.SilenceUsage = true
+			fmt.Println("\n💡 Run 'viki discovery' to regenerate the context")
+			return &ExitError{Code: 1, Err: fmt.Errorf("%s is out of date", contextPath)}
+		},
+	}
+
+	cmd.Flags().IntVar(&maxFiles, "max-files", lsp.DefaultMaxFiles, "Refuse to analyze more files than this (0 for no limit)")
+	cmd.Flags().StringArrayVar(&include, "include", nil, "Only analyze paths matching this glob (repeatable)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "Skip paths matching this glob (repeatable)")
+
+	return cmd
+}
+
+// showContextDrift prints the discrepancies between the stored context and
+// the code
+func showContextDrift(drift *lsp.ContextDrift) {
+	fmt.Println("\n⚠️  Context Drift")
+	fmt.Println("================")
+
+	if len(drift.RemovedIntegrationPoints) > 0 {
+		fmt.Printf("\n🔗 Integration points no longer present: %d\n", len(drift.RemovedIntegrationPoints))
+		for _, point := range drift.RemovedIntegrationPoints {
+			fmt.Printf("  • %s (%s)", point.Name, point.Type)
+			if len(point.Files) > 0 {
+				fmt.Printf(" - %s", strings.Join(point.Files, ", "))
+			}
+			fmt.Println()
+		}
+	}
+	if len(drift.AddedIntegrationPoints) > 0 {
+		fmt.Printf("\n🔗 Integration points not in the context: %d\n", len(drift.AddedIntegrationPoints))
+		for _, point := range drift.AddedIntegrationPoints {
+			fmt.Printf("  • %s (%s)\n", point.Name, point.Type)
+		}
+	}
+	if len(drift.NewForbiddenOccurrences) > 0 {
+		fmt.Printf("\n🚫 New forbidden-pattern occurrences: %d patterns\n", len(drift.NewForbiddenOccurrences))
+		for _, pattern := range drift.NewForbiddenOccurrences {
+			fmt.Printf("  • %s [%s]: %s\n", pattern.Pattern, pattern.Severity, strings.Join(pattern.Occurrences, ", "))
+		}
+	}
+	if len(drift.ResolvedDebt) > 0 {
+		fmt.Printf("\n✅ Technical debt resolved: %d\n", len(drift.ResolvedDebt))
+		for _, debt := range drift.ResolvedDebt {
+			fmt.Printf("  • %s\n", debtSummary(debt))
+		}
+	}
+	if len(drift.IntroducedDebt) > 0 {
+		fmt.Printf("\n💸 Technical debt introduced: %d\n", len(drift.IntroducedDebt))
+		for _, debt := range drift.IntroducedDebt {
+			fmt.Printf("  • %s\n", debtSummary(debt))
+		}
+	}
+}
+
+// debtSummary describes a debt item on one line
+func debtSummary(debt lsp.TechnicalDebtItem) string {
+	summary := debt.Issue
+	if debt.Severity != "" {
+		summary += " [" + debt.Severity + "]"
+	}
+	if len(debt.Files) > 0 {
+		summary += " - " + strings.Join(debt.Files, ", ")
+	}
+	return summary
+}
+
+// printAnalysisProgress reports codebase analysis progress on one line
+func printAnalysisProgress(processed, discovered int) {
+	fmt.Printf("\r📂 Analyzed %d/%d files", processed, discovered)
+	if processed == discovered {
+		fmt.Println()
+	}
+}
+
+// showCommittedSecrets lists the secrets found in git-tracked files
+func showCommittedSecrets(bfc *lsp.BrownfieldContext) {
+	if len(bfc.Secrets) == 0 {
+		return
+	}
+	fmt.Printf("🔐 %d committed secret(s) found in git-tracked files:\n", len(bfc.Secrets))
+	for _, finding := range bfc.Secrets {
+		fmt.Printf("  • %s\n", finding)
+	}
+}
+
+// showSkippedFiles prints how many files were left out of the analysis and,
+// when detailed, each of them with its reason
+func showSkippedFiles(bfc *lsp.BrownfieldContext, detailed bool) {
+	summary := bfc.SkippedSummary()
+	if summary == "" {
+		return
+	}
+
+	fmt.Printf("\n⏭️  %s\n", summary)
+	if !detailed {
+		fmt.Println("  Run with --show-skipped to list them")
+		return
+	}
+	for _, file := range bfc.Skipped {
+		if file.Detail != "" {
+			fmt.Printf("  • %s: %s (%s)\n", file.Path, file.Reason, file.Detail)
+		} else {
+			fmt.Printf("  • %s: %s\n", file.Path, file.Reason)
+		}
+	}
+}
+
 func showDiscoverySummary(bfc *lsp.BrownfieldContext) {
 	fmt.Println("\n📊 Discovery Summary")
 	fmt.Println("===================")
@@ -98,6 +358,32 @@ func showDiscoverySummary(bfc *lsp.BrownfieldContext) {
 		fmt.Printf("✨ Features: %s\n", fmt.Sprintf("%v", features))
 	}
 
+	if len(bfc.Structure.Dependencies) > 0 {
+		var versions []string
+		for _, dependency := range bfc.Structure.Dependencies {
+			versions = append(versions, dependency.Label())
+		}
+		fmt.Printf("📌 Versions: %s\n", strings.Join(versions, ", "))
+	}
+
+	// Go modules of a monorepo
+	if len(bfc.Structure.Modules) > 1 {
+		fmt.Printf("\n📦 Go Modules: %d\n", len(bfc.Structure.Modules))
+		for _, module := range bfc.Structure.Modules {
+			fmt.Printf("  • %s (%s): %d files", module.Root, module.Path, module.Files)
+			if languages := module.TechStack["Languages"]; len(languages) > 0 {
+				fmt.Printf(", %s", strings.Join(languages, "/"))
+			}
+			if frameworks := module.TechStack["Frameworks"]; len(frameworks) > 0 {
+				fmt.Printf(", frameworks: %s", strings.Join(frameworks, ", "))
+			}
+			if databases := module.TechStack["Databases"]; len(databases) > 0 {
+				fmt.Printf(", databases: %s", strings.Join(databases, ", "))
+			}
+			fmt.Println()
+		}
+	}
+
 	// Legacy patterns found
 	if len(bfc.LegacyPatterns) > 0 {
 		fmt.Printf("\n📚 Legacy Patterns: %d identified\n", len(bfc.LegacyPatterns))
@@ -148,6 +434,16 @@ func showDiscoverySummary(bfc *lsp.BrownfieldContext) {
 		for severity, count := range severityCount {
 			fmt.Printf("  • %s: %d\n", severity, count)
 		}
+
+		var candidates []string
+		for _, debt := range bfc.TechnicalDebt {
+			if strings.HasPrefix(debt.Issue, "Refactor Candidate") && len(candidates) < 3 {
+				candidates = append(candidates, debt.Files...)
+			}
+		}
+		if len(candidates) > 0 {
+			fmt.Printf("  Top refactor candidates: %s\n", strings.Join(candidates, ", "))
+		}
 	}
 
 	// Constitution status
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/mcp"
+)
+
+// doctorStatus is the outcome of one doctor check
+type doctorStatus int
+
+const (
+	doctorPass doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+// doctorCheck is one row of the doctor checklist
+type doctorCheck struct {
+	Name    string
+	Status  doctorStatus
+	Details []string
+	Hint    string
+}
+
+func NewDoctorCmd() *cobra.Command {
+	var ping bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the project setup and explain how to fix problems",
+		Long: `Run a self-check of the project setup:
+- The .sdd directory is readable and uses the current layout
+- Every role file in .sdd/role parses
+- The required agents are present and the agent service initializes
+- At least one AI provider is configured and enabled (reachable with --ping)
+- The skills the workflow phases equip exist in .sdd/skill
+- git is installed
+
+Each failed check comes with a hint on how to fix it. The command exits
+with status 1 when a check fails.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectRoot := "."
+
+			fmt.Println("🩺 Checking the project setup...")
+			fmt.Println()
+
+			checks := []doctorCheck{
+				checkSDDDir(projectRoot),
+				checkLayout(projectRoot),
+				checkRoleFiles(projectRoot),
+				checkAgentSetup(projectRoot),
+				checkProviders(projectRoot, ping),
+				checkSkills(projectRoot),
+				checkGit(),
+			}
+
+			failed := 0
+			for _, check := range checks {
+				printDoctorCheck(check)
+				if check.Status == doctorFail {
+					failed++
+				}
+			}
+
+			fmt.Println()
+			if failed > 0 {
+				cmd.SilenceUsage = true
+				return &ExitError{Code: 1, Err: fmt.Errorf("%d of %d checks failed", failed, len(checks))}
+			}
+			fmt.Println(successStyle.Render("✅ Everything looks good"))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&ping, "ping", false, "Send a test message to each enabled provider")
+
+	return cmd
+}
+
+// printDoctorCheck prints a checklist row with its details and, unless it
+// passed, the remediation hint
+func printDoctorCheck(check doctorCheck) {
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+	switch check.Status {
+	case doctorPass:
+		fmt.Println(successStyle.Render("✓ " + check.Name))
+	case doctorWarn:
+		fmt.Println(warnStyle.Render("! " + check.Name))
+	default:
+		fmt.Println(errorStyle.Render("✗ " + check.Name))
+	}
+	for _, detail := range check.Details {
+		detail = strings.TrimRight(detail, "\n")
+		fmt.Printf("    %s\n", strings.ReplaceAll(detail, "\n", "\n    "))
+	}
+	if check.Status != doctorPass && check.Hint != "" {
+		fmt.Printf("    💡 %s\n", check.Hint)
+	}
+}
+
+// checkSDDDir checks that the project has been initialized
+func checkSDDDir(projectRoot string) doctorCheck {
+	check := doctorCheck{Name: ".sdd directory is readable"}
+	if _, err := os.ReadDir(filepath.Join(projectRoot, ".sdd")); err != nil {
+		check.Status = doctorFail
+		check.Details = []string{err.Error()}
+		check.Hint = "Run 'viki init <project-name>' in the project root"
+	}
+	return check
+}
+
+// checkLayout checks for files an older version left where agents no
+// longer read them
+func checkLayout(projectRoot string) doctorCheck {
+	check := doctorCheck{Name: ".sdd layout is current"}
+	if agents.HasLegacyContext(projectRoot) {
+		check.Status = doctorWarn
+		check.Details = []string{agents.LegacyContextPath(projectRoot) + " is not loaded as brownfield context"}
+		check.Hint = "Run 'viki migrate'"
+	}
+	return check
+}
+
+// checkRoleFiles parses every role file, reporting each that fails
+func checkRoleFiles(projectRoot string) doctorCheck {
+	check := doctorCheck{Name: "Role files parse"}
+	failures, err := agents.NewAgentManager(projectRoot).RoleFileErrors()
+	if err != nil {
+		check.Status = doctorFail
+		check.Details = []string{err.Error()}
+		check.Hint = "Run 'viki init <project-name>' to create the default roles in .sdd/role"
+		return check
+	}
+	if len(failures) == 0 {
+		return check
+	}
+
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	check.Status = doctorFail
+	for _, name := range names {
+		check.Details = append(check.Details, fmt.Sprintf("%s: %v", name, failures[name]))
+	}
+	check.Hint = "Fix the YAML frontmatter between the '---' lines at the top of each file"
+	return check
+}
+
+// checkAgentSetup initializes the agent service and runs its setup
+// validation
+func checkAgentSetup(projectRoot string) doctorCheck {
+	check := doctorCheck{Name: "Agent service is set up"}
+	agentSvc := agents.NewAgentService(projectRoot)
+	if err := agentSvc.Initialize(); err != nil {
+		check.Status = doctorFail
+		check.Details = []string{err.Error()}
+		check.Hint = "Fix the error above; 'viki init <project-name>' restores a missing .sdd layout"
+		return check
+	}
+
+	// Provider problems have their own check
+	for _, issue := range agentSvc.ValidateSetup() {
+		if strings.Contains(issue, "provider") {
+			continue
+		}
+		check.Status = doctorFail
+		check.Details = append(check.Details, issue)
+	}
+	if check.Status == doctorFail {
+		check.Hint = "Restore missing roles with 'viki init <project-name>' or add them to .sdd/role"
+	}
+	return check
+}
+
+// checkProviders checks that at least one provider is enabled and, with
+// ping, that one answers
+func checkProviders(projectRoot string, ping bool) doctorCheck {
+	check := doctorCheck{Name: "AI provider is configured"}
+	if ping {
+		check.Name = "AI provider is reachable"
+	}
+	addHint := "Run 'viki mcp add <name> --provider <openai|anthropic|google|ollama|azure> --model <model> --default'"
+
+	mcpMgr := mcp.NewMCPManager(projectRoot)
+	if err := mcpMgr.LoadConfig(); err != nil {
+		check.Status = doctorFail
+		check.Details = []string{err.Error()}
+		check.Hint = "Fix the JSON in .sdd/mcp.json or remove the file"
+		return check
+	}
+
+	var enabled []string
+	for name, config := range mcpMgr.ListProviders() {
+		if config.Enabled {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+
+	if len(enabled) == 0 {
+		check.Status = doctorFail
+		if len(mcpMgr.ListProviders()) == 0 {
+			check.Details = []string{"No AI providers configured"}
+		} else {
+			check.Details = []string{"No AI providers are enabled"}
+		}
+		check.Hint = addHint
+		return check
+	}
+
+	if warnings := mcpMgr.KeyWarnings(); len(warnings) > 0 {
+		check.Status = doctorWarn
+		check.Details = append(check.Details, warnings...)
+		check.Hint = "Store the key with 'viki secrets set <provider>' or set it in the secrets backend"
+	}
+
+	if !ping {
+		check.Details = append(check.Details, fmt.Sprintf("Enabled: %s (run with --ping to test them)", strings.Join(enabled, ", ")))
+		return check
+	}
+
+	reachable := 0
+	for _, name := range enabled {
+		if err := mcpMgr.ValidateProvider(name); err != nil {
+			check.Details = append(check.Details, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		reachable++
+		check.Details = append(check.Details, fmt.Sprintf("%s: OK", name))
+	}
+	if reachable == 0 {
+		check.Status = doctorFail
+		check.Hint = "Check the API keys ('viki secrets') and base URLs ('viki mcp list'), then run 'viki mcp test <name>'"
+	}
+	return check
+}
+
+// checkSkills checks that the skill of every workflow phase exists; a
+// missing skill only means the agent works without its instructions
+func checkSkills(projectRoot string) doctorCheck {
+	check := doctorCheck{Name: "Workflow skills are present"}
+	var missing []string
+	for _, skill := range agents.RequiredSkills() {
+		if _, err := os.Stat(filepath.Join(projectRoot, ".sdd", "skill", skill, "SKILL.md")); err != nil {
+			missing = append(missing, skill)
+		}
+	}
+	if len(missing) > 0 {
+		check.Status = doctorWarn
+		check.Details = []string{"Missing: " + strings.Join(missing, ", ")}
+		check.Hint = "Add .sdd/skill/<skill>/SKILL.md with the instructions for each missing skill"
+	}
+	return check
+}
+
+// checkGit checks that git is installed, which baseline comparisons and
+// plugin installs rely on
+func checkGit() doctorCheck {
+	check := doctorCheck{Name: "git is available"}
+	path, err := exec.LookPath("git")
+	if err != nil {
+		check.Status = doctorFail
+		check.Details = []string{"git was not found on PATH"}
+		check.Hint = "Install git from https://git-scm.com/downloads"
+		return check
+	}
+	check.Details = []string{path}
+	return check
+}
@@ -1,13 +1,17 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/collaboration"
 	"ultimate-sdd-framework/internal/gates"
+	"ultimate-sdd-framework/internal/learning"
 )
 
 func NewEvolveCmd() *cobra.Command {
@@ -15,6 +19,7 @@ func NewEvolveCmd() *cobra.Command {
 		bugDescription string
 		ruleCategory   string
 		autoUpdate     bool
+		applyLearned   bool
 	)
 
 	cmd := &cobra.Command{
@@ -25,12 +30,21 @@ func NewEvolveCmd() *cobra.Command {
 This command implements the "System Evolution" philosophy - every bug becomes
 a learning opportunity that improves the development system permanently.
 
+With --apply, the rules suggested by the learning data ('viki learn evolve')
+are presented one by one. Accepted rules are added to the team rules and logged
+as rule evolutions; rejected ones are not suggested again.
+
 Examples:
   nexus evolve "User registration fails with null pointer exception"
   nexus evolve --category frontend "Login form doesn't validate email format"
-  nexus evolve --auto-update "Database connection timeout causes app crash"`,
+  nexus evolve --auto-update "Database connection timeout causes app crash"
+  nexus evolve --apply`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if applyLearned {
+				return applyLearnedRules(cmd.InOrStdin())
+			}
+
 			// Get bug description from args or flag
 			if len(args) > 0 {
 				bugDescription = args[0]
@@ -77,6 +91,7 @@ Examples:
 
 	cmd.Flags().StringVarP(&ruleCategory, "category", "c", "", "Rule category to update (global, frontend, backend, api)")
 	cmd.Flags().BoolVar(&autoUpdate, "auto-update", false, "Automatically apply rule updates")
+	cmd.Flags().BoolVar(&applyLearned, "apply", false, "Review the rules suggested by learning data and add accepted ones to the team rules")
 	cmd.Flags().StringVarP(&bugDescription, "bug", "b", "", "Bug description (alternative to positional argument)")
 
 	return cmd
@@ -356,4 +371,87 @@ func applyRuleEvolution(evolution *RuleEvolution) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// applyLearnedRules offers each rule evolution suggested by the learning
+// data for acceptance, adding accepted ones to the team rules
+func applyLearnedRules(in io.Reader) error {
+	learner, err := learning.NewAdaptiveLearner(".")
+	if err != nil {
+		return fmt.Errorf("failed to initialize learner: %w", err)
+	}
+
+	suggestions, err := learner.EvolveRules()
+	if err != nil {
+		return fmt.Errorf("failed to analyze rule evolution: %w", err)
+	}
+	if len(suggestions) == 0 {
+		fmt.Println("📊 No rule evolution suggestions to apply.")
+		return nil
+	}
+
+	teamCollab, err := collaboration.NewTeamCollaboration(".")
+	if err != nil {
+		return fmt.Errorf("failed to initialize team collaboration: %w", err)
+	}
+
+	reader := bufio.NewReader(in)
+	accepted := 0
+	for i, suggestion := range suggestions {
+		fmt.Printf("\n%d/%d. ✨ %s (%.0f%% confidence)\n", i+1, len(suggestions), suggestion.SuggestedRule, suggestion.Confidence*100)
+		fmt.Printf("   📝 Reason: %s\n", suggestion.Reason)
+		if suggestion.Evidence != "" {
+			fmt.Printf("   📊 Evidence: %s\n", suggestion.Evidence)
+		}
+		if suggestion.Mitigation != "" {
+			fmt.Printf("   🛠️  Mitigation: %s\n", suggestion.Mitigation)
+		}
+		fmt.Print("   Accept this rule? [y/N]: ")
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && answer == "" {
+			// Undecided suggestions are offered again next time
+			fmt.Println()
+			break
+		}
+
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			if err := learner.RejectRuleEvolution(suggestion); err != nil {
+				return fmt.Errorf("failed to record rejection: %w", err)
+			}
+			fmt.Println("   ⏭️  Rejected")
+			continue
+		}
+
+		var examples []string
+		if suggestion.Mitigation != "" {
+			examples = append(examples, suggestion.Mitigation)
+		}
+		category := teamRuleCategory(suggestion.Category)
+		description := suggestion.Reason
+		if suggestion.Evidence != "" {
+			description += ". " + suggestion.Evidence
+		}
+		if _, err := teamCollab.AddTeamRule(category, suggestion.SuggestedRule, description, "recommended", "viki evolve", examples); err != nil {
+			return fmt.Errorf("failed to add team rule: %w", err)
+		}
+		if err := learner.RecordRuleEvolution(suggestion); err != nil {
+			return fmt.Errorf("failed to record rule evolution: %w", err)
+		}
+		accepted++
+		fmt.Printf("   ✅ Added to team rules (%s)\n", category)
+	}
+
+	fmt.Printf("\n📋 %d rule(s) added. Run 'viki team rule list' to see the team's rules.\n", accepted)
+	return nil
+}
+
+// teamRuleCategory maps the category of a learned pattern to a team rule
+// category, defaulting to coding standards
+func teamRuleCategory(patternCategory string) string {
+	switch patternCategory {
+	case "testing", "performance", "security", "documentation":
+		return patternCategory
+	default:
+		return "coding_standards"
+	}
+}
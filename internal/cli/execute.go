@@ -3,10 +3,13 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/editor"
 	"ultimate-sdd-framework/internal/gates"
 )
 
@@ -17,7 +20,8 @@ func NewExecuteCmd() *cobra.Command {
 		Long: `Start implementing the approved tasks.
 
 This command uses the Developer agent to guide the implementation
-process and track progress against the task breakdown.`,
+process and track progress against the task breakdown. In the quick
+workflow profile, which has no task breakdown, it follows the spec.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Check project state
 			stateMgr := gates.NewStateManager(".")
@@ -26,20 +30,46 @@ process and track progress against the task breakdown.`,
 				return fmt.Errorf("project not initialized: %w", err)
 			}
 
-			if state.CurrentPhase != gates.PhaseTask {
-				return fmt.Errorf("cannot execute: current phase is %s (need %s)", state.CurrentPhase, gates.PhaseTask)
+			// Hold the track lock so a concurrent phase or 'execute tasks'
+			// does not move the project state underneath this one
+			trackID := "feature-implementation"
+			if t, ok := state.Metadata["current_track"].(string); ok && t != "" {
+				trackID = t
+			}
+			lock, err := gates.LockTrack(".", trackID, agents.TrackLockWait, func(pid int, since time.Time) {
+				fmt.Printf("⏳ Track %s is in use by another viki process (pid %d, since %s); waiting...\n",
+					trackID, pid, since.Format("15:04:05"))
+			})
+			if err != nil {
+				return err
+			}
+			defer lock.Release()
+
+			// Execution follows the phase the workflow profile runs before it:
+			// the task breakdown, or the spec in the quick profile
+			workflow, err := agents.LoadWorkflow(".")
+			if err != nil {
+				return err
+			}
+			previous := legacyPhase(workflow.Previous("execute"))
+			if previous == "" {
+				return fmt.Errorf("the execute phase is not part of the %s workflow (phases: %s)",
+					workflow.Name, strings.Join(workflow.Phases, " → "))
+			}
+			if state.CurrentPhase != previous {
+				return fmt.Errorf("cannot execute: current phase is %s (need %s)", state.CurrentPhase, previous)
 			}
 
-			// Check if tasks exist
-			taskPath := stateMgr.GetPhaseOutputPath(gates.PhaseTask)
+			// Check if the previous phase's output exists
+			taskPath := stateMgr.GetPhaseOutputPath(previous)
 			if _, err := os.Stat(taskPath); os.IsNotExist(err) {
-				return fmt.Errorf("tasks not found: %s", taskPath)
+				return fmt.Errorf("%s output not found: %s", previous, taskPath)
 			}
 
 			// Load tasks
 			taskContent, err := os.ReadFile(taskPath)
 			if err != nil {
-				return fmt.Errorf("failed to read tasks: %w", err)
+				return fmt.Errorf("failed to read %s: %w", taskPath, err)
 			}
 
 			// Intent Gating: Verify Spec Approval
@@ -66,6 +96,16 @@ process and track progress against the task breakdown.`,
 				return fmt.Errorf("builder agent not available: %w", err)
 			}
 
+			// Snapshot everything execute writes so 'viki undo' can roll it back
+			implPath := stateMgr.GetPhaseOutputPath(gates.PhaseExecute)
+			undoStack := editor.NewUndoStack(".")
+			op := undoStack.Begin("execute")
+			for _, path := range []string{implPath, filepath.Join(".sdd", "state.yaml")} {
+				if err := undoStack.Track(op, path); err != nil {
+					return err
+				}
+			}
+
 			// Transition to execute phase
 			if err := stateMgr.TransitionPhase(gates.PhaseExecute, "builder"); err != nil {
 				return fmt.Errorf("failed to transition to execute phase: %w", err)
@@ -75,7 +115,6 @@ process and track progress against the task breakdown.`,
 			implContent := generateImplementationGuide(builderAgent, string(taskContent))
 
 			// Save implementation guide
-			implPath := stateMgr.GetPhaseOutputPath(gates.PhaseExecute)
 			if err := os.WriteFile(implPath, []byte(implContent), 0644); err != nil {
 				return fmt.Errorf("failed to save implementation guide: %w", err)
 			}
@@ -85,6 +124,10 @@ process and track progress against the task breakdown.`,
 				return fmt.Errorf("failed to complete execute phase: %w", err)
 			}
 
+			if err := undoStack.Commit(op); err != nil {
+				fmt.Printf("⚠️ Warning: could not record undo history: %v\n", err)
+			}
+
 			fmt.Printf("✅ Implementation phase started: %s\n", implPath)
 			fmt.Println("Next: Work through the implementation tasks, then run 'sdd review'")
 
@@ -92,9 +135,104 @@ process and track progress against the task breakdown.`,
 		},
 	}
 
+	cmd.AddCommand(newExecuteTasksSubCmd())
+
+	return cmd
+}
+
+func newExecuteTasksSubCmd() *cobra.Command {
+	var parallel int
+
+	cmd := &cobra.Command{
+		Use:   "tasks [trackID]",
+		Short: "Have the builder implement the track's gsd.json tasks",
+		Long: `Run the pending tasks of the track's approved gsd.json through the builder.
+
+Tasks may declare an "id" and the ids they "depends_on"; tasks without
+pending prerequisites run concurrently, up to --parallel at a time. When a
+task fails, the tasks that depend on it are skipped.
+
+The files in the builder's responses are written, then the project is compiled
+(go build, tsc --noEmit, cargo check or py_compile). On failure the builder
+gets the compiler output for up to two fix-up passes; code that still does not
+compile stops here instead of reaching validation. Finished tasks are marked
+done in gsd.json and all outputs are merged into execution.md.
+
+When the workflow profile has no task phase (the quick profile), the
+builder implements the approved spec as a single task.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trackID := "feature-implementation"
+			if len(args) > 0 {
+				trackID = args[0]
+			} else if state, err := gates.NewStateManager(".").LoadState(); err == nil && state.Metadata != nil {
+				if t, ok := state.Metadata["current_track"].(string); ok && t != "" {
+					trackID = t
+				}
+			}
+
+			agentSvc := agents.NewAgentService(".")
+			if err := agentSvc.Initialize(); err != nil {
+				return fmt.Errorf("failed to initialize agent service: %w", err)
+			}
+
+			fmt.Printf("🏗️  Builder is executing the tasks of %s (up to %d at a time)...\n", trackID, parallel)
+
+			results, build, err := agentSvc.ExecuteTasks(trackID, parallel, func(result agents.TaskResult) {
+				switch result.Status {
+				case agents.TaskSucceeded:
+					fmt.Printf("✅ %s %s (%s)\n", result.ID, result.Title, result.Finished.Sub(result.Started).Round(time.Millisecond))
+				case agents.TaskFailed:
+					fmt.Printf("❌ %s %s: %v\n", result.ID, result.Title, result.Err)
+				case agents.TaskSkipped:
+					fmt.Printf("⏭️  %s %s skipped: %v\n", result.ID, result.Title, result.Err)
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("task execution failed: %w", err)
+			}
+
+			counts := make(map[string]int)
+			for _, result := range results {
+				counts[result.Status]++
+				for _, file := range result.Files {
+					fmt.Printf("✏️  %s wrote %s\n", result.ID, file)
+				}
+			}
+			if build != nil {
+				fmt.Printf("✅ Build passed: %s (%d fix-up pass(es))\n", strings.Join(build.Check.Command, " "), build.Attempts)
+			}
+			fmt.Printf("\n📄 Results merged into .sdd/tracks/%s/execution.md\n", trackID)
+			fmt.Printf("Succeeded: %d  Failed: %d  Skipped: %d  Already done: %d\n",
+				counts[agents.TaskSucceeded], counts[agents.TaskFailed], counts[agents.TaskSkipped], counts[agents.TaskDone])
+
+			if counts[agents.TaskFailed] > 0 {
+				return fmt.Errorf("%d task(s) failed; rerun 'viki execute tasks %s' to retry them", counts[agents.TaskFailed], trackID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&parallel, "parallel", "p", agents.DefaultParallelism, "Maximum number of tasks to run at once")
+
 	return cmd
 }
 
+// legacyPhase maps a workflow phase to the phase of the project state it
+// corresponds to, "" when there is none
+func legacyPhase(phase string) gates.Phase {
+	switch phase {
+	case "":
+		return ""
+	case "discover":
+		return gates.PhaseInit
+	case "design", "audit":
+		return gates.PhasePlan
+	default:
+		return gates.Phase(phase)
+	}
+}
+
 func generateImplementationGuide(agent *agents.Agent, context string) string {
 	template := `---
 title: Implementation Guide
@@ -0,0 +1,16 @@
+package cli
+
+// ExitError is a command failure that should end the process with a
+// specific exit code, e.g. to tell CI how serious a failure is
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
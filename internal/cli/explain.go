@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
+)
+
+func NewExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain <phase>",
+		Short: "Explain what a workflow phase does",
+		Long: `Explain a workflow phase: which agent runs it, the artifact it consumes,
+what it produces and which gate must be approved before it can run.
+
+The explanation is read from the same configuration the orchestrator uses,
+so custom roles and the workflow profile of the project are taken into
+account. Outside a project the default workflow is explained.
+
+Phases: ` + strings.Join(agents.WorkflowPhases, ", "),
+		Example:   `  viki explain execute`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: agents.WorkflowPhases,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			agentSvc := agents.NewAgentService(".")
+			if _, err := os.Stat(".sdd"); err == nil {
+				if err := agentSvc.Initialize(); err != nil {
+					return fmt.Errorf("failed to initialize agent service: %w", err)
+				}
+			}
+
+			explanation, err := agentSvc.ExplainPhase(args[0])
+			if err != nil {
+				return err
+			}
+
+			printPhaseExplanation(explanation)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// printPhaseExplanation narrates a phase in plain sentences
+func printPhaseExplanation(e *agents.PhaseExplanation) {
+	fmt.Println(infoStyle.Render(fmt.Sprintf("📖 The %s phase", e.Phase)))
+	fmt.Println()
+
+	fmt.Printf("🤖 Agent: the %s runs it with the '%s' skill\n", e.Role, e.Skill)
+
+	if e.Input == "" {
+		fmt.Println("📥 Input: none, it starts the workflow from the codebase itself")
+	} else {
+		fmt.Printf("📥 Input: %s\n", e.Input)
+	}
+	fmt.Println("   Also given:")
+	for _, context := range e.Context {
+		fmt.Printf("   - %s\n", context)
+	}
+
+	fmt.Printf("📤 Output: %s\n", e.Output)
+
+	switch {
+	case e.Gate == "" && e.Input == "source_code":
+		fmt.Println("🚦 Gate: none, the source code is approved implicitly")
+	case e.Gate == "":
+		fmt.Println("🚦 Gate: none, the phase can run at any time")
+	case e.GatePhase != "":
+		fmt.Printf("🚦 Gate: %s from the %s phase must be APPROVED first\n", e.Gate, e.GatePhase)
+	default:
+		fmt.Printf("🚦 Gate: %s must be APPROVED first\n", e.Gate)
+	}
+
+	if !e.Included {
+		fmt.Println()
+		fmt.Println("ℹ️  The project's workflow profile skips this phase")
+	}
+}
@@ -232,6 +232,17 @@ func NewLearnEvolveCmd() *cobra.Command {
 				return fmt.Errorf("failed to initialize learner: %w", err)
 			}
 
+			// Analyze the failures recorded since the last pass
+			if pending := learner.PendingMitigations(); pending > 0 {
+				fmt.Printf("🛠️  Analyzing %d failure pattern(s) awaiting a mitigation...\n", pending)
+				filled, err := learner.ProcessPendingMitigations()
+				if err != nil {
+					fmt.Printf("⚠️  %v (%d left pending for the next pass)\n", err, pending-filled)
+				} else {
+					fmt.Printf("✅ Filled in %d mitigation(s)\n", filled)
+				}
+			}
+
 			// Get rule evolution suggestions
 			suggestions, err := learner.EvolveRules()
 			if err != nil {
@@ -266,10 +277,9 @@ func NewLearnEvolveCmd() *cobra.Command {
 			}
 
 			fmt.Println("\n📝 To implement these suggestions:")
-			fmt.Println("  1. Review each suggestion carefully")
-			fmt.Println("  2. Update .sdd/rules/ files accordingly")
-			fmt.Println("  3. Run: nexus evolve \"rule update description\"")
-			fmt.Println("  4. The framework will learn from your rule updates!")
+			fmt.Println("  1. Run: viki evolve --apply")
+			fmt.Println("  2. Accept or reject each suggestion")
+			fmt.Println("  3. Accepted rules are added to the team rules and logged")
 
 			return nil
 		},
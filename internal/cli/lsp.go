@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/gates"
+	"ultimate-sdd-framework/internal/lsp"
+	"ultimate-sdd-framework/internal/review"
+)
+
+// lspFileContext is the answer to a viki/context request
+type lspFileContext struct {
+	File    string `json:"file"`
+	Track   string `json:"track"`
+	Phase   string `json:"phase"`
+	Context string `json:"context"`
+}
+
+func NewLSPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lsp",
+		Short: "Language server for editor integration",
+		Long:  "Run viki as a language server so editors can show review findings and workflow context.",
+	}
+
+	cmd.AddCommand(newLSPServeCmd())
+
+	return cmd
+}
+
+func newLSPServeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Serve JSON-RPC over stdio",
+		Long: `Speak a minimal Language Server Protocol over stdin and stdout, for editor
+extensions to connect to:
+
+- textDocument/didSave reviews the saved file and publishes the issues found
+  as diagnostics
+- viki/context, with {"textDocument": {"uri": ...}}, returns the current
+  track and phase and the context the phase's agent works from
+
+Run it from the project root. Anything the framework prints is sent to
+stderr, so stdout carries only protocol messages.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Only protocol messages may go to stdout
+			protocolOut := os.Stdout
+			os.Stdout = os.Stderr
+			defer func() { os.Stdout = protocolOut }()
+
+			projectRoot := "."
+			reviewer, err := review.NewCodeReviewer(projectRoot)
+			if err != nil {
+				return fmt.Errorf("failed to create reviewer: %w", err)
+			}
+			agentSvc := agents.NewAgentService(projectRoot)
+			if err := agentSvc.Initialize(); err != nil {
+				return fmt.Errorf("failed to initialize agent service: %w", err)
+			}
+
+			server := lsp.NewServer(os.Stdin, protocolOut)
+			server.Review = func(path string) ([]lsp.Diagnostic, error) {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return nil, err
+				}
+				fileReview, err := reviewer.ReviewContent(projectRelativePath(path), string(content))
+				if err != nil {
+					return nil, err
+				}
+				return reviewDiagnostics(fileReview, strings.Split(string(content), "\n")), nil
+			}
+			server.Context = func(path string) (interface{}, error) {
+				trackID, phase := currentTrackAndPhase()
+				context, err := agentSvc.PhaseContext(phase, trackID)
+				if err != nil {
+					return nil, err
+				}
+				return lspFileContext{File: projectRelativePath(path), Track: trackID, Phase: phase, Context: context}, nil
+			}
+
+			fmt.Fprintln(os.Stderr, "viki language server listening on stdio")
+			return server.Serve()
+		},
+	}
+}
+
+// reviewDiagnostics turns the issues of a file review into diagnostics
+// spanning the lines they were found on
+func reviewDiagnostics(fileReview *review.FileReview, lines []string) []lsp.Diagnostic {
+	diagnostics := []lsp.Diagnostic{}
+	for _, issue := range fileReview.Issues {
+		line := max(issue.Line-1, 0)
+		end := 0
+		if line < len(lines) {
+			end = len(lines[line])
+		}
+
+		message := issue.Message
+		if issue.Suggestion != "" {
+			message += "\n" + issue.Suggestion
+		}
+
+		code := issue.Category
+		if code == "" {
+			code = issue.Type
+		}
+
+		diagnostics = append(diagnostics, lsp.Diagnostic{
+			Range:    lsp.Range{Start: lsp.Position{Line: line}, End: lsp.Position{Line: line, Character: end}},
+			Severity: diagnosticSeverity(issue.Severity),
+			Code:     code,
+			Source:   "viki",
+			Message:  message,
+		})
+	}
+	return diagnostics
+}
+
+// diagnosticSeverity maps a review severity to a diagnostic severity
+func diagnosticSeverity(severity string) int {
+	switch severity {
+	case "critical", "high":
+		return lsp.SeverityError
+	case "medium":
+		return lsp.SeverityWarning
+	default:
+		return lsp.SeverityInformation
+	}
+}
+
+// currentTrackAndPhase returns the active track and the workflow phase the
+// project is in, as named by the agents
+func currentTrackAndPhase() (string, string) {
+	trackID := "feature-implementation"
+	phase := "discover"
+
+	state, err := gates.NewStateManager(".").LoadState()
+	if err != nil {
+		return trackID, phase
+	}
+	if t, ok := state.Metadata["current_track"].(string); ok && t != "" {
+		trackID = t
+	}
+
+	switch state.CurrentPhase {
+	case gates.PhaseInit, "":
+		phase = "discover"
+	case gates.PhasePlan:
+		phase = "design"
+	case gates.PhaseComplete:
+		phase = "review"
+	default:
+		phase = string(state.CurrentPhase)
+	}
+	return trackID, phase
+}
+
+// projectRelativePath returns a path relative to the working directory when
+// it lies inside it
+func projectRelativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -38,7 +39,9 @@ Supports multiple providers: OpenAI, Anthropic, Google Gemini, Ollama, and Azure
 	cmd.AddCommand(NewMCPRemoveCmd())
 	cmd.AddCommand(NewMCPListCmd())
 	cmd.AddCommand(NewMCPDefaultCmd())
+	cmd.AddCommand(NewMCPFallbackCmd())
 	cmd.AddCommand(NewMCPTestCmd())
+	cmd.AddCommand(NewMCPModelsCmd())
 	cmd.AddCommand(NewMCPChatCmd())
 
 	return cmd
@@ -50,6 +53,10 @@ func NewMCPAddCmd() *cobra.Command {
 		model    string
 		baseURL  string
 		setDefault bool
+		fromSecrets bool
+		skipModelCheck bool
+		requestsPerMinute int
+		maxConcurrent int
 	)
 
 	cmd := &cobra.Command{
@@ -59,8 +66,25 @@ func NewMCPAddCmd() *cobra.Command {
 
 Supported providers: openai, anthropic, google, ollama, azure
 
+Ollama runs models locally and needs no API key. It defaults to
+http://localhost:11434; use --base-url for a different host.
+
+With --from-secrets no key is written to the config; it is fetched from the
+configured secrets backend (see 'viki secrets backend') each time the
+provider is used, under the provider's name or type.
+
+The model is checked against the provider's models endpoint, or the
+maintained list of 'viki mcp models' when the endpoint cannot be reached.
+Use --skip-model-check for a model the list does not know yet.
+
+--rpm and --max-concurrent set the rate limits AI-assisted reviews keep to
+(default 60 requests per minute, 4 at once), so large reviews are throttled
+instead of failing with rate-limit errors.
+
 Example:
-  sdd mcp add my-openai --provider openai --model gpt-4`,
+  sdd mcp add my-openai --provider openai --model gpt-4
+  sdd mcp add local --provider ollama --model llama3
+  sdd mcp add work --provider anthropic --from-secrets`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
@@ -79,9 +103,15 @@ Example:
 				return fmt.Errorf("invalid provider '%s'. Valid providers: %v", provider, validProviders)
 			}
 
-			// Get API key from environment or prompt
+			if requestsPerMinute < 0 || maxConcurrent < 0 {
+				return fmt.Errorf("--rpm and --max-concurrent must be positive")
+			}
+
+			// Get API key from environment or prompt (local providers need none)
 			apiKey := os.Getenv("SDD_API_KEY")
-			if apiKey == "" {
+			if fromSecrets {
+				apiKey = ""
+			} else if apiKey == "" && mcp.RequiresAPIKey(modelProvider) {
 				fmt.Printf("Enter API key for %s: ", mcp.GetProviderDisplayName(modelProvider))
 				var err error
 				apiKey, err = readPassword()
@@ -91,7 +121,7 @@ Example:
 				apiKey = strings.TrimSpace(apiKey)
 			}
 
-			if apiKey == "" {
+			if apiKey == "" && mcp.RequiresAPIKey(modelProvider) && !fromSecrets {
 				return fmt.Errorf("API key is required")
 			}
 
@@ -100,6 +130,17 @@ Example:
 				model = mcp.GetDefaultModelForProvider(modelProvider)
 			}
 
+			if !skipModelCheck {
+				client := mcp.NewModelClient(modelProvider, apiKey, model)
+				if baseURL != "" {
+					client.SetBaseURL(baseURL)
+				}
+				models, _, _ := client.AvailableModels()
+				if err := mcp.CheckModel(modelProvider, models, model); err != nil {
+					return fmt.Errorf("%w\nUse --skip-model-check to add it anyway", err)
+				}
+			}
+
 			// Initialize MCP manager
 			mcpMgr := mcp.NewMCPManager(".")
 			if err := mcpMgr.LoadConfig(); err != nil {
@@ -111,6 +152,12 @@ Example:
 			if baseURL != "" {
 				options["base_url"] = baseURL
 			}
+			if requestsPerMinute > 0 {
+				options["requests_per_minute"] = requestsPerMinute
+			}
+			if maxConcurrent > 0 {
+				options["max_concurrent"] = maxConcurrent
+			}
 
 			if err := mcpMgr.AddProvider(name, modelProvider, apiKey, model, options); err != nil {
 				return fmt.Errorf("failed to add provider: %w", err)
@@ -138,6 +185,10 @@ Example:
 	cmd.Flags().StringVarP(&model, "model", "m", "", "Model name (provider-specific)")
 	cmd.Flags().StringVar(&baseURL, "base-url", "", "Custom base URL for the provider")
 	cmd.Flags().BoolVar(&setDefault, "default", false, "Set this provider as the default")
+	cmd.Flags().BoolVar(&fromSecrets, "from-secrets", false, "Resolve the API key from the secrets backend at runtime")
+	cmd.Flags().BoolVar(&skipModelCheck, "skip-model-check", false, "Add the model without checking that the provider offers it")
+	cmd.Flags().IntVar(&requestsPerMinute, "rpm", 0, "Requests per minute AI-assisted reviews may send (default 60)")
+	cmd.Flags().IntVar(&maxConcurrent, "max-concurrent", 0, "Calls AI-assisted reviews may run at once (default 4)")
 
 	cmd.MarkFlagRequired("provider")
 
@@ -212,6 +263,11 @@ func NewMCPListCmd() *cobra.Command {
 				fmt.Println()
 			}
 
+			if fallbacks := mcpMgr.GetFallbackProviders(); len(fallbacks) > 0 {
+				fmt.Printf("Fallback order: %s\n", strings.Join(fallbacks, " → "))
+			}
+			printKeyWarnings(mcpMgr)
+
 			return nil
 		},
 	}
@@ -245,6 +301,62 @@ func NewMCPDefaultCmd() *cobra.Command {
 	return cmd
 }
 
+func NewMCPFallbackCmd() *cobra.Command {
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "fallback [name...]",
+		Short: "Set the providers to try when the default fails",
+		Long: `Configure an ordered list of fallback providers.
+
+When the default provider is unreachable, rate limited or rejects its API key,
+agents transparently retry the request on each fallback in order.
+
+Without arguments the current chain is shown.
+
+Example:
+  sdd mcp fallback my-gemini local`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mcpMgr := mcp.NewMCPManager(".")
+			if err := mcpMgr.LoadConfig(); err != nil {
+				return fmt.Errorf("failed to load MCP config: %w", err)
+			}
+
+			if clear {
+				if err := mcpMgr.SetFallbackProviders(nil); err != nil {
+					return fmt.Errorf("failed to clear fallback providers: %w", err)
+				}
+				fmt.Println(successStyle.Render("✅ Fallback providers cleared"))
+				return nil
+			}
+
+			if len(args) > 0 {
+				if err := mcpMgr.SetFallbackProviders(args); err != nil {
+					return fmt.Errorf("failed to set fallback providers: %w", err)
+				}
+				fmt.Println(successStyle.Render("✅ Fallback providers updated"))
+			}
+
+			chain := mcpMgr.ProviderChain()
+			if len(chain) == 0 {
+				fmt.Println(infoStyle.Render("No enabled providers configured."))
+				return nil
+			}
+
+			fmt.Println(mcpStyle.Render("🔁 Provider Chain"))
+			for i, name := range chain {
+				fmt.Printf("  %d. %s\n", i+1, name)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&clear, "clear", false, "Remove all fallback providers")
+
+	return cmd
+}
+
 func NewMCPTestCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "test [name]",
@@ -264,6 +376,8 @@ If no provider name is specified, tests the default provider.`,
 				return fmt.Errorf("failed to load MCP config: %w", err)
 			}
 
+			printKeyWarnings(mcpMgr)
+
 			client, err := mcpMgr.GetClient(providerName)
 			if err != nil {
 				return fmt.Errorf("failed to get client: %w", err)
@@ -284,6 +398,73 @@ If no provider name is specified, tests the default provider.`,
 	return cmd
 }
 
+// printKeyWarnings reports the providers whose API key could not be resolved
+func printKeyWarnings(mcpMgr *mcp.MCPManager) {
+	for _, warning := range mcpMgr.KeyWarnings() {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+}
+
+func NewMCPModelsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "models <provider>",
+		Short: "List the models a provider offers",
+		Long: `List the models of a configured provider, or of a provider type
+(openai, anthropic, google, ollama, azure).
+
+The provider's models endpoint is queried with the configured key (for a
+provider type, with SDD_API_KEY). When it cannot be reached, the maintained
+list of known models is shown instead.
+
+Example:
+  viki mcp models my-openai
+  viki mcp models ollama`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mcpMgr := mcp.NewMCPManager(".")
+			if err := mcpMgr.LoadConfig(); err != nil {
+				return fmt.Errorf("failed to load MCP config: %w", err)
+			}
+
+			client, err := mcpMgr.GetClient(args[0])
+			if err != nil {
+				provider := mcp.ModelProvider(args[0])
+				if !slices.Contains(mcp.GetAvailableProviders(), provider) {
+					return fmt.Errorf("'%s' is neither a configured provider nor a provider type (%v)", args[0], mcp.GetAvailableProviders())
+				}
+				client = mcp.NewModelClient(provider, os.Getenv("SDD_API_KEY"), "")
+			}
+
+			models, live, err := client.AvailableModels()
+			if len(models) == 0 {
+				if err != nil {
+					return fmt.Errorf("failed to list %s models: %w", mcp.GetProviderDisplayName(client.Provider), err)
+				}
+				return fmt.Errorf("%s has no fixed model list; use the model or deployment name you set up", mcp.GetProviderDisplayName(client.Provider))
+			}
+
+			source := "models endpoint"
+			if !live {
+				source = "known models"
+				if err != nil {
+					fmt.Printf("⚠️  Could not query the models endpoint (%v); showing the known models\n", err)
+				}
+			}
+			fmt.Println(mcpStyle.Render(fmt.Sprintf("🤖 %s models (%s)", mcp.GetProviderDisplayName(client.Provider), source)))
+			for _, model := range models {
+				mark := ""
+				if client.Model != "" && mcp.HasModel([]string{model}, client.Model) {
+					mark = successStyle.Render(" (configured)")
+				}
+				fmt.Printf("  • %s%s\n", model, mark)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
 func NewMCPChatCmd() *cobra.Command {
 	var (
 		provider string
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
+)
+
+func NewMigrateCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Move a legacy .sdd layout to the current structure",
+		Long: `Upgrade a project created by an older version of viki.
+
+Older versions wrote the brownfield context to .sdd/CONTEXT.md, which agents
+no longer read. This command moves it, unchanged, to
+.sdd/context/current_state.md so agents load the legacy patterns, forbidden
+practices and constraints of the codebase again.
+
+An existing .sdd/context/current_state.md is only replaced with --force.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectRoot := "."
+
+			if !agents.HasLegacyContext(projectRoot) {
+				fmt.Println("✅ Nothing to migrate: the project already uses the current layout")
+				return nil
+			}
+
+			to, err := agents.MigrateLegacyContext(projectRoot, force)
+			if err != nil {
+				return fmt.Errorf("migration failed: %w", err)
+			}
+
+			fmt.Println(successStyle.Render("✅ Migrated brownfield context"))
+			fmt.Printf("   %s → %s\n", agents.LegacyContextPath(projectRoot), to)
+			fmt.Println("Agents now load it as brownfield context")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing .sdd/context/current_state.md")
+
+	return cmd
+}
@@ -1,10 +1,16 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
+	"ultimate-sdd-framework/internal/config"
+	"ultimate-sdd-framework/internal/lsp"
+	"ultimate-sdd-framework/internal/plugins"
 	"ultimate-sdd-framework/internal/templates"
 
 	"github.com/charmbracelet/lipgloss"
@@ -15,6 +21,8 @@ func NewNewCmd() *cobra.Command {
 	var (
 		listTemplates bool
 		outputDir     string
+		projectFlag   string
+		templateVars  map[string]string
 	)
 
 	cmd := &cobra.Command{
@@ -29,14 +37,34 @@ Available templates:
   nextjs      - Next.js 14 with App Router
   go-cli      - Go CLI with Cobra
 
+Custom templates live in .sdd/templates/<name>/. Every file in the
+directory is rendered with Go text/template, so {{.ProjectName}} and any
+other declared variable can appear in file contents and paths. An optional
+template.yaml declares the template's description and variables:
+
+  description: Internal Go service
+  variables:
+    - name: ProjectName
+      description: Service name
+      required: true
+    - name: Port
+      description: HTTP port
+      default: "8080"
+
+Variables not given with --var are prompted for.
+
 Examples:
   viki new go-api my-api
+  viki new go-api --name my-api --var ModulePath=github.com/acme/my-api
   viki new react-app my-frontend
   viki new --list`,
 		Args: cobra.MaximumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			tm := templates.NewTemplateManager("")
+			tm := templates.NewTemplateManager(filepath.Join(".sdd", "templates"))
 			tm.LoadBuiltinTemplates()
+			if err := tm.LoadCustomTemplates(); err != nil {
+				return err
+			}
 
 			if listTemplates {
 				return listAvailableTemplates(tm)
@@ -48,7 +76,9 @@ Examples:
 
 			templateName := args[0]
 			projectName := "my-project"
-			if len(args) > 1 {
+			if projectFlag != "" {
+				projectName = projectFlag
+			} else if len(args) > 1 {
 				projectName = args[1]
 			}
 
@@ -65,8 +95,11 @@ Examples:
 			// Prepare variables
 			vars := map[string]string{
 				"ProjectName": projectName,
-				"ModulePath":  fmt.Sprintf("github.com/user/%s", projectName),
 			}
+			for name, value := range templateVars {
+				vars[name] = value
+			}
+			promptTemplateVariables(t, vars)
 
 			fmt.Printf("🆕 Creating %s project: %s\n\n", templateName, projectName)
 
@@ -90,10 +123,44 @@ Examples:
 
 	cmd.Flags().BoolVarP(&listTemplates, "list", "l", false, "List available templates")
 	cmd.Flags().StringVarP(&outputDir, "output", "o", "", "Output directory")
+	cmd.Flags().StringVarP(&projectFlag, "name", "n", "", "Project name")
+	cmd.Flags().StringToStringVar(&templateVars, "var", nil, "Template variable as key=value (repeatable)")
 
 	return cmd
 }
 
+// promptTemplateVariables asks for each declared template variable that has
+// no value yet. Nothing is asked when stdin is not a terminal; defaults apply.
+func promptTemplateVariables(t *templates.Template, vars map[string]string) {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for _, v := range t.Variables {
+		if vars[v.Name] != "" {
+			continue
+		}
+
+		prompt := v.Description
+		if prompt == "" {
+			prompt = v.Name
+		}
+		if def := v.DefaultValue(vars); def != "" {
+			prompt = fmt.Sprintf("%s [%s]", prompt, def)
+		}
+		fmt.Printf("%s: ", prompt)
+
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		if input = strings.TrimSpace(input); input != "" {
+			vars[v.Name] = input
+		}
+	}
+}
+
 func listAvailableTemplates(tm *templates.TemplateManager) error {
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
 	fmt.Println(titleStyle.Render("📦 Available Templates"))
@@ -122,6 +189,7 @@ func NewConfigCmd() *cobra.Command {
 	cmd.AddCommand(NewConfigSetCmd())
 	cmd.AddCommand(NewConfigListCmd())
 	cmd.AddCommand(NewConfigResetCmd())
+	cmd.AddCommand(NewConfigProfileCmd())
 
 	return cmd
 }
@@ -133,8 +201,15 @@ func NewConfigGetCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key := args[0]
-			// Would use config.NewConfigManager() here
-			fmt.Printf("%s = <value>\n", key)
+			cm := config.NewConfigManager()
+			if err := cm.Load(); err != nil {
+				return err
+			}
+			value, err := cm.GetValue(key)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s = %v\n", key, value)
 			return nil
 		},
 	}
@@ -147,6 +222,13 @@ func NewConfigSetCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key, value := args[0], args[1]
+			cm := config.NewConfigManager()
+			if err := cm.Load(); err != nil {
+				return err
+			}
+			if err := cm.SetValueString(key, value); err != nil {
+				return err
+			}
 			fmt.Printf(successStyle.Render("✓ Set %s = %s\n"), key, value)
 			return nil
 		},
@@ -185,11 +267,15 @@ func NewPluginCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "plugin",
 		Short: "🔌 Manage Viki plugins",
-		Long:  "Install, remove, and manage Viki plugins.",
+		Long: `Install, remove, and manage Viki plugins.
+
+Project plugins live in .sdd/plugins and user plugins in ~/.viki/plugins.
+Commands declared in a plugin's plugin.yaml become viki commands.`,
 	}
 
 	cmd.AddCommand(NewPluginListCmd())
 	cmd.AddCommand(NewPluginInstallCmd())
+	cmd.AddCommand(NewPluginRemoveCmd())
 	cmd.AddCommand(NewPluginCreateCmd())
 
 	return cmd
@@ -200,51 +286,150 @@ func NewPluginListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List installed plugins",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			homeDir, _ := os.UserHomeDir()
-			pluginsDir := filepath.Join(homeDir, ".viki", "plugins")
+			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+			dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 
-			entries, err := os.ReadDir(pluginsDir)
-			if err != nil {
-				if os.IsNotExist(err) {
-					fmt.Println("No plugins installed.")
-					return nil
+			found := false
+			for _, dir := range []string{plugins.ProjectPluginsDir("."), plugins.GlobalPluginsDir()} {
+				pm := plugins.NewPluginManager(dir)
+				if _, err := os.Stat(dir); os.IsNotExist(err) {
+					continue
+				}
+				if err := pm.Discover(); err != nil {
+					return err
+				}
+				registry, err := pm.Registry()
+				if err != nil {
+					return err
+				}
+
+				installed := pm.List()
+				if len(installed) == 0 {
+					continue
+				}
+				sort.Slice(installed, func(i, j int) bool { return installed[i].Manifest.Name < installed[j].Manifest.Name })
+
+				if !found {
+					fmt.Println(titleStyle.Render("🔌 Installed Plugins"))
+					found = true
+				}
+				fmt.Println()
+				fmt.Println(dimStyle.Render(dir))
+				for _, info := range installed {
+					manifest := info.Manifest
+					fmt.Printf("  • %s %s (%s)", manifest.Name, manifest.Version, manifest.Type)
+					if manifest.Description != "" {
+						fmt.Printf(" - %s", manifest.Description)
+					}
+					fmt.Println()
+					if len(manifest.Commands) > 0 {
+						fmt.Println(dimStyle.Render("      commands: " + strings.Join(manifest.Commands, ", ")))
+					}
+					if record, ok := registry[manifest.Name]; ok {
+						fmt.Println(dimStyle.Render("      source: " + record.Source))
+					}
 				}
-				return err
 			}
 
-			if len(entries) == 0 {
+			if !found {
 				fmt.Println("No plugins installed.")
-				return nil
 			}
+			return nil
+		},
+	}
+}
 
-			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
-			fmt.Println(titleStyle.Render("🔌 Installed Plugins"))
+func NewPluginInstallCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "install <git-url>",
+		Short: "Install a plugin from a git repository",
+		Long: `Clone a plugin repository into .sdd/plugins, check that its plugin.yaml
+declares a valid plugin whose entry point exists, and register it.
+
+The plugin's commands are available from the next run:
+  viki plugin install https://github.com/acme/viki-lint-plugin.git
+  viki lint-docs`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Printf("📥 Installing plugin from %s...\n", args[0])
 
-			for _, entry := range entries {
-				if entry.IsDir() {
-					fmt.Printf("  • %s\n", entry.Name())
-				}
+			pm := plugins.NewPluginManager(plugins.ProjectPluginsDir("."))
+			if err := pm.Discover(); err != nil {
+				return err
+			}
+			manifest, err := pm.Install(args[0])
+			if err != nil {
+				return err
 			}
 
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Installed %s %s", manifest.Name, manifest.Version)))
+			for _, command := range manifest.Commands {
+				if existing, _, err := cmd.Root().Find([]string{command}); err == nil && existing != cmd.Root() {
+					fmt.Printf("  ⚠️  Command '%s' conflicts with 'viki %s' and will be skipped\n", command, existing.Name())
+					continue
+				}
+				fmt.Printf("  New command: viki %s\n", command)
+			}
+			for _, agent := range manifest.Agents {
+				fmt.Printf("  New agent: %s\n", agent)
+			}
 			return nil
 		},
 	}
 }
 
-func NewPluginInstallCmd() *cobra.Command {
+func NewPluginRemoveCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "install <source>",
-		Short: "Install a plugin",
+		Use:   "remove <name>",
+		Short: "Remove an installed plugin",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			source := args[0]
-			fmt.Printf("Installing plugin from: %s\n", source)
-			// Would use plugins.PluginManager here
-			return fmt.Errorf("plugin installation from remote sources not yet implemented")
+			pm := plugins.NewPluginManager(plugins.ProjectPluginsDir("."))
+			if err := pm.Discover(); err != nil {
+				return err
+			}
+			if err := pm.Remove(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Removed plugin %s", args[0])))
+			return nil
 		},
 	}
 }
 
+// AddPluginCommands registers the commands declared by installed plugins,
+// project plugins first, skipping names that are already taken
+func AddPluginCommands(root *cobra.Command) {
+	for _, dir := range []string{plugins.ProjectPluginsDir("."), plugins.GlobalPluginsDir()} {
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+
+		pm := plugins.NewPluginManager(dir)
+		if err := pm.Discover(); err != nil {
+			continue
+		}
+		pm.SetContext(plugins.PluginContext{WorkDir: ".", ConfigDir: filepath.Dir(plugins.GlobalPluginsDir()), SDDDir: ".sdd"})
+
+		for command, pluginName := range pm.GetCommands() {
+			if existing, _, err := root.Find([]string{command}); err == nil && existing != root {
+				continue
+			}
+
+			command, pluginName := command, pluginName
+			root.AddCommand(&cobra.Command{
+				Use:                command,
+				Short:              fmt.Sprintf("🔌 Provided by the %s plugin", pluginName),
+				DisableFlagParsing: true,
+				RunE: func(cmd *cobra.Command, args []string) error {
+					return pm.Execute(pluginName, append([]string{command}, args...))
+				},
+			})
+		}
+	}
+}
+
 func NewPluginCreateCmd() *cobra.Command {
 	var pluginType string
 
@@ -272,7 +457,7 @@ func NewPluginCreateCmd() *cobra.Command {
 }
 
 func NewIndexCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "index",
 		Short: "📇 Index the codebase for AI context",
 		Long: `Analyze and index the current codebase.
@@ -283,22 +468,117 @@ This creates a searchable index of:
 • Imports and dependencies
 • File structure
 
-The index is used to provide better context to AI assistants.`,
+The index is saved to .sdd/index.json and used to provide better context
+to AI assistants.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Would use lsp.NewIndexer here
 			fmt.Println("🔍 Indexing codebase...")
 
-			// Simulate indexing
-			fmt.Println("  Scanning files...")
-			fmt.Println("  Extracting symbols...")
-			fmt.Println("  Building dependency graph...")
+			indexer := lsp.NewIndexer(".")
+			if err := indexer.Index(); err != nil {
+				return fmt.Errorf("failed to index codebase: %w", err)
+			}
+			if err := indexer.Save(); err != nil {
+				return err
+			}
 
+			stats := indexer.GetStats()
 			successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
 			fmt.Println(successStyle.Render("\n✓ Indexing complete!"))
-			fmt.Println("  Files: 42")
-			fmt.Println("  Symbols: 156")
-			fmt.Println("  Index saved to: .sdd/index.json")
+			fmt.Printf("  Files: %d\n", stats["files"])
+			fmt.Printf("  Symbols: %d\n", stats["symbols"])
+			fmt.Printf("  Index saved to: %s\n", lsp.IndexPath("."))
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newIndexSymbolsCmd())
+	cmd.AddCommand(newIndexRefsCmd())
+
+	return cmd
+}
+
+func newIndexSymbolsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "symbols <name>",
+		Short: "Find where a symbol is defined",
+		Long: `Look up a function, method, type, variable or constant in the index.
+
+Methods can be qualified with their receiver type:
+  viki index symbols NewAgentService
+  viki index symbols AgentService.Initialize`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			indexer, err := lsp.LoadIndex(".")
+			if err != nil {
+				return fmt.Errorf("%w (run 'viki index' first)", err)
+			}
+
+			symbols := indexer.FindSymbol(args[0])
+			if len(symbols) == 0 {
+				fmt.Printf("No symbol named %s in the index\n", args[0])
+				return nil
+			}
+
+			dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+			for _, sym := range symbols {
+				name := sym.Name
+				if sym.Parent != "" {
+					name = sym.Parent + "." + sym.Name
+				}
+				fmt.Printf("%s:%d  %s %s\n", sym.File, sym.Line, sym.Kind, name)
+				if sym.Signature != "" {
+					fmt.Println(dimStyle.Render("    " + sym.Signature))
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func newIndexRefsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "refs <name>",
+		Short: "Find where a symbol is defined and used",
+		Long: `List every definition and use of a symbol across the indexed files.
+
+Go files are searched by identifier, so matches in comments and strings are
+skipped; other languages use whole-word matching.
+
+  viki index refs AgentService`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			indexer, err := lsp.LoadIndex(".")
+			if err != nil {
+				return fmt.Errorf("%w (run 'viki index' first)", err)
+			}
+
+			refs := indexer.FindReferences(args[0])
+			if len(refs) == 0 {
+				fmt.Printf("No references to %s found\n", args[0])
+				return nil
+			}
+
+			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+			dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+			uses := 0
+			printedUses := false
+			fmt.Println(titleStyle.Render("Definitions"))
+			for _, ref := range refs {
+				if !ref.Definition {
+					uses++
+					if !printedUses {
+						fmt.Println()
+						fmt.Println(titleStyle.Render("Uses"))
+						printedUses = true
+					}
+				}
+				fmt.Printf("  %s:%d:%d  %s\n", ref.File, ref.Line, ref.Column, dimStyle.Render(ref.Context))
+			}
 
+			fmt.Printf("\n%d definition(s), %d use(s)\n", len(refs)-uses, uses)
 			return nil
 		},
 	}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -18,6 +19,9 @@ var (
 	requestType   string
 	suggestionID  string
 	userAction    string
+	errorOutput   string
+	transcriptFormat string
+	transcriptOutput string
 )
 
 func NewPairCmd() *cobra.Command {
@@ -40,6 +44,7 @@ Supports various interaction modes for different development needs.`,
 	cmd.AddCommand(NewPairActionCmd())
 	cmd.AddCommand(NewPairEndCmd())
 	cmd.AddCommand(NewPairReportCmd())
+	cmd.AddCommand(NewPairTranscriptCmd())
 
 	return cmd
 }
@@ -94,6 +99,7 @@ Focus areas help tailor suggestions to your current task.`,
 			fmt.Println("  nexus pair suggest --file <file> --line <line> --type <completion|refactor|test|explanation>")
 			fmt.Println("  nexus pair action --id <suggestion-id> --action <accepted|rejected|modified>")
 			fmt.Println("  nexus pair report  # View session summary")
+			fmt.Println("  nexus pair transcript  # Export the full conversation")
 			fmt.Println("  nexus pair end     # End session")
 
 			return nil
@@ -111,7 +117,9 @@ func NewPairSuggestCmd() *cobra.Command {
 - completion: Code completion and continuation
 - refactor: Refactoring suggestions and improvements
 - test: Testing strategies and test code generation
-- explanation: Code explanation and best practice guidance`,
+- explanation: Code explanation and best practice guidance
+- debug: Root cause analysis of an error or stack trace given with --error
+  ("-" reads it from stdin), answered by the debugger agent`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectRoot := "."
 
@@ -123,10 +131,20 @@ func NewPairSuggestCmd() *cobra.Command {
 				requestType = "completion" // Default
 			}
 
-			// Get code context (placeholder - would read from file)
+			if errorOutput == "-" {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("failed to read error output from stdin: %w", err)
+				}
+				errorOutput = string(data)
+			}
+			if requestType == "debug" && strings.TrimSpace(errorOutput) == "" {
+				return fmt.Errorf("debug requests need the error or stack trace with --error")
+			}
+
+			// Get code context from the lines around the cursor
 			if contextCode == "" {
-				// Read some context from the file
-				contextCode = fmt.Sprintf("// Context from %s around line %d", activeFile, cursorLine)
+				contextCode = linesAround(activeFile, cursorLine, 15)
 			}
 
 			fmt.Printf("🧠 Getting %s suggestion for %s:%d...\n", requestType, activeFile, cursorLine)
@@ -138,7 +156,7 @@ func NewPairSuggestCmd() *cobra.Command {
 			}
 
 			// Get suggestion
-			suggestion, err := pairProgrammer.GetSuggestion(activeFile, cursorLine, contextCode, requestType)
+			suggestion, err := pairProgrammer.GetSuggestion(activeFile, cursorLine, contextCode, requestType, errorOutput)
 			if err != nil {
 				return fmt.Errorf("failed to get suggestion: %w", err)
 			}
@@ -170,7 +188,8 @@ func NewPairSuggestCmd() *cobra.Command {
 	cmd.Flags().StringVar(&activeFile, "file", "", "File path for context")
 	cmd.Flags().IntVar(&cursorLine, "line", 1, "Cursor line number")
 	cmd.Flags().StringVar(&contextCode, "context", "", "Code context (auto-detected if not provided)")
-	cmd.Flags().StringVar(&requestType, "type", "completion", "Suggestion type: completion, refactor, test, explanation")
+	cmd.Flags().StringVar(&requestType, "type", "completion", "Suggestion type: completion, refactor, test, explanation, debug")
+	cmd.Flags().StringVar(&errorOutput, "error", "", "Error message or stack trace to diagnose (\"-\" reads stdin)")
 
 	return cmd
 }
@@ -267,6 +286,17 @@ func NewPairEndCmd() *cobra.Command {
 				fmt.Printf("📄 Session report saved to: %s\n", reportPath)
 			}
 
+			// Save the full conversation for sharing
+			transcript, err := pairProgrammer.ExportTranscript(session, "markdown")
+			if err == nil {
+				transcriptPath := ".sdd/pair_session_transcript.md"
+				if err := os.WriteFile(transcriptPath, []byte(transcript), 0644); err != nil {
+					fmt.Printf("Warning: Failed to save session transcript: %v\n", err)
+				} else {
+					fmt.Printf("📜 Session transcript saved to: %s\n", transcriptPath)
+				}
+			}
+
 			fmt.Printf("👋 Session ended after %v\n", session.Stats.TimeSpent.Round(0))
 
 			return nil
@@ -306,4 +336,68 @@ func NewPairReportCmd() *cobra.Command {
 	}
 
 	return cmd
-}
\ No newline at end of file
+}
+// linesAround returns the lines of a file within radius of line, falling
+// back to a placeholder when the file cannot be read
+func linesAround(file string, line, radius int) string {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Sprintf("// Context from %s around line %d", file, line)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	start := max(line-1-radius, 0)
+	end := min(line+radius, len(lines))
+	if start >= end {
+		return fmt.Sprintf("// Context from %s around line %d", file, line)
+	}
+	return strings.Join(lines[start:end], "\n")
+}
+
+func NewPairTranscriptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transcript",
+		Short: "Export the current session as a shareable transcript",
+		Long: `Export every prompt and AI response of the current pair programming
+session, in full and in order, e.g. to attach to a code review.
+
+Formats: markdown (default), json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectRoot := "."
+
+			// Create pair programmer
+			pairProgrammer, err := pair.NewPairProgrammer(projectRoot)
+			if err != nil {
+				return fmt.Errorf("failed to initialize pair programmer: %w", err)
+			}
+
+			session := pairProgrammer.GetActiveSession()
+			if session == nil {
+				fmt.Println("No active pair programming session.")
+				fmt.Println("The transcript of the last ended session is in .sdd/pair_session_transcript.md")
+				return nil
+			}
+
+			transcript, err := pairProgrammer.ExportTranscript(session, transcriptFormat)
+			if err != nil {
+				return fmt.Errorf("failed to export transcript: %w", err)
+			}
+
+			if transcriptOutput == "" {
+				fmt.Println(transcript)
+				return nil
+			}
+			if err := os.WriteFile(transcriptOutput, []byte(transcript), 0644); err != nil {
+				return fmt.Errorf("failed to write transcript: %w", err)
+			}
+			fmt.Printf("📜 Transcript saved to: %s\n", transcriptOutput)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&transcriptFormat, "format", "markdown", "Transcript format: markdown, json")
+	cmd.Flags().StringVarP(&transcriptOutput, "output", "o", "", "Write the transcript to a file instead of stdout")
+
+	return cmd
+}
@@ -6,13 +6,18 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/analysis"
 	"ultimate-sdd-framework/internal/performance"
 )
 
 var (
-	profileType  string
-	profileDepth string
-	outputFile   string
+	profileType     string
+	profileDepth    string
+	outputFile      string
+	includeReceiver bool
+	byPackage       bool
+	perfInclude     []string
+	perfExclude     []string
 )
 
 func NewPerformanceCmd() *cobra.Command {
@@ -26,13 +31,33 @@ func NewPerformanceCmd() *cobra.Command {
 - Runtime performance profiling and bottleneck identification
 - Automated optimization recommendations and code improvements
 
-Provides detailed performance insights and actionable optimization strategies.`,
+Provides detailed performance insights and actionable optimization strategies.
+
+Functions listed in .sdd/perf-ignore.yaml, or preceded by a
+//viki:ignore-complexity comment, are never flagged as complex.
+
+Each function's performance score is 100 minus a penalty for every metric
+above its threshold. .sdd/perf-scoring.yaml calibrates the formula; omitted
+fields keep these defaults:
+
+  complexity: {threshold: 10, penalty: 20}
+  lines:      {threshold: 100, penalty: 15}
+  nesting:    {threshold: 5, penalty: 10}
+  parameters: {threshold: 7, penalty: 5}
+
+--include and --exclude (repeatable globs relative to the project root)
+limit profiling to part of the tree, e.g. --include 'internal/api/**'.`,
 	}
 
+	cmd.PersistentFlags().BoolVar(&includeReceiver, "include-receiver", false, "Count method receivers as parameters")
+	cmd.PersistentFlags().StringArrayVar(&perfInclude, "include", nil, "Only profile paths matching this glob (repeatable)")
+	cmd.PersistentFlags().StringArrayVar(&perfExclude, "exclude", nil, "Skip paths matching this glob (repeatable)")
+
 	// Subcommands
 	cmd.AddCommand(NewPerformanceAnalyzeCmd())
 	cmd.AddCommand(NewPerformanceProfileCmd())
 	cmd.AddCommand(NewPerformanceOptimizeCmd())
+	cmd.AddCommand(NewPerformanceCompareCmd())
 
 	return cmd
 }
@@ -58,6 +83,8 @@ Generates detailed performance reports with specific improvement suggestions.`,
 
 			// Create performance profiler
 			profiler := performance.NewPerformanceProfiler(projectRoot)
+			profiler.SetIncludeReceiver(includeReceiver)
+			profiler.SetPathFilter(analysis.NewPathFilter(perfInclude, perfExclude))
 
 			// Run analysis
 			report, err := profiler.AnalyzeProject()
@@ -105,7 +132,8 @@ func NewPerformanceProfileCmd() *cobra.Command {
 - cpu: CPU usage and algorithmic complexity analysis
 - runtime: Runtime performance and concurrency analysis
 
-Provides focused analysis for specific performance concerns.`,
+Provides focused analysis for specific performance concerns. With
+--by-package, complexity is also aggregated per directory, most complex first.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
 				profileType = args[0]
@@ -119,6 +147,8 @@ Provides focused analysis for specific performance concerns.`,
 
 			projectRoot := "."
 			profiler := performance.NewPerformanceProfiler(projectRoot)
+			profiler.SetIncludeReceiver(includeReceiver)
+			profiler.SetPathFilter(analysis.NewPathFilter(perfInclude, perfExclude))
 
 			report, err := profiler.AnalyzeProject()
 			if err != nil {
@@ -140,12 +170,17 @@ Provides focused analysis for specific performance concerns.`,
 				fmt.Println(report.GetPerformanceSummary())
 			}
 
+			if byPackage {
+				showPackageComplexity(report)
+			}
+
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&profileType, "type", "full", "Profile type: complexity, memory, cpu, runtime, full")
 	cmd.Flags().StringVar(&profileDepth, "depth", "detailed", "Analysis depth: basic, detailed, comprehensive")
+	cmd.Flags().BoolVar(&byPackage, "by-package", false, "Aggregate complexity per package directory")
 
 	return cmd
 }
@@ -164,6 +199,8 @@ Provides actionable code changes and implementation guidance.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectRoot := "."
 			profiler := performance.NewPerformanceProfiler(projectRoot)
+			profiler.SetIncludeReceiver(includeReceiver)
+			profiler.SetPathFilter(analysis.NewPathFilter(perfInclude, perfExclude))
 
 			fmt.Println("🔧 Analyzing performance bottlenecks and generating optimizations...")
 
@@ -231,6 +268,82 @@ Provides actionable code changes and implementation guidance.`,
 	return cmd
 }
 
+func NewPerformanceCompareCmd() *cobra.Command {
+	var failOnRegression bool
+
+	cmd := &cobra.Command{
+		Use:   "compare <ref>",
+		Short: "Compare performance metrics against a git ref",
+		Long: `Profile the tree as of a git ref (a branch, tag or commit) and the current
+tree, and report how complexity, bottlenecks and duplication changed.
+
+The ref is checked out in a temporary git worktree that is removed afterwards.
+With --fail-on-regression the command fails when any metric got worse or any
+function got more complex, for use as a CI gate.
+
+Example:
+  viki performance compare main`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profiler := performance.NewPerformanceProfiler(".")
+			profiler.SetIncludeReceiver(includeReceiver)
+			profiler.SetPathFilter(analysis.NewPathFilter(perfInclude, perfExclude))
+
+			fmt.Printf("⚖️  Comparing the current tree against %s...\n\n", args[0])
+			comparison, err := profiler.CompareWithRef(args[0])
+			if err != nil {
+				return fmt.Errorf("comparison failed: %w", err)
+			}
+
+			fmt.Printf("%-40s %10s %10s %10s\n", "METRIC", args[0], "CURRENT", "DELTA")
+			for _, metric := range comparison.Metrics {
+				marker := ""
+				if metric.Regressed() {
+					marker = " ⚠️"
+				}
+				fmt.Printf("%-40s %10.1f %10.1f %+10.1f%s\n", metric.Name, metric.Baseline, metric.Current, metric.Delta(), marker)
+			}
+
+			if len(comparison.MoreComplex) > 0 {
+				fmt.Printf("\n📈 Functions that got more complex (%d):\n", len(comparison.MoreComplex))
+				for i, fn := range comparison.MoreComplex {
+					if i == 15 {
+						fmt.Printf("  ... and %d more\n", len(comparison.MoreComplex)-15)
+						break
+					}
+					if fn.Baseline == 0 {
+						fmt.Printf("  • %s (%s): new, complexity %d\n", fn.Name, fn.File, fn.Current)
+					} else {
+						fmt.Printf("  • %s (%s): %d → %d\n", fn.Name, fn.File, fn.Baseline, fn.Current)
+					}
+				}
+			}
+
+			if len(comparison.NewBottlenecks) > 0 {
+				fmt.Printf("\n🚧 New bottlenecks (%d):\n", len(comparison.NewBottlenecks))
+				for _, bottleneck := range comparison.NewBottlenecks {
+					fmt.Printf("  • %s (%s severity) at %s\n", bottleneck.Description, bottleneck.Severity, bottleneck.Location)
+				}
+			}
+
+			regressions := comparison.Regressions()
+			if len(regressions) == 0 && len(comparison.MoreComplex) == 0 {
+				fmt.Printf("\n✅ No performance regressions since %s\n", args[0])
+				return nil
+			}
+			if failOnRegression {
+				return fmt.Errorf("%d metric(s) regressed and %d function(s) got more complex since %s",
+					len(regressions), len(comparison.MoreComplex), args[0])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&failOnRegression, "fail-on-regression", false, "Exit with an error when any metric regressed")
+
+	return cmd
+}
+
 // Helper functions
 
 func showPerformanceScoreInterpretation(score float64) {
@@ -299,6 +412,7 @@ func showComplexityAnalysis(report *performance.PerformanceReport) {
 	fmt.Printf("Average Cyclomatic Complexity: %.1f\n", report.ComplexityAnalysis.CyclomaticComplexity)
 	fmt.Printf("Average Function Length: %.1f lines\n", report.ComplexityAnalysis.FunctionLength)
 	fmt.Printf("Average Nesting Depth: %.1f\n", report.ComplexityAnalysis.NestingDepth)
+	fmt.Printf("Average Maintainability Index: %.1f\n", report.ComplexityAnalysis.MaintainabilityIndex)
 	fmt.Printf("Complex Functions: %d\n\n", len(report.ComplexityAnalysis.ComplexFunctions))
 
 	if len(report.ComplexityAnalysis.ComplexFunctions) > 0 {
@@ -307,12 +421,37 @@ func showComplexityAnalysis(report *performance.PerformanceReport) {
 		complexFuncs := report.ComplexityAnalysis.ComplexFunctions
 		for i := 0; i < len(complexFuncs) && i < 5; i++ {
 			fn := complexFuncs[i]
-			fmt.Printf("  • %s (%s:%s) - Complexity: %d, Lines: %d\n",
-				fn.Name, fn.File, fn.Name, fn.Complexity, fn.Lines)
+			warning := ""
+			if fn.Maintainability < performance.LowMaintainabilityThreshold {
+				warning = " ⚠️ hard to maintain"
+			}
+			fmt.Printf("  • %s (%s:%s) - Complexity: %d, Lines: %d, MI: %.1f, Score: %.0f%s\n",
+				fn.Name, fn.File, fn.Name, fn.Complexity, fn.Lines, fn.Maintainability, fn.Performance, warning)
 		}
 	}
 }
 
+func showPackageComplexity(report *performance.PerformanceReport) {
+	packages := report.ComplexityAnalysis.Packages
+	fmt.Println()
+	fmt.Println("📦 Complexity by Package")
+	fmt.Println("========================")
+	if len(packages) == 0 {
+		fmt.Println("No Go functions analyzed.")
+		return
+	}
+
+	fmt.Printf("%-40s %9s %8s %8s %6s %6s\n", "PACKAGE", "FUNCTIONS", "COMPLEX", "AVG CC", "MAX CC", "MI")
+	for _, pkg := range packages {
+		fmt.Printf("%-40s %9d %8d %8.1f %6d %6.1f\n", pkg.Package, pkg.Functions, pkg.ComplexFunctions,
+			pkg.AverageComplexity, pkg.MaxComplexity, pkg.AverageMaintainability)
+	}
+
+	worst := packages[0]
+	fmt.Printf("\n🎯 %s has avg complexity %.1f across %d functions (max %d in %s)\n",
+		worst.Package, worst.AverageComplexity, worst.Functions, worst.MaxComplexity, worst.MaxFunction)
+}
+
 func showMemoryAnalysis(report *performance.PerformanceReport) {
 	fmt.Println("🧠 Memory Analysis")
 	fmt.Println("==================")
@@ -11,14 +11,24 @@ import (
 )
 
 func NewPlanCmd() *cobra.Command {
+	var revise string
+
 	cmd := &cobra.Command{
 		Use:   "plan",
 		Short: "Create architecture plan using the Designer agent",
 		Long: `Generate a detailed system architecture plan based on specifications.
 
 This command uses the Architect agent to design the system components,
-technology choices, data flow, and implementation strategy.`,
+technology choices, data flow, and implementation strategy.
+
+The designer lists its architecture decisions in a structured block; each
+is written as a numbered decision record under docs/adr/. Regenerating the
+plan updates the records of decisions with the same title.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if revise != "" {
+				return runRevision("design", revise, "")
+			}
+
 			// Check project state
 			stateMgr := gates.NewStateManager(".")
 			state, err := stateMgr.LoadState()
@@ -60,16 +70,18 @@ technology choices, data flow, and implementation strategy.`,
 			}
 
 			// Generate architecture plan
-			planContent, err := agentSvc.GetAgentResponse("designer", "plan", string(specContent), "", "")
+			planContent, err := agentSvc.GetAgentResponse("designer", "plan", string(specContent), agentSvc.VisionContext()+agents.DecisionRecordInstructions, "")
 			if err != nil {
 				return fmt.Errorf("failed to generate architecture plan: %w", err)
 			}
+			agentSvc.WarnVisionDivergence("Architecture plan", planContent)
 
 			// Save plan
 			planPath := stateMgr.GetPhaseOutputPath(gates.PhasePlan)
 			if err := os.WriteFile(planPath, []byte(planContent), 0644); err != nil {
 				return fmt.Errorf("failed to save plan: %w", err)
 			}
+			agentSvc.ExportDecisions(planPath, planContent)
 
 			// Complete phase
 			if err := stateMgr.CompletePhase([]string{filepath.Base(planPath)}); err != nil {
@@ -83,5 +95,7 @@ technology choices, data flow, and implementation strategy.`,
 		},
 	}
 
+	cmd.Flags().StringVar(&revise, "revise", "", "Regenerate the rejected architecture of this track using its feedback")
+
 	return cmd
 }
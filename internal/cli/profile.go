@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/config"
+)
+
+func NewConfigProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage named config profiles (dev, prod, personal, ...)",
+		Long: `A profile selects the AI provider, model, temperature and gate policy that
+apply, so one setup can use a cheap local model while CI uses a premium one.
+
+The active profile is the one named by --profile or VIKI_PROFILE, else the one
+chosen with 'viki config profile use'.
+
+Examples:
+  viki config profile set dev --provider local --model llama3 --temperature 0.2
+  viki config profile set ci --provider claude --gate-policy .sdd/gates.ci.yaml
+  viki config profile use dev
+  VIKI_PROFILE=ci viki task`,
+	}
+
+	cmd.AddCommand(newConfigProfileListCmd())
+	cmd.AddCommand(newConfigProfileSetCmd())
+	cmd.AddCommand(newConfigProfileUseCmd())
+	cmd.AddCommand(newConfigProfileRemoveCmd())
+
+	return cmd
+}
+
+func newConfigProfileListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List profiles and show which is active",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm := config.NewConfigManager()
+			if err := cm.Load(); err != nil {
+				return err
+			}
+			cfg := cm.Get()
+
+			active, _, err := config.ActiveProfile()
+			if err != nil {
+				return err
+			}
+
+			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+			dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+			fmt.Println(titleStyle.Render("⚙️ Config Profiles"))
+			fmt.Println()
+
+			names := config.ProfileNames(cfg)
+			if len(names) == 0 {
+				fmt.Println(dimStyle.Render("No profiles. Create one with 'viki config profile set <name>'."))
+				return nil
+			}
+
+			for _, name := range names {
+				marker := "  "
+				if name == active {
+					marker = successStyle.Render("● ")
+				}
+				fmt.Printf("%s%s %s\n", marker, name, dimStyle.Render(describeProfile(cfg.Profiles[name])))
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigProfileSetCmd() *cobra.Command {
+	var provider, model, temperature, gatePolicy string
+
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Create or update a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm := config.NewConfigManager()
+			if err := cm.Load(); err != nil {
+				return err
+			}
+
+			// Only the flags given change an existing profile
+			profile := cm.Get().Profiles[args[0]]
+			if cmd.Flags().Changed("provider") {
+				profile.Provider = provider
+			}
+			if cmd.Flags().Changed("model") {
+				profile.Model = model
+			}
+			if cmd.Flags().Changed("gate-policy") {
+				profile.GatePolicy = gatePolicy
+			}
+			if cmd.Flags().Changed("temperature") {
+				profile.Temperature = nil
+				if temperature != "" {
+					value, err := strconv.ParseFloat(temperature, 64)
+					if err != nil || value < 0 || value > 2 {
+						return fmt.Errorf("invalid temperature '%s'", temperature)
+					}
+					profile.Temperature = &value
+				}
+			}
+
+			if err := cm.SetProfile(args[0], profile); err != nil {
+				return err
+			}
+
+			fmt.Printf(successStyle.Render("✓ Saved profile %s")+" %s\n", args[0], describeProfile(profile))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&provider, "provider", "", "MCP provider to use by default (see 'viki mcp list')")
+	cmd.Flags().StringVar(&model, "model", "", "Model to use with that provider")
+	cmd.Flags().StringVar(&temperature, "temperature", "", "Temperature for every request (empty to unset)")
+	cmd.Flags().StringVar(&gatePolicy, "gate-policy", "", "Gate policy file to use instead of .sdd/gates.yaml")
+
+	return cmd
+}
+
+func newConfigProfileUseCmd() *cobra.Command {
+	var none bool
+
+	cmd := &cobra.Command{
+		Use:   "use <name>",
+		Short: "Make a profile the active one",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if none {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm := config.NewConfigManager()
+			if err := cm.Load(); err != nil {
+				return err
+			}
+
+			name := ""
+			if !none {
+				name = args[0]
+			}
+			if err := cm.UseProfile(name); err != nil {
+				return err
+			}
+
+			if name == "" {
+				fmt.Println(successStyle.Render("✓ No profile active"))
+				return nil
+			}
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Using profile %s", name)))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&none, "none", false, "Deactivate profiles")
+
+	return cmd
+}
+
+func newConfigProfileRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Delete a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cm := config.NewConfigManager()
+			if err := cm.Load(); err != nil {
+				return err
+			}
+			if err := cm.RemoveProfile(args[0]); err != nil {
+				return err
+			}
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Removed profile %s", args[0])))
+			return nil
+		},
+	}
+}
+
+// describeProfile summarizes the overrides of a profile
+func describeProfile(profile config.Profile) string {
+	description := fmt.Sprintf("provider=%s model=%s", orDash(profile.Provider), orDash(profile.Model))
+	if profile.Temperature != nil {
+		description += fmt.Sprintf(" temperature=%.2g", *profile.Temperature)
+	}
+	if profile.GatePolicy != "" {
+		description += " gate_policy=" + profile.GatePolicy
+	}
+	return description
+}
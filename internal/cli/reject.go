@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
+)
+
+func NewRejectCmd() *cobra.Command {
+	var feedback string
+
+	cmd := &cobra.Command{
+		Use:   "reject <trackID> <artifact>",
+		Short: "Reject a track artifact with feedback for its agent",
+		Long: `Reject a gate artifact and record why.
+
+The feedback is stored in the artifact's frontmatter. Re-run the phase with
+--revise <trackID> to have the agent produce an improved version.
+
+Example:
+  viki reject user-auth 1_prd.md --feedback "missing non-functional requirements"
+  viki specify --revise user-auth`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trackID, artifact := args[0], args[1]
+
+			if feedback == "" {
+				fmt.Printf("Rejecting %s/%s\n", trackID, artifact)
+				fmt.Print("Feedback for the agent: ")
+
+				scanner := bufio.NewScanner(os.Stdin)
+				if scanner.Scan() {
+					feedback = strings.TrimSpace(scanner.Text())
+				}
+			}
+
+			if feedback == "" {
+				return fmt.Errorf("feedback is required when rejecting an artifact")
+			}
+
+			agentSvc := agents.NewAgentService(".")
+			if err := agentSvc.RejectArtifact(trackID, artifact, feedback); err != nil {
+				return fmt.Errorf("failed to reject artifact: %w", err)
+			}
+
+			fmt.Printf("❌ %s/%s rejected\n", trackID, artifact)
+			fmt.Printf("Feedback: %s\n", feedback)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&feedback, "feedback", "f", "", "Why the artifact is rejected")
+
+	return cmd
+}
+
+// runRevision re-runs a phase for a track using its rejected draft and feedback
+func runRevision(phase, trackID, userInput string) error {
+	agentSvc := agents.NewAgentService(".")
+	if err := agentSvc.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialize agent service: %w", err)
+	}
+
+	fmt.Printf("🔁 Revising %s for track %s with reviewer feedback...\n", phase, trackID)
+
+	response, err := agentSvc.Revise(phase, trackID, userInput)
+	if err != nil {
+		return fmt.Errorf("revision failed: %w", err)
+	}
+
+	fmt.Println(response)
+	fmt.Printf("\n✅ Revised draft saved to .sdd/tracks/%s (status: PENDING)\n", trackID)
+	fmt.Println("Next: Run 'viki approve --interactive' to review it")
+
+	return nil
+}
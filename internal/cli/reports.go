@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/gates"
+)
+
+// reportSuffixes are the file name endings of the reports commands write to
+// the top of .sdd, e.g. performance_report.md or optimization_plan.md
+var reportSuffixes = []string{"_report.md", "_plan.md", "_transcript.md"}
+
+// report is a generated report or track artifact
+type report struct {
+	Name     string // e.g. team_report.md or <track>/1_prd.md
+	Path     string
+	Modified time.Time
+	Size     int64
+}
+
+func NewReportsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reports",
+		Short: "List and open generated reports",
+		Long: `List and open the reports viki has generated: the reports commands such as
+performance, team, analyze and review write to .sdd, and the artifacts of
+every track under .sdd/tracks.`,
+	}
+
+	cmd.AddCommand(newReportsListCmd())
+	cmd.AddCommand(newReportsOpenCmd())
+
+	return cmd
+}
+
+func newReportsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List generated reports, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reports, err := listReports(".")
+			if err != nil {
+				return err
+			}
+			if len(reports) == 0 {
+				fmt.Println("No reports generated yet")
+				return nil
+			}
+
+			fmt.Println(infoStyle.Render(fmt.Sprintf("📄 %d report(s)", len(reports))))
+			fmt.Println()
+			for _, r := range reports {
+				fmt.Printf("  %-45s %s  %s\n", r.Name, r.Modified.Format("2006-01-02 15:04"), formatReportSize(r.Size))
+			}
+			fmt.Println()
+			fmt.Println("View one with 'viki reports open <name>'")
+			return nil
+		},
+	}
+}
+
+func newReportsOpenCmd() *cobra.Command {
+	var launch bool
+
+	cmd := &cobra.Command{
+		Use:   "open <name>",
+		Short: "Print a report, or open it in the default application",
+		Long: `Print a report to the terminal, or open it in the system's default
+application with --launch.
+
+The name is as shown by 'viki reports list'; the extension and, for track
+artifacts, the track can be left out. When several tracks have the artifact
+the most recent is opened.`,
+		Example: `  viki reports open performance_report
+  viki reports open 1_prd
+  viki reports open track-123/gsd.json --launch`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reports, err := listReports(".")
+			if err != nil {
+				return err
+			}
+
+			matches := matchReports(reports, args[0])
+			if len(matches) == 0 {
+				return fmt.Errorf("no report named '%s'; run 'viki reports list' to see the reports", args[0])
+			}
+			r := matches[0]
+			if len(matches) > 1 {
+				fmt.Printf("ℹ️  %d reports match '%s'; opening the latest, %s\n\n", len(matches), args[0], r.Name)
+			}
+
+			if launch {
+				openBrowser(r.Path)
+				fmt.Printf("📂 Opened %s\n", r.Path)
+				return nil
+			}
+
+			content, err := os.ReadFile(r.Path)
+			if err != nil {
+				return fmt.Errorf("failed to read report: %w", err)
+			}
+			fmt.Print(string(content))
+			if !strings.HasSuffix(string(content), "\n") {
+				fmt.Println()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&launch, "launch", false, "Open the report in the default application instead of printing it")
+
+	return cmd
+}
+
+// listReports finds the reports in .sdd and the artifacts of every track,
+// newest first
+func listReports(projectRoot string) ([]report, error) {
+	var reports []report
+
+	entries, err := os.ReadDir(filepath.Join(projectRoot, ".sdd"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("not a viki project. Run 'viki init' first")
+		}
+		return nil, fmt.Errorf("failed to read .sdd: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !isReportName(entry.Name()) {
+			continue
+		}
+		if r, ok := statReport(filepath.Join(projectRoot, ".sdd", entry.Name()), entry.Name()); ok {
+			reports = append(reports, r)
+		}
+	}
+
+	tracks, err := os.ReadDir(gates.TracksDir(projectRoot))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read tracks: %w", err)
+	}
+	for _, track := range tracks {
+		if !track.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(gates.TracksDir(projectRoot), track.Name()))
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			// Skip the history of prior versions and the track lock
+			if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+				continue
+			}
+			path := filepath.Join(gates.TracksDir(projectRoot), track.Name(), file.Name())
+			if r, ok := statReport(path, track.Name()+"/"+file.Name()); ok {
+				reports = append(reports, r)
+			}
+		}
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		if !reports[i].Modified.Equal(reports[j].Modified) {
+			return reports[i].Modified.After(reports[j].Modified)
+		}
+		return reports[i].Name < reports[j].Name
+	})
+	return reports, nil
+}
+
+// isReportName reports whether a file at the top of .sdd is a generated
+// report
+func isReportName(name string) bool {
+	for _, suffix := range reportSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// statReport describes a report file, or reports false when it can't be read
+func statReport(path, name string) (report, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return report{}, false
+	}
+	return report{Name: name, Path: path, Modified: info.ModTime(), Size: info.Size()}, true
+}
+
+// matchReports returns the reports a name refers to, newest first: by full
+// name, or without the extension or track
+func matchReports(reports []report, name string) []report {
+	name = filepath.ToSlash(name)
+	var matches []report
+	for _, r := range reports {
+		base := r.Name[strings.LastIndex(r.Name, "/")+1:]
+		candidates := []string{
+			r.Name,
+			strings.TrimSuffix(r.Name, filepath.Ext(r.Name)),
+			base,
+			strings.TrimSuffix(base, filepath.Ext(base)),
+		}
+		for _, candidate := range candidates {
+			if candidate == name {
+				matches = append(matches, r)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// formatReportSize renders a file size for the report list
+func formatReportSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f KB", float64(size)/1024)
+}
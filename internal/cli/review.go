@@ -1,17 +1,30 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/analysis"
+	"ultimate-sdd-framework/internal/gates"
+	"ultimate-sdd-framework/internal/learning"
 	"ultimate-sdd-framework/internal/review"
 )
 
 var (
-	prNumber   int
-	reviewDeep bool
+	prNumber      int
+	reviewDeep    bool
+	reviewInclude []string
+	reviewExclude []string
+	reviewFailOn  string
+	reviewFocus   string
+	reviewFix     bool
 )
 
 func NewReviewCmd() *cobra.Command {
@@ -25,10 +38,43 @@ func NewReviewCmd() *cobra.Command {
 - Best practice compliance
 - Maintainability evaluation
 
-Supports both PR review and general codebase analysis.`,
+Supports both PR review and general codebase analysis.
+
+Configuration files are reviewed too: Dockerfiles for containers running as
+root, unpinned base images and a missing HEALTHCHECK, and YAML/JSON for
+hardcoded secrets and overly permissive values.
+
+--include and --exclude (repeatable globs relative to the project root)
+limit which files are reviewed.
+
+The command exits with code 2 when a critical issue is found and 1 when
+changes are requested or the review is blocked, for use as a CI gate.
+--fail-on tunes this: "critical" fails only on critical issues, "none" never
+fails.
+
+With --deep the QA agent also reviews every file. Its calls are throttled
+to the rate limits of the default provider (see 'viki mcp add --rpm
+--max-concurrent') and retried with backoff when the provider reports its
+limit exceeded, so large reviews slow down instead of failing.
+
+--focus security|performance|style limits the review to one area: only
+issues of that category and critical issues are reported, and scores and
+approval are based on them alone. Detection itself is unchanged.
+
+Mechanical issues carry a unified-diff patch: deleting a console.log
+line, moving the trailing comment of a long line above it, and replacing a
+Go panic with an error return stub. --fix offers the safe ones one by one
+and applies those you accept; the panic stub always needs a human.
+
+Per-language line-length limits can be set in .sdd/review.json:
+  {"line_length": {"go": 100, "python": 80}}`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectRoot := "."
 
+			if err := validateFailOn(reviewFailOn); err != nil {
+				return err
+			}
+
 			// Get changed files (simplified - would integrate with Git in real implementation)
 			changedFiles := []string{}
 			if len(args) > 0 {
@@ -43,6 +89,15 @@ Supports both PR review and general codebase analysis.`,
 				changedFiles = []string{"internal/analysis/metrics.go"} // Placeholder
 			}
 
+			filter := analysis.NewPathFilter(reviewInclude, reviewExclude)
+			selected := changedFiles[:0]
+			for _, file := range changedFiles {
+				if filter.Matches(file) {
+					selected = append(selected, file)
+				}
+			}
+			changedFiles = selected
+
 			if len(changedFiles) == 0 {
 				fmt.Println("No files to review. Specify a PR number or ensure there are changes.")
 				return nil
@@ -56,33 +111,241 @@ Supports both PR review and general codebase analysis.`,
 				return fmt.Errorf("failed to create reviewer: %w", err)
 			}
 
+			if err := reviewer.SetFocus(reviewFocus); err != nil {
+				return err
+			}
+			if reviewDeep {
+				reviewer.EnableAIReview()
+			}
+
 			// Perform review
 			codeReview, err := reviewer.ReviewPullRequest(prNumber, changedFiles)
 			if err != nil {
 				return fmt.Errorf("review failed: %w", err)
 			}
 
-			// Display results
-			fmt.Println(reviewer.GetReviewReport(codeReview))
+			finishReview(projectRoot, reviewer, codeReview)
+			if reviewFix {
+				if err := applyReviewFixes(codeReview, os.Stdin); err != nil {
+					return err
+				}
+			}
 
-			// Save detailed report
-			reportPath := ".sdd/review_report.md"
-			if err := os.WriteFile(reportPath, []byte(reviewer.GetReviewReport(codeReview)), 0644); err != nil {
-				fmt.Printf("Warning: Failed to save review report: %v\n", err)
-			} else {
-				fmt.Printf("📄 Review report saved to: %s\n", reportPath)
+			return reviewFailure(cmd, codeReview, reviewFailOn)
+		},
+	}
+
+	cmd.PersistentFlags().BoolVar(&reviewDeep, "deep", false, "Have the QA agent review each file too, within the provider's rate limits")
+	cmd.PersistentFlags().StringArrayVar(&reviewInclude, "include", nil, "Only review paths matching this glob (repeatable)")
+	cmd.PersistentFlags().StringArrayVar(&reviewExclude, "exclude", nil, "Skip paths matching this glob (repeatable)")
+	cmd.PersistentFlags().StringVar(&reviewFailOn, "fail-on", "high", "Findings that fail the command: none, high, critical")
+	cmd.PersistentFlags().StringVar(&reviewFocus, "focus", review.FocusAll, "Area to review: security, performance, style or all")
+	cmd.PersistentFlags().BoolVar(&reviewFix, "fix", false, "Offer to apply the safe automatic fixes one by one")
+
+	cmd.AddCommand(newReviewDirCmd())
+	cmd.AddCommand(newReviewTrackCmd())
+
+	return cmd
+}
+
+func newReviewDirCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "dir <path>",
+		Short: "Review every source file under a directory",
+		Long: `Review all Go, JavaScript/TypeScript, Python and Rust files, Dockerfiles
+and YAML/JSON configuration under a directory as one review, e.g. to audit
+a module. Hidden, vendor and node_modules directories are skipped, as are
+lock files and paths ruled out by --include and --exclude.
+
+Example:
+  viki review dir internal/cli`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectRoot := "."
+
+			if err := validateFailOn(reviewFailOn); err != nil {
+				return err
+			}
+
+			reviewer, err := review.NewCodeReviewer(projectRoot)
+			if err != nil {
+				return fmt.Errorf("failed to create reviewer: %w", err)
+			}
+
+			if err := reviewer.SetFocus(reviewFocus); err != nil {
+				return err
+			}
+			if reviewDeep {
+				reviewer.EnableAIReview()
+			}
+
+			fmt.Printf("🤖 Reviewing every source file under %s...\n", args[0])
+			codeReview, err := reviewer.ReviewDirectory(args[0], analysis.NewPathFilter(reviewInclude, reviewExclude))
+			if err != nil {
+				return fmt.Errorf("review failed: %w", err)
+			}
+
+			finishReview(projectRoot, reviewer, codeReview)
+			if reviewFix {
+				if err := applyReviewFixes(codeReview, os.Stdin); err != nil {
+					return err
+				}
+			}
+
+			return reviewFailure(cmd, codeReview, reviewFailOn)
+		},
+	}
+}
+
+func newReviewTrackCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "track [trackID]",
+		Short: "Run the workflow's code review phase on a track",
+		Long: `Have the Guardian review the implementation of a track, the review phase
+of the enterprise workflow profile. The review is written to
+4_code_review.md and must be approved before validation runs.
+
+Workflows without a review phase reject this command.
+
+Example:
+  viki review track auth-feature`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trackID, _ := currentTrackAndPhase()
+			if len(args) > 0 {
+				trackID = args[0]
 			}
 
-			// Show approval status
-			showReviewStatus(codeReview)
+			agentSvc := agents.NewAgentService(".")
+			if err := agentSvc.Initialize(); err != nil {
+				return fmt.Errorf("failed to initialize agent service: %w", err)
+			}
+
+			fmt.Println("🛡️  Guardian is reviewing the implementation...")
+			if _, err := agentSvc.Orchestrate("review", trackID, ""); err != nil {
+				return fmt.Errorf("code review failed: %w", err)
+			}
 
+			fmt.Println(successStyle.Render("✅ Code review ready"))
+			fmt.Printf("📄 Review: %s\n", filepath.Join(gates.TracksDir("."), trackID, agents.ArtifactName(".", "review")))
+			fmt.Println("Next: Run 'viki approve --interactive' to review it, then 'viki validate'")
 			return nil
 		},
 	}
+}
+
+// applyReviewFixes offers each safe fix of the review, showing its patch,
+// and applies the accepted ones file by file
+func applyReviewFixes(codeReview *review.CodeReview, in io.Reader) error {
+	var offered int
+	for _, file := range codeReview.Files {
+		offered += len(file.SafeFixes())
+	}
+	if offered == 0 {
+		fmt.Println("\n🩹 No issues can be fixed automatically")
+		return nil
+	}
 
-	cmd.Flags().BoolVar(&reviewDeep, "deep", false, "Perform deep analysis with AI reasoning")
+	fmt.Printf("\n🩹 %d issue(s) can be fixed automatically\n", offered)
+	reader := bufio.NewReader(in)
+	applied := 0
+	ended := false
+	for _, file := range codeReview.Files {
+		var accepted []review.CodeIssue
+		for _, issue := range file.SafeFixes() {
+			if ended {
+				break
+			}
+			fmt.Printf("\n%s:%d: %s\n%s", file.Path, issue.Line, issue.Message, issue.Patch)
+			fmt.Print("   Apply this fix? [y/N]: ")
 
-	return cmd
+			answer, err := reader.ReadString('\n')
+			if err != nil && answer == "" {
+				// Input ended; the fixes accepted so far are still applied
+				fmt.Println()
+				ended = true
+				break
+			}
+			if answer = strings.ToLower(strings.TrimSpace(answer)); answer == "y" || answer == "yes" {
+				accepted = append(accepted, issue)
+			}
+		}
+		if len(accepted) == 0 {
+			continue
+		}
+
+		count, err := review.ApplyFixes(file.Path, accepted)
+		applied += count
+		if err != nil {
+			fmt.Printf("⚠️  %s: %v\n", file.Path, err)
+		}
+	}
+
+	fmt.Printf("\n✅ Applied %d of %d fix(es)\n", applied, offered)
+	return nil
+}
+
+// validateFailOn checks the value of --fail-on
+func validateFailOn(failOn string) error {
+	switch failOn {
+	case "none", "high", "critical":
+		return nil
+	default:
+		return fmt.Errorf("invalid --fail-on %q: use none, high or critical", failOn)
+	}
+}
+
+// reviewFailure returns the error that fails the command for the review's
+// findings: exit code 2 for critical issues and, unless only critical ones
+// fail, 1 when changes are requested or the review is blocked
+func reviewFailure(cmd *cobra.Command, codeReview *review.CodeReview, failOn string) error {
+	if failOn == "none" {
+		return nil
+	}
+
+	critical := 0
+	for _, file := range codeReview.Files {
+		for _, issue := range file.Issues {
+			if issue.Severity == "critical" {
+				critical++
+			}
+		}
+	}
+
+	// The findings were reported above; usage would only bury them
+	cmd.SilenceUsage = true
+	if critical > 0 {
+		return &ExitError{Code: 2, Err: fmt.Errorf("review found %d critical issue(s)", critical)}
+	}
+	if failOn == "high" && (codeReview.Summary.ApprovalStatus == "requested_changes" || codeReview.Summary.ApprovalStatus == "blocked") {
+		return &ExitError{Code: 1, Err: fmt.Errorf("review status is %s", codeReview.Summary.ApprovalStatus)}
+	}
+	return nil
+}
+
+// finishReview shows and saves the report of a review, feeds it into the
+// learning system, and shows its approval status
+func finishReview(projectRoot string, reviewer *review.CodeReviewer, codeReview *review.CodeReview) {
+	// Display results
+	fmt.Println(reviewer.GetReviewReport(codeReview))
+
+	// Save detailed report
+	reportPath := ".sdd/review_report.md"
+	if err := os.WriteFile(reportPath, []byte(reviewer.GetReviewReport(codeReview)), 0644); err != nil {
+		fmt.Printf("Warning: Failed to save review report: %v\n", err)
+	} else {
+		fmt.Printf("📄 Review report saved to: %s\n", reportPath)
+	}
+
+	// Feed the issues found into the learning system
+	if learner, err := learning.NewAdaptiveLearner(projectRoot); err != nil {
+		fmt.Printf("Warning: Failed to load learning data: %v\n", err)
+	} else if err := learner.LearnFromReview(codeReview); err != nil {
+		fmt.Printf("Warning: Failed to update learning data: %v\n", err)
+	}
+
+	// Show approval status
+	showReviewStatus(codeReview)
 }
 
 func showReviewStatus(review *review.CodeReview) {
@@ -4,9 +4,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"ultimate-sdd-framework/internal/agents"
 	"ultimate-sdd-framework/internal/db"
+	"ultimate-sdd-framework/internal/gates"
+	"ultimate-sdd-framework/internal/mcp"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -23,6 +30,7 @@ Sessions are stored in SQLite and can be:
 - Listed to see all conversations
 - Switched between
 - Exported to markdown
+- Searched by message content
 - Deleted when no longer needed`,
 	}
 
@@ -31,6 +39,8 @@ Sessions are stored in SQLite and can be:
 	cmd.AddCommand(newSessionDeleteCmd())
 	cmd.AddCommand(newSessionExportCmd())
 	cmd.AddCommand(newSessionNewCmd())
+	cmd.AddCommand(newSessionSearchCmd())
+	cmd.AddCommand(newSessionCompactCmd())
 
 	return cmd
 }
@@ -248,6 +258,195 @@ func newSessionNewCmd() *cobra.Command {
 	}
 }
 
+func newSessionSearchCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search message content across sessions",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			query := strings.Join(args, " ")
+
+			database, err := getDatabase()
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				return
+			}
+			defer database.Close()
+
+			store := db.NewSessionStore(database)
+			results, err := store.SearchSessions(query, limit)
+			if err != nil {
+				fmt.Printf("❌ Error searching sessions: %v\n", err)
+				return
+			}
+
+			if len(results) == 0 {
+				fmt.Printf("📭 No messages found matching %q\n", query)
+				return
+			}
+
+			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+			highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+			dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+			fmt.Println(titleStyle.Render(fmt.Sprintf("\n🔎 Results for %q", query)))
+			fmt.Println(dimStyle.Render("─────────────────────────────────────────────────"))
+
+			for _, r := range results {
+				fmt.Printf("%s %s\n",
+					highlightStyle.Render(r.SessionTitle),
+					dimStyle.Render(fmt.Sprintf("(%s, %s)", r.Role, formatAge(r.CreatedAt))))
+				fmt.Printf("   %s\n", r.Snippet)
+				fmt.Printf("   ID: %s  Message: %s\n", dimStyle.Render(r.SessionID), dimStyle.Render(r.MessageID))
+			}
+
+			fmt.Println()
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Maximum number of matches to show")
+
+	return cmd
+}
+
+func newSessionCompactCmd() *cobra.Command {
+	var (
+		maxTokens  int
+		keepRecent int
+		provider   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compact <session-id>",
+		Short: "Summarize old messages to fit the context window",
+		Long: `Compact a session that has grown beyond the model context window.
+
+The oldest messages are summarized by the AI into a single summary message,
+while the most recent turns are kept verbatim.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			sessionID := args[0]
+
+			database, err := getDatabase()
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				return
+			}
+			defer database.Close()
+
+			store := db.NewSessionStore(database)
+
+			session, err := store.GetByID(sessionID)
+			if err != nil || session == nil {
+				fmt.Printf("❌ Session not found: %s\n", sessionID)
+				return
+			}
+
+			mcpMgr := mcp.NewMCPManager(".")
+			if err := mcpMgr.LoadConfig(); err != nil {
+				fmt.Printf("❌ Error loading MCP config: %v\n", err)
+				return
+			}
+
+			client, err := mcpMgr.GetClient(provider)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				return
+			}
+
+			fmt.Println("🗜️  Compacting session...")
+			result, err := store.CompactMessages(sessionID, maxTokens, keepRecent, newAISummarizer(client, maxTokens/2))
+			if err != nil {
+				fmt.Printf("❌ Error compacting session: %v\n", err)
+				return
+			}
+
+			if result.SummarizedCount == 0 {
+				fmt.Printf("✅ Session already fits the window (~%d tokens)\n", result.TokensBefore)
+				return
+			}
+
+			fmt.Printf("✅ Compacted session: %s\n", session.Title)
+			fmt.Printf("   Summarized: %d messages\n", result.SummarizedCount)
+			fmt.Printf("   Kept verbatim: %d messages\n", result.KeptCount)
+			fmt.Printf("   Tokens: ~%d → ~%d\n", result.TokensBefore, result.TokensAfter)
+		},
+	}
+
+	cmd.Flags().IntVarP(&maxTokens, "max-tokens", "t", 8000, "Context window size in tokens")
+	cmd.Flags().IntVarP(&keepRecent, "keep", "k", 10, "Maximum number of recent messages to keep verbatim")
+	cmd.Flags().StringVarP(&provider, "provider", "p", "", "AI provider used to summarize (uses default if not specified)")
+
+	return cmd
+}
+
+// newAISummarizer summarizes messages with the given client, splitting the
+// transcript into chunks of at most chunkTokens so each request fits the
+// window. A message too long for one chunk is split across several rather
+// than cut short.
+func newAISummarizer(client *mcp.ModelClient, chunkTokens int) db.Summarizer {
+	return func(messages []*db.Message) (string, error) {
+		var chunks []string
+		var current strings.Builder
+		currentTokens := 0
+
+		for _, msg := range messages {
+			for _, entry := range splitText(fmt.Sprintf("%s: %s\n\n", msg.Role, msg.Content), chunkTokens*4) {
+				tokens := db.EstimateTokens(entry)
+				if currentTokens > 0 && currentTokens+tokens > chunkTokens {
+					chunks = append(chunks, current.String())
+					current.Reset()
+					currentTokens = 0
+				}
+				current.WriteString(entry)
+				currentTokens += tokens
+			}
+		}
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+		}
+
+		var summaries []string
+		for _, chunk := range chunks {
+			prompt := "Summarize the following conversation. Preserve decisions, requirements, " +
+				"open questions and any names, files or values that later turns may rely on. " +
+				"Be concise.\n\n" + chunk
+
+			resp, err := client.Chat([]mcp.Message{{Role: "user", Content: prompt}}, map[string]interface{}{
+				"temperature": 0.2,
+			})
+			if err != nil {
+				return "", err
+			}
+			if len(resp.Choices) == 0 {
+				return "", fmt.Errorf("no response from AI")
+			}
+			summaries = append(summaries, strings.TrimSpace(resp.Choices[0].Message.Content))
+		}
+
+		return strings.Join(summaries, "\n\n"), nil
+	}
+}
+
+// splitText splits text into pieces of at most maxBytes, cutting on rune
+// boundaries so each piece stays valid UTF-8
+func splitText(text string, maxBytes int) []string {
+	maxBytes = max(maxBytes, utf8.UTFMax)
+
+	var pieces []string
+	for len(text) > maxBytes {
+		cut := maxBytes
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		pieces = append(pieces, text[:cut])
+		text = text[cut:]
+	}
+	return append(pieces, text)
+}
+
 // NewWorkflowCmd creates the workflow command
 func NewWorkflowCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -265,60 +464,96 @@ Workflows guide you through multi-step development processes:
 	cmd.AddCommand(newWorkflowStatusCmd())
 	cmd.AddCommand(newWorkflowNextCmd())
 	cmd.AddCommand(newWorkflowListCmd())
+	cmd.AddCommand(newWorkflowDiffCmd())
+	cmd.AddCommand(newWorkflowMarkCmd())
 
 	return cmd
 }
 
 func newWorkflowInitCmd() *cobra.Command {
-	return &cobra.Command{
+	var scale string
+
+	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize workflow for current project",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("🔍 Analyzing project to recommend workflow track...")
+		Long: `Choose the workflow profile the orchestrator follows.
 
-			wd, _ := os.Getwd()
+Profiles (phase sets) can be added or overridden under 'profiles' in
+.sdd/workflow.yaml. A profile may rename the artifacts its phases produce,
+and gates follow the new names:
+
+  profiles:
+    team:
+      phases: [discover, specify, design, task, execute, validate]
+      artifacts: {specify: prd.md, design: architecture.md}
 
-			// Simple track detection
-			fileCount := 0
-			filepath.Walk(wd, func(path string, info os.FileInfo, err error) error {
-				if err != nil || info.IsDir() {
+Without --scale, a profile is recommended from the size of the project.
+
+Examples:
+  viki workflow init
+  viki workflow init --scale quick`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+			highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+
+			if scale == "" {
+				fmt.Println("🔍 Analyzing project to recommend workflow track...")
+
+				wd, _ := os.Getwd()
+
+				// Simple track detection
+				fileCount := 0
+				filepath.Walk(wd, func(path string, info os.FileInfo, err error) error {
+					if err != nil || info.IsDir() {
+						return nil
+					}
+					ext := filepath.Ext(path)
+					if ext == ".go" || ext == ".py" || ext == ".js" || ext == ".ts" {
+						fileCount++
+					}
 					return nil
+				})
+
+				switch {
+				case fileCount > 50:
+					scale = "enterprise"
+				case fileCount > 10:
+					scale = "standard"
+				default:
+					scale = "quick"
 				}
-				ext := filepath.Ext(path)
-				if ext == ".go" || ext == ".py" || ext == ".js" || ext == ".ts" {
-					fileCount++
-				}
-				return nil
-			})
-
-			var track string
-			var timeEstimate string
+				fmt.Printf("Files Analyzed: %d\n", fileCount)
+			}
 
-			switch {
-			case fileCount > 50:
-				track = "Enterprise"
-				timeEstimate = "~30 minutes"
-			case fileCount > 10:
-				track = "Standard Method"
-				timeEstimate = "~15 minutes"
-			default:
-				track = "Quick Flow"
-				timeEstimate = "~5 minutes"
+			if _, err := os.Stat(".sdd"); os.IsNotExist(err) {
+				return fmt.Errorf("project not initialized. Run 'viki init' first")
+			}
+			if err := agents.SetWorkflowScale(".", scale); err != nil {
+				return err
 			}
 
-			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
-			highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+			profile, err := agents.LoadWorkflow(".")
+			if err != nil {
+				return err
+			}
 
 			fmt.Println()
-			fmt.Println(titleStyle.Render("📋 Workflow Recommendation"))
+			fmt.Println(titleStyle.Render("📋 Workflow Profile"))
 			fmt.Println("─────────────────────────────────────────────────")
-			fmt.Printf("Recommended Track: %s\n", highlightStyle.Render(track))
-			fmt.Printf("Time to First Story: %s\n", timeEstimate)
-			fmt.Printf("Files Analyzed: %d\n", fileCount)
+			fmt.Printf("Scale:  %s\n", highlightStyle.Render(profile.Name))
+			if profile.Description != "" {
+				fmt.Printf("        %s\n", profile.Description)
+			}
+			fmt.Printf("Phases: %s\n", strings.Join(profile.Phases, " → "))
 			fmt.Println()
 			fmt.Println("💡 Run 'viki workflow next' to start the first step")
+			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&scale, "scale", "", "Workflow profile to follow (quick, standard, enterprise or a custom profile)")
+
+	return cmd
 }
 
 func newWorkflowStatusCmd() *cobra.Command {
@@ -405,35 +640,140 @@ func newWorkflowListCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
 		Short: "List available workflows",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
 			highlightStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
 			dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 
+			profiles, err := agents.WorkflowProfiles(".")
+			if err != nil {
+				return err
+			}
+			current := ""
+			if profile, err := agents.LoadWorkflow("."); err == nil {
+				current = profile.Name
+			}
+
+			// Built-in profiles first, from the lightest to the heaviest
+			names := []string{"quick", "standard", "enterprise"}
+			var custom []string
+			for name := range profiles {
+				if !slices.Contains(names, name) {
+					custom = append(custom, name)
+				}
+			}
+			sort.Strings(custom)
+			names = append(names, custom...)
+
 			fmt.Println()
 			fmt.Println(titleStyle.Render("📋 Available Workflow Tracks"))
 			fmt.Println("─────────────────────────────────────────────────")
 
-			tracks := []struct {
-				name string
-				desc string
-				time string
-			}{
-				{"Quick Flow", "Bug fixes, small features", "~5 minutes"},
-				{"Standard Method", "Products and platforms", "~15 minutes"},
-				{"Enterprise", "Compliance-heavy systems", "~30 minutes"},
+			for _, name := range names {
+				profile := profiles[name]
+				label := name
+				if name == current {
+					label += " (current)"
+				}
+				fmt.Printf("\n%s\n", highlightStyle.Render(label))
+				if profile.Description != "" {
+					fmt.Printf("   %s\n", dimStyle.Render(profile.Description))
+				}
+				fmt.Printf("   Phases: %s\n", strings.Join(profile.Phases, " → "))
+				if len(profile.Artifacts) > 0 {
+					var renamed []string
+					for _, phase := range agents.WorkflowPhases {
+						if _, ok := profile.Artifacts[phase]; ok {
+							renamed = append(renamed, fmt.Sprintf("%s → %s", phase, profile.Artifacts[phase]))
+						}
+					}
+					fmt.Printf("   Artifacts: %s\n", strings.Join(renamed, ", "))
+				}
+			}
+			fmt.Println()
+			return nil
+		},
+	}
+}
+
+func newWorkflowDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <trackID> <artifact>",
+		Short: "Show changes between the two latest versions of an artifact",
+		Long: `Show a unified diff between an artifact and its previous revision.
+
+Previous revisions are kept under .sdd/tracks/<trackID>/history/ each time
+an agent regenerates an artifact.
+
+Example:
+  viki workflow diff user-auth 2_architecture.md`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			diff, err := gates.DiffArtifact(".", args[0], args[1])
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				return
 			}
 
-			for _, t := range tracks {
-				fmt.Printf("\n%s\n", highlightStyle.Render(t.name))
-				fmt.Printf("   %s\n", dimStyle.Render(t.desc))
-				fmt.Printf("   Time to First Story: %s\n", t.time)
+			addStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+			delStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+			hunkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("99"))
+
+			for i, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+				switch {
+				case i < 2:
+					fmt.Println(line)
+				case strings.HasPrefix(line, "@@"):
+					fmt.Println(hunkStyle.Render(line))
+				case strings.HasPrefix(line, "+"):
+					fmt.Println(addStyle.Render(line))
+				case strings.HasPrefix(line, "-"):
+					fmt.Println(delStyle.Render(line))
+				default:
+					fmt.Println(line)
+				}
 			}
-			fmt.Println()
 		},
 	}
 }
 
+func newWorkflowMarkCmd() *cobra.Command {
+	var reviewer string
+
+	cmd := &cobra.Command{
+		Use:   "mark <trackID> <artifact> <status>",
+		Short: "Set the status of a track artifact",
+		Long: `Set the status in an artifact's frontmatter, e.g. to show it is being
+reviewed. Besides PENDING, APPROVED and REJECTED, the statuses listed under
+'statuses' in .sdd/gates.yaml are allowed:
+
+  statuses: [IN_REVIEW]
+
+Only APPROVED lets the next phase start; use 'viki reject' to send an
+artifact back with feedback.
+
+Example:
+  viki workflow mark user-auth 1_prd.md IN_REVIEW --reviewer alice`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trackID, artifact, status := args[0], args[1], strings.ToUpper(args[2])
+			if err := gates.MarkArtifact(".", trackID, artifact, status, reviewer); err != nil {
+				return fmt.Errorf("failed to mark artifact: %w", err)
+			}
+
+			fmt.Printf("✅ %s/%s → %s\n", trackID, artifact, status)
+			if reviewer != "" {
+				fmt.Printf("Reviewer: %s\n", reviewer)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&reviewer, "reviewer", "", "Who is reviewing the artifact")
+
+	return cmd
+}
+
 func getDatabase() (*db.DB, error) {
 	homeDir, _ := os.UserHomeDir()
 	cfg := db.Config{
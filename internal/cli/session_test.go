@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxBytes int
+		want     int
+	}{
+		{"fits", "short", 16, 1},
+		{"exact multiple", strings.Repeat("a", 32), 16, 2},
+		{"remainder", strings.Repeat("a", 33), 16, 3},
+		{"multibyte runes kept whole", strings.Repeat("é", 10), 5, 5},
+		{"limit below a rune", strings.Repeat("€", 3), 1, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pieces := splitText(tt.text, tt.maxBytes)
+			if len(pieces) != tt.want {
+				t.Errorf("splitText() = %d pieces, want %d", len(pieces), tt.want)
+			}
+			if joined := strings.Join(pieces, ""); joined != tt.text {
+				t.Errorf("splitText() lost content: %q", joined)
+			}
+			for _, piece := range pieces {
+				if !utf8.ValidString(piece) {
+					t.Errorf("piece %q is not valid UTF-8", piece)
+				}
+			}
+		})
+	}
+}
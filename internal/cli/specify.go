@@ -13,7 +13,10 @@ import (
 )
 
 func NewSpecifyCmd() *cobra.Command {
-	var useTUI bool
+	var (
+		useTUI bool
+		revise string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "specify [description]",
@@ -32,11 +35,22 @@ Examples:
 • "Create a simple blog with posts and comments"
 • "Make a weather app that shows the forecast for my city"
 
-Don't worry about technical details - just describe what you want! ✨`,
-		Args: cobra.MinimumNArgs(1),
+Don't worry about technical details - just describe what you want! ✨
+
+In an existing codebase Viki also shares its most relevant files: entry
+points and configuration first, at most 20 files under 5000 bytes. Tune
+this in .sdd/codebase.json:
+  {"key_files": {"max_size": 8000, "max_files": 30}}`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			description := strings.Join(args, " ")
 
+			if revise != "" {
+				return runRevision("specify", revise, description)
+			}
+			if len(args) == 0 {
+				return fmt.Errorf("requires a description of what you want to build")
+			}
+
 			// Check project state
 			stateMgr := gates.NewStateManager(".")
 			state, err := stateMgr.LoadState()
@@ -79,10 +93,11 @@ Don't worry about technical details - just describe what you want! ✨`,
 			}
 
 			// Generate specifications using AI
-			specContent, err := agentSvc.GetAgentResponse("strategist", "specify", description, "", "")
+			specContent, err := agentSvc.GetAgentResponse("strategist", "specify", description, agentSvc.CodebaseContext("specify")+agentSvc.VisionContext(), "")
 			if err != nil {
 				return fmt.Errorf("🤔 Viki had trouble understanding your request. Try rephrasing it or check your AI provider setup: %w", err)
 			}
+			agentSvc.WarnVisionDivergence("Specification", specContent)
 
 			// Add Status to specification
 			specContentWithStatus := fmt.Sprintf("---\nstatus: pending\n---\n\n%s", specContent)
@@ -106,6 +121,7 @@ Don't worry about technical details - just describe what you want! ✨`,
 	}
 
 	cmd.Flags().BoolVarP(&useTUI, "tui", "t", false, "Use terminal UI for specification creation")
+	cmd.Flags().StringVar(&revise, "revise", "", "Regenerate the rejected PRD of this track using its feedback")
 
 	return cmd
 }
@@ -2,22 +2,35 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
 	"ultimate-sdd-framework/internal/gates"
 	"ultimate-sdd-framework/internal/ui"
 )
 
 func NewStatusCmd() *cobra.Command {
+	var dashboard bool
+
 	cmd := &cobra.Command{
 		Use:   "status",
-		Short: "Launch Nexus UI Dashboard",
-		Long:  "Launch the interactive Nexus UI Dashboard to manage project status and workflow.",
+		Short: "Show every track and its gate state",
+		Long: `Show each track in .sdd/tracks with its current phase, the next phase,
+and whether it is waiting on an approval or a revision, followed by overall
+project stats.
+
+Use --ui to launch the interactive Nexus UI Dashboard instead.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Initialize state manager
 			stateMgr := gates.NewStateManager(".")
 
+			if !dashboard {
+				return printTrackStatus(stateMgr)
+			}
+
 			// Initialize UI model
 			model, err := ui.NewSDDModel(stateMgr)
 			if err != nil {
@@ -37,5 +50,98 @@ func NewStatusCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&dashboard, "ui", false, "Launch the interactive dashboard")
+
 	return cmd
 }
+
+// printTrackStatus renders the table of tracks and the project totals
+func printTrackStatus(stateMgr *gates.StateManager) error {
+	state, err := stateMgr.LoadState()
+	if err != nil {
+		return fmt.Errorf("project not initialized: %w", err)
+	}
+
+	tracks, err := agents.TrackStatuses(".")
+	if err != nil {
+		return fmt.Errorf("failed to read tracks: %w", err)
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+	headerStyle := lipgloss.NewStyle().Bold(true)
+	approvedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	pendingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+	blockedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("📊 %s", state.ProjectName)))
+	fmt.Println()
+
+	if len(tracks) == 0 {
+		fmt.Println(dimStyle.Render("No tracks yet. Run 'viki specify' to start one."))
+		return nil
+	}
+
+	idWidth := len("TRACK")
+	for _, track := range tracks {
+		idWidth = max(idWidth, len(track.ID))
+	}
+	row := fmt.Sprintf("%%-%ds  %%-10s  %%-10s  %%-24s  %%s", idWidth)
+
+	fmt.Println(headerStyle.Render(fmt.Sprintf(row, "TRACK", "PHASE", "NEXT", "GATE", "STATE")))
+	fmt.Println(dimStyle.Render(strings.Repeat("─", idWidth+70)))
+
+	awaiting, blocked, approved := 0, 0, 0
+	for _, track := range tracks {
+		approved += track.Approved
+
+		phase, gate, next := orDash(track.Phase), orDash(track.Artifact), track.NextPhase
+		if next == "" {
+			next = "done"
+		}
+
+		var label string
+		switch {
+		case track.AwaitingApproval:
+			awaiting++
+			if track.Status == gates.ArtifactPending {
+				label = pendingStyle.Render("⏳ awaiting approval")
+			} else {
+				label = pendingStyle.Render(fmt.Sprintf("⏳ awaiting approval (%s)", track.Status))
+			}
+		case track.Blocked:
+			blocked++
+			label = blockedStyle.Render("❌ rejected, needs revision")
+		case track.Status == gates.ArtifactApproved:
+			label = approvedStyle.Render("✅ approved, ready for " + next)
+		case track.Phase == "":
+			label = dimStyle.Render("not started")
+		default:
+			label = dimStyle.Render("no gate status")
+		}
+
+		fmt.Printf(row+"\n", track.ID, phase, next, gate, label)
+	}
+
+	fmt.Println()
+	fmt.Println(headerStyle.Render("Project"))
+	fmt.Printf("  Phase:              %s\n", state.CurrentPhase)
+	fmt.Printf("  Tracks:             %d\n", len(tracks))
+	fmt.Printf("  Awaiting approval:  %d\n", awaiting)
+	fmt.Printf("  Needing revision:   %d\n", blocked)
+	fmt.Printf("  Approved artifacts: %d\n", approved)
+
+	if awaiting > 0 {
+		fmt.Println()
+		fmt.Println(dimStyle.Render("💡 Run 'viki approve --interactive' to review pending artifacts"))
+	}
+
+	return nil
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
+)
+
+// EnableSuggestions makes every command that only groups subcommands, the
+// root included, reject an unknown subcommand with the one it most likely
+// meant instead of printing its help or a bare error
+func EnableSuggestions(root *cobra.Command) {
+	if root.HasSubCommands() && !root.Runnable() {
+		root.Args = cobra.ArbitraryArgs
+		root.RunE = func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			cmd.SilenceUsage = true
+			return unknownCommandError(cmd, args[0])
+		}
+	}
+	for _, sub := range root.Commands() {
+		EnableSuggestions(sub)
+	}
+}
+
+// unknownCommandError reports a subcommand cmd does not have, suggesting the
+// closest of its subcommands and their aliases
+func unknownCommandError(cmd *cobra.Command, name string) error {
+	var names []string
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() {
+			names = append(names, sub.Name())
+			names = append(names, sub.Aliases...)
+		}
+	}
+
+	if suggestion := agents.ClosestMatch(name, names); suggestion != "" {
+		return fmt.Errorf("unknown command %q for %q - did you mean %q?\nRun '%s --help' for usage", name, cmd.CommandPath(), suggestion, cmd.CommandPath())
+	}
+	return fmt.Errorf("unknown command %q for %q\nRun '%s --help' for usage", name, cmd.CommandPath(), cmd.CommandPath())
+}
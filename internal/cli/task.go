@@ -3,13 +3,17 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"ultimate-sdd-framework/internal/agents"
 	"ultimate-sdd-framework/internal/gates"
 )
 
 func NewTaskCmd() *cobra.Command {
+	var revise string
+
 	cmd := &cobra.Command{
 		Use:   "task",
 		Short: "Break down plan into actionable tasks",
@@ -18,6 +22,10 @@ func NewTaskCmd() *cobra.Command {
 This command uses the Developer agent to convert the high-level plan
 into specific, actionable tasks with clear deliverables and acceptance criteria.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if revise != "" {
+				return runRevision("task", revise, "")
+			}
+
 			// Check project state
 			stateMgr := gates.NewStateManager(".")
 			state, err := stateMgr.LoadState()
@@ -82,7 +90,7 @@ into specific, actionable tasks with clear deliverables and acceptance criteria.
 
 			// Complete phase
 			// We point to gsd.json instead of tasks.md
-			if err := stateMgr.CompletePhase([]string{"gsd.json"}); err != nil {
+			if err := stateMgr.CompletePhase([]string{agents.ArtifactName(".", "task")}); err != nil {
 				return fmt.Errorf("failed to complete task phase: %w", err)
 			}
 
@@ -93,5 +101,96 @@ into specific, actionable tasks with clear deliverables and acceptance criteria.
 		},
 	}
 
+	cmd.Flags().StringVar(&revise, "revise", "", "Regenerate the rejected task checklist of this track using its feedback")
+
+	cmd.AddCommand(newTaskEstimateSubCmd())
+
+	return cmd
+}
+
+func newTaskEstimateSubCmd() *cobra.Command {
+	var price float64
+
+	cmd := &cobra.Command{
+		Use:   "estimate [trackID]",
+		Short: "Forecast tasks, files, risk and AI cost before execute",
+		Long: `Read the track's gsd.json and forecast its execution without calling an AI
+provider: the files each task touches (its "files" list or paths named in its
+title), a risk rating from the complexity and maintainability of those that
+already exist, and the AI calls and tokens execution will take.
+
+Pass --price (USD per 1K tokens) to turn the token total into a cost.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trackID := "feature-implementation"
+			if len(args) > 0 {
+				trackID = args[0]
+			} else if state, err := gates.NewStateManager(".").LoadState(); err == nil && state.Metadata != nil {
+				if t, ok := state.Metadata["current_track"].(string); ok && t != "" {
+					trackID = t
+				}
+			}
+
+			estimate, err := agents.EstimateTrack(".", trackID)
+			if err != nil {
+				return fmt.Errorf("estimate failed: %w", err)
+			}
+
+			titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("99"))
+			headerStyle := lipgloss.NewStyle().Bold(true)
+			dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+			riskStyles := map[string]lipgloss.Style{
+				agents.RiskLow:    lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
+				agents.RiskMedium: lipgloss.NewStyle().Foreground(lipgloss.Color("220")),
+				agents.RiskHigh:   lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+			}
+
+			fmt.Println(titleStyle.Render(fmt.Sprintf("📐 Execution estimate: %s", trackID)))
+			fmt.Println()
+
+			fmt.Println(headerStyle.Render(fmt.Sprintf("%-4s  %-44s  %-6s  %-8s  %5s  %8s", "ID", "TASK", "FILES", "RISK", "CALLS", "TOKENS")))
+			fmt.Println(dimStyle.Render(strings.Repeat("─", 84)))
+
+			pending := 0
+			for _, task := range estimate.Tasks {
+				title := task.Title
+				if len(title) > 44 {
+					title = title[:41] + "..."
+				}
+				files := fmt.Sprintf("%d", len(task.Files))
+				if task.NewFiles > 0 {
+					files = fmt.Sprintf("%d+%d", len(task.Files)-task.NewFiles, task.NewFiles)
+				}
+				risk := riskStyles[task.Risk].Render(fmt.Sprintf("%-8s", task.Risk))
+				if task.Done {
+					risk = dimStyle.Render(fmt.Sprintf("%-8s", "done"))
+				} else {
+					pending++
+				}
+
+				fmt.Printf("%-4s  %-44s  %-6s  %s  %5d  %8d\n", task.ID, title, files, risk, task.AICalls, task.Tokens)
+				if len(task.Reasons) > 0 && !task.Done {
+					fmt.Println(dimStyle.Render("      " + strings.Join(task.Reasons, "; ")))
+				}
+			}
+
+			fmt.Println()
+			fmt.Printf("Tasks:          %d (%d pending)\n", len(estimate.Tasks), pending)
+			fmt.Printf("Files touched:  %d\n", estimate.Files)
+			fmt.Printf("AI calls:       %d\n", estimate.AICalls)
+			fmt.Printf("Tokens:         ~%d\n", estimate.Tokens)
+			if price > 0 {
+				fmt.Printf("Projected cost: $%.2f (at $%g per 1K tokens)\n", float64(estimate.Tokens)/1000*price, price)
+			} else {
+				fmt.Printf("Projected cost: %d AI calls, ~%d tokens %s\n", estimate.AICalls, estimate.Tokens, dimStyle.Render("(pass --price for a dollar figure)"))
+			}
+			fmt.Println(dimStyle.Render("Files are shown as existing+new."))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Float64Var(&price, "price", 0, "Price in USD per 1K tokens of the configured model")
+
 	return cmd
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"ultimate-sdd-framework/internal/collaboration"
+	"ultimate-sdd-framework/internal/config"
 )
 
 var (
@@ -32,6 +33,10 @@ var (
 	patternUseCase  string
 	searchQuery     string
 	searchCategory  string
+	reportFormat    string
+	teamActor       string
+	knowledgeFull   bool
+	knowledgeWidth  int
 )
 
 func NewTeamCmd() *cobra.Command {
@@ -44,9 +49,16 @@ func NewTeamCmd() *cobra.Command {
 - Knowledge base management
 - Code pattern sharing
 - Decision logging
-- Collaborative development workflows`,
+- Collaborative development workflows
+
+Rules, knowledge and patterns are attributed to the member given with --as
+(name or email), whose last activity is then updated. Without --as they are
+attributed to $VIKI_USER, the user set with 'viki config set user <email>',
+git's user.email or the login name, in that order.`,
 	}
 
+	cmd.PersistentFlags().StringVar(&teamActor, "as", "", "Team member performing the action (name or email)")
+
 	// Subcommands
 	cmd.AddCommand(NewTeamInitCmd())
 	cmd.AddCommand(NewTeamMemberCmd())
@@ -56,6 +68,7 @@ func NewTeamCmd() *cobra.Command {
 	cmd.AddCommand(NewTeamDecisionCmd())
 	cmd.AddCommand(NewTeamSearchCmd())
 	cmd.AddCommand(NewTeamReportCmd())
+	cmd.AddCommand(NewTeamImportCmd())
 
 	return cmd
 }
@@ -228,7 +241,7 @@ func NewTeamRuleAddCmd() *cobra.Command {
 			}
 
 			// Add rule
-			rule, err := teamCollab.AddTeamRule(ruleCategory, ruleTitle, ruleDescription, ruleSeverity, "current_user", ruleExamples)
+			rule, err := teamCollab.AddTeamRule(ruleCategory, ruleTitle, ruleDescription, ruleSeverity, actingMember(), ruleExamples)
 			if err != nil {
 				return fmt.Errorf("failed to add team rule: %w", err)
 			}
@@ -306,6 +319,8 @@ func NewTeamKnowledgeCmd() *cobra.Command {
 
 	cmd.AddCommand(NewTeamKnowledgeAddCmd())
 	cmd.AddCommand(NewTeamKnowledgeListCmd())
+	cmd.AddCommand(NewTeamKnowledgeViewCmd())
+	cmd.AddCommand(NewTeamKnowledgeHelpfulCmd())
 
 	return cmd
 }
@@ -341,7 +356,7 @@ func NewTeamKnowledgeAddCmd() *cobra.Command {
 			}
 
 			// Add knowledge
-			item, err := teamCollab.AddKnowledgeItem(knowledgeTitle, knowledgeContent, knowledgeCategory, "current_user", knowledgeTags)
+			item, err := teamCollab.AddKnowledgeItem(knowledgeTitle, knowledgeContent, knowledgeCategory, actingMember(), knowledgeTags)
 			if err != nil {
 				return fmt.Errorf("failed to add knowledge: %w", err)
 			}
@@ -367,7 +382,7 @@ func NewTeamKnowledgeListCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List team knowledge",
-		Long:  "Display items from the team knowledge base.",
+		Long:  "Display items from the team knowledge base, most helpful first. Content is shortened to --truncate characters unless --full is given; 'team knowledge show <id>' prints one item in full.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectRoot := "."
 
@@ -391,8 +406,21 @@ func NewTeamKnowledgeListCmd() *cobra.Command {
 			for category, items := range knowledgeCategories {
 				if len(items) > 0 {
 					fmt.Printf("\n### %s (%d items)\n", category, len(items))
+					// Most helpful first
+					items = append([]collaboration.KnowledgeItem(nil), items...)
+					collaboration.SortByHelpfulness(items)
 					for _, item := range items {
-						fmt.Printf("  • **%s**: %s\n", item.Title, truncateString(item.Content, 100))
+						if knowledgeFull {
+							fmt.Printf("  • **%s** [%s]:\n", item.Title, item.ID)
+							for _, line := range strings.Split(item.Content, "\n") {
+								fmt.Printf("    %s\n", line)
+							}
+						} else {
+							fmt.Printf("  • **%s** [%s]: %s\n", item.Title, item.ID, truncateString(item.Content, knowledgeWidth))
+						}
+						if item.Helpful > 0 || item.Views > 0 {
+							fmt.Printf("    👍 %d helpful, 👀 %d views\n", item.Helpful, item.Views)
+						}
 					}
 					totalItems += len(items)
 				}
@@ -415,6 +443,77 @@ func NewTeamKnowledgeListCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&knowledgeFull, "full", false, "Show each item's full content")
+	cmd.Flags().IntVar(&knowledgeWidth, "truncate", 100, "Shorten content to this many characters")
+
+	return cmd
+}
+
+func NewTeamKnowledgeViewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "view <id>",
+		Aliases: []string{"show"},
+		Short:   "Read a knowledge item in full",
+		Long:    "Print a knowledge item's full content and details and count the view. IDs are shown by 'team knowledge list'.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectRoot := "."
+
+			teamCollab, err := collaboration.NewTeamCollaboration(projectRoot)
+			if err != nil {
+				return fmt.Errorf("failed to initialize team collaboration: %w", err)
+			}
+
+			item, err := teamCollab.RecordView(args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("🧠 %s\n", item.Title)
+			fmt.Printf("ID: %s\n", item.ID)
+			fmt.Printf("Category: %s\n", item.Category)
+			fmt.Printf("Author: %s\n", item.Author)
+			if len(item.Tags) > 0 {
+				fmt.Printf("Tags: %s\n", strings.Join(item.Tags, ", "))
+			}
+			fmt.Printf("Created: %s, updated: %s\n", item.Created.Format("2006-01-02"), item.Updated.Format("2006-01-02"))
+			fmt.Printf("👍 %d helpful, 👀 %d views\n\n", item.Helpful, item.Views)
+			fmt.Println(item.Content)
+			fmt.Println()
+			fmt.Printf("Found it useful? Run: viki team knowledge helpful %s\n", item.ID)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func NewTeamKnowledgeHelpfulCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "helpful <id>",
+		Short: "Mark a knowledge item as helpful",
+		Long:  "Count a knowledge item as helpful; the most helpful items are listed first.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectRoot := "."
+
+			teamCollab, err := collaboration.NewTeamCollaboration(projectRoot)
+			if err != nil {
+				return fmt.Errorf("failed to initialize team collaboration: %w", err)
+			}
+
+			item, err := teamCollab.MarkHelpful(args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("👍 Marked helpful: %s (%d helpful)\n", item.Title, item.Helpful)
+
+			return nil
+		},
+	}
+
 	return cmd
 }
 
@@ -455,7 +554,7 @@ func NewTeamPatternAddCmd() *cobra.Command {
 			}
 
 			// Add pattern
-			pattern, err := teamCollab.AddCodePattern(patternName, patternDesc, patternLang, patternCode, patternUseCase, "current_user")
+			pattern, err := teamCollab.AddCodePattern(patternName, patternDesc, patternLang, patternCode, patternUseCase, actingMember())
 			if err != nil {
 				return fmt.Errorf("failed to add pattern: %w", err)
 			}
@@ -591,11 +690,17 @@ func NewTeamReportCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "report",
 		Short: "Generate team collaboration report",
-		Long:  "Create a comprehensive report of team activities, knowledge, and collaboration metrics.",
+		Long: `Create a comprehensive report of team activities, knowledge, and collaboration metrics.
+
+With --format json the report is printed as structured data for dashboards
+and integrations: member and project counts, rule and knowledge counts by
+category, the most used code patterns and the latest decisions.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectRoot := "."
 
-			fmt.Println("📊 Generating team collaboration report...")
+			if reportFormat != "markdown" && reportFormat != "json" {
+				return fmt.Errorf("unknown report format %q (use markdown or json)", reportFormat)
+			}
 
 			// Create team collaboration
 			teamCollab, err := collaboration.NewTeamCollaboration(projectRoot)
@@ -603,6 +708,23 @@ func NewTeamReportCmd() *cobra.Command {
 				return fmt.Errorf("failed to initialize team collaboration: %w", err)
 			}
 
+			// JSON goes to stdout alone so it can be piped
+			if reportFormat == "json" {
+				report, err := teamCollab.GenerateTeamReportJSON()
+				if err != nil {
+					return err
+				}
+				fmt.Println(report)
+
+				reportPath := ".sdd/team_report.json"
+				if err := os.WriteFile(reportPath, []byte(report+"\n"), 0644); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Failed to save team report: %v\n", err)
+				}
+				return nil
+			}
+
+			fmt.Println("📊 Generating team collaboration report...")
+
 			// Generate report
 			report := teamCollab.GenerateTeamReport()
 
@@ -621,19 +743,81 @@ func NewTeamReportCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVar(&reportFormat, "format", "markdown", "Report format: markdown, json")
+
+	return cmd
+}
+
+func NewTeamImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <team.json>",
+		Short: "Merge another team's rules and knowledge",
+		Long: `Merge the rules, knowledge items and code patterns of another team's
+team.json into this team, e.g. to inherit organization standards in a new
+project.
+
+Entries the team already has are not duplicated: rules match by title within
+their category, knowledge items by title and code patterns by name, and the
+imported votes, views and usage counts are added to the existing entry.
+
+Example:
+  viki team import ../standards/.sdd/team.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectRoot := "."
+
+			teamCollab, err := collaboration.NewTeamCollaboration(projectRoot)
+			if err != nil {
+				return fmt.Errorf("failed to initialize team collaboration: %w", err)
+			}
+
+			summary, err := teamCollab.ImportTeam(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to import team: %w", err)
+			}
+
+			fmt.Printf("📥 Imported %s\n", args[0])
+			fmt.Printf("  📋 Rules: %d added, %d merged\n", summary.RulesAdded, summary.RulesMerged)
+			fmt.Printf("  🧠 Knowledge: %d added, %d merged\n", summary.KnowledgeAdded, summary.KnowledgeMerged)
+			fmt.Printf("  🔧 Code patterns: %d added, %d merged\n", summary.PatternsAdded, summary.PatternsMerged)
+
+			return nil
+		},
+	}
+
 	return cmd
 }
 
 // Helper functions
 
+// actingMember returns who the team command acts as: --as, or else the
+// resolved user identity
+func actingMember() string {
+	if teamActor != "" {
+		return teamActor
+	}
+	return config.ResolveUser(".")
+}
+
 func readFromStdin() (string, error) {
 	// Simple implementation - would need proper stdin reading
 	return "", fmt.Errorf("stdin reading not implemented yet")
 }
 
+// truncateString shortens s to at most maxLen characters, ending in "..."
+// when it is cut
 func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	// Count characters, not bytes, so multibyte runes are never split
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	if maxLen <= 0 {
+		return ""
+	}
+	if maxLen <= 3 {
+		// No room for an ellipsis
+		return string(runes[:maxLen])
+	}
+	return string(runes[:maxLen-3]) + "..."
 }
\ No newline at end of file
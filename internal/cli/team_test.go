@@ -0,0 +1,31 @@
+package cli
+
+import "testing"
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		maxLen int
+		want   string
+	}{
+		{"fits", "hello", 10, "hello"},
+		{"exact length", "hello", 5, "hello"},
+		{"truncated with ellipsis", "hello world", 8, "hello..."},
+		{"multibyte runes kept whole", "héllo wörld", 8, "héllo..."},
+		{"emoji kept whole", "🚀🚀🚀🚀🚀", 4, "🚀..."},
+		{"no room for an ellipsis", "hello", 3, "hel"},
+		{"one rune", "héllo", 1, "h"},
+		{"zero length", "hello", 0, ""},
+		{"negative length", "hello", -1, ""},
+		{"empty string", "", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateString(tt.s, tt.maxLen); got != tt.want {
+				t.Errorf("truncateString(%q, %d) = %q, want %q", tt.s, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"ultimate-sdd-framework/internal/editor"
+	"ultimate-sdd-framework/internal/secrets"
+
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
@@ -32,20 +35,38 @@ func NewUndoCmd() *cobra.Command {
 		Short: "⏪ Undo recent file changes",
 		Long: `Rollback file changes made by Viki.
 
-Viki automatically backs up files before modifying them. This command
-lets you restore previous versions.
+Every 'viki execute' and 'viki execute tasks' run is recorded as one
+operation on the undo stack in .sdd/undo, with a snapshot of each file it
+touched: generated source files, the track's execution.md and gsd.json, and
+the project state. Undoing an operation
+restores those files exactly as they were before it ran, newest first.
+Backups in .sdd/history from earlier versions can still be restored.
 
 Examples:
-  viki undo          # Undo the last change
-  viki undo 3        # Undo the last 3 changes
-  viki undo --list   # Show change history
+  viki undo              # Undo the last operation
+  viki undo --steps 2    # Undo the last 2 operations
+  viki undo --list       # Show the undo stack
+  viki redo              # Replay the last undone operation
   viki undo --restore <backup-file>  # Restore specific backup`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			historyDir := filepath.Join(".sdd", "history")
+			undoStack := editor.NewUndoStack(".")
+
+			ops, err := undoStack.List()
+			if err != nil {
+				return err
+			}
 
 			if listAll {
-				return listHistory(historyDir)
+				undone, err := undoStack.Undone()
+				if err != nil {
+					return err
+				}
+				if len(ops) == 0 && len(undone) == 0 {
+					return listHistory(historyDir)
+				}
+				return listUndoStack(ops, undone)
 			}
 
 			if restore != "" {
@@ -60,17 +81,115 @@ Examples:
 				steps = 1
 			}
 
-			return undoChanges(historyDir, steps)
+			if len(ops) == 0 {
+				return undoChanges(historyDir, steps)
+			}
+			return undoOperations(undoStack, steps)
 		},
 	}
 
-	cmd.Flags().IntVarP(&steps, "steps", "n", 1, "Number of changes to undo")
-	cmd.Flags().BoolVarP(&listAll, "list", "l", false, "List change history")
+	cmd.Flags().IntVarP(&steps, "steps", "n", 1, "Number of operations to undo")
+	cmd.Flags().BoolVarP(&listAll, "list", "l", false, "List the undo stack")
 	cmd.Flags().StringVarP(&restore, "restore", "r", "", "Restore specific backup file")
 
 	return cmd
 }
 
+func listUndoStack(ops, undone []*editor.Operation) error {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39"))
+
+	fmt.Println(titleStyle.Render("📜 Undo Stack"))
+	fmt.Println(strings.Repeat("─", 60))
+
+	timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	fileStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+
+	for i, op := range ops {
+		fmt.Printf("%2d. %s  %s\n", i+1,
+			timeStyle.Render(op.Timestamp.Format("2006-01-02 15:04:05")),
+			op.Command)
+		for _, file := range op.Files {
+			action := "modified"
+			if !file.Existed {
+				action = "created"
+			}
+			fmt.Printf("      %s (%s)\n", fileStyle.Render(file.Path), action)
+		}
+	}
+
+	if len(undone) > 0 {
+		fmt.Println()
+		fmt.Println(timeStyle.Render(fmt.Sprintf("%d undone operation(s) can be replayed with 'viki redo'", len(undone))))
+	}
+
+	fmt.Println()
+	fmt.Println("Use 'viki undo --steps N' to roll back the top N operations")
+
+	return nil
+}
+
+func NewRedoCmd() *cobra.Command {
+	var steps int
+
+	cmd := &cobra.Command{
+		Use:   "redo",
+		Short: "⏩ Redo changes rolled back by undo",
+		Long: `Replay operations rolled back with 'viki undo'.
+
+Each redone file is restored to exactly the content the operation left it
+with. Recording a new operation, such as another 'viki execute', clears
+the redo history.
+
+Examples:
+  viki redo              # Redo the last undone operation
+  viki redo --steps 2    # Redo the last 2 undone operations`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			undoStack := editor.NewUndoStack(".")
+
+			redone, err := undoStack.Redo(max(steps, 1))
+
+			successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+			for _, op := range redone {
+				for _, file := range op.Files {
+					fmt.Println(successStyle.Render(fmt.Sprintf("✓ Reapplied %s", file.Path)))
+				}
+				fmt.Printf("⏩ Redone %s from %s\n", op.Command, op.Timestamp.Format("2006-01-02 15:04:05"))
+			}
+
+			if err != nil {
+				return fmt.Errorf("redo stopped after %d operation(s): %w", len(redone), err)
+			}
+			if len(redone) == 0 {
+				fmt.Println(infoStyle.Render("Nothing to redo."))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&steps, "steps", "n", 1, "Number of operations to redo")
+
+	return cmd
+}
+
+func undoOperations(undoStack *editor.UndoStack, steps int) error {
+	undone, err := undoStack.Undo(steps)
+
+	successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+	for _, op := range undone {
+		for _, file := range op.Files {
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ Restored %s", file.Path)))
+		}
+		fmt.Printf("⏪ Undone %s from %s\n", op.Command, op.Timestamp.Format("2006-01-02 15:04:05"))
+	}
+
+	if err != nil {
+		return fmt.Errorf("undo stopped after %d operation(s): %w", len(undone), err)
+	}
+	return nil
+}
+
 func listHistory(historyDir string) error {
 	entries, err := os.ReadDir(historyDir)
 	if err != nil {
@@ -274,13 +393,26 @@ func NewSecretsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "secrets",
 		Short: "🔐 Manage API keys and secrets",
-		Long:  "Securely store and manage API keys using your system keychain.",
+		Long: `Securely store and manage API keys.
+
+Keys live in the configured backend: the system keychain, an encrypted
+local file, read-only environment variables, or HashiCorp Vault. Providers
+added with 'viki mcp add --from-secrets' fetch their key from it at runtime.
+
+The local file is encrypted with a key derived from your passphrase. Run
+'viki secrets unlock' once per session, or set VIKI_VAULT_PASSPHRASE for
+non-interactive use. Until a passphrase is set the file is keyed from the
+hostname and home directory, which only obfuscates it: anyone who can read
+the file can decrypt it.`,
 	}
 
 	cmd.AddCommand(NewSecretsSetCmd())
 	cmd.AddCommand(NewSecretsGetCmd())
 	cmd.AddCommand(NewSecretsListCmd())
 	cmd.AddCommand(NewSecretsDeleteCmd())
+	cmd.AddCommand(NewSecretsBackendCmd())
+	cmd.AddCommand(NewSecretsUnlockCmd())
+	cmd.AddCommand(NewSecretsLockCmd())
 
 	return cmd
 }
@@ -293,16 +425,30 @@ func NewSecretsSetCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			provider := args[0]
 
+			sm, err := secrets.NewSecretsManager()
+			if err != nil {
+				return err
+			}
+
 			fmt.Printf("Enter API key for %s: ", provider)
-			var apiKey string
-			fmt.Scanln(&apiKey)
+			apiKey, err := readPassword()
+			if err != nil {
+				return fmt.Errorf("failed to read API key: %w", err)
+			}
+			apiKey = strings.TrimSpace(apiKey)
 
 			if apiKey == "" {
 				return fmt.Errorf("API key cannot be empty")
 			}
 
-			// Would use secrets.NewSecretsManager() here
-			fmt.Printf(successStyle.Render("✓ API key stored for %s\n"), provider)
+			if err := sm.SetAPIKey(provider, apiKey); err != nil {
+				return fmt.Errorf("failed to store API key: %w", err)
+			}
+
+			fmt.Printf(successStyle.Render("✓ API key stored for %s in the %s backend\n"), provider, sm.Backend())
+			if sm.Obfuscated() {
+				fmt.Println("⚠️  The secrets file is only obfuscated with a key derived from this machine; run 'viki secrets unlock' to protect it with a passphrase")
+			}
 			return nil
 		},
 	}
@@ -315,8 +461,18 @@ func NewSecretsGetCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			provider := args[0]
-			// Would use secrets manager here
-			fmt.Printf("API key for %s: <hidden>\n", provider)
+
+			sm, err := secrets.NewSecretsManager()
+			if err != nil {
+				return err
+			}
+
+			apiKey, err := sm.GetAPIKey(provider)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("API key for %s: %s\n", provider, maskSecret(apiKey))
 			return nil
 		},
 	}
@@ -327,9 +483,24 @@ func NewSecretsListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List all stored providers",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Stored API keys:")
-			fmt.Println("  - openai")
-			fmt.Println("  - anthropic")
+			sm, err := secrets.NewSecretsManager()
+			if err != nil {
+				return err
+			}
+
+			providers, err := sm.ListProviders()
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Stored API keys (%s backend):\n", sm.Backend())
+			if len(providers) == 0 {
+				fmt.Println("  (none listed)")
+			}
+			sort.Strings(providers)
+			for _, provider := range providers {
+				fmt.Printf("  - %s\n", provider)
+			}
 			return nil
 		},
 	}
@@ -342,8 +513,154 @@ func NewSecretsDeleteCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			provider := args[0]
+
+			sm, err := secrets.NewSecretsManager()
+			if err != nil {
+				return err
+			}
+
+			if err := sm.DeleteAPIKey(provider); err != nil {
+				return fmt.Errorf("failed to delete API key: %w", err)
+			}
+
 			fmt.Printf(successStyle.Render("✓ API key deleted for %s\n"), provider)
 			return nil
 		},
 	}
 }
+
+func NewSecretsBackendCmd() *cobra.Command {
+	var vaultConfig secrets.VaultConfig
+
+	cmd := &cobra.Command{
+		Use:   "backend [name]",
+		Short: "Show or select the secrets backend",
+		Long: `Show or select where API keys are stored.
+
+Backends:
+  auto      System keychain when available, otherwise an encrypted file
+  keychain  macOS Keychain, libsecret (secret-tool) or Windows Credential Manager
+  file      Encrypted file under ~/.viki/secrets
+  env       Read-only; keys come from VIKI_<PROVIDER>_API_KEY variables
+  vault     HashiCorp Vault KV v2; the token is read from VAULT_TOKEN
+
+VIKI_SECRETS_BACKEND and VAULT_ADDR override the saved configuration.
+
+Example:
+  viki secrets backend vault --address https://vault.example.com:8200 --path team/viki`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := secrets.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				fmt.Printf("Secrets backend: %s\n", config.Backend)
+				if config.Backend == secrets.BackendVault {
+					fmt.Printf("Vault: %s (%s/%s)\n", config.Vault.Address, config.Vault.Mount, config.Vault.Path)
+				}
+				return nil
+			}
+
+			backend := args[0]
+			valid := false
+			for _, name := range secrets.Backends {
+				if name == backend {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				return fmt.Errorf("unknown secrets backend '%s' (valid: %s)", backend, strings.Join(secrets.Backends, ", "))
+			}
+
+			config.Backend = backend
+			if vaultConfig.Address != "" {
+				config.Vault.Address = vaultConfig.Address
+			}
+			if vaultConfig.Mount != "" {
+				config.Vault.Mount = vaultConfig.Mount
+			}
+			if vaultConfig.Path != "" {
+				config.Vault.Path = vaultConfig.Path
+			}
+
+			if err := secrets.SaveConfig(config); err != nil {
+				return err
+			}
+
+			fmt.Printf(successStyle.Render("✓ Secrets backend set to %s\n"), backend)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&vaultConfig.Address, "address", "", "Vault address")
+	cmd.Flags().StringVar(&vaultConfig.Mount, "mount", "", "Vault KV v2 mount (default \"secret\")")
+	cmd.Flags().StringVar(&vaultConfig.Path, "path", "", "Secret path within the mount (default \"viki\")")
+
+	return cmd
+}
+
+func NewSecretsUnlockCmd() *cobra.Command {
+	var duration time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "unlock",
+		Short: "Unlock the encrypted local secrets file",
+		Long: `Unlock the encrypted local secrets file for a limited time.
+
+The passphrase is stretched with argon2id and only the derived key is kept,
+in the OS keyring, until it expires or 'viki secrets lock' is run. Nothing
+is cached on disk. A file that is not yet passphrase-protected is
+re-encrypted under the passphrase.
+
+Without an OS keyring (macOS Keychain or libsecret's secret-tool) the file
+is still protected, but each command needs VIKI_VAULT_PASSPHRASE.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Print("Enter secrets passphrase: ")
+			passphrase, err := readPassword()
+			if err != nil {
+				return fmt.Errorf("failed to read passphrase: %w", err)
+			}
+
+			protected, err := secrets.Unlock(strings.TrimSpace(passphrase), duration)
+			if protected {
+				fmt.Println(successStyle.Render("✓ Secrets file is now protected by your passphrase"))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to unlock secrets: %w", err)
+			}
+
+			fmt.Printf(successStyle.Render("✓ Secrets unlocked for %s\n"), duration)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&duration, "for", secrets.DefaultUnlockDuration, "How long to keep the secrets unlocked")
+
+	return cmd
+}
+
+func NewSecretsLockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lock",
+		Short: "Lock the encrypted local secrets file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := secrets.Lock(); err != nil {
+				return err
+			}
+
+			fmt.Println(successStyle.Render("✓ Secrets locked"))
+			return nil
+		},
+	}
+}
+
+// maskSecret shows only the first and last few characters of a secret
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", len(secret)-8) + secret[len(secret)-4:]
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/gates"
+)
+
+func NewValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [trackID]",
+		Short: "Run the test suite and have the Inspector validate the implementation",
+		Long: `Validate the implementation of a track.
+
+The project's test suite (go test, npm test, pytest or cargo test, or the
+'tests.command' of .sdd/gates.yaml) runs first. When too many tests fail, or
+coverage is below 'tests.min_coverage', the validation report is FAIL and lists
+the failing tests. Otherwise the Inspector reviews the implementation with the
+real test results in hand.
+
+The report is written to 5_validation_report.md and awaits approval.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stateMgr := gates.NewStateManager(".")
+			state, err := stateMgr.LoadState()
+			if err != nil {
+				return fmt.Errorf("project not initialized: %w", err)
+			}
+
+			trackID := "feature-implementation"
+			if len(args) > 0 {
+				trackID = args[0]
+			} else if state.Metadata != nil {
+				if t, ok := state.Metadata["current_track"].(string); ok && t != "" {
+					trackID = t
+				}
+			}
+
+			agentSvc := agents.NewAgentService(".")
+			if err := agentSvc.Initialize(); err != nil {
+				return fmt.Errorf("failed to initialize agent service: %w", err)
+			}
+
+			fmt.Println("🔍 Inspector is validating the implementation...")
+			report, err := agentSvc.Orchestrate("validate", trackID, "")
+			if err != nil {
+				return fmt.Errorf("validation failed: %w", err)
+			}
+
+			reportPath := filepath.Join(gates.TracksDir("."), trackID, agents.ArtifactName(".", "validate"))
+			if strings.Contains(report, "[STATUS: FAIL]") {
+				failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+				fmt.Println(failStyle.Render("❌ Validation FAILED"))
+				if failing := reportSection(report, "### Failing Tests"); failing != "" {
+					fmt.Println(failing)
+				}
+			} else {
+				fmt.Println(successStyle.Render("✅ Validation report ready"))
+			}
+			fmt.Printf("📄 Report: %s\n", reportPath)
+			fmt.Println("Next: Run 'viki approve --interactive' to review it")
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// reportSection returns the body of a markdown section, up to the next heading
+func reportSection(report, heading string) string {
+	start := strings.Index(report, heading)
+	if start < 0 {
+		return ""
+	}
+	body := report[start+len(heading):]
+	if end := strings.Index(body, "\n#"); end >= 0 {
+		body = body[:end]
+	}
+	return strings.TrimSpace(body)
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"ultimate-sdd-framework/internal/agents"
 	"ultimate-sdd-framework/internal/vision"
 )
 
@@ -27,7 +28,11 @@ func NewVisionCmd() *cobra.Command {
 - Code screenshot analysis and improvements
 - Flowchart and process diagram understanding
 
-Supports various image formats and provides actionable insights.`,
+Supports various image formats and provides actionable insights.
+
+It also records the project vision (north star) in .sdd/vision.md. The
+vision guides the specify and plan phases, and generated PRDs and plans are
+flagged when they appear to diverge from it.`,
 	}
 
 	// Subcommands
@@ -35,6 +40,9 @@ Supports various image formats and provides actionable insights.`,
 	cmd.AddCommand(NewVisionScreenshotCmd())
 	cmd.AddCommand(NewVisionArchitectureCmd())
 	cmd.AddCommand(NewVisionCodeCmd())
+	cmd.AddCommand(newVisionSetCmd())
+	cmd.AddCommand(newVisionShowCmd())
+	cmd.AddCommand(newVisionCheckCmd())
 
 	return cmd
 }
@@ -421,4 +429,98 @@ Useful for code reviews, learning from examples, and identifying issues in code
 	cmd.Flags().StringVar(&imagePath, "image", "", "Path to code screenshot")
 
 	return cmd
-}
\ No newline at end of file
+}
+
+func newVisionSetCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "set [statement]",
+		Short: "Record the project vision in .sdd/vision.md",
+		Long: `Record the project's vision or north-star document.
+
+List what the project will not do under a "Non-goals" heading, or as
+statements like "No mobile app", so PRDs that propose them are flagged.
+
+Examples:
+  viki vision set "The fastest way for small teams to track invoices"
+  viki vision set --file docs/VISION.md`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content := strings.Join(args, " ")
+			if file != "" {
+				data, err := os.ReadFile(file)
+				if err != nil {
+					return fmt.Errorf("failed to read vision file: %w", err)
+				}
+				content = string(data)
+			}
+			if strings.TrimSpace(content) == "" {
+				return fmt.Errorf("provide the vision as an argument or with --file")
+			}
+			if !strings.HasPrefix(strings.TrimSpace(content), "#") {
+				content = "# Project Vision\n\n" + content
+			}
+
+			if err := agents.SaveVision(".", content); err != nil {
+				return err
+			}
+			fmt.Printf("✅ Project vision saved to %s\n", agents.VisionPath("."))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Read the vision from a markdown file")
+
+	return cmd
+}
+
+func newVisionShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the recorded project vision",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vision, err := agents.LoadVision(".")
+			if err != nil {
+				return err
+			}
+			if vision == "" {
+				fmt.Println("No project vision recorded. Run 'viki vision set' to add one.")
+				return nil
+			}
+			fmt.Print(vision)
+			return nil
+		},
+	}
+}
+
+func newVisionCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check <file>",
+		Short: "Check a PRD or plan against the project vision",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vision, err := agents.LoadVision(".")
+			if err != nil {
+				return err
+			}
+			if vision == "" {
+				return fmt.Errorf("no project vision recorded; run 'viki vision set' first")
+			}
+
+			document, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", args[0], err)
+			}
+
+			notes := agents.CheckVisionAlignment(vision, string(document))
+			if len(notes) == 0 {
+				fmt.Printf("✅ %s is consistent with the project vision\n", args[0])
+				return nil
+			}
+			for _, note := range notes {
+				fmt.Printf("⚠️  %s %s\n", args[0], note)
+			}
+			return nil
+		},
+	}
+}
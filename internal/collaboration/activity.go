@@ -0,0 +1,54 @@
+package collaboration
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// StaleMemberAfter is how long a member can go without recorded activity
+// before the team report lists them as inactive
+const StaleMemberAfter = 30 * 24 * time.Hour
+
+// FindMember returns the member with the given email, ID or name, compared
+// case-insensitively, or nil
+func (tc *TeamCollaboration) FindMember(identity string) *TeamMember {
+	identity = strings.TrimSpace(identity)
+	if identity == "" {
+		return nil
+	}
+	for i := range tc.teamData.Members {
+		member := &tc.teamData.Members[i]
+		if strings.EqualFold(member.Email, identity) || member.ID == identity || strings.EqualFold(member.Name, identity) {
+			return member
+		}
+	}
+	return nil
+}
+
+// touchMember records that the member acting as identity was just active;
+// identities that are not members are ignored. The caller saves the team.
+func (tc *TeamCollaboration) touchMember(identity string) {
+	if member := tc.FindMember(identity); member != nil {
+		member.LastActive = time.Now()
+	}
+}
+
+// IsStale reports whether the member has had no recorded activity for
+// StaleMemberAfter
+func (m TeamMember) IsStale() bool {
+	return time.Since(m.LastActive) > StaleMemberAfter
+}
+
+// StaleMembers returns the members with no recent activity, longest
+// inactive first
+func (tc *TeamCollaboration) StaleMembers() []TeamMember {
+	var stale []TeamMember
+	for _, member := range tc.teamData.Members {
+		if member.IsStale() {
+			stale = append(stale, member)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].LastActive.Before(stale[j].LastActive) })
+	return stale
+}
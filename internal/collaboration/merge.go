@@ -0,0 +1,143 @@
+package collaboration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ImportSummary counts what an import added to the team and what it merged
+// into entries the team already had
+type ImportSummary struct {
+	RulesAdded      int
+	RulesMerged     int
+	KnowledgeAdded  int
+	KnowledgeMerged int
+	PatternsAdded   int
+	PatternsMerged  int
+}
+
+// ImportTeam merges the rules, knowledge items and code patterns of another
+// team's team.json into this team. Rules are matched by title within their
+// category, knowledge items by title within theirs and patterns by name;
+// a match adds its votes, views, helpful marks or usage count to the
+// existing entry instead of duplicating it.
+func (tc *TeamCollaboration) ImportTeam(path string) (*ImportSummary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read team file: %w", err)
+	}
+
+	var other Team
+	if err := json.Unmarshal(data, &other); err != nil {
+		return nil, fmt.Errorf("failed to parse team file %s: %w", path, err)
+	}
+
+	summary := &ImportSummary{}
+	rules := &tc.teamData.Rules
+	for _, merge := range []struct {
+		into *[]RuleDefinition
+		from []RuleDefinition
+	}{
+		{&rules.CodingStandards, other.Rules.CodingStandards},
+		{&rules.CodeReviewRules, other.Rules.CodeReviewRules},
+		{&rules.TestingStandards, other.Rules.TestingStandards},
+		{&rules.SecurityPolicies, other.Rules.SecurityPolicies},
+		{&rules.PerformanceRules, other.Rules.PerformanceRules},
+		{&rules.DocumentationRules, other.Rules.DocumentationRules},
+	} {
+		added, merged := mergeRules(merge.into, merge.from)
+		summary.RulesAdded += added
+		summary.RulesMerged += merged
+	}
+
+	knowledge := &tc.teamData.Knowledge
+	for _, merge := range []struct {
+		into *[]KnowledgeItem
+		from []KnowledgeItem
+	}{
+		{&knowledge.BestPractices, other.Knowledge.BestPractices},
+		{&knowledge.CommonIssues, other.Knowledge.CommonIssues},
+		{&knowledge.ArchitectureDocs, other.Knowledge.ArchitectureDocs},
+	} {
+		added, merged := mergeKnowledge(merge.into, merge.from)
+		summary.KnowledgeAdded += added
+		summary.KnowledgeMerged += merged
+	}
+
+	summary.PatternsAdded, summary.PatternsMerged = mergePatterns(&knowledge.CodePatterns, other.Knowledge.CodePatterns)
+
+	tc.teamData.LastUpdated = time.Now()
+	return summary, tc.saveTeamData()
+}
+
+// sameTitle reports whether two titles or names denote the same entry
+func sameTitle(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// mergeRules adds the rules of from to into, summing the votes of rules
+// already there
+func mergeRules(into *[]RuleDefinition, from []RuleDefinition) (added, merged int) {
+	for _, rule := range from {
+		found := false
+		for i := range *into {
+			if sameTitle((*into)[i].Title, rule.Title) {
+				(*into)[i].Votes += rule.Votes
+				found = true
+				merged++
+				break
+			}
+		}
+		if !found {
+			*into = append(*into, rule)
+			added++
+		}
+	}
+	return added, merged
+}
+
+// mergeKnowledge adds the items of from to into, summing the views and
+// helpful marks of items already there
+func mergeKnowledge(into *[]KnowledgeItem, from []KnowledgeItem) (added, merged int) {
+	for _, item := range from {
+		found := false
+		for i := range *into {
+			if sameTitle((*into)[i].Title, item.Title) {
+				(*into)[i].Views += item.Views
+				(*into)[i].Helpful += item.Helpful
+				found = true
+				merged++
+				break
+			}
+		}
+		if !found {
+			*into = append(*into, item)
+			added++
+		}
+	}
+	return added, merged
+}
+
+// mergePatterns adds the patterns of from to into, summing the usage of
+// patterns already there
+func mergePatterns(into *[]CodePattern, from []CodePattern) (added, merged int) {
+	for _, pattern := range from {
+		found := false
+		for i := range *into {
+			if sameTitle((*into)[i].Name, pattern.Name) {
+				(*into)[i].UsageCount += pattern.UsageCount
+				found = true
+				merged++
+				break
+			}
+		}
+		if !found {
+			*into = append(*into, pattern)
+			added++
+		}
+	}
+	return added, merged
+}
@@ -0,0 +1,122 @@
+package collaboration
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// topPatternCount is how many of the most used code patterns a report lists
+const topPatternCount = 5
+
+// recentDecisionCount is how many of the latest decisions a report lists
+const recentDecisionCount = 3
+
+// TeamReport is the team report as structured data, for dashboards and
+// integrations
+type TeamReport struct {
+	Team                string         `json:"team"`
+	Description         string         `json:"description"`
+	Created             time.Time      `json:"created"`
+	Generated           time.Time      `json:"generated"`
+	MemberCount         int            `json:"member_count"`
+	ProjectCount        int            `json:"project_count"`
+	Members             []TeamMember   `json:"members"`
+	StaleMembers        []string       `json:"stale_members"` // names of members inactive for StaleMemberAfter
+	RuleCount           int            `json:"rule_count"`
+	RulesByCategory     map[string]int `json:"rules_by_category"`
+	KnowledgeCount      int            `json:"knowledge_count"`
+	KnowledgeByCategory map[string]int `json:"knowledge_by_category"`
+	TopPatterns         []PatternUsage `json:"top_patterns"`
+	RecentDecisions     []Decision     `json:"recent_decisions"`
+}
+
+// PatternUsage is how often a code pattern has been used
+type PatternUsage struct {
+	Name       string `json:"name"`
+	Language   string `json:"language"`
+	UsageCount int    `json:"usage_count"`
+}
+
+// ruleCategory is a rule category, as named when adding a rule, with its
+// rules
+type ruleCategory struct {
+	Name  string
+	Rules []RuleDefinition
+}
+
+// ruleCategories returns the team's rules by category
+func (tc *TeamCollaboration) ruleCategories() []ruleCategory {
+	rules := tc.teamData.Rules
+	return []ruleCategory{
+		{"coding_standards", rules.CodingStandards},
+		{"code_review", rules.CodeReviewRules},
+		{"testing", rules.TestingStandards},
+		{"security", rules.SecurityPolicies},
+		{"performance", rules.PerformanceRules},
+		{"documentation", rules.DocumentationRules},
+	}
+}
+
+// Report summarizes the team: its members, rule and knowledge counts by
+// category, most used code patterns and latest decisions
+func (tc *TeamCollaboration) Report() *TeamReport {
+	team := tc.teamData
+	report := &TeamReport{
+		Team:            team.Name,
+		Description:     team.Description,
+		Created:         team.Created,
+		Generated:       time.Now(),
+		MemberCount:     len(team.Members),
+		ProjectCount:    len(team.Projects),
+		Members:         team.Members,
+		RulesByCategory: make(map[string]int),
+		KnowledgeByCategory: map[string]int{
+			"best_practices": len(team.Knowledge.BestPractices),
+			"common_issues":  len(team.Knowledge.CommonIssues),
+			"architecture":   len(team.Knowledge.ArchitectureDocs),
+			"code_patterns":  len(team.Knowledge.CodePatterns),
+			"decisions":      len(team.Knowledge.DecisionLog),
+		},
+		TopPatterns:     []PatternUsage{},
+		RecentDecisions: []Decision{},
+	}
+	if report.Members == nil {
+		report.Members = []TeamMember{}
+	}
+	report.StaleMembers = []string{}
+	for _, member := range tc.StaleMembers() {
+		report.StaleMembers = append(report.StaleMembers, member.Name)
+	}
+
+	for _, category := range tc.ruleCategories() {
+		report.RulesByCategory[category.Name] = len(category.Rules)
+		report.RuleCount += len(category.Rules)
+	}
+	for _, count := range report.KnowledgeByCategory {
+		report.KnowledgeCount += count
+	}
+
+	for i, pattern := range tc.GetCodePatterns("", "") {
+		if i >= topPatternCount {
+			break
+		}
+		report.TopPatterns = append(report.TopPatterns, PatternUsage{pattern.Name, pattern.Language, pattern.UsageCount})
+	}
+
+	decisions := team.Knowledge.DecisionLog
+	for i := len(decisions) - 1; i >= 0 && i >= len(decisions)-recentDecisionCount; i-- {
+		report.RecentDecisions = append(report.RecentDecisions, decisions[i])
+	}
+
+	return report
+}
+
+// GenerateTeamReportJSON returns the team report as indented JSON
+func (tc *TeamCollaboration) GenerateTeamReportJSON() (string, error) {
+	data, err := json.MarshalIndent(tc.Report(), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal team report: %w", err)
+	}
+	return string(data), nil
+}
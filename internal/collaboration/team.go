@@ -211,6 +211,7 @@ func (tc *TeamCollaboration) AddTeamRule(category, title, description, severity,
 		Created:     time.Now(),
 		Votes:       1, // Creator automatically votes
 	}
+	tc.touchMember(createdBy)
 
 	// Add to appropriate category
 	switch category {
@@ -246,6 +247,7 @@ func (tc *TeamCollaboration) AddKnowledgeItem(title, content, category, author s
 		Views:    0,
 		Helpful:  0,
 	}
+	tc.touchMember(author)
 
 	// Add to appropriate category
 	switch category {
@@ -261,6 +263,55 @@ func (tc *TeamCollaboration) AddKnowledgeItem(title, content, category, author s
 	return &item, tc.saveTeamData()
 }
 
+// findKnowledgeItem returns the knowledge item with the given ID, or nil
+func (tc *TeamCollaboration) findKnowledgeItem(id string) *KnowledgeItem {
+	for _, items := range []*[]KnowledgeItem{
+		&tc.teamData.Knowledge.BestPractices,
+		&tc.teamData.Knowledge.CommonIssues,
+		&tc.teamData.Knowledge.ArchitectureDocs,
+	} {
+		for i := range *items {
+			if (*items)[i].ID == id {
+				return &(*items)[i]
+			}
+		}
+	}
+	return nil
+}
+
+// RecordView counts a view of a knowledge item and returns the item
+func (tc *TeamCollaboration) RecordView(id string) (*KnowledgeItem, error) {
+	item := tc.findKnowledgeItem(id)
+	if item == nil {
+		return nil, fmt.Errorf("knowledge item '%s' not found", id)
+	}
+
+	item.Views++
+	return item, tc.saveTeamData()
+}
+
+// MarkHelpful counts a knowledge item as helpful and returns the item
+func (tc *TeamCollaboration) MarkHelpful(id string) (*KnowledgeItem, error) {
+	item := tc.findKnowledgeItem(id)
+	if item == nil {
+		return nil, fmt.Errorf("knowledge item '%s' not found", id)
+	}
+
+	item.Helpful++
+	return item, tc.saveTeamData()
+}
+
+// SortByHelpfulness orders knowledge items most helpful first, then most
+// viewed
+func SortByHelpfulness(items []KnowledgeItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Helpful != items[j].Helpful {
+			return items[i].Helpful > items[j].Helpful
+		}
+		return items[i].Views > items[j].Views
+	})
+}
+
 // AddCodePattern adds a reusable code pattern
 func (tc *TeamCollaboration) AddCodePattern(name, description, language, code, useCase, author string) (*CodePattern, error) {
 	pattern := CodePattern{
@@ -274,6 +325,7 @@ func (tc *TeamCollaboration) AddCodePattern(name, description, language, code, u
 		Created:     time.Now(),
 		UsageCount:  0,
 	}
+	tc.touchMember(author)
 
 	tc.teamData.Knowledge.CodePatterns = append(tc.teamData.Knowledge.CodePatterns, pattern)
 	tc.teamData.LastUpdated = time.Now()
@@ -294,6 +346,7 @@ func (tc *TeamCollaboration) RecordDecision(title, context, decision string, alt
 		Date:         time.Now(),
 		Status:       "implemented",
 	}
+	tc.touchMember(madeBy)
 
 	tc.teamData.Knowledge.DecisionLog = append(tc.teamData.Knowledge.DecisionLog, teamDecision)
 	tc.teamData.LastUpdated = time.Now()
@@ -376,6 +429,11 @@ func (tc *TeamCollaboration) GenerateTeamReport() string {
 			report.WriteString(fmt.Sprintf("### %s (%s)\n", member.Name, member.Role))
 			report.WriteString(fmt.Sprintf("**Email:** %s\n", member.Email))
 			report.WriteString(fmt.Sprintf("**Joined:** %s\n", member.Joined.Format("2006-01-02")))
+			report.WriteString(fmt.Sprintf("**Last active:** %s", member.LastActive.Format("2006-01-02")))
+			if member.IsStale() {
+				report.WriteString(" ⚠️ inactive")
+			}
+			report.WriteString("\n")
 			if len(member.Skills) > 0 {
 				report.WriteString(fmt.Sprintf("**Skills:** %s\n", strings.Join(member.Skills, ", ")))
 			}
@@ -383,6 +441,15 @@ func (tc *TeamCollaboration) GenerateTeamReport() string {
 		}
 	}
 
+	if stale := tc.StaleMembers(); len(stale) > 0 {
+		report.WriteString(fmt.Sprintf("### ⏳ Inactive Members (%d)\n\n", len(stale)))
+		report.WriteString(fmt.Sprintf("No recorded activity in the last %d days:\n", int(StaleMemberAfter.Hours()/24)))
+		for _, member := range stale {
+			report.WriteString(fmt.Sprintf("- %s (last active %s)\n", member.Name, member.LastActive.Format("2006-01-02")))
+		}
+		report.WriteString("\n")
+	}
+
 	// Team Rules Summary
 	totalRules := len(tc.teamData.Rules.CodingStandards) + len(tc.teamData.Rules.CodeReviewRules) +
 	              len(tc.teamData.Rules.TestingStandards) + len(tc.teamData.Rules.SecurityPolicies) +
@@ -405,7 +472,11 @@ func (tc *TeamCollaboration) GenerateTeamReport() string {
 			if len(rules) > 0 {
 				report.WriteString(fmt.Sprintf("### %s (%d rules)\n", category, len(rules)))
 				for _, rule := range rules {
-					report.WriteString(fmt.Sprintf("- **%s**: %s\n", rule.Title, rule.Description))
+					report.WriteString(fmt.Sprintf("- **%s**: %s", rule.Title, rule.Description))
+					if rule.CreatedBy != "" {
+						report.WriteString(fmt.Sprintf(" _(by %s)_", rule.CreatedBy))
+					}
+					report.WriteString("\n")
 				}
 				report.WriteString("\n")
 			}
@@ -284,7 +284,7 @@ Include in summary:
 
 Read:
 - .viki/constitution.md
-- .sdd/CONTEXT.md
+- .sdd/context/current_state.md
 - README.md
 - Key source files
 
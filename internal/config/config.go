@@ -5,12 +5,16 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 
 	"github.com/goccy/go-yaml"
 )
 
 // Config represents the global Viki configuration
 type Config struct {
+	// Identity actions are attributed to, e.g. an email; see ResolveUser
+	User string `yaml:"user,omitempty"`
+
 	// Default AI provider
 	DefaultProvider string `yaml:"default_provider"`
 
@@ -28,6 +32,10 @@ type Config struct {
 
 	// Telemetry settings
 	Telemetry TelemetryConfig `yaml:"telemetry"`
+
+	// Named profiles and the one in use
+	Profiles      map[string]Profile `yaml:"profiles,omitempty"`
+	ActiveProfile string             `yaml:"active_profile,omitempty"`
 }
 
 // ThemeConfig represents theme settings
@@ -191,6 +199,8 @@ func (cm *ConfigManager) Set(config *Config) {
 // GetValue gets a configuration value by key path
 func (cm *ConfigManager) GetValue(key string) (interface{}, error) {
 	switch key {
+	case "user":
+		return cm.config.User, nil
 	case "default_provider":
 		return cm.config.DefaultProvider, nil
 	case "theme.color_scheme":
@@ -215,6 +225,8 @@ func (cm *ConfigManager) GetValue(key string) (interface{}, error) {
 // SetValue sets a configuration value by key path
 func (cm *ConfigManager) SetValue(key string, value interface{}) error {
 	switch key {
+	case "user":
+		cm.config.User = value.(string)
 	case "default_provider":
 		cm.config.DefaultProvider = value.(string)
 	case "theme.color_scheme":
@@ -238,6 +250,38 @@ func (cm *ConfigManager) SetValue(key string, value interface{}) error {
 	return cm.Save()
 }
 
+// SetValueString sets a configuration value given as text, parsing it as
+// the key's type
+func (cm *ConfigManager) SetValueString(key, value string) error {
+	current, err := cm.GetValue(key)
+	if err != nil {
+		return err
+	}
+
+	switch current.(type) {
+	case bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false", key)
+		}
+		return cm.SetValue(key, parsed)
+	case float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be a number", key)
+		}
+		return cm.SetValue(key, parsed)
+	case int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a whole number", key)
+		}
+		return cm.SetValue(key, parsed)
+	default:
+		return cm.SetValue(key, value)
+	}
+}
+
 // Reset resets the configuration to defaults
 func (cm *ConfigManager) Reset() error {
 	cm.config = DefaultConfig()
@@ -287,6 +331,7 @@ func getDefaultEditor() string {
 // ListAllKeys returns all available config keys
 func ListAllKeys() []string {
 	return []string{
+		"user",
 		"default_provider",
 		"theme.color_scheme",
 		"theme.accent",
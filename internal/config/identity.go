@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// UserEnvVar overrides the user identity actions are attributed to
+const UserEnvVar = "VIKI_USER"
+
+// ResolveUser returns who actions in projectRoot are attributed to: the
+// VIKI_USER environment variable, else the user set with 'viki config set
+// user', else git's user.email for the project, else the login name. It
+// returns "unknown" when none is available.
+func ResolveUser(projectRoot string) string {
+	if user := strings.TrimSpace(os.Getenv(UserEnvVar)); user != "" {
+		return user
+	}
+
+	// Only read an existing config; resolving a user should not create one
+	cm := NewConfigManager()
+	if _, err := os.Stat(cm.configFile); err == nil && cm.Load() == nil {
+		if user := strings.TrimSpace(cm.config.User); user != "" {
+			return user
+		}
+	}
+
+	if out, err := exec.Command("git", "-C", projectRoot, "config", "--get", "user.email").Output(); err == nil {
+		if email := strings.TrimSpace(string(out)); email != "" {
+			return email
+		}
+	}
+
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return "unknown"
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ProfileEnvVar selects the active profile, overriding the config file
+const ProfileEnvVar = "VIKI_PROFILE"
+
+// Profile is a named set of overrides, such as a cheap local model for
+// development and a premium one in CI
+type Profile struct {
+	Provider    string   `yaml:"provider,omitempty"`    // MCP provider used by default
+	Model       string   `yaml:"model,omitempty"`       // model override for that provider
+	Temperature *float64 `yaml:"temperature,omitempty"` // overrides the temperature of every request
+	GatePolicy  string   `yaml:"gate_policy,omitempty"` // gate policy file used instead of .sdd/gates.yaml
+}
+
+// SetProfile creates or replaces a named profile
+func (cm *ConfigManager) SetProfile(name string, profile Profile) error {
+	if cm.config.Profiles == nil {
+		cm.config.Profiles = make(map[string]Profile)
+	}
+	cm.config.Profiles[name] = profile
+	return cm.Save()
+}
+
+// RemoveProfile deletes a named profile, deactivating it if it was active
+func (cm *ConfigManager) RemoveProfile(name string) error {
+	if _, ok := cm.config.Profiles[name]; !ok {
+		return fmt.Errorf("profile '%s' not found", name)
+	}
+	delete(cm.config.Profiles, name)
+	if cm.config.ActiveProfile == name {
+		cm.config.ActiveProfile = ""
+	}
+	return cm.Save()
+}
+
+// UseProfile makes a profile the active one; "" deactivates profiles
+func (cm *ConfigManager) UseProfile(name string) error {
+	if _, ok := cm.config.Profiles[name]; !ok && name != "" {
+		return fmt.Errorf("profile '%s' not found (available: %v)", name, ProfileNames(cm.config))
+	}
+	cm.config.ActiveProfile = name
+	return cm.Save()
+}
+
+// ProfileNames returns the names of the configured profiles, sorted
+func ProfileNames(config *Config) []string {
+	names := make([]string, 0, len(config.Profiles))
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActiveProfile returns the profile in effect: the one named by VIKI_PROFILE
+// (set by the --profile flag) or else the one selected with 'viki config
+// profile use'. It returns "" and nil when no profile is active, and does not
+// create the config file.
+func ActiveProfile() (string, *Profile, error) {
+	cm := NewConfigManager()
+
+	data, err := os.ReadFile(cm.configFile)
+	if err != nil && !os.IsNotExist(err) {
+		return "", nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, cm.config); err != nil {
+			return "", nil, fmt.Errorf("failed to parse config: %w", err)
+		}
+	}
+
+	name := cm.config.ActiveProfile
+	if env := os.Getenv(ProfileEnvVar); env != "" {
+		name = env
+	}
+	if name == "" {
+		return "", nil, nil
+	}
+
+	profile, ok := cm.config.Profiles[name]
+	if !ok {
+		return "", nil, fmt.Errorf("profile '%s' not found (available: %v)", name, ProfileNames(cm.config))
+	}
+	return name, &profile, nil
+}
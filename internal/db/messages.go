@@ -140,12 +140,25 @@ func (m *MessageStore) DeleteBySession(sessionID string) error {
 	return err
 }
 
+// EstimateTokens approximates the token count of text (~4 characters per token)
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// EstimatedTokens returns the stored token count, estimating it from the content when unset
+func (msg *Message) EstimatedTokens() int {
+	if msg.TokenCount > 0 {
+		return msg.TokenCount
+	}
+	return EstimateTokens(msg.Content)
+}
+
 // Search searches messages by content
 func (m *MessageStore) Search(query string, limit int) ([]*Message, error) {
 	rows, err := m.db.conn.Query(`
 		SELECT id, session_id, role, content, created_at, token_count, model, tool_calls, tool_results
-		FROM messages WHERE content LIKE ? ORDER BY created_at DESC LIMIT ?
-	`, "%"+query+"%", limit)
+		FROM messages WHERE content LIKE ? ESCAPE '\' ORDER BY created_at DESC LIMIT ?
+	`, containsPattern(query), limit)
 
 	if err != nil {
 		return nil, err
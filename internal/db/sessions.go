@@ -4,7 +4,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Session represents a chat session
@@ -219,3 +221,244 @@ func (s *SessionStore) ExportToJSON(id string) ([]byte, error) {
 
 	return json.MarshalIndent(export, "", "  ")
 }
+
+// SessionSearchResult represents a message match within a session
+type SessionSearchResult struct {
+	SessionID    string    `json:"session_id"`
+	SessionTitle string    `json:"session_title"`
+	MessageID    string    `json:"message_id"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+	Snippet      string    `json:"snippet"`
+}
+
+// likeEscaper escapes the LIKE wildcards of user input, for ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns a LIKE pattern matching text containing query
+// literally
+func containsPattern(query string) string {
+	return "%" + likeEscaper.Replace(query) + "%"
+}
+
+// SearchSessions searches message content across all sessions and returns
+// the matching sessions with a snippet of the surrounding text
+func (s *SessionStore) SearchSessions(query string, limit int) ([]*SessionSearchResult, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rows, err := s.db.conn.Query(`
+		SELECT s.id, s.title, m.id, m.role, m.content, m.created_at
+		FROM messages m JOIN sessions s ON s.id = m.session_id
+		WHERE m.content LIKE ? ESCAPE '\' ORDER BY m.created_at DESC LIMIT ?
+	`, containsPattern(query), limit)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*SessionSearchResult
+	for rows.Next() {
+		result := &SessionSearchResult{}
+		var content string
+		if err := rows.Scan(&result.SessionID, &result.SessionTitle, &result.MessageID, &result.Role, &content, &result.CreatedAt); err != nil {
+			return nil, err
+		}
+		result.Snippet = buildSnippet(content, query, 60)
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// buildSnippet extracts the text surrounding the first match of query,
+// keeping up to radius characters on either side
+func buildSnippet(content, query string, radius int) string {
+	idx, length := indexFold(content, query)
+	if idx < 0 {
+		idx, length = 0, 0
+	}
+
+	start := idx - radius
+	if start < 0 {
+		start = 0
+	}
+	end := idx + length + radius
+	if end > len(content) {
+		end = len(content)
+	}
+
+	// Avoid cutting multi-byte characters in half
+	for start > 0 && !utf8.RuneStart(content[start]) {
+		start--
+	}
+	for end < len(content) && !utf8.RuneStart(content[end]) {
+		end++
+	}
+
+	snippet := strings.Join(strings.Fields(content[start:end]), " ")
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(content) {
+		snippet += "..."
+	}
+
+	return snippet
+}
+
+// indexFold returns the byte offset and length in s of the first
+// case-insensitive match of substr, or -1. It compares rune by rune so both
+// are offsets into s itself, even where changing case would change a
+// character's length in bytes.
+func indexFold(s, substr string) (int, int) {
+	for i := range s {
+		if n := prefixFold(s[i:], substr); n >= 0 {
+			return i, n
+		}
+	}
+	return -1, 0
+}
+
+// prefixFold returns the length in bytes of the prefix of s matching prefix
+// case-insensitively, or -1 when s does not start with it
+func prefixFold(s, prefix string) int {
+	n := 0
+	for _, want := range prefix {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if size == 0 || !strings.EqualFold(string(r), string(want)) {
+			return -1
+		}
+		n += size
+	}
+	return n
+}
+
+// SummaryPrefix marks the synthetic message produced by CompactMessages
+const SummaryPrefix = "[Summary of earlier conversation]"
+
+// maxResummaries is how many times CompactMessages summarizes a summary
+// that is still too long for the window
+const maxResummaries = 2
+
+// Summarizer condenses a run of messages into a short summary
+type Summarizer func(messages []*Message) (string, error)
+
+// CompactionResult describes the outcome of CompactMessages
+type CompactionResult struct {
+	SummarizedCount int `json:"summarized_count"`
+	KeptCount       int `json:"kept_count"`
+	TokensBefore    int `json:"tokens_before"`
+	TokensAfter     int `json:"tokens_after"`
+}
+
+// CompactMessages keeps a session under maxTokens by replacing its oldest
+// messages with a single summary message. Up to keepRecent of the newest
+// messages are preserved verbatim as long as they fit in the budget. It
+// fails without changing the session when the summary cannot be made to fit.
+func (s *SessionStore) CompactMessages(sessionID string, maxTokens, keepRecent int, summarize Summarizer) (*CompactionResult, error) {
+	msgStore := NewMessageStore(s.db)
+	messages, err := msgStore.ListBySession(sessionID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CompactionResult{}
+	for _, msg := range messages {
+		result.TokensBefore += msg.EstimatedTokens()
+	}
+	result.TokensAfter = result.TokensBefore
+	result.KeptCount = len(messages)
+
+	if result.TokensBefore <= maxTokens {
+		return result, nil
+	}
+
+	// Reserve a quarter of the window for the summary itself
+	budget := maxTokens - maxTokens/4
+	split := len(messages)
+	used := 0
+	for i := len(messages) - 1; i >= 0 && len(messages)-i <= keepRecent; i-- {
+		tokens := messages[i].EstimatedTokens()
+		if used+tokens > budget {
+			break
+		}
+		used += tokens
+		split = i
+	}
+
+	older, recent := messages[:split], messages[split:]
+	if len(older) == 0 {
+		return result, nil
+	}
+
+	summary, err := summarize(older)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize messages: %w", err)
+	}
+
+	// A summary longer than the room left next to the kept messages is
+	// summarized again, for as long as that still shortens it
+	summaryTokens := EstimateTokens(SummaryPrefix + "\n\n" + summary)
+	for attempt := 0; used+summaryTokens > maxTokens && attempt < maxResummaries; attempt++ {
+		shorter, err := summarize([]*Message{{SessionID: sessionID, Role: "system", Content: summary}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to shorten the summary: %w", err)
+		}
+		tokens := EstimateTokens(SummaryPrefix + "\n\n" + shorter)
+		if tokens >= summaryTokens {
+			break
+		}
+		summary, summaryTokens = shorter, tokens
+	}
+	if used+summaryTokens > maxTokens {
+		return nil, fmt.Errorf("summary of %d messages is ~%d tokens and does not fit the %d-token window next to the %d kept messages (~%d tokens)",
+			len(older), summaryTokens, maxTokens, len(recent), used)
+	}
+
+	summaryMsg := &Message{
+		ID:         GenerateID("msg"),
+		SessionID:  sessionID,
+		Role:       "system",
+		Content:    SummaryPrefix + "\n\n" + summary,
+		CreatedAt:  older[0].CreatedAt,
+		TokenCount: summaryTokens,
+	}
+
+	tx, err := s.db.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, msg := range older {
+		if _, err := tx.Exec(`DELETE FROM messages WHERE id = ?`, msg.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO messages (id, session_id, role, content, created_at, token_count, model, tool_calls, tool_results)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, summaryMsg.ID, summaryMsg.SessionID, summaryMsg.Role, summaryMsg.Content, summaryMsg.CreatedAt,
+		summaryMsg.TokenCount, summaryMsg.Model, summaryMsg.ToolCalls, summaryMsg.ToolResults); err != nil {
+		return nil, err
+	}
+
+	result.SummarizedCount = len(older)
+	result.KeptCount = len(recent)
+	result.TokensAfter = used + summaryMsg.TokenCount
+
+	if _, err := tx.Exec(`UPDATE sessions SET summary = ?, token_count = ?, message_count = ?, updated_at = ? WHERE id = ?`,
+		summary, result.TokensAfter, len(recent)+1, time.Now(), sessionID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestBuildSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		query   string
+		radius  int
+		want    string
+	}{
+		{"whole content", "fix the login bug", "login", 20, "fix the login bug"},
+		{"case-insensitive", "Fix the LOGIN bug today", "login", 4, "...the LOGIN bug..."},
+		{"no match starts at the beginning", "nothing to see here", "absent", 7, "nothing..."},
+		{"whitespace collapsed", "a\n\n  match\tb", "match", 10, "a match b"},
+		// Lowercasing İ and ẞ changes their length in bytes
+		{"dotted capital I before the match", strings.Repeat("İ", 20) + " needle", "needle", 2, "...İ needle"},
+		{"sharp s before the match at the end", strings.Repeat("ẞ", 30) + "end", "END", 1, "...ẞend"},
+		{"multibyte match", "größe Über alles", "über", 0, "...Über..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildSnippet(tt.content, tt.query, tt.radius)
+			if !utf8.ValidString(got) {
+				t.Fatalf("buildSnippet() = %q, not valid UTF-8", got)
+			}
+			if got != tt.want {
+				t.Errorf("buildSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		// summaries are what successive summarize calls return
+		summaries      []string
+		wantErr        string
+		wantCalls      int
+		wantSummarized int
+		wantMessages   int
+	}{
+		{"summary fits", []string{"short"}, "", 1, 4, 3},
+		{"long summary shortened", []string{strings.Repeat("s", 200), "short"}, "", 2, 4, 3},
+		{"summary never fits", []string{strings.Repeat("s", 200), strings.Repeat("s", 150), strings.Repeat("s", 120)}, "does not fit the 40-token window", 3, 0, 6},
+		{"summary stops shrinking", []string{strings.Repeat("s", 200), strings.Repeat("s", 200)}, "does not fit", 2, 0, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database, err := New(Config{Path: filepath.Join(t.TempDir(), "viki.db")})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer database.Close()
+
+			store := NewSessionStore(database)
+			session := &Session{Title: "compact"}
+			if err := store.Create(session); err != nil {
+				t.Fatal(err)
+			}
+			msgStore := NewMessageStore(database)
+			for i := 0; i < 6; i++ {
+				// 10 tokens each: 60 in all, of which the newest 2 are kept
+				msg := &Message{SessionID: session.ID, Role: "user", Content: strings.Repeat("m", 40)}
+				if err := msgStore.Create(msg); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			calls := 0
+			summarize := func(messages []*Message) (string, error) {
+				calls++
+				return tt.summaries[min(calls, len(tt.summaries))-1], nil
+			}
+
+			result, err := store.CompactMessages(session.ID, 40, 2, summarize)
+			if calls != tt.wantCalls {
+				t.Errorf("summarize calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("CompactMessages() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("CompactMessages() error = %v", err)
+			} else {
+				if result.SummarizedCount != tt.wantSummarized {
+					t.Errorf("SummarizedCount = %d, want %d", result.SummarizedCount, tt.wantSummarized)
+				}
+				if result.TokensAfter > 40 {
+					t.Errorf("TokensAfter = %d, over the window", result.TokensAfter)
+				}
+			}
+
+			messages, err := msgStore.ListBySession(session.ID, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(messages) != tt.wantMessages {
+				t.Errorf("messages after compaction = %d, want %d", len(messages), tt.wantMessages)
+			}
+		})
+	}
+}
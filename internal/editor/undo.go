@@ -0,0 +1,290 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Operation is one undoable command run, such as a single 'viki execute'
+type Operation struct {
+	ID        string         `json:"id"`
+	Command   string         `json:"command"`
+	Timestamp time.Time      `json:"timestamp"`
+	Files     []FileSnapshot `json:"files"`
+
+	before map[string][]byte
+}
+
+// FileSnapshot records the state of a file before and after an operation
+// changed it. Snapshot names are files within the operation directory.
+type FileSnapshot struct {
+	Path        string `json:"path"`
+	Existed     bool   `json:"existed"`
+	Before      string `json:"before,omitempty"`
+	ExistsAfter bool   `json:"exists_after"`
+	After       string `json:"after,omitempty"`
+}
+
+// UndoStack is the ordered changelog of operations kept in .sdd/undo. Each
+// operation directory holds a snapshot of every file as it was before and
+// after the operation, so operations can be rolled back newest first and
+// replayed again with Redo.
+type UndoStack struct {
+	projectRoot string
+	dir         string
+}
+
+// undoLog is the on-disk form of the stack. Operations are applied, oldest
+// first; Undone holds rolled-back operations, the most recently undone last.
+type undoLog struct {
+	Operations []*Operation `json:"operations"`
+	Undone     []*Operation `json:"undone,omitempty"`
+}
+
+// NewUndoStack creates an undo stack for the given project root
+func NewUndoStack(projectRoot string) *UndoStack {
+	return &UndoStack{
+		projectRoot: projectRoot,
+		dir:         filepath.Join(projectRoot, ".sdd", "undo"),
+	}
+}
+
+// Begin starts recording an operation. Call Track for every file before
+// changing it, then Commit once the changes are written.
+func (s *UndoStack) Begin(command string) *Operation {
+	now := time.Now()
+	return &Operation{
+		ID:        now.Format("20060102_150405.000000000"),
+		Command:   command,
+		Timestamp: now,
+		before:    make(map[string][]byte),
+	}
+}
+
+// Track snapshots a file, relative to the project root, before op changes
+// it. Tracking the same file twice keeps the first snapshot.
+func (s *UndoStack) Track(op *Operation, path string) error {
+	path = filepath.Clean(path)
+	for _, file := range op.Files {
+		if file.Path == path {
+			return nil
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(s.projectRoot, path))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to snapshot %s: %w", path, err)
+	}
+
+	snapshot := FileSnapshot{Path: path, Existed: err == nil}
+	if snapshot.Existed {
+		snapshot.Before = fmt.Sprintf("%d.before", len(op.Files))
+		op.before[snapshot.Before] = content
+	}
+	op.Files = append(op.Files, snapshot)
+
+	return nil
+}
+
+// Commit snapshots the tracked files as the operation left them, pushes it
+// onto the stack and discards any undone operations, which can no longer be
+// redone on top of the new change.
+func (s *UndoStack) Commit(op *Operation) error {
+	if len(op.Files) == 0 {
+		return nil
+	}
+
+	opDir := filepath.Join(s.dir, op.ID)
+	if err := os.MkdirAll(opDir, 0755); err != nil {
+		return fmt.Errorf("failed to create undo directory: %w", err)
+	}
+
+	for name, content := range op.before {
+		if err := os.WriteFile(filepath.Join(opDir, name), content, 0644); err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+
+	for i := range op.Files {
+		file := &op.Files[i]
+		content, err := os.ReadFile(filepath.Join(s.projectRoot, file.Path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", file.Path, err)
+		}
+
+		file.ExistsAfter = true
+		file.After = fmt.Sprintf("%d.after", i)
+		if err := os.WriteFile(filepath.Join(opDir, file.After), content, 0644); err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+
+	log, err := s.load()
+	if err != nil {
+		return err
+	}
+	log.Operations = append(log.Operations, op)
+
+	for _, undone := range log.Undone {
+		os.RemoveAll(filepath.Join(s.dir, undone.ID))
+	}
+	log.Undone = nil
+
+	return s.save(log)
+}
+
+// List returns the recorded operations, newest first
+func (s *UndoStack) List() ([]*Operation, error) {
+	log, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]*Operation, len(log.Operations))
+	for i, op := range log.Operations {
+		ops[len(ops)-1-i] = op
+	}
+	return ops, nil
+}
+
+// Undo rolls back the last steps operations, newest first, restoring every
+// tracked file to its snapshot and removing files the operation created.
+// It returns the operations that were undone.
+func (s *UndoStack) Undo(steps int) ([]*Operation, error) {
+	log, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var undone []*Operation
+	for len(undone) < steps && len(log.Operations) > 0 {
+		op := log.Operations[len(log.Operations)-1]
+
+		if err := s.restore(op, false); err != nil {
+			return undone, err
+		}
+
+		log.Operations = log.Operations[:len(log.Operations)-1]
+		log.Undone = append(log.Undone, op)
+		if err := s.save(log); err != nil {
+			return undone, err
+		}
+
+		undone = append(undone, op)
+	}
+
+	return undone, nil
+}
+
+// Redo replays the last steps undone operations, most recently undone
+// first, restoring every tracked file to its state after the operation. It
+// returns the operations that were redone.
+func (s *UndoStack) Redo(steps int) ([]*Operation, error) {
+	log, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var redone []*Operation
+	for len(redone) < steps && len(log.Undone) > 0 {
+		op := log.Undone[len(log.Undone)-1]
+
+		if err := s.restore(op, true); err != nil {
+			return redone, err
+		}
+
+		log.Undone = log.Undone[:len(log.Undone)-1]
+		log.Operations = append(log.Operations, op)
+		if err := s.save(log); err != nil {
+			return redone, err
+		}
+
+		redone = append(redone, op)
+	}
+
+	return redone, nil
+}
+
+// Undone returns the operations that can be redone, the next one first
+func (s *UndoStack) Undone() ([]*Operation, error) {
+	log, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]*Operation, len(log.Undone))
+	for i, op := range log.Undone {
+		ops[len(ops)-1-i] = op
+	}
+	return ops, nil
+}
+
+// restore puts every file tracked by op back to its state before op, or
+// after it when after is set
+func (s *UndoStack) restore(op *Operation, after bool) error {
+	for _, file := range op.Files {
+		fullPath := filepath.Join(s.projectRoot, file.Path)
+
+		exists, snapshot := file.Existed, file.Before
+		if after {
+			exists, snapshot = file.ExistsAfter, file.After
+		}
+
+		if !exists {
+			if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s: %w", file.Path, err)
+			}
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(s.dir, op.ID, snapshot))
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot of %s: %w", file.Path, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(fullPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", file.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *UndoStack) load() (*undoLog, error) {
+	log := &undoLog{}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, "stack.json"))
+	if os.IsNotExist(err) {
+		return log, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read undo stack: %w", err)
+	}
+
+	if err := json.Unmarshal(data, log); err != nil {
+		return nil, fmt.Errorf("failed to parse undo stack: %w", err)
+	}
+	return log, nil
+}
+
+func (s *UndoStack) save(log *undoLog) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create undo directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal undo stack: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(s.dir, "stack.json"), data, 0644)
+}
@@ -0,0 +1,119 @@
+package gates
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestApproveArtifact(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		required   int
+		approvers  []string
+		wantCount  int
+		wantStatus string
+	}{
+		{"single approver", ArtifactPending, 1, []string{"ana"}, 1, ArtifactApproved},
+		{"waits for the second approver", ArtifactPending, 2, []string{"ana"}, 1, ArtifactPending},
+		{"two distinct approvers", ArtifactPending, 2, []string{"ana", "ben"}, 2, ArtifactApproved},
+		{"approving twice counts once", ArtifactPending, 2, []string{"ana", "ana"}, 1, ArtifactPending},
+		{"rejected artifact goes back to pending", ArtifactRejected, 2, []string{"ana"}, 1, ArtifactPending},
+		{"raised requirement reopens approval", ArtifactApproved, 3, []string{"ana", "ben"}, 2, ArtifactPending},
+		{"zero required approves at once", ArtifactPending, 0, []string{"ana"}, 1, ArtifactApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			dir := filepath.Join(TracksDir(root), "t1")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			content := "---\nstatus: " + tt.status + "\n---\n\n# Design\n"
+			if err := os.WriteFile(filepath.Join(dir, "design.md"), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			count := 0
+			for _, approver := range tt.approvers {
+				var err error
+				if count, err = ApproveArtifact(root, "t1", "design.md", approver, tt.required); err != nil {
+					t.Fatalf("ApproveArtifact(%s) error = %v", approver, err)
+				}
+			}
+			if count != tt.wantCount {
+				t.Errorf("approvers = %d, want %d", count, tt.wantCount)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "design.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			fm, body, err := UnmarshalFrontmatter(string(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fm.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", fm.Status, tt.wantStatus)
+			}
+			if len(fm.Approvers()) != tt.wantCount {
+				t.Errorf("recorded approvers = %v, want %d", fm.Approvers(), tt.wantCount)
+			}
+			if body != "# Design\n" {
+				t.Errorf("body = %q, want it unchanged", body)
+			}
+		})
+	}
+}
+
+func TestApproveArtifactRequiresApprover(t *testing.T) {
+	if _, err := ApproveArtifact(t.TempDir(), "t1", "design.md", "", 1); err == nil {
+		t.Error("ApproveArtifact() with no approver succeeded")
+	}
+}
+
+func TestApprovers(t *testing.T) {
+	tests := []struct {
+		name      string
+		approvals []Approval
+		want      []string
+	}{
+		{"none", nil, nil},
+		{"in approval order", []Approval{{ApprovedBy: "ben"}, {ApprovedBy: "ana"}}, []string{"ben", "ana"}},
+		{"duplicates counted once", []Approval{{ApprovedBy: "ana"}, {ApprovedBy: "ben"}, {ApprovedBy: "ana"}}, []string{"ana", "ben"}},
+		{"blank approvers skipped", []Approval{{ApprovedBy: ""}, {ApprovedBy: "ana"}}, []string{"ana"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := approvers(tt.approvals); !slices.Equal(got, tt.want) {
+				t.Errorf("approvers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredApprovers(t *testing.T) {
+	policy := &GatePolicy{Approvers: map[string]int{"design": 2, "audit": 0, "task": -1}}
+
+	tests := []struct {
+		phase string
+		want  int
+	}{
+		{"design", 2},
+		{"audit", 1},
+		{"task", 1},
+		{"specify", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phase, func(t *testing.T) {
+			if got := policy.RequiredApprovers(tt.phase); got != tt.want {
+				t.Errorf("RequiredApprovers(%s) = %d, want %d", tt.phase, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package gates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"ultimate-sdd-framework/internal/metrics"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Artifact status values as written in track artifact frontmatter
+const (
+	ArtifactPending  = "PENDING"
+	ArtifactApproved = "APPROVED"
+	ArtifactRejected = "REJECTED"
+)
+
+// Artifact represents a gate artifact stored under .sdd/tracks/<track>/
+type Artifact struct {
+	TrackID  string
+	Name     string
+	Path     string
+	Status   string
+	Reviewer string
+	Feedback string
+	// Approvers are the distinct users who approved the artifact
+	Approvers []string
+	Metadata  map[string]interface{}
+	Body      string
+}
+
+// TracksDir returns the directory holding all tracks for a project
+func TracksDir(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "tracks")
+}
+
+// ParseFrontmatter splits a document into its YAML frontmatter and body.
+// Documents without frontmatter return a nil map and the full content.
+func ParseFrontmatter(content string) (map[string]interface{}, string, error) {
+	if !strings.HasPrefix(strings.TrimLeft(content, "\n"), "---") {
+		return nil, content, nil
+	}
+
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, content, nil
+	}
+
+	var metadata map[string]interface{}
+	if err := yaml.Unmarshal([]byte(parts[1]), &metadata); err != nil {
+		return nil, content, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+
+	return metadata, strings.TrimLeft(parts[2], "\n"), nil
+}
+
+// LoadArtifact reads a single track artifact
+func LoadArtifact(projectRoot, trackID, name string) (*Artifact, error) {
+	path := filepath.Join(TracksDir(projectRoot), trackID, name)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, body, err := ParseFrontmatter(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	artifact := &Artifact{
+		TrackID:  trackID,
+		Name:     name,
+		Path:     path,
+		Metadata: metadata,
+		Body:     body,
+	}
+	fm := frontmatterFromMap(metadata)
+	artifact.Status = fm.Status
+	artifact.Reviewer = fm.Reviewer
+	artifact.Feedback = fm.Feedback
+	artifact.Approvers = fm.Approvers()
+
+	return artifact, nil
+}
+
+// ListArtifacts returns every artifact with frontmatter across all tracks
+func ListArtifacts(projectRoot string) ([]*Artifact, error) {
+	tracks, err := os.ReadDir(TracksDir(projectRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var artifacts []*Artifact
+	for _, track := range tracks {
+		if !track.IsDir() {
+			continue
+		}
+
+		files, err := os.ReadDir(filepath.Join(TracksDir(projectRoot), track.Name()))
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			artifact, err := LoadArtifact(projectRoot, track.Name(), file.Name())
+			if err != nil || artifact.Metadata == nil {
+				continue
+			}
+			artifacts = append(artifacts, artifact)
+		}
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool {
+		if artifacts[i].TrackID != artifacts[j].TrackID {
+			return artifacts[i].TrackID < artifacts[j].TrackID
+		}
+		return artifacts[i].Name < artifacts[j].Name
+	})
+
+	return artifacts, nil
+}
+
+// AwaitingApproval reports whether a status is neither approved nor
+// rejected, e.g. PENDING or a team's IN_REVIEW
+func AwaitingApproval(status string) bool {
+	return status != "" && status != ArtifactApproved && status != ArtifactRejected
+}
+
+// ListPendingArtifacts returns artifacts awaiting approval across all tracks
+func ListPendingArtifacts(projectRoot string) ([]*Artifact, error) {
+	artifacts, err := ListArtifacts(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*Artifact
+	for _, artifact := range artifacts {
+		if AwaitingApproval(artifact.Status) {
+			pending = append(pending, artifact)
+		}
+	}
+	return pending, nil
+}
+
+// SetArtifactStatus rewrites the status field in an artifact's frontmatter,
+// adding frontmatter if the file has none
+func SetArtifactStatus(projectRoot, trackID, name, status string) error {
+	return MarkArtifact(projectRoot, trackID, name, status, "")
+}
+
+// MarkArtifact sets an artifact's status, and its reviewer when one is
+// given. The status must be one the project's gate policy allows.
+func MarkArtifact(projectRoot, trackID, name, status, reviewer string) error {
+	return updateArtifact(projectRoot, trackID, name, func(fm *Frontmatter) {
+		fm.Status = strings.ToUpper(status)
+		if reviewer != "" {
+			fm.Reviewer = reviewer
+		}
+	})
+}
+
+// ApproveArtifact records approver's sign-off on an artifact and marks it
+// APPROVED once required distinct users have approved; until then it keeps
+// its status. Approving twice counts once. It returns the number of
+// distinct approvers.
+func ApproveArtifact(projectRoot, trackID, name, approver string, required int) (int, error) {
+	if approver == "" {
+		return 0, fmt.Errorf("approver is required")
+	}
+
+	count := 0
+	err := updateArtifact(projectRoot, trackID, name, func(fm *Frontmatter) {
+		if !slices.Contains(fm.Approvers(), approver) {
+			fm.Approvals = append(fm.Approvals, Approval{ApprovedBy: approver, ApprovedAt: time.Now()})
+		}
+		count = len(fm.Approvers())
+		fm.Reviewer = approver
+		if count >= required {
+			fm.Status = ArtifactApproved
+		} else if fm.Status == ArtifactRejected || fm.Status == ArtifactApproved {
+			fm.Status = ArtifactPending
+		}
+	})
+	return count, err
+}
+
+// RejectArtifact marks an artifact REJECTED and records the reviewer's feedback
+// in its frontmatter so the owning agent can address it on revision
+func RejectArtifact(projectRoot, trackID, name, feedback string) error {
+	err := updateArtifact(projectRoot, trackID, name, func(fm *Frontmatter) {
+		fm.Status = ArtifactRejected
+		fm.Feedback = strings.TrimSpace(feedback)
+		fm.RejectedAt = time.Now().Format(time.RFC3339)
+		// A rejection starts the sign-off over
+		fm.Approvals = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	metrics.NewStore(projectRoot).Add(metrics.GateRejections, metrics.Labels{"artifact": name}, 1)
+	return nil
+}
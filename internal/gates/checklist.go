@@ -0,0 +1,238 @@
+package gates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Checklist item results
+const (
+	CheckPass       = "PASS"
+	CheckFail       = "FAIL"
+	CheckUnverified = "UNVERIFIED"
+)
+
+// Checklist is a quality checklist an artifact is validated against. It is
+// read from .sdd/checklists/<name>.md:
+//
+//	---
+//	artifact: 1_prd.md
+//	---
+//	# Spec Quality
+//
+//	- [ ] Has measurable acceptance criteria
+//	  - pattern: (?i)acceptance criteria
+//	  - fix: Add acceptance criteria with testable conditions for each story
+//
+// Items with a pattern are checked with it; the others need a reviewer
+// such as the QA agent.
+type Checklist struct {
+	Name     string
+	Artifact string // track artifact checked by default
+	Items    []ChecklistItem
+}
+
+// ChecklistItem is a single check of a checklist
+type ChecklistItem struct {
+	Text    string
+	Pattern *regexp.Regexp // nil when the item needs a reviewer
+	Fix     string         // remediation shown when the item fails
+}
+
+// CheckResult is the outcome of one checklist item
+type CheckResult struct {
+	Item   ChecklistItem
+	Status string
+	Note   string
+}
+
+// builtinChecklists are available without a template in .sdd/checklists
+var builtinChecklists = map[string]string{
+	"spec-quality": `---
+artifact: 1_prd.md
+---
+# Spec Quality
+
+- [ ] Has measurable acceptance criteria
+  - pattern: (?i)acceptance criteria|\bgiven\b.+\bwhen\b.+\bthen\b
+  - fix: Add an "Acceptance Criteria" section with testable, measurable conditions for each story
+- [ ] Describes the users and their goals
+  - pattern: (?i)user stor|as an? [a-z]+.*i want|persona|target users?
+  - fix: Add user stories ("As a <user>, I want <action>, so that <benefit>")
+- [ ] Defines what is out of scope
+  - pattern: (?i)out of scope|non-goals?|not in scope
+  - fix: List what this feature will deliberately not do
+- [ ] States non-functional requirements
+  - pattern: (?i)non-functional|performance|latency|availability|scalab
+  - fix: Add performance, availability and scalability targets
+- [ ] Covers error handling and edge cases
+  - pattern: (?i)edge case|error|failure|invalid
+  - fix: Describe how failures, invalid input and boundary conditions are handled
+- [ ] Requirements are unambiguous and free of undefined terms
+  - fix: Define domain terms and replace vague words ("fast", "easy") with measurable ones
+`,
+	"architecture-quality": `---
+artifact: 2_architecture.md
+---
+# Architecture Quality
+
+- [ ] Describes the components and their responsibilities
+  - pattern: (?i)component|module|service
+  - fix: List each component with its single responsibility
+- [ ] Documents the data model
+  - pattern: (?i)data model|schema|entity|table
+  - fix: Add the entities, their fields and relationships
+- [ ] Identifies integration points and APIs
+  - pattern: (?i)\bapi\b|endpoint|integration|interface
+  - fix: Document each API or external integration with its contract
+- [ ] Addresses security
+  - pattern: (?i)security|authenticat|authoriz|encrypt
+  - fix: Describe authentication, authorization and data protection
+- [ ] Justifies technology choices
+  - fix: Explain why each technology was chosen over the alternatives
+`,
+}
+
+// ChecklistsDir returns the directory holding checklist templates
+func ChecklistsDir(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "checklists")
+}
+
+// LoadChecklist reads a checklist template from .sdd/checklists, falling
+// back to the built-in checklist of the same name
+func LoadChecklist(projectRoot, name string) (*Checklist, error) {
+	name = strings.TrimSuffix(name, ".md")
+
+	content, err := os.ReadFile(filepath.Join(ChecklistsDir(projectRoot), name+".md"))
+	if os.IsNotExist(err) {
+		builtin, ok := builtinChecklists[name]
+		if !ok {
+			return nil, fmt.Errorf("checklist '%s' not found in %s (built-in: %s)",
+				name, ChecklistsDir(projectRoot), strings.Join(BuiltinChecklistNames(), ", "))
+		}
+		content = []byte(builtin)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read checklist: %w", err)
+	}
+
+	return ParseChecklist(name, string(content))
+}
+
+// BuiltinChecklistNames lists the checklists available without a template
+func BuiltinChecklistNames() []string {
+	names := make([]string, 0, len(builtinChecklists))
+	for name := range builtinChecklists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinChecklist returns the template of a built-in checklist
+func BuiltinChecklist(name string) (string, bool) {
+	content, ok := builtinChecklists[name]
+	return content, ok
+}
+
+// ParseChecklist parses a checklist template
+func ParseChecklist(name, content string) (*Checklist, error) {
+	metadata, body, err := ParseFrontmatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("checklist %s: %w", name, err)
+	}
+
+	checklist := &Checklist{Name: name}
+	if artifact, ok := metadata["artifact"].(string); ok {
+		checklist.Artifact = artifact
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if text, ok := cutCheckbox(trimmed); ok {
+			checklist.Items = append(checklist.Items, ChecklistItem{Text: text})
+			continue
+		}
+		if len(checklist.Items) == 0 || !strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+
+		item := &checklist.Items[len(checklist.Items)-1]
+		if pattern, ok := strings.CutPrefix(trimmed, "- pattern:"); ok {
+			compiled, err := regexp.Compile(strings.TrimSpace(pattern))
+			if err != nil {
+				return nil, fmt.Errorf("checklist %s: invalid pattern for %q: %w", name, item.Text, err)
+			}
+			item.Pattern = compiled
+		} else if fix, ok := strings.CutPrefix(trimmed, "- fix:"); ok {
+			item.Fix = strings.TrimSpace(fix)
+		}
+	}
+
+	if len(checklist.Items) == 0 {
+		return nil, fmt.Errorf("checklist %s has no items", name)
+	}
+	return checklist, nil
+}
+
+// cutCheckbox returns the text of a "- [ ]" or "- [x]" list item
+func cutCheckbox(line string) (string, bool) {
+	for _, box := range []string{"- [ ]", "- [x]", "- [X]"} {
+		if text, ok := strings.CutPrefix(line, box); ok {
+			return strings.TrimSpace(text), true
+		}
+	}
+	return "", false
+}
+
+// CheckPatterns runs the pattern items of the checklist against content.
+// Items without a pattern are returned UNVERIFIED for a reviewer to decide.
+func (c *Checklist) CheckPatterns(content string) []CheckResult {
+	results := make([]CheckResult, len(c.Items))
+	for i, item := range c.Items {
+		results[i] = CheckResult{Item: item, Status: CheckUnverified}
+		if item.Pattern == nil {
+			continue
+		}
+
+		if item.Pattern.MatchString(content) {
+			results[i].Status = CheckPass
+		} else {
+			results[i].Status = CheckFail
+			results[i].Note = item.Fix
+		}
+	}
+	return results
+}
+
+// ChecklistReport renders checklist results as a markdown report
+func ChecklistReport(checklist *Checklist, target string, results []CheckResult) string {
+	var report strings.Builder
+
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	report.WriteString(fmt.Sprintf("# Checklist: %s\n\n", checklist.Name))
+	report.WriteString(fmt.Sprintf("Target: %s\n\n", target))
+	report.WriteString(fmt.Sprintf("**%d passed, %d failed, %d unverified**\n\n",
+		counts[CheckPass], counts[CheckFail], counts[CheckUnverified]))
+
+	for _, result := range results {
+		box := "[ ]"
+		if result.Status == CheckPass {
+			box = "[x]"
+		}
+		report.WriteString(fmt.Sprintf("- %s **%s** %s\n", box, result.Status, result.Item.Text))
+		if result.Note != "" {
+			report.WriteString(fmt.Sprintf("  - %s\n", result.Note))
+		}
+	}
+
+	return report.String()
+}
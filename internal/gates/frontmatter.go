@@ -0,0 +1,164 @@
+package gates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Frontmatter is the metadata block at the top of a track artifact. Keys it
+// does not model are kept in Extra and written back unchanged.
+type Frontmatter struct {
+	Status     string
+	Phase      string
+	Reviewer   string
+	Feedback   string
+	RejectedAt string
+	Approvals  []Approval
+	Extra      map[string]interface{}
+}
+
+// Approvers returns the distinct users who approved, in approval order
+func (fm *Frontmatter) Approvers() []string {
+	return approvers(fm.Approvals)
+}
+
+// approvers returns the distinct users of approvals, in order
+func approvers(approvals []Approval) []string {
+	var users []string
+	for _, approval := range approvals {
+		if approval.ApprovedBy != "" && !slices.Contains(users, approval.ApprovedBy) {
+			users = append(users, approval.ApprovedBy)
+		}
+	}
+	return users
+}
+
+// UnmarshalFrontmatter splits an artifact into its frontmatter and body. A
+// document without frontmatter returns an empty Frontmatter.
+func UnmarshalFrontmatter(content string) (*Frontmatter, string, error) {
+	metadata, body, err := ParseFrontmatter(content)
+	if err != nil {
+		return nil, content, err
+	}
+
+	return frontmatterFromMap(metadata), body, nil
+}
+
+// frontmatterFromMap types parsed frontmatter, keeping unknown keys in Extra
+func frontmatterFromMap(metadata map[string]interface{}) *Frontmatter {
+	fm := &Frontmatter{Extra: make(map[string]interface{})}
+	for key, value := range metadata {
+		text, isText := value.(string)
+		switch {
+		case key == "status" && isText:
+			fm.Status = strings.ToUpper(text)
+		case key == "phase" && isText:
+			fm.Phase = text
+		case key == "reviewer" && isText:
+			fm.Reviewer = text
+		case key == "feedback" && isText:
+			fm.Feedback = text
+		case key == "rejected_at" && isText:
+			fm.RejectedAt = text
+		case key == "approvals":
+			fm.Approvals = approvalsFromValue(value)
+		default:
+			fm.Extra[key] = value
+		}
+	}
+	return fm
+}
+
+// approvalsFromValue reads the approvals list of parsed frontmatter,
+// skipping entries without an approver
+func approvalsFromValue(value interface{}) []Approval {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var approvals []Approval
+	if err := yaml.Unmarshal(data, &approvals); err != nil {
+		return nil
+	}
+	return slices.DeleteFunc(approvals, func(a Approval) bool { return a.ApprovedBy == "" })
+}
+
+// Marshal renders the frontmatter followed by the body, modeled keys first
+func (fm *Frontmatter) Marshal(body string) (string, error) {
+	var fields yaml.MapSlice
+	for _, field := range []struct{ key, value string }{
+		{"status", fm.Status},
+		{"phase", fm.Phase},
+		{"reviewer", fm.Reviewer},
+		{"feedback", fm.Feedback},
+		{"rejected_at", fm.RejectedAt},
+	} {
+		if field.value != "" {
+			fields = append(fields, yaml.MapItem{Key: field.key, Value: field.value})
+		}
+	}
+	if len(fm.Approvals) > 0 {
+		fields = append(fields, yaml.MapItem{Key: "approvals", Value: fm.Approvals})
+	}
+
+	keys := make([]string, 0, len(fm.Extra))
+	for key := range fm.Extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, yaml.MapItem{Key: key, Value: fm.Extra[key]})
+	}
+
+	data, err := yaml.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal frontmatter: %w", err)
+	}
+	return fmt.Sprintf("---\n%s---\n\n%s", data, body), nil
+}
+
+// WriteArtifact writes a track artifact with its frontmatter, rejecting a
+// status the project's gate policy does not allow
+func WriteArtifact(projectRoot, trackID, name string, fm *Frontmatter, body string) error {
+	if err := ValidateArtifactStatus(projectRoot, fm.Status); err != nil {
+		return err
+	}
+
+	content, err := fm.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(TracksDir(projectRoot), trackID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+}
+
+// updateArtifact rewrites the frontmatter of an existing artifact, keeping
+// its body
+func updateArtifact(projectRoot, trackID, name string, update func(fm *Frontmatter)) error {
+	path := filepath.Join(TracksDir(projectRoot), trackID, name)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read artifact: %w", err)
+	}
+
+	fm, body, err := UnmarshalFrontmatter(string(content))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	update(fm)
+
+	if err := WriteArtifact(projectRoot, trackID, name, fm, body); err != nil {
+		return fmt.Errorf("failed to write artifact: %w", err)
+	}
+	return nil
+}
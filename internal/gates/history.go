@@ -0,0 +1,221 @@
+package gates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// ArtifactVersion is an archived revision of a track artifact
+type ArtifactVersion struct {
+	Number int
+	Path   string
+}
+
+// HistoryDir returns the directory holding prior artifact versions for a track
+func HistoryDir(projectRoot, trackID string) string {
+	return filepath.Join(TracksDir(projectRoot), trackID, "history")
+}
+
+// historyFileName builds <artifact>.<n><ext>, e.g. 2_architecture.3.md
+func historyFileName(name string, n int) string {
+	ext := filepath.Ext(name)
+	return fmt.Sprintf("%s.%d%s", strings.TrimSuffix(name, ext), n, ext)
+}
+
+// ListArtifactVersions returns the archived versions of an artifact, oldest first
+func ListArtifactVersions(projectRoot, trackID, name string) ([]ArtifactVersion, error) {
+	entries, err := os.ReadDir(HistoryDir(projectRoot, trackID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ext := filepath.Ext(name)
+	prefix := strings.TrimSuffix(name, ext) + "."
+
+	var versions []ArtifactVersion
+	for _, entry := range entries {
+		file := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(file, prefix) || !strings.HasSuffix(file, ext) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(file, prefix), ext))
+		if err != nil {
+			continue
+		}
+		versions = append(versions, ArtifactVersion{
+			Number: n,
+			Path:   filepath.Join(HistoryDir(projectRoot, trackID), file),
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Number < versions[j].Number
+	})
+
+	return versions, nil
+}
+
+// ArchiveArtifact copies the current artifact into the track history before it
+// is overwritten. Nothing is archived if the artifact does not exist yet or if
+// its body is unchanged from newBody.
+func ArchiveArtifact(projectRoot, trackID, name, newBody string) error {
+	path := filepath.Join(TracksDir(projectRoot), trackID, name)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if _, body, err := ParseFrontmatter(string(content)); err == nil && body == strings.TrimLeft(newBody, "\n") {
+		return nil
+	}
+
+	versions, err := ListArtifactVersions(projectRoot, trackID, name)
+	if err != nil {
+		return err
+	}
+	next := 1
+	if len(versions) > 0 {
+		next = versions[len(versions)-1].Number + 1
+	}
+
+	dir := HistoryDir(projectRoot, trackID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, historyFileName(name, next)), content, 0644)
+}
+
+// DiffArtifact returns a unified diff between the latest archived version of
+// an artifact and its current content
+func DiffArtifact(projectRoot, trackID, name string) (string, error) {
+	versions, err := ListArtifactVersions(projectRoot, trackID, name)
+	if err != nil {
+		return "", err
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no previous versions of %s in track %s", name, trackID)
+	}
+
+	previous := versions[len(versions)-1]
+	before, err := os.ReadFile(previous.Path)
+	if err != nil {
+		return "", err
+	}
+	after, err := os.ReadFile(filepath.Join(TracksDir(projectRoot), trackID, name))
+	if err != nil {
+		return "", err
+	}
+
+	return unifiedDiff(
+		filepath.Join("history", filepath.Base(previous.Path)), name,
+		string(before), string(after),
+	), nil
+}
+
+type diffLine struct {
+	op   diffmatchpatch.Operation
+	text string
+}
+
+// unifiedDiff renders a line-based diff in unified format
+func unifiedDiff(fromName, toName, before, after string) string {
+	dmp := diffmatchpatch.New()
+	a, b, lineArray := dmp.DiffLinesToChars(before, after)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lineArray)
+
+	var lines []diffLine
+	for _, d := range diffs {
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text != "" {
+				lines = append(lines, diffLine{op: d.Type, text: strings.TrimSuffix(text, "\n")})
+			}
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	oldLine, newLine := 1, 1
+	for i := 0; i < len(lines); {
+		if lines[i].op == diffmatchpatch.DiffEqual {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are within 2*diffContext lines of each other
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(lines) {
+			if lines[end].op != diffmatchpatch.DiffEqual {
+				end++
+				continue
+			}
+			gap := end
+			for gap < len(lines) && lines[gap].op == diffmatchpatch.DiffEqual {
+				gap++
+			}
+			if gap == len(lines) || gap-end > 2*diffContext {
+				break
+			}
+			end = gap
+		}
+		stop := end + diffContext
+		if stop > len(lines) {
+			stop = len(lines)
+		}
+
+		hunkOld, hunkNew := oldLine-(i-start), newLine-(i-start)
+		oldCount, newCount := 0, 0
+		var body strings.Builder
+		for _, l := range lines[start:stop] {
+			switch l.op {
+			case diffmatchpatch.DiffEqual:
+				body.WriteString(" " + l.text + "\n")
+				oldCount++
+				newCount++
+			case diffmatchpatch.DiffDelete:
+				body.WriteString("-" + l.text + "\n")
+				oldCount++
+			case diffmatchpatch.DiffInsert:
+				body.WriteString("+" + l.text + "\n")
+				newCount++
+			}
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", hunkOld, oldCount, hunkNew, newCount)
+		out.WriteString(body.String())
+
+		for _, l := range lines[i:stop] {
+			if l.op != diffmatchpatch.DiffInsert {
+				oldLine++
+			}
+			if l.op != diffmatchpatch.DiffDelete {
+				newLine++
+			}
+		}
+		i = stop
+	}
+
+	return out.String()
+}
@@ -0,0 +1,143 @@
+package gates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StaleLockTimeout is how long a track lock may go without a heartbeat
+// before another invocation may take it over, e.g. after the process holding
+// it was killed
+const StaleLockTimeout = 2 * time.Minute
+
+// lockHeartbeat is how often a held lock is refreshed
+const lockHeartbeat = StaleLockTimeout / 4
+
+// lockPollInterval is how often a waiting invocation retries the lock
+const lockPollInterval = 250 * time.Millisecond
+
+// TrackLock is a held lock on a track, stopping other viki invocations from
+// writing its artifacts at the same time
+type TrackLock struct {
+	path    string
+	stop    chan struct{}
+	release sync.Once
+}
+
+// lockOwner is the content of a lockfile, shown to waiting invocations
+type lockOwner struct {
+	PID      int       `json:"pid"`
+	Acquired time.Time `json:"acquired"`
+}
+
+// TrackLockPath returns the lockfile of a track
+func TrackLockPath(projectRoot, trackID string) string {
+	return filepath.Join(TracksDir(projectRoot), trackID, ".lock")
+}
+
+// LockTrack acquires the lock of a track, waiting up to wait for another
+// invocation to release it. A lock whose holder stopped refreshing it for
+// StaleLockTimeout is taken over. onWait, when set, is called once if the
+// lock is busy.
+func LockTrack(projectRoot, trackID string, wait time.Duration, onWait func(pid int, since time.Time)) (*TrackLock, error) {
+	path := TrackLockPath(projectRoot, trackID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(wait)
+	waiting := false
+	for {
+		err := createLockFile(path)
+		if err == nil {
+			lock := &TrackLock{path: path, stop: make(chan struct{})}
+			go lock.heartbeat()
+			return lock, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock track %s: %w", trackID, err)
+		}
+
+		info, statErr := os.Stat(path)
+		if statErr != nil {
+			// Released between the two calls
+			continue
+		}
+		if time.Since(info.ModTime()) > StaleLockTimeout {
+			// Only remove the stale file, not one another waiter just created
+			if current, err := os.Stat(path); err == nil && os.SameFile(info, current) {
+				os.Remove(path)
+			}
+			continue
+		}
+
+		owner := readLockOwner(path)
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("track %s is locked by another viki process (pid %d, since %s); wait for it to finish, or remove %s if it is no longer running",
+				trackID, owner.PID, owner.Acquired.Format(time.RFC3339), path)
+		}
+		if !waiting && onWait != nil {
+			onWait(owner.PID, owner.Acquired)
+		}
+		waiting = true
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// Release removes the lockfile; releasing twice is harmless
+func (l *TrackLock) Release() error {
+	var err error
+	l.release.Do(func() {
+		close(l.stop)
+		if removeErr := os.Remove(l.path); removeErr != nil && !os.IsNotExist(removeErr) {
+			err = removeErr
+		}
+	})
+	return err
+}
+
+// heartbeat refreshes the lockfile until the lock is released, so a long
+// phase is not mistaken for a stale lock
+func (l *TrackLock) heartbeat() {
+	ticker := time.NewTicker(lockHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case now := <-ticker.C:
+			os.Chtimes(l.path, now, now)
+		}
+	}
+}
+
+// createLockFile creates the lockfile, failing with os.ErrExist when another
+// invocation holds it
+func createLockFile(path string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	data, err := json.Marshal(lockOwner{PID: os.Getpid(), Acquired: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	return err
+}
+
+// readLockOwner reads who holds a lock, zero values if it cannot be read
+func readLockOwner(path string) lockOwner {
+	var owner lockOwner
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &owner)
+	}
+	return owner
+}
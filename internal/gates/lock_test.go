@@ -0,0 +1,135 @@
+package gates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockTrack(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup prepares the track's lockfile and returns a function run
+		// while LockTrack waits, if any
+		setup      func(t *testing.T, root string) func()
+		wait       time.Duration
+		wantErr    string
+		wantWaited bool
+	}{
+		{
+			name:  "free track",
+			setup: func(t *testing.T, root string) func() { return nil },
+		},
+		{
+			name: "released lock is free again",
+			setup: func(t *testing.T, root string) func() {
+				lock := mustLock(t, root)
+				if err := lock.Release(); err != nil {
+					t.Fatal(err)
+				}
+				if err := lock.Release(); err != nil {
+					t.Fatalf("second Release() error = %v", err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "held lock times out",
+			setup: func(t *testing.T, root string) func() {
+				lock := mustLock(t, root)
+				t.Cleanup(func() { lock.Release() })
+				return nil
+			},
+			wait:       2 * lockPollInterval,
+			wantErr:    "is locked by another viki process (pid",
+			wantWaited: true,
+		},
+		{
+			name: "held lock without waiting",
+			setup: func(t *testing.T, root string) func() {
+				lock := mustLock(t, root)
+				t.Cleanup(func() { lock.Release() })
+				return nil
+			},
+			wantErr: "is locked by another viki process",
+		},
+		{
+			name: "lock released while waiting",
+			setup: func(t *testing.T, root string) func() {
+				lock := mustLock(t, root)
+				return func() { lock.Release() }
+			},
+			wait:       5 * time.Second,
+			wantWaited: true,
+		},
+		{
+			name: "stale lock taken over",
+			setup: func(t *testing.T, root string) func() {
+				path := TrackLockPath(root, "t1")
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(`{"pid":1}`), 0644); err != nil {
+					t.Fatal(err)
+				}
+				old := time.Now().Add(-2 * StaleLockTimeout)
+				if err := os.Chtimes(path, old, old); err != nil {
+					t.Fatal(err)
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			onBusy := tt.setup(t, root)
+
+			waited := false
+			lock, err := LockTrack(root, "t1", tt.wait, func(pid int, since time.Time) {
+				waited = true
+				if pid != os.Getpid() {
+					t.Errorf("onWait pid = %d, want %d", pid, os.Getpid())
+				}
+				if onBusy != nil {
+					go onBusy()
+				}
+			})
+			if waited != tt.wantWaited {
+				t.Errorf("waited = %v, want %v", waited, tt.wantWaited)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LockTrack() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LockTrack() error = %v", err)
+			}
+
+			if owner := readLockOwner(TrackLockPath(root, "t1")); owner.PID != os.Getpid() {
+				t.Errorf("lock owner pid = %d, want %d", owner.PID, os.Getpid())
+			}
+			if err := lock.Release(); err != nil {
+				t.Fatalf("Release() error = %v", err)
+			}
+			if _, err := os.Stat(TrackLockPath(root, "t1")); !os.IsNotExist(err) {
+				t.Errorf("lockfile still present after Release: %v", err)
+			}
+		})
+	}
+}
+
+// mustLock takes the lock of track t1 without waiting
+func mustLock(t *testing.T, root string) *TrackLock {
+	t.Helper()
+	lock, err := LockTrack(root, "t1", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lock
+}
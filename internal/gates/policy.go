@@ -0,0 +1,243 @@
+package gates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"ultimate-sdd-framework/internal/config"
+)
+
+// GatePolicy configures gates that approve themselves when the artifact of
+// a phase passes its checks. It is read from .sdd/gates.yaml:
+//
+//	auto_approve:
+//	  discover: {}            # approve as soon as the artifact exists
+//	  audit:
+//	    verdict: PASS         # the report must state [STATUS: PASS]
+//	  validate:
+//	    min_score: 80         # the report must state a score of at least 80
+//	tests:
+//	  min_pass_rate: 1        # share of tests that must pass in validate
+//	  min_coverage: 70        # statement coverage percent, 0 to skip
+//	statuses: [IN_REVIEW]     # artifact statuses besides PENDING, APPROVED and REJECTED
+//	approvers:
+//	  design: 2               # distinct users who must approve the artifact
+type GatePolicy struct {
+	AutoApprove map[string]AutoApproveRule `yaml:"auto_approve"` // phase -> rule
+	Tests       TestPolicy                 `yaml:"tests,omitempty"`
+	Statuses    []string                   `yaml:"statuses,omitempty"`
+	Approvers   map[string]int             `yaml:"approvers,omitempty"` // phase -> required approvers
+}
+
+// RequiredApprovers returns how many distinct users must approve the
+// artifact of a phase, 1 unless the policy asks for more
+func (p *GatePolicy) RequiredApprovers(phase string) int {
+	if required := p.Approvers[phase]; required > 1 {
+		return required
+	}
+	return 1
+}
+
+// DefaultStatuses are the artifact statuses every project allows; only
+// APPROVED lets a gate pass
+var DefaultStatuses = []string{ArtifactPending, ArtifactApproved, ArtifactRejected}
+
+// AllowedStatuses returns the default statuses followed by the policy's own
+func (p *GatePolicy) AllowedStatuses() []string {
+	statuses := append([]string{}, DefaultStatuses...)
+	for _, status := range p.Statuses {
+		status = strings.ToUpper(strings.TrimSpace(status))
+		if status != "" && !slices.Contains(statuses, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// ValidateStatus returns an error when an artifact status is not allowed
+func (p *GatePolicy) ValidateStatus(status string) error {
+	allowed := p.AllowedStatuses()
+	if !slices.Contains(allowed, strings.ToUpper(status)) {
+		return fmt.Errorf("unknown artifact status '%s' (allowed: %s; add it to 'statuses' in .sdd/gates.yaml)",
+			status, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// ValidateArtifactStatus checks a status against the project's gate policy
+func ValidateArtifactStatus(projectRoot, status string) error {
+	policy, err := LoadPolicy(projectRoot)
+	if err != nil {
+		return err
+	}
+	return policy.ValidateStatus(status)
+}
+
+// TestPolicy is what the validate phase requires of the project's test
+// suite before its report can pass
+type TestPolicy struct {
+	Command     string   `yaml:"command,omitempty"`       // overrides the detected test command
+	MinPassRate *float64 `yaml:"min_pass_rate,omitempty"` // 1 (every test) when unset
+	MinCoverage float64  `yaml:"min_coverage,omitempty"`
+}
+
+// PassRate returns the share of tests that must pass
+func (t TestPolicy) PassRate() float64 {
+	if t.MinPassRate == nil {
+		return 1
+	}
+	return *t.MinPassRate
+}
+
+// AutoApproveRule lists the checks an artifact must pass to be approved
+// without a manual review. A rule without checks approves any artifact that
+// has not been rejected.
+type AutoApproveRule struct {
+	Verdict  string  `yaml:"verdict,omitempty"`   // required [STATUS: ...] verdict
+	MinScore float64 `yaml:"min_score,omitempty"` // minimum reported score
+}
+
+// The verdict and score must sit on a line of their own, such as
+// "[STATUS: PASS]", "**Status:** PASS" or "Overall Score: 85/100", so prose
+// mentioning a status or score is never read as one
+var (
+	verdictPattern = regexp.MustCompile(`(?im)^[\s#>*_-]*\[?[*_]*status[*_]*\s*:\s*[*_]*\s*([a-z_]+)\s*[*_]*\]?[*_]*\s*$`)
+	scorePattern   = regexp.MustCompile(`(?im)^[\s#>*_-]*(?:[a-z]+\s+)?score[*_]*\s*:\s*[*_]*\s*(\d+(?:\.\d+)?)\s*(?:/\s*\d+|%)?\s*[*_]*\s*$`)
+	codeFence      = regexp.MustCompile("(?ms)^\\s*```.*?^\\s*```[^\n]*$")
+)
+
+// PolicyPath returns the file holding the project's gate policy
+func PolicyPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "gates.yaml")
+}
+
+// LoadPolicy reads the project's gate policy, or the one named by the
+// active config profile; without a policy file every gate requires manual
+// approval
+func LoadPolicy(projectRoot string) (*GatePolicy, error) {
+	policy := &GatePolicy{AutoApprove: make(map[string]AutoApproveRule)}
+
+	path := PolicyPath(projectRoot)
+	if _, profile, err := config.ActiveProfile(); err != nil {
+		return nil, err
+	} else if profile != nil && profile.GatePolicy != "" {
+		path = profile.GatePolicy
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectRoot, path)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && path == PolicyPath(projectRoot) {
+		return policy, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gate policy: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse gate policy: %w", err)
+	}
+	if policy.AutoApprove == nil {
+		policy.AutoApprove = make(map[string]AutoApproveRule)
+	}
+	return policy, nil
+}
+
+// AutoApproves reports whether the policy approves the artifact produced by
+// phase, with the reason it does or does not
+func (p *GatePolicy) AutoApproves(phase string, artifact *Artifact) (bool, string) {
+	rule, ok := p.AutoApprove[phase]
+	if !ok {
+		return false, fmt.Sprintf("no auto-approve rule for the %s phase", phase)
+	}
+	if artifact.Status == ArtifactRejected {
+		return false, fmt.Sprintf("%s was rejected", artifact.Name)
+	}
+
+	var passed []string
+
+	if rule.Verdict != "" {
+		verdicts := artifactVerdicts(artifact)
+		switch {
+		case len(verdicts) == 0:
+			return false, fmt.Sprintf("%s reports no verdict (need %s)", artifact.Name, strings.ToUpper(rule.Verdict))
+		case len(verdicts) > 1:
+			return false, fmt.Sprintf("%s reports conflicting verdicts %s (need %s)", artifact.Name, strings.Join(verdicts, ", "), strings.ToUpper(rule.Verdict))
+		case !strings.EqualFold(verdicts[0], rule.Verdict):
+			return false, fmt.Sprintf("%s verdict is %s (need %s)", artifact.Name, verdicts[0], strings.ToUpper(rule.Verdict))
+		}
+		passed = append(passed, "verdict "+verdicts[0])
+	}
+
+	if rule.MinScore > 0 {
+		score, ok := artifactScore(artifact)
+		if !ok {
+			return false, fmt.Sprintf("%s reports no score (need %g)", artifact.Name, rule.MinScore)
+		}
+		if score < rule.MinScore {
+			return false, fmt.Sprintf("%s score %g is below %g", artifact.Name, score, rule.MinScore)
+		}
+		passed = append(passed, fmt.Sprintf("score %g >= %g", score, rule.MinScore))
+	}
+
+	if len(passed) == 0 {
+		return true, fmt.Sprintf("the %s phase is auto-approved", phase)
+	}
+	return true, strings.Join(passed, ", ")
+}
+
+// artifactVerdicts returns the distinct PASS/FAIL style verdicts of an
+// artifact, upper-cased: the verdict in its frontmatter, or else those on
+// verdict lines of its body outside code blocks. More than one means the
+// artifact contradicts itself.
+func artifactVerdicts(artifact *Artifact) []string {
+	if verdict, ok := artifact.Metadata["verdict"].(string); ok && strings.TrimSpace(verdict) != "" {
+		return []string{strings.ToUpper(strings.TrimSpace(verdict))}
+	}
+
+	var verdicts []string
+	for _, match := range verdictPattern.FindAllStringSubmatch(withoutCodeBlocks(artifact.Body), -1) {
+		if verdict := strings.ToUpper(match[1]); !slices.Contains(verdicts, verdict) {
+			verdicts = append(verdicts, verdict)
+		}
+	}
+	return verdicts
+}
+
+// artifactScore returns the score from the artifact's frontmatter, or else
+// the lowest score on a score line of its body outside code blocks
+func artifactScore(artifact *Artifact) (float64, bool) {
+	switch score := artifact.Metadata["score"].(type) {
+	case uint64:
+		return float64(score), true
+	case int64:
+		return float64(score), true
+	case float64:
+		return score, true
+	}
+
+	lowest, found := 0.0, false
+	for _, match := range scorePattern.FindAllStringSubmatch(withoutCodeBlocks(artifact.Body), -1) {
+		score, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			continue
+		}
+		if !found || score < lowest {
+			lowest, found = score, true
+		}
+	}
+	return lowest, found
+}
+
+// withoutCodeBlocks drops fenced code blocks from markdown, where an agent
+// may quote reports or input it was given
+func withoutCodeBlocks(body string) string {
+	return codeFence.ReplaceAllString(body, "")
+}
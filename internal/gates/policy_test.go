@@ -0,0 +1,52 @@
+package gates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutoApproves(t *testing.T) {
+	policy := &GatePolicy{AutoApprove: map[string]AutoApproveRule{
+		"audit":    {Verdict: "PASS"},
+		"validate": {MinScore: 80},
+	}}
+
+	tests := []struct {
+		name       string
+		phase      string
+		body       string
+		metadata   map[string]interface{}
+		want       bool
+		wantReason string
+	}{
+		{"verdict line", "audit", "# Audit\n\n[STATUS: PASS]\n", nil, true, "verdict PASS"},
+		{"bold verdict line", "audit", "**Status:** pass\n", nil, true, "verdict PASS"},
+		{"failing verdict", "audit", "[STATUS: FAIL]\n", nil, false, "verdict is FAIL"},
+		{"verdict in prose", "audit", "The previous status: pass no longer holds.\n[STATUS: FAIL]\n", nil, false, "verdict is FAIL"},
+		{"only prose", "audit", "Nothing here has status: pass yet.\n", nil, false, "reports no verdict"},
+		{"conflicting verdicts", "audit", "[STATUS: FAIL]\n\n[STATUS: PASS]\n", nil, false, "conflicting verdicts FAIL, PASS"},
+		{"verdict quoted in a code block", "audit", "[STATUS: FAIL]\n\n```\n[STATUS: PASS]\n```\n", nil, false, "verdict is FAIL"},
+		{"verdict only in a code block", "audit", "```md\n[STATUS: PASS]\n```\n", nil, false, "reports no verdict"},
+		{"frontmatter verdict", "audit", "[STATUS: FAIL]\n", map[string]interface{}{"verdict": "pass"}, true, "verdict PASS"},
+		{"score line", "validate", "## Summary\n\nOverall Score: 85/100\n", nil, true, "score 85 >= 80"},
+		{"bold score line", "validate", "**Score:** 92%\n", nil, true, "score 92 >= 80"},
+		{"score in prose", "validate", "A score: 95 was expected.\nScore: 60\n", nil, false, "score 60 is below 80"},
+		{"lowest score wins", "validate", "Score: 95\nCoverage score: 70\n", nil, false, "score 70 is below 80"},
+		{"score only in a code block", "validate", "```\nScore: 99\n```\n", nil, false, "reports no score"},
+		{"frontmatter score", "validate", "Score: 10\n", map[string]interface{}{"score": uint64(90)}, true, "score 90 >= 80"},
+		{"no rule", "design", "[STATUS: PASS]\n", nil, false, "no auto-approve rule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact := &Artifact{Name: "report.md", Status: ArtifactPending, Body: tt.body, Metadata: tt.metadata}
+			got, reason := policy.AutoApproves(tt.phase, artifact)
+			if got != tt.want {
+				t.Errorf("AutoApproves() = %v (%s), want %v", got, reason, tt.want)
+			}
+			if !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("AutoApproves() reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
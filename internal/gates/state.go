@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"ultimate-sdd-framework/internal/metrics"
+
 	"github.com/goccy/go-yaml"
 )
 
@@ -186,7 +188,12 @@ func (sm *StateManager) CompletePhase(outputFiles []string) error {
 	state.Phases[currentPhase] = phaseState
 	state.UpdatedAt = now
 
-	return sm.saveState(state)
+	if err := sm.saveState(state); err != nil {
+		return err
+	}
+
+	metrics.NewStore(sm.projectRoot).Add(metrics.PhasesCompleted, metrics.Labels{"phase": string(currentPhase)}, 1)
+	return nil
 }
 
 // GetPhaseOutputPath returns the expected output path for a phase
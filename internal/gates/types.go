@@ -70,6 +70,7 @@ var ValidTransitions = []PhaseTransition{
 	{From: PhaseSpecify, To: PhasePlan, RequiresApproval: false},
 	{From: PhasePlan, To: PhaseTask, RequiresApproval: true}, // Requires approval before task breakdown
 	{From: PhaseTask, To: PhaseExecute, RequiresApproval: false},
+	{From: PhaseSpecify, To: PhaseExecute, RequiresApproval: false}, // Quick workflow: no design or task breakdown
 	{From: PhaseExecute, To: PhaseReview, RequiresApproval: false},
 	{From: PhaseReview, To: PhaseComplete, RequiresApproval: false},
 	// Allow revisions
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/review"
 )
 
 // LearningData represents accumulated learning from development sessions
@@ -19,6 +20,8 @@ type LearningData struct {
 	SuccessMetrics    []SuccessMetric     `json:"success_metrics"`
 	FailurePatterns   []FailurePattern    `json:"failure_patterns"`
 	RuleEvolutions    []RuleEvolution     `json:"rule_evolutions"`
+	ReviewedIssues    map[string]map[string]int `json:"reviewed_issues,omitempty"` // file -> issue -> consecutive reviews raising it
+	RejectedRules     []string            `json:"rejected_rules,omitempty"` // suggested rules not to offer again
 	LastUpdated       time.Time           `json:"last_updated"`
 }
 
@@ -43,6 +46,7 @@ type CodePattern struct {
 	Examples    []string  `json:"examples"`
 	LastUsed    time.Time `json:"last_used"`
 	SuccessRate float64   `json:"success_rate"`
+	Project     string    `json:"project,omitempty"` // ProjectID of the project it was learned in
 }
 
 // SuccessMetric tracks successful patterns and approaches
@@ -57,12 +61,13 @@ type SuccessMetric struct {
 
 // FailurePattern tracks patterns that lead to issues
 type FailurePattern struct {
-	Pattern      string    `json:"pattern"`
-	Description  string    `json:"description"`
-	Consequence  string    `json:"consequence"`
-	Frequency    int       `json:"frequency"`
-	LastOccurred time.Time `json:"last_occurred"`
-	Mitigation   string    `json:"mitigation"`
+	Pattern           string    `json:"pattern"`
+	Description       string    `json:"description"`
+	Consequence       string    `json:"consequence"`
+	Frequency         int       `json:"frequency"`
+	LastOccurred      time.Time `json:"last_occurred"`
+	Mitigation        string    `json:"mitigation"`
+	PendingMitigation bool      `json:"pending_mitigation,omitempty"` // awaiting AI analysis
 }
 
 // RuleEvolution tracks how project rules have evolved
@@ -78,6 +83,7 @@ type RuleEvolution struct {
 // AdaptiveLearner manages the learning and adaptation system
 type AdaptiveLearner struct {
 	projectRoot  string
+	projectID    string
 	learningData LearningData
 	dataPath     string
 	agentSvc     *agents.AgentService
@@ -94,6 +100,7 @@ func NewAdaptiveLearner(projectRoot string) (*AdaptiveLearner, error) {
 
 	learner := &AdaptiveLearner{
 		projectRoot: projectRoot,
+		projectID:   ProjectID(projectRoot),
 		dataPath:    dataPath,
 		agentSvc:    agentSvc,
 	}
@@ -177,6 +184,65 @@ func (al *AdaptiveLearner) LearnFromCodeReview(reviewResults map[string]interfac
 	return al.saveLearningData()
 }
 
+// LearnFromReview learns from a completed automated review. An issue raised
+// by the previous review of a file and gone now was accepted and fixed; one
+// raised again was rejected. Every category found counts towards a recurring
+// failure pattern, once per review.
+func (al *AdaptiveLearner) LearnFromReview(codeReview *review.CodeReview) error {
+	if al.learningData.ReviewedIssues == nil {
+		al.learningData.ReviewedIssues = make(map[string]map[string]int)
+	}
+
+	categories := make(map[string]string) // category -> an example message
+	for _, file := range codeReview.Files {
+		previous := al.learningData.ReviewedIssues[file.Path]
+		current := make(map[string]int)
+
+		for _, issue := range file.Issues {
+			category := issue.Category
+			if category == "" {
+				category = issue.Type
+			}
+			if _, seen := categories[category]; !seen {
+				categories[category] = issue.Message
+			}
+
+			key := category + "|" + issue.Message
+			if _, counted := current[key]; counted {
+				continue
+			}
+			current[key] = previous[key] + 1
+			if current[key] == 2 {
+				al.learnFailurePattern("code_review_suggestion", category, issue.Message)
+			}
+		}
+
+		for key := range previous {
+			if _, raised := current[key]; !raised {
+				category, message, _ := strings.Cut(key, "|")
+				al.learnSuccessfulPattern("code_review_suggestion", category, message)
+			}
+		}
+
+		for _, comment := range file.Comments {
+			al.learnFromReviewComment(comment.Message)
+		}
+
+		if len(current) == 0 {
+			delete(al.learningData.ReviewedIssues, file.Path)
+		} else {
+			al.learningData.ReviewedIssues[file.Path] = current
+		}
+	}
+
+	for category, example := range categories {
+		al.learnFailurePattern("review_issue", category, example)
+	}
+
+	al.learningData.LastUpdated = time.Now()
+	return al.saveLearningData()
+}
+
 // LearnFromPairProgramming records insights from pair programming sessions
 func (al *AdaptiveLearner) LearnFromPairProgramming(sessionData map[string]interface{}) error {
 	if interactions, ok := sessionData["interactions"].([]map[string]interface{}); ok {
@@ -205,21 +271,56 @@ func (al *AdaptiveLearner) GetPersonalizedSuggestions(context, taskType string)
 
 	// Get patterns relevant to the context
 	relevantPatterns := al.getRelevantPatterns(context, taskType)
+	others := al.otherProjectPatterns()
 
 	for _, pattern := range relevantPatterns {
 		if pattern.Confidence > 0.7 && pattern.SuccessRate > 0.8 {
+			reason := fmt.Sprintf("Based on your successful use of this pattern (%.1f%% success rate)", pattern.SuccessRate*100)
+			if n := len(others[pattern.Pattern]); n > 0 {
+				reason += fmt.Sprintf(", also proven in %d other project(s)", n)
+			}
 			suggestion := PersonalizedSuggestion{
 				Type:        "pattern",
 				Title:       pattern.Pattern,
 				Description: pattern.Description,
-				Confidence:  pattern.Confidence,
+				Confidence:  mergedConfidence(&pattern, others[pattern.Pattern]),
 				Examples:    pattern.Examples,
-				Reason:      fmt.Sprintf("Based on your successful use of this pattern (%.1f%% success rate)", pattern.SuccessRate*100),
+				Reason:      reason,
 			}
 			suggestions = append(suggestions, suggestion)
 		}
 	}
 
+	// Patterns proven only in other projects rank below this project's own
+	keys := make([]string, 0, len(others))
+	for key := range others {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if al.hasPattern(key) {
+			continue
+		}
+		var best *CodePattern
+		for i, pattern := range others[key] {
+			if patternMatches(pattern, context, taskType) && pattern.Confidence > 0.7 && pattern.SuccessRate > 0.8 &&
+				(best == nil || pattern.Confidence > best.Confidence) {
+				best = &others[key][i]
+			}
+		}
+		if best == nil {
+			continue
+		}
+		suggestions = append(suggestions, PersonalizedSuggestion{
+			Type:        "pattern",
+			Title:       best.Pattern,
+			Description: best.Description,
+			Confidence:  mergedConfidence(nil, others[key]),
+			Examples:    best.Examples,
+			Reason:      fmt.Sprintf("Proven in %d other project(s) (%.1f%% success rate), not yet in this one", len(others[key]), best.SuccessRate*100),
+		})
+	}
+
 	// Add preference-based suggestions
 	preferenceSuggestions := al.getPreferenceBasedSuggestions(context, taskType)
 	suggestions = append(suggestions, preferenceSuggestions...)
@@ -245,10 +346,14 @@ func (al *AdaptiveLearner) GetPersonalizedSuggestions(context, taskType string)
 func (al *AdaptiveLearner) EvolveRules() ([]RuleEvolutionSuggestion, error) {
 	suggestions := []RuleEvolutionSuggestion{}
 
+	// Failures whose analysis fails stay pending for the next pass
+	al.ProcessPendingMitigations()
+
 	// Analyze failure patterns for rule evolution opportunities
 	for _, failure := range al.learningData.FailurePatterns {
 		if failure.Frequency >= 3 { // Pattern occurs frequently
 			suggestion := RuleEvolutionSuggestion{
+				Category:     al.categorizePattern(failure.Pattern),
 				CurrentRule:  fmt.Sprintf("Avoid: %s", failure.Pattern),
 				SuggestedRule: fmt.Sprintf("Proactively prevent: %s", failure.Pattern),
 				Reason:       fmt.Sprintf("This pattern has caused issues %d times", failure.Frequency),
@@ -265,6 +370,7 @@ func (al *AdaptiveLearner) EvolveRules() ([]RuleEvolutionSuggestion, error) {
 	for _, pattern := range successPatterns {
 		if pattern.SuccessRate > 0.9 && len(pattern.Examples) >= 3 {
 			suggestion := RuleEvolutionSuggestion{
+				Category:     pattern.Category,
 				CurrentRule:  "No specific rule",
 				SuggestedRule: fmt.Sprintf("Best Practice: %s", pattern.Pattern),
 				Reason:       fmt.Sprintf("Highly successful pattern with %.1f%% success rate", pattern.SuccessRate*100),
@@ -275,7 +381,54 @@ func (al *AdaptiveLearner) EvolveRules() ([]RuleEvolutionSuggestion, error) {
 		}
 	}
 
-	return suggestions, nil
+	// Rules already evolved or rejected are not offered again
+	offered := suggestions[:0]
+	for _, suggestion := range suggestions {
+		if !al.ruleDecided(suggestion.SuggestedRule) {
+			offered = append(offered, suggestion)
+		}
+	}
+
+	return offered, nil
+}
+
+// RecordRuleEvolution logs that a suggested rule was accepted into the
+// project's rules
+func (al *AdaptiveLearner) RecordRuleEvolution(suggestion RuleEvolutionSuggestion) error {
+	al.learningData.RuleEvolutions = append(al.learningData.RuleEvolutions, RuleEvolution{
+		OriginalRule:    suggestion.CurrentRule,
+		EvolvedRule:     suggestion.SuggestedRule,
+		Reason:          suggestion.Reason,
+		DateEvolved:     time.Now(),
+		Improvement:     suggestion.Evidence,
+		ValidationScore: suggestion.Confidence,
+	})
+	al.learningData.LastUpdated = time.Now()
+	return al.saveLearningData()
+}
+
+// RejectRuleEvolution records that a suggested rule was rejected so it is
+// not suggested again
+func (al *AdaptiveLearner) RejectRuleEvolution(suggestion RuleEvolutionSuggestion) error {
+	al.learningData.RejectedRules = append(al.learningData.RejectedRules, suggestion.SuggestedRule)
+	al.learningData.LastUpdated = time.Now()
+	return al.saveLearningData()
+}
+
+// ruleDecided reports whether a suggested rule was already accepted or
+// rejected
+func (al *AdaptiveLearner) ruleDecided(rule string) bool {
+	for _, evolution := range al.learningData.RuleEvolutions {
+		if evolution.EvolvedRule == rule {
+			return true
+		}
+	}
+	for _, rejected := range al.learningData.RejectedRules {
+		if rejected == rule {
+			return true
+		}
+	}
+	return false
 }
 
 // PersonalizedSuggestion represents a personalized recommendation
@@ -290,6 +443,7 @@ type PersonalizedSuggestion struct {
 
 // RuleEvolutionSuggestion represents a suggested rule improvement
 type RuleEvolutionSuggestion struct {
+	Category     string  `json:"category"` // category of the pattern behind it
 	CurrentRule  string  `json:"current_rule"`
 	SuggestedRule string  `json:"suggested_rule"`
 	Reason       string  `json:"reason"`
@@ -331,6 +485,7 @@ func (al *AdaptiveLearner) learnSuccessfulPattern(action, context, outcome strin
 			Examples:    []string{outcome},
 			LastUsed:    time.Now(),
 			SuccessRate: 1.0, // First success
+			Project:     al.projectID,
 		}
 		al.learningData.CodePatterns = append(al.learningData.CodePatterns, pattern)
 	}
@@ -352,17 +507,15 @@ func (al *AdaptiveLearner) learnFailurePattern(action, context, outcome string)
 
 	if !found {
 		failure := FailurePattern{
-			Pattern:      patternKey,
-			Description:  fmt.Sprintf("Failed attempt: %s", outcome),
-			Consequence:  outcome,
-			Frequency:    1,
-			LastOccurred: time.Now(),
-			Mitigation:   "Needs analysis", // Will be filled by AI analysis
+			Pattern:           patternKey,
+			Description:       fmt.Sprintf("Failed attempt: %s", outcome),
+			Consequence:       outcome,
+			Frequency:         1,
+			LastOccurred:      time.Now(),
+			Mitigation:        pendingMitigation, // Filled in by ProcessPendingMitigations
+			PendingMitigation: true,
 		}
 		al.learningData.FailurePatterns = append(al.learningData.FailurePatterns, failure)
-
-		// Request AI analysis for mitigation
-		al.analyzeFailureForMitigation(&failure)
 	}
 }
 
@@ -419,9 +572,7 @@ func (al *AdaptiveLearner) getRelevantPatterns(context, taskType string) []CodeP
 	relevant := []CodePattern{}
 
 	for _, pattern := range al.learningData.CodePatterns {
-		// Match context and task type
-		if strings.Contains(strings.ToLower(context), strings.ToLower(pattern.Language)) ||
-		   strings.Contains(strings.ToLower(taskType), strings.ToLower(pattern.Category)) {
+		if patternMatches(pattern, context, taskType) {
 			relevant = append(relevant, pattern)
 		}
 	}
@@ -429,6 +580,23 @@ func (al *AdaptiveLearner) getRelevantPatterns(context, taskType string) []CodeP
 	return relevant
 }
 
+// patternMatches reports whether a pattern's language or category fits the
+// context and task type
+func patternMatches(pattern CodePattern, context, taskType string) bool {
+	return strings.Contains(strings.ToLower(context), strings.ToLower(pattern.Language)) ||
+		strings.Contains(strings.ToLower(taskType), strings.ToLower(pattern.Category))
+}
+
+// hasPattern reports whether this project has learned a pattern
+func (al *AdaptiveLearner) hasPattern(key string) bool {
+	for _, pattern := range al.learningData.CodePatterns {
+		if pattern.Pattern == key {
+			return true
+		}
+	}
+	return false
+}
+
 func (al *AdaptiveLearner) getPreferenceBasedSuggestions(context, taskType string) []PersonalizedSuggestion {
 	suggestions := []PersonalizedSuggestion{}
 
@@ -558,18 +726,6 @@ func (al *AdaptiveLearner) categorizePattern(action string) string {
 	return "general"
 }
 
-func (al *AdaptiveLearner) analyzeFailureForMitigation(failure *FailurePattern) {
-	// Use AI to analyze failure and suggest mitigation
-	prompt := fmt.Sprintf("Analyze this development failure and suggest mitigation:\nPattern: %s\nConsequence: %s\n\nProvide a specific mitigation strategy.",
-		failure.Pattern, failure.Consequence)
-
-	if response, err := al.agentSvc.GetAgentResponse("system", "analyze", prompt, "", ""); err == nil {
-		failure.Mitigation = strings.TrimSpace(response)
-	} else {
-		failure.Mitigation = "AI analysis failed - manual review needed"
-	}
-}
-
 func (al *AdaptiveLearner) loadLearningData() error {
 	data, err := os.ReadFile(al.dataPath)
 	if err != nil {
@@ -590,7 +746,13 @@ func (al *AdaptiveLearner) saveLearningData() error {
 		return err
 	}
 
-	return os.WriteFile(al.dataPath, data, 0644)
+	if err := os.WriteFile(al.dataPath, data, 0644); err != nil {
+		return err
+	}
+
+	// Sharing patterns with other projects is best effort
+	al.syncSharedPatterns()
+	return nil
 }
 
 // GetLearningSummary provides a summary of learned patterns and preferences
@@ -0,0 +1,113 @@
+package learning
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// CrossProjectWeight scales the confidence of a pattern learned in another
+// project, so evidence from the current project counts for more
+const CrossProjectWeight = 0.5
+
+// ProjectID identifies the project a pattern was learned in
+func ProjectID(projectRoot string) string {
+	if abs, err := filepath.Abs(projectRoot); err == nil {
+		return abs
+	}
+	return projectRoot
+}
+
+// SharedPatternsPath returns the store of patterns learned across all
+// projects, ~/.viki/learning/patterns.json
+func SharedPatternsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".viki", "learning", "patterns.json"), nil
+}
+
+// loadSharedPatterns reads the patterns of every project, none if the store
+// does not exist
+func loadSharedPatterns() ([]CodePattern, error) {
+	path, err := SharedPatternsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []CodePattern
+	if err := json.Unmarshal(data, &patterns); err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+// syncSharedPatterns replaces this project's patterns in the shared store
+func (al *AdaptiveLearner) syncSharedPatterns() error {
+	shared, err := loadSharedPatterns()
+	if err != nil {
+		return err
+	}
+
+	patterns := []CodePattern{}
+	for _, pattern := range shared {
+		if pattern.Project != al.projectID {
+			patterns = append(patterns, pattern)
+		}
+	}
+	for _, pattern := range al.learningData.CodePatterns {
+		pattern.Project = al.projectID
+		patterns = append(patterns, pattern)
+	}
+
+	path, err := SharedPatternsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(patterns, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// otherProjectPatterns returns the shared patterns learned in other
+// projects, keyed by pattern
+func (al *AdaptiveLearner) otherProjectPatterns() map[string][]CodePattern {
+	shared, err := loadSharedPatterns()
+	if err != nil {
+		return nil
+	}
+
+	others := make(map[string][]CodePattern)
+	for _, pattern := range shared {
+		if pattern.Project != "" && pattern.Project != al.projectID {
+			others[pattern.Pattern] = append(others[pattern.Pattern], pattern)
+		}
+	}
+	return others
+}
+
+// mergedConfidence combines the evidence for one pattern as independent
+// signals, with other projects' confidence scaled by CrossProjectWeight
+func mergedConfidence(local *CodePattern, others []CodePattern) float64 {
+	doubt := 1.0
+	if local != nil {
+		doubt *= 1 - local.Confidence
+	}
+	for _, other := range others {
+		doubt *= 1 - CrossProjectWeight*other.Confidence
+	}
+	return 1 - doubt
+}
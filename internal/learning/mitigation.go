@@ -0,0 +1,91 @@
+package learning
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MitigationBatchSize bounds how many failure patterns one AI call analyzes
+const MitigationBatchSize = 10
+
+// pendingMitigation is the mitigation of a failure pattern not analyzed yet
+const pendingMitigation = "Needs analysis"
+
+// mitigationLinePattern matches a numbered line of a batched analysis
+var mitigationLinePattern = regexp.MustCompile(`(?m)^\s*(\d+)[.)]\s*(.+?)\s*$`)
+
+// PendingMitigations returns how many failure patterns await analysis
+func (al *AdaptiveLearner) PendingMitigations() int {
+	pending := 0
+	for _, failure := range al.learningData.FailurePatterns {
+		if isPendingMitigation(failure) {
+			pending++
+		}
+	}
+	return pending
+}
+
+// ProcessPendingMitigations has the AI suggest a mitigation for every failure
+// pattern recorded since the last pass, MitigationBatchSize per call, and
+// returns how many it filled in. Recording failures never waits for this.
+func (al *AdaptiveLearner) ProcessPendingMitigations() (int, error) {
+	var pending []int
+	for i, failure := range al.learningData.FailurePatterns {
+		if isPendingMitigation(failure) {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	filled := 0
+	var batchErr error
+	for start := 0; start < len(pending); start += MitigationBatchSize {
+		batch := pending[start:min(start+MitigationBatchSize, len(pending))]
+
+		var prompt strings.Builder
+		prompt.WriteString("Analyze these development failures and suggest a specific mitigation strategy for each.\n")
+		prompt.WriteString("Reply with exactly one line per failure, formatted as '<number>. <mitigation>'.\n\n")
+		for n, i := range batch {
+			failure := al.learningData.FailurePatterns[i]
+			prompt.WriteString(fmt.Sprintf("%d. Pattern: %s\n   Consequence: %s\n", n+1, failure.Pattern, failure.Consequence))
+		}
+
+		response, err := al.agentSvc.GetAgentResponse("inspector", "review", prompt.String(), "", "")
+		if err != nil {
+			batchErr = fmt.Errorf("mitigation analysis failed: %w", err)
+			break
+		}
+
+		for _, match := range mitigationLinePattern.FindAllStringSubmatch(response, -1) {
+			n, _ := strconv.Atoi(match[1])
+			if n < 1 || n > len(batch) {
+				continue
+			}
+			failure := &al.learningData.FailurePatterns[batch[n-1]]
+			if isPendingMitigation(*failure) {
+				failure.Mitigation = match[2]
+				failure.PendingMitigation = false
+				filled++
+			}
+		}
+	}
+
+	if filled > 0 {
+		al.learningData.LastUpdated = time.Now()
+		if err := al.saveLearningData(); err != nil {
+			return filled, err
+		}
+	}
+	return filled, batchErr
+}
+
+// isPendingMitigation reports whether a failure pattern awaits analysis,
+// including those recorded before the flag existed
+func isPendingMitigation(failure FailurePattern) bool {
+	return failure.PendingMitigation || failure.Mitigation == pendingMitigation
+}
@@ -0,0 +1,115 @@
+package lsp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// structureCacheVersion changes whenever the cached FileInfo analysis does,
+// so stale caches are discarded
+const structureCacheVersion = 4
+
+// structureCache is the analysis saved in .sdd/cache/structure.json
+type structureCache struct {
+	Version   int               `json:"version"`
+	TreeHash  string            `json:"tree_hash"`
+	Structure ProjectStructure  `json:"structure"`
+	Files     []FileInfo        `json:"files"`
+	Stamps    map[string]string `json:"stamps"` // path -> size and modification time
+
+	byPath map[string]FileInfo
+}
+
+func (cc *CodebaseContext) cacheDir() string {
+	return filepath.Join(cc.RootPath, ".sdd", "cache")
+}
+
+// StructureCachePath returns the location of the cached project analysis
+func StructureCachePath(rootPath string) string {
+	return filepath.Join(rootPath, ".sdd", "cache", "structure.json")
+}
+
+// loadStructureCache returns the cached analysis, or nil if there is none
+func (cc *CodebaseContext) loadStructureCache() *structureCache {
+	data, err := os.ReadFile(StructureCachePath(cc.RootPath))
+	if err != nil {
+		return nil
+	}
+
+	var cache structureCache
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Version != structureCacheVersion {
+		return nil
+	}
+
+	cache.byPath = make(map[string]FileInfo, len(cache.Files))
+	for _, file := range cache.Files {
+		cache.byPath[file.Path] = file
+	}
+	return &cache
+}
+
+// file returns the cached analysis of path if it was taken at stamp. File
+// contents are not cached, so the content is read back from diskPath.
+func (c *structureCache) file(path, stamp, diskPath string) (FileInfo, bool) {
+	if c == nil || c.Stamps[path] != stamp {
+		return FileInfo{}, false
+	}
+	file, ok := c.byPath[path]
+	if !ok {
+		return FileInfo{}, false
+	}
+	content, err := os.ReadFile(diskPath)
+	if err != nil {
+		return FileInfo{}, false
+	}
+	file.Content = string(content)
+	return file, true
+}
+
+// saveStructureCache records the current analysis. Nothing is written
+// outside initialized projects, and failures only cost a re-analysis.
+func (cc *CodebaseContext) saveStructureCache(treeHash string, stamps map[string]string) {
+	if _, err := os.Stat(filepath.Join(cc.RootPath, ".sdd")); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(structureCache{
+		Version:   structureCacheVersion,
+		TreeHash:  treeHash,
+		Structure: cc.Structure,
+		Files:     cc.Files,
+		Stamps:    stamps,
+	})
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(cc.cacheDir(), 0755); err != nil {
+		return
+	}
+	os.WriteFile(StructureCachePath(cc.RootPath), data, 0644)
+}
+
+func fileStamp(info os.FileInfo) string {
+	return fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano())
+}
+
+// hashStamps returns a hash identifying the analyzed files and their stamps
+func hashStamps(stamps map[string]string) string {
+	paths := make([]string, 0, len(stamps))
+	for path := range stamps {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(h, "%s\x00%s\n", path, stamps[path])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,164 @@
+package lsp
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ChurnRecentWindow is how far back a change counts as recent
+	ChurnRecentWindow = 90 * 24 * time.Hour
+	// churnMinComplexity is the complexity from which a file is considered
+	// for refactoring
+	churnMinComplexity = 10
+	// maxRefactorCandidates caps the churn items of the debt report
+	maxRefactorCandidates = 10
+)
+
+// FileChurn is how often a file changed in the git history
+type FileChurn struct {
+	Commits       int // commits touching the file
+	RecentCommits int // of which within ChurnRecentWindow
+	LastChanged   time.Time
+}
+
+// SetChurn makes AnalyzeBrownfield read the git history to rank complex
+// files that change often as the top refactor candidates
+func (bfc *BrownfieldContext) SetChurn(enabled bool) {
+	bfc.churn = enabled
+}
+
+// gitChurn reads the churn of every file under root from git log, keyed by
+// path relative to root
+func gitChurn(root string, now time.Time) (map[string]FileChurn, error) {
+	out, err := exec.Command("git", "-C", root, "log", "--no-merges", "--relative", "--format=@%ct", "--name-only", "--", ".").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git history: %w", err)
+	}
+
+	churn := make(map[string]FileChurn)
+	var committed time.Time
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if stamp, ok := strings.CutPrefix(line, "@"); ok {
+			seconds, err := strconv.ParseInt(stamp, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected git log line %q", line)
+			}
+			committed = time.Unix(seconds, 0)
+			continue
+		}
+
+		file := churn[line]
+		file.Commits++
+		if now.Sub(committed) <= ChurnRecentWindow {
+			file.RecentCommits++
+		}
+		if committed.After(file.LastChanged) {
+			file.LastChanged = committed
+		}
+		churn[line] = file
+	}
+	return churn, nil
+}
+
+// branchPattern matches the branches of non-Go source
+var branchPattern = regexp.MustCompile(`\b(if|for|while|case|catch|elif|except)\b|&&|\|\|`)
+
+// fileComplexity returns the cyclomatic complexity of a file, summed over
+// its Go functions or estimated from branch keywords for other languages
+func fileComplexity(file FileInfo) int {
+	if file.Type != FileTypeGo {
+		return 1 + len(branchPattern.FindAllStringIndex(file.Content, -1))
+	}
+
+	parsed, err := parser.ParseFile(token.NewFileSet(), file.Path, file.Content, parser.SkipObjectResolution)
+	if err != nil {
+		return 0
+	}
+	complexity := 0
+	ast.Inspect(parsed, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			complexity++
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt, *ast.CaseClause, *ast.CommClause:
+			complexity++
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// churnScore weighs complexity by how often a file changes, recent changes
+// counting double
+func churnScore(complexity int, churn FileChurn) int {
+	return complexity * (churn.Commits + churn.RecentCommits)
+}
+
+// assessChurnDebt ranks the complex files that change most often as
+// refactor candidates, the riskiest first
+func (bfc *BrownfieldContext) assessChurnDebt() []TechnicalDebtItem {
+	type candidate struct {
+		path       string
+		complexity int
+		churn      FileChurn
+		score      int
+	}
+
+	var candidates []candidate
+	for _, file := range bfc.Files {
+		if file.Type == FileTypeConfig || file.Type == FileTypeDoc {
+			continue
+		}
+		churn, ok := bfc.Churn[file.Path]
+		if !ok {
+			continue
+		}
+		complexity := fileComplexity(file)
+		if complexity < churnMinComplexity {
+			continue
+		}
+		candidates = append(candidates, candidate{file.Path, complexity, churn, churnScore(complexity, churn)})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].path < candidates[j].path
+	})
+	if len(candidates) > maxRefactorCandidates {
+		candidates = candidates[:maxRefactorCandidates]
+	}
+
+	debt := []TechnicalDebtItem{}
+	for _, c := range candidates {
+		severity := "Medium"
+		if c.churn.RecentCommits > 0 {
+			severity = "High"
+		}
+		debt = append(debt, TechnicalDebtItem{
+			Issue:          "Refactor Candidate (High Churn & Complexity)",
+			Severity:       severity,
+			Files:          []string{c.path},
+			Description:    fmt.Sprintf("Complexity %d, changed in %d commits (%d in the last %d days, last on %s)", c.complexity, c.churn.Commits, c.churn.RecentCommits, int(ChurnRecentWindow.Hours()/24), c.churn.LastChanged.Format("2006-01-02")),
+			Recommendation: "Refactor before further changes: complex code that changes often is where defects concentrate",
+		})
+	}
+	return debt
+}
@@ -4,7 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
+
+	"ultimate-sdd-framework/internal/analysis"
 )
 
 // CodebaseContext provides LSP-like context analysis for the project
@@ -13,6 +19,13 @@ type CodebaseContext struct {
 	Files        []FileInfo
 	Dependencies map[string][]string
 	Structure    ProjectStructure
+	Skipped      []SkippedFile // files found but left out of the analysis
+
+	maxFiles   int                             // 0 means no limit
+	progress   func(processed, discovered int) // called while files are analyzed
+	pathFilter *analysis.PathFilter
+	// keyFileLimits bound the key files listed in the specify context
+	keyFileLimits KeyFileLimits
 }
 
 // FileInfo represents information about a file in the codebase
@@ -20,7 +33,7 @@ type FileInfo struct {
 	Path     string
 	Type     FileType
 	Language string
-	Content  string
+	Content  string `json:"-"` // read from disk, never cached
 	Size     int64
 	Imports  []string
 }
@@ -49,6 +62,8 @@ type ProjectStructure struct {
 	HasTests        bool
 	EntryPoints     []string
 	ConfigFiles     []string
+	Modules         []GoModule // Go modules, more than one in a monorepo
+	Dependencies    []DependencyVersion // versions declared in manifests
 }
 
 // BrownfieldContext provides comprehensive analysis for existing codebases
@@ -59,6 +74,11 @@ type BrownfieldContext struct {
 	IntegrationPoints  []IntegrationPoint
 	TechnicalDebt      []TechnicalDebtItem
 	Constitution       Constitution
+	Churn              map[string]FileChurn // by file path, when churn analysis ran
+	DebtMarkers        []DebtMarker         // TODO/FIXME/HACK/XXX comments
+	Secrets            []SecretFinding      // credentials committed to git-tracked files
+
+	churn bool // read the git history for refactor candidates
 }
 
 // LegacyPattern represents established patterns in the codebase
@@ -129,8 +149,45 @@ func NewBrownfieldContext(rootPath string) *BrownfieldContext {
 	}
 }
 
-// AnalyzeProject analyzes the entire project structure
+// SetMaxFiles makes AnalyzeProject refuse projects with more than max
+// analyzable files, 0 for no limit
+func (cc *CodebaseContext) SetMaxFiles(max int) {
+	cc.maxFiles = max
+}
+
+// SetProgress sets a function AnalyzeProject reports to as it analyzes the
+// files it discovered
+func (cc *CodebaseContext) SetProgress(progress func(processed, discovered int)) {
+	cc.progress = progress
+}
+
+// SetPathFilter limits analysis to the files the filter selects
+func (cc *CodebaseContext) SetPathFilter(filter *analysis.PathFilter) {
+	cc.pathFilter = filter
+}
+
+// DefaultMaxFiles is the file limit commands apply unless told otherwise
+const DefaultMaxFiles = 50000
+
+// progressInterval is how many files are analyzed between progress reports
+const progressInterval = 500
+
+// AnalyzeProject analyzes the entire project structure. Files unchanged
+// since the analysis cached in .sdd/cache/structure.json are reused rather
+// than re-read, and the structure itself is reused when the tree hash
+// matches.
 func (cc *CodebaseContext) AnalyzeProject() error {
+	type discoveredFile struct {
+		path string
+		info os.FileInfo
+	}
+
+	cache := cc.loadStructureCache()
+	stamps := make(map[string]string)
+	cc.Files = []FileInfo{}
+	cc.Skipped = nil
+	var discovered []discoveredFile
+
 	// Walk through all files
 	err := filepath.WalkDir(cc.RootPath, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -140,8 +197,8 @@ func (cc *CodebaseContext) AnalyzeProject() error {
 		name := d.Name()
 		isDir := d.IsDir()
 
-		// Skip hidden directories and certain files
-		if strings.HasPrefix(name, ".") && isDir {
+		// Skip hidden directories and certain files (but not a root of ".")
+		if strings.HasPrefix(name, ".") && isDir && path != cc.RootPath {
 			if name == ".sdd" || name == ".agents" {
 				return nil // Don't skip our own directories
 			}
@@ -153,18 +210,31 @@ func (cc *CodebaseContext) AnalyzeProject() error {
 			return filepath.SkipDir
 		}
 
+		// The cache describes the tree, so it is not part of it
+		if isDir && path == cc.cacheDir() {
+			return filepath.SkipDir
+		}
+
+		if rel, err := filepath.Rel(cc.RootPath, path); err == nil && path != cc.RootPath {
+			if isDir && cc.pathFilter.SkipsDir(rel) {
+				return filepath.SkipDir
+			}
+			if !isDir && !cc.pathFilter.Matches(rel) {
+				return nil
+			}
+		}
+
 		if !isDir {
-			info, err := d.Info()
-			if err != nil {
-				return err
+			if getFileType(path, strings.ToLower(filepath.Ext(path))) == FileTypeOther {
+				cc.skip(path, SkipUnsupported, "")
+				return nil
 			}
-			fileInfo, err := cc.analyzeFile(path, info)
+
+			info, err := d.Info()
 			if err != nil {
 				return err
 			}
-			if fileInfo != nil {
-				cc.Files = append(cc.Files, *fileInfo)
-			}
+			discovered = append(discovered, discoveredFile{path, info})
 		}
 
 		return nil
@@ -174,26 +244,70 @@ func (cc *CodebaseContext) AnalyzeProject() error {
 		return fmt.Errorf("failed to analyze project: %w", err)
 	}
 
+	if cc.maxFiles > 0 && len(discovered) > cc.maxFiles {
+		return fmt.Errorf("found %d files to analyze, more than the limit of %d: run from a subdirectory to narrow the scope, or raise the limit with --max-files", len(discovered), cc.maxFiles)
+	}
+
+	for i, file := range discovered {
+		relPath := strings.TrimPrefix(file.path, cc.RootPath+"/")
+		stamp := fileStamp(file.info)
+		stamps[relPath] = stamp
+
+		if cached, ok := cache.file(relPath, stamp, file.path); ok {
+			cc.Files = append(cc.Files, cached)
+		} else if fileInfo := cc.analyzeFile(file.path, file.info); fileInfo != nil {
+			cc.Files = append(cc.Files, *fileInfo)
+		}
+
+		if cc.progress != nil && ((i+1)%progressInterval == 0 || i+1 == len(discovered)) {
+			cc.progress(i+1, len(discovered))
+		}
+	}
+
+	sort.Slice(cc.Skipped, func(i, j int) bool { return cc.Skipped[i].Path < cc.Skipped[j].Path })
+	cc.buildDependencyGraph()
+
+	treeHash := hashStamps(stamps)
+	if cache != nil && cache.TreeHash == treeHash {
+		cc.Structure = cache.Structure
+		return nil
+	}
+
 	// Analyze project structure
 	cc.analyzeStructure()
+	cc.saveStructureCache(treeHash, stamps)
 
 	return nil
 }
 
-// analyzeFile analyzes a single file
-func (cc *CodebaseContext) analyzeFile(path string, info os.FileInfo) (*FileInfo, error) {
+// analyzeFile analyzes a single file, recording it as skipped and returning
+// nil when it cannot be analyzed
+func (cc *CodebaseContext) analyzeFile(path string, info os.FileInfo) *FileInfo {
 	ext := strings.ToLower(filepath.Ext(path))
 	fileType := getFileType(path, ext)
 
 	// Only analyze relevant files
 	if fileType == FileTypeOther {
-		return nil, nil
+		cc.skip(path, SkipUnsupported, "")
+		return nil
+	}
+	if info.Size() > MaxAnalyzedFileSize {
+		cc.skip(path, SkipTooLarge, fmt.Sprintf("%d bytes, the limit is %d", info.Size(), MaxAnalyzedFileSize))
+		return nil
 	}
 
 	// Read file content
 	content, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		cc.skip(path, SkipUnreadable, err.Error())
+		return nil
+	}
+
+	if fileType == FileTypeGo {
+		if parseErr := goParseError(strings.TrimPrefix(path, cc.RootPath+"/"), content); parseErr != "" {
+			cc.skip(path, SkipParseError, parseErr)
+			return nil
+		}
 	}
 
 	fileInfo := &FileInfo{
@@ -205,16 +319,23 @@ func (cc *CodebaseContext) analyzeFile(path string, info os.FileInfo) (*FileInfo
 		Imports:  extractImports(string(content), fileType),
 	}
 
-	return fileInfo, nil
+	return fileInfo
 }
 
-// analyzeStructure determines the project structure
+// analyzeStructure determines the project structure, and that of each Go
+// module in it
 func (cc *CodebaseContext) analyzeStructure() {
+	cc.Structure = structureOf(cc.Files)
+	cc.Structure.Modules = cc.detectModules()
+}
+
+// structureOf determines the structure of a set of files
+func structureOf(files []FileInfo) ProjectStructure {
 	structure := ProjectStructure{}
 
 	// Count files by type
 	typeCounts := make(map[FileType]int)
-	for _, file := range cc.Files {
+	for _, file := range files {
 		typeCounts[file.Type]++
 	}
 
@@ -237,7 +358,7 @@ func (cc *CodebaseContext) analyzeStructure() {
 	}
 
 	// Detect framework and features
-	for _, file := range cc.Files {
+	for _, file := range files {
 		content := strings.ToLower(file.Content)
 
 		// Framework detection
@@ -277,8 +398,9 @@ func (cc *CodebaseContext) analyzeStructure() {
 			structure.ConfigFiles = append(structure.ConfigFiles, file.Path)
 		}
 	}
+	structure.Dependencies = dependencyVersions(files)
 
-	cc.Structure = structure
+	return structure
 }
 
 // GetContextForPhase returns relevant context for a specific SDD phase
@@ -329,6 +451,10 @@ func (cc *CodebaseContext) getSpecificationContext() string {
 		ctx.WriteString("- Includes tests\n")
 	}
 
+	if modules := cc.ModulesSummary(); modules != "" {
+		ctx.WriteString("\n" + modules)
+	}
+
 	if len(cc.Structure.EntryPoints) > 0 {
 		ctx.WriteString("\n**Entry Points:**\n")
 		for _, entry := range cc.Structure.EntryPoints {
@@ -337,10 +463,12 @@ func (cc *CodebaseContext) getSpecificationContext() string {
 	}
 
 	ctx.WriteString("\n**Key Files to Consider:**\n")
-	for _, file := range cc.Files {
-		if len(file.Content) < 5000 { // Only include smaller files
-			ctx.WriteString(fmt.Sprintf("- %s (%s)\n", file.Path, file.Language))
-		}
+	keyFiles, omitted := cc.KeyFiles()
+	for _, file := range keyFiles {
+		ctx.WriteString(fmt.Sprintf("- %s (%s)\n", file.Path, file.Language))
+	}
+	if omitted > 0 {
+		ctx.WriteString(fmt.Sprintf("- ... and %d less relevant files\n", omitted))
 	}
 
 	return ctx.String()
@@ -356,6 +484,10 @@ func (cc *CodebaseContext) getPlanningContext() string {
 	ctx.WriteString(fmt.Sprintf("- Main Language: %s\n", cc.Structure.MainLanguage))
 	ctx.WriteString(fmt.Sprintf("- Framework: %s\n", cc.Structure.Framework))
 
+	if modules := cc.ModulesSummary(); modules != "" {
+		ctx.WriteString("\n" + modules)
+	}
+
 	ctx.WriteString("\n**Technology Stack:**\n")
 
 	// Analyze dependencies
@@ -486,6 +618,10 @@ func (cc *CodebaseContext) getReviewContext() string {
 // Helper functions
 
 func getFileType(path, ext string) FileType {
+	if filepath.Base(path) == "go.mod" {
+		return FileTypeConfig
+	}
+
 	switch ext {
 	case ".go":
 		return FileTypeGo
@@ -527,63 +663,27 @@ func getLanguage(ext string) string {
 	}
 }
 
-func extractImports(content string, fileType FileType) []string {
-	var imports []string
+// jsImportPattern matches the module of ES imports and exports and of
+// require calls
+var jsImportPattern = regexp.MustCompile(`(?:\bfrom|^\s*import|\brequire\s*\()\s*['"]([^'"]+)['"]`)
 
+func extractImports(content string, fileType FileType) []string {
 	switch fileType {
 	case FileTypeGo:
-		// Extract Go imports
-		lines := strings.Split(content, "\n")
-		inImport := false
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "import") {
-				if strings.Contains(line, "(") {
-					inImport = true
-					continue
-				} else {
-					// Single import
-					imp := extractGoImport(line)
-					if imp != "" {
-						imports = append(imports, imp)
-					}
-				}
-			} else if inImport {
-				if strings.Contains(line, ")") {
-					inImport = false
-				} else {
-					imp := extractGoImport(line)
-					if imp != "" {
-						imports = append(imports, imp)
-					}
-				}
-			}
-		}
+		return parseGoImports(content)
 	case FileTypeTypeScript, FileTypeJavaScript:
-		// Extract JS/TS imports
-		lines := strings.Split(content, "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "import") || strings.HasPrefix(line, "require") {
-				// Basic import extraction - can be enhanced
-				imports = append(imports, "external_dependency")
+		var imports []string
+		for _, line := range strings.Split(content, "\n") {
+			if match := jsImportPattern.FindStringSubmatch(line); match != nil {
+				imports = append(imports, match[1])
 			}
 		}
+		return imports
+	case FileTypePython:
+		return parsePythonImports(content)
 	}
 
-	return imports
-}
-
-func extractGoImport(line string) string {
-	line = strings.TrimSpace(line)
-	line = strings.Trim(line, `"`)
-	if strings.Contains(line, `"`) {
-		parts := strings.Split(line, `"`)
-		if len(parts) >= 2 {
-			return parts[1]
-		}
-	}
-	return ""
+	return nil
 }
 
 func isEntryPoint(path string, fileType FileType) bool {
@@ -605,20 +705,21 @@ func isConfigFile(path string) bool {
 		"Cargo.toml", "requirements.txt", "Pipfile", ".env", "config.yaml",
 		"config.json", "docker-compose.yml", "Dockerfile",
 	}
-	for _, config := range configFiles {
-		if strings.HasSuffix(path, config) {
-			return true
-		}
-	}
-	return false
+	return slices.Contains(configFiles, filepath.Base(path))
 }
 
 func (cc *CodebaseContext) analyzeTechStack() map[string][]string {
+	return techStackOf(cc.Files)
+}
+
+// techStackOf detects the languages, frameworks and databases of a set of
+// files, each category sorted
+func techStackOf(files []FileInfo) map[string][]string {
 	stack := make(map[string][]string)
 
 	technologies := make(map[string]bool)
 
-	for _, file := range cc.Files {
+	for _, file := range files {
 		content := strings.ToLower(file.Content)
 
 		// Web frameworks
@@ -670,6 +771,13 @@ func (cc *CodebaseContext) analyzeTechStack() map[string][]string {
 		}
 	}
 
+	// Manifests declare what the code may only hint at, with versions
+	labels := make(map[string][]string)
+	for _, dependency := range dependencyVersions(files) {
+		technologies[dependency.Technology] = true
+		labels[dependency.Technology] = append(labels[dependency.Technology], dependency.Label())
+	}
+
 	// Categorize technologies
 	stack["Languages"] = []string{}
 	stack["Frameworks"] = []string{}
@@ -677,17 +785,24 @@ func (cc *CodebaseContext) analyzeTechStack() map[string][]string {
 	stack["Tools"] = []string{}
 
 	for tech := range technologies {
+		names := []string{tech}
+		if versioned, ok := labels[tech]; ok {
+			names = versioned
+		}
 		switch tech {
 		case "go", "typescript", "javascript", "python", "rust":
-			stack["Languages"] = append(stack["Languages"], tech)
-		case "gin", "echo", "fiber", "express", "react", "vue":
-			stack["Frameworks"] = append(stack["Frameworks"], tech)
+			stack["Languages"] = append(stack["Languages"], names...)
+		case "gin", "echo", "fiber", "chi", "express", "react", "vue", "next", "angular", "django", "flask", "fastapi":
+			stack["Frameworks"] = append(stack["Frameworks"], names...)
 		case "postgresql", "mysql", "mongodb", "redis":
-			stack["Databases"] = append(stack["Databases"], tech)
+			stack["Databases"] = append(stack["Databases"], names...)
 		default:
-			stack["Tools"] = append(stack["Tools"], tech)
+			stack["Tools"] = append(stack["Tools"], names...)
 		}
 	}
+	for _, technologies := range stack {
+		sort.Strings(technologies)
+	}
 
 	return stack
 }
@@ -762,6 +877,14 @@ func (bfc *BrownfieldContext) AnalyzeBrownfield() error {
 		return fmt.Errorf("failed to map integration points: %w", err)
 	}
 
+	// Read the change history when asked to; without git history the
+	// refactor candidates are simply left out
+	if bfc.churn {
+		if churn, err := gitChurn(bfc.RootPath, time.Now()); err == nil {
+			bfc.Churn = churn
+		}
+	}
+
 	// Assess technical debt
 	if err := bfc.assessTechnicalDebt(); err != nil {
 		return fmt.Errorf("failed to assess technical debt: %w", err)
@@ -812,6 +935,9 @@ func (bfc *BrownfieldContext) identifyForbiddenPatterns() error {
 	performancePatterns := bfc.checkPerformancePatterns()
 	forbidden = append(forbidden, performancePatterns...)
 
+	// Check git-tracked files for committed secrets
+	forbidden = append(forbidden, bfc.checkCommittedSecrets()...)
+
 	bfc.ForbiddenPatterns = forbidden
 	return nil
 }
@@ -844,10 +970,18 @@ func (bfc *BrownfieldContext) mapIntegrationPoints() error {
 func (bfc *BrownfieldContext) assessTechnicalDebt() error {
 	debt := []TechnicalDebtItem{}
 
+	// Complex files that change often are the top refactor candidates
+	if bfc.Churn != nil {
+		debt = append(debt, bfc.assessChurnDebt()...)
+	}
+
 	// Code quality debt
 	qualityDebt := bfc.assessCodeQualityDebt()
 	debt = append(debt, qualityDebt...)
 
+	// Debt the code's own comments admit to
+	debt = append(debt, bfc.assessCommentDebt()...)
+
 	// Architecture debt
 	archDebt := bfc.assessArchitectureDebt()
 	debt = append(debt, archDebt...)
@@ -1015,6 +1149,10 @@ func (bfc *BrownfieldContext) GenerateCONTEXTFile() string {
 		ctx.WriteString(fmt.Sprintf("- %s\n", entry))
 	}
 
+	if modules := bfc.ModulesSummary(); modules != "" {
+		ctx.WriteString("\n" + modules)
+	}
+
 	ctx.WriteString("\n---\n*Generated by Ultimate SDD Framework - Brownfield Discovery Phase*\n")
 	ctx.WriteString("*This document should be updated whenever the system architecture changes.*\n")
 
@@ -1334,30 +1472,15 @@ func (bfc *BrownfieldContext) assessCodeQualityDebt() []TechnicalDebtItem {
 func (bfc *BrownfieldContext) assessArchitectureDebt() []TechnicalDebtItem {
 	debt := []TechnicalDebtItem{}
 
-	// Check for circular dependencies (simplified)
-	fileMap := make(map[string][]string)
-	for _, file := range bfc.Files {
-		fileMap[file.Path] = file.Imports
-	}
-
-	// This is a simplified check - real circular dependency detection is more complex
-	for file, imports := range fileMap {
-		for _, imp := range imports {
-			if importedFile, exists := fileMap[imp]; exists {
-				for _, reverseImp := range importedFile {
-					if reverseImp == file {
-						debt = append(debt, TechnicalDebtItem{
-							Issue:        "Potential Circular Dependency",
-							Severity:     "High",
-							Files:        []string{file, imp},
-							Description:  "Files may have circular import dependencies",
-							Recommendation: "Refactor to break circular dependencies using interfaces or dependency injection",
-						})
-						break
-					}
-				}
-			}
-		}
+	// Check for circular dependencies in the import graph
+	for _, cycle := range bfc.DependencyCycles() {
+		debt = append(debt, TechnicalDebtItem{
+			Issue:        "Circular Dependency",
+			Severity:     "High",
+			Files:        cycle,
+			Description:  fmt.Sprintf("%s import each other in a cycle", strings.Join(cycle, ", ")),
+			Recommendation: "Refactor to break circular dependencies using interfaces or dependency injection",
+		})
 	}
 
 	return debt
@@ -1426,8 +1549,13 @@ func (bfc *BrownfieldContext) loadConstitution() error {
 }
 
 func (bfc *BrownfieldContext) createDefaultConstitution() {
+	techStack := []string{bfc.Structure.MainLanguage, bfc.Structure.Framework}
+	for _, dependency := range bfc.Structure.Dependencies {
+		techStack = append(techStack, dependency.Label())
+	}
+
 	bfc.Constitution = Constitution{
-		TechStack:         []string{bfc.Structure.MainLanguage, bfc.Structure.Framework},
+		TechStack:         techStack,
 		ArchitecturalRules: []string{"Follow established patterns", "Maintain separation of concerns"},
 		CodingStandards:   []string{"Follow language conventions", "Consistent error handling"},
 		IntegrationRules:  []string{"Use existing integration points", "Maintain API contracts"},
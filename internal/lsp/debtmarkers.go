@@ -0,0 +1,171 @@
+package lsp
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// debtMarkerMinCount is how many markers a module needs before its
+	// density is considered
+	debtMarkerMinCount = 10
+	// debtMarkerDensity is the markers per 1000 lines from which a module
+	// is a debt hotspot, and debtMarkerHighDensity where it becomes severe
+	debtMarkerDensity     = 5.0
+	debtMarkerHighDensity = 20.0
+	// debtMarkerTopFiles is how many of a hotspot's files are listed
+	debtMarkerTopFiles = 5
+)
+
+// DebtMarkerKinds are the comment markers counted as debt
+var DebtMarkerKinds = []string{"TODO", "FIXME", "HACK", "XXX"}
+
+var (
+	debtMarkerPattern = regexp.MustCompile(`\b(TODO|FIXME|HACK|XXX)\b`)
+	// commentLinePattern matches a marker in a line comment of languages
+	// parsed without an AST
+	commentLinePattern = regexp.MustCompile(`(//|#|/\*|^\s*\*|--)[^\n]*\b(TODO|FIXME|HACK|XXX)\b`)
+)
+
+// DebtMarker is a TODO, FIXME, HACK or XXX comment
+type DebtMarker struct {
+	Path string
+	Line int
+	Kind string
+	Text string
+}
+
+// findDebtMarkers returns the debt markers in a source file's comments,
+// read from the AST for Go
+func findDebtMarkers(file FileInfo) []DebtMarker {
+	if file.Type == FileTypeGo {
+		fset := token.NewFileSet()
+		parsed, err := parser.ParseFile(fset, file.Path, file.Content, parser.ParseComments|parser.SkipObjectResolution)
+		if err == nil {
+			var markers []DebtMarker
+			for _, group := range parsed.Comments {
+				for _, comment := range group.List {
+					line := fset.Position(comment.Pos()).Line
+					for i, text := range strings.Split(comment.Text, "\n") {
+						if match := debtMarkerPattern.FindString(text); match != "" {
+							markers = append(markers, DebtMarker{file.Path, line + i, match, strings.TrimSpace(text)})
+						}
+					}
+				}
+			}
+			return markers
+		}
+	}
+
+	var markers []DebtMarker
+	for i, text := range strings.Split(file.Content, "\n") {
+		if match := commentLinePattern.FindStringSubmatch(text); match != nil {
+			markers = append(markers, DebtMarker{file.Path, i + 1, match[2], strings.TrimSpace(text)})
+		}
+	}
+	return markers
+}
+
+// assessCommentDebt collects the debt markers of the codebase and reports
+// the modules where they are dense as debt hotspots, the most marked first
+func (bfc *BrownfieldContext) assessCommentDebt() []TechnicalDebtItem {
+	type module struct {
+		dir     string
+		lines   int
+		markers int
+		kinds   map[string]int
+		files   map[string]int
+	}
+
+	modules := make(map[string]*module)
+	bfc.DebtMarkers = nil
+	for _, file := range bfc.Files {
+		if file.Type == FileTypeConfig || file.Type == FileTypeDoc || isFrameworkFile(file.Path) {
+			continue
+		}
+		markers := findDebtMarkers(file)
+
+		dir := filepath.Dir(file.Path)
+		m, ok := modules[dir]
+		if !ok {
+			m = &module{dir: dir, kinds: make(map[string]int), files: make(map[string]int)}
+			modules[dir] = m
+		}
+		m.lines += strings.Count(file.Content, "\n") + 1
+		for _, marker := range markers {
+			m.markers++
+			m.kinds[marker.Kind]++
+			m.files[marker.Path]++
+		}
+		bfc.DebtMarkers = append(bfc.DebtMarkers, markers...)
+	}
+
+	var hotspots []*module
+	for _, m := range modules {
+		if m.markers >= debtMarkerMinCount && markerDensity(m.markers, m.lines) >= debtMarkerDensity {
+			hotspots = append(hotspots, m)
+		}
+	}
+	sort.Slice(hotspots, func(i, j int) bool {
+		if hotspots[i].markers != hotspots[j].markers {
+			return hotspots[i].markers > hotspots[j].markers
+		}
+		return hotspots[i].dir < hotspots[j].dir
+	})
+
+	debt := []TechnicalDebtItem{}
+	for _, m := range hotspots {
+		density := markerDensity(m.markers, m.lines)
+		severity := "Medium"
+		if density >= debtMarkerHighDensity {
+			severity = "High"
+		}
+
+		var kinds []string
+		for _, kind := range DebtMarkerKinds {
+			if count := m.kinds[kind]; count > 0 {
+				kinds = append(kinds, fmt.Sprintf("%d %s", count, kind))
+			}
+		}
+
+		files := make([]string, 0, len(m.files))
+		for path := range m.files {
+			files = append(files, path)
+		}
+		sort.Slice(files, func(i, j int) bool {
+			if m.files[files[i]] != m.files[files[j]] {
+				return m.files[files[i]] > m.files[files[j]]
+			}
+			return files[i] < files[j]
+		})
+		if len(files) > debtMarkerTopFiles {
+			files = files[:debtMarkerTopFiles]
+		}
+		var top []string
+		for _, path := range files {
+			top = append(top, fmt.Sprintf("%s (%d)", filepath.Base(path), m.files[path]))
+		}
+
+		debt = append(debt, TechnicalDebtItem{
+			Issue:          "Comment-marked Debt",
+			Severity:       severity,
+			Files:          files,
+			Description:    fmt.Sprintf("%s has %d debt markers (%s), %.1f per 1000 lines; most in %s", m.dir, m.markers, strings.Join(kinds, ", "), density, strings.Join(top, ", ")),
+			Recommendation: "Triage the markers: fix what is cheap, turn the rest into tracked issues and remove the comments",
+		})
+	}
+	return debt
+}
+
+// markerDensity returns the markers per 1000 lines
+func markerDensity(markers, lines int) float64 {
+	if lines == 0 {
+		return 0
+	}
+	return float64(markers) * 1000 / float64(lines)
+}
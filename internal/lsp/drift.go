@@ -0,0 +1,234 @@
+package lsp
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// StoredContext is the part of a generated CONTEXT file that can drift from
+// the code: the forbidden patterns, integration points and technical debt
+type StoredContext struct {
+	ForbiddenPatterns []ForbiddenPattern
+	IntegrationPoints []IntegrationPoint
+	TechnicalDebt     []TechnicalDebtItem
+}
+
+// ContextDrift lists where a stored context no longer matches the code
+type ContextDrift struct {
+	// RemovedIntegrationPoints are listed in the context but no longer found
+	RemovedIntegrationPoints []IntegrationPoint
+	// AddedIntegrationPoints are found in the code but not in the context
+	AddedIntegrationPoints []IntegrationPoint
+	// NewForbiddenOccurrences holds, per pattern, the occurrences the
+	// context does not list
+	NewForbiddenOccurrences []ForbiddenPattern
+	ResolvedDebt            []TechnicalDebtItem
+	IntroducedDebt          []TechnicalDebtItem
+}
+
+var (
+	contextItemHeading = regexp.MustCompile(`^###\s+\d+\.\s+(.+)$`)
+	contextField       = regexp.MustCompile(`^\*\*([^*]+):\*\*\s*(.*)$`)
+	integrationTitle   = regexp.MustCompile(`^(.+)\s+\(([^()]*)\)$`)
+)
+
+// ParseCONTEXTFile reads the forbidden patterns, integration points and
+// technical debt back from a file written by GenerateCONTEXTFile
+func ParseCONTEXTFile(content string) *StoredContext {
+	stored := &StoredContext{}
+	section := ""
+	var list *[]string
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if strings.HasPrefix(line, "## ") {
+			switch {
+			case strings.Contains(line, "Forbidden Patterns"):
+				section = "forbidden"
+			case strings.Contains(line, "Integration Points"):
+				section = "integration"
+			case strings.Contains(line, "Technical Debt"):
+				section = "debt"
+			default:
+				section = ""
+			}
+			list = nil
+			continue
+		}
+		if section == "" {
+			continue
+		}
+
+		if match := contextItemHeading.FindStringSubmatch(line); match != nil {
+			title := strings.TrimSpace(match[1])
+			switch section {
+			case "forbidden":
+				stored.ForbiddenPatterns = append(stored.ForbiddenPatterns, ForbiddenPattern{Pattern: title})
+			case "integration":
+				point := IntegrationPoint{Name: title}
+				if parts := integrationTitle.FindStringSubmatch(title); parts != nil {
+					point.Name, point.Type = parts[1], parts[2]
+				}
+				stored.IntegrationPoints = append(stored.IntegrationPoints, point)
+			case "debt":
+				stored.TechnicalDebt = append(stored.TechnicalDebt, TechnicalDebtItem{Issue: title})
+			}
+			list = nil
+			continue
+		}
+
+		if strings.HasPrefix(line, "- ") && list != nil {
+			*list = append(*list, strings.TrimSpace(strings.TrimPrefix(line, "- ")))
+			continue
+		}
+
+		match := contextField.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		name, value := match[1], strings.TrimSpace(match[2])
+		list = nil
+
+		switch section {
+		case "forbidden":
+			if len(stored.ForbiddenPatterns) == 0 {
+				continue
+			}
+			pattern := &stored.ForbiddenPatterns[len(stored.ForbiddenPatterns)-1]
+			switch name {
+			case "Severity":
+				pattern.Severity = value
+			case "Description":
+				pattern.Description = value
+			case "Recommended":
+				pattern.Recommended = value
+			case "Found in":
+				list = &pattern.Occurrences
+			}
+		case "integration":
+			if len(stored.IntegrationPoints) == 0 {
+				continue
+			}
+			point := &stored.IntegrationPoints[len(stored.IntegrationPoints)-1]
+			switch name {
+			case "Description":
+				point.Description = value
+			case "Files":
+				list = &point.Files
+			case "Dependencies":
+				list = &point.Dependencies
+			}
+		case "debt":
+			if len(stored.TechnicalDebt) == 0 {
+				continue
+			}
+			debt := &stored.TechnicalDebt[len(stored.TechnicalDebt)-1]
+			switch name {
+			case "Severity":
+				debt.Severity = value
+			case "Description":
+				debt.Description = value
+			case "Recommendation":
+				debt.Recommendation = value
+			case "Affected files":
+				list = &debt.Files
+			}
+		}
+	}
+
+	return stored
+}
+
+// VerifyAgainst compares the analyzed codebase with a stored context.
+// AnalyzeBrownfield must have run first.
+func (bfc *BrownfieldContext) VerifyAgainst(stored *StoredContext) *ContextDrift {
+	drift := &ContextDrift{}
+
+	current := make(map[string]bool)
+	for _, point := range bfc.IntegrationPoints {
+		current[integrationKey(point)] = true
+	}
+	listed := make(map[string]bool)
+	for _, point := range stored.IntegrationPoints {
+		listed[integrationKey(point)] = true
+		if !current[integrationKey(point)] {
+			drift.RemovedIntegrationPoints = append(drift.RemovedIntegrationPoints, point)
+		}
+	}
+	for _, point := range bfc.IntegrationPoints {
+		if !listed[integrationKey(point)] {
+			drift.AddedIntegrationPoints = append(drift.AddedIntegrationPoints, point)
+			// Points are found per file, so one file can yield the same
+			// point more than once
+			listed[integrationKey(point)] = true
+		}
+	}
+
+	known := make(map[string]bool)
+	for _, pattern := range stored.ForbiddenPatterns {
+		for _, occurrence := range pattern.Occurrences {
+			known[pattern.Pattern+"\x00"+occurrence] = true
+		}
+	}
+	byPattern := make(map[string]int)
+	for _, pattern := range bfc.ForbiddenPatterns {
+		for _, occurrence := range pattern.Occurrences {
+			key := pattern.Pattern + "\x00" + occurrence
+			if known[key] {
+				continue
+			}
+			known[key] = true
+
+			i, ok := byPattern[pattern.Pattern]
+			if !ok {
+				i = len(drift.NewForbiddenOccurrences)
+				byPattern[pattern.Pattern] = i
+				added := pattern
+				added.Occurrences = nil
+				drift.NewForbiddenOccurrences = append(drift.NewForbiddenOccurrences, added)
+			}
+			drift.NewForbiddenOccurrences[i].Occurrences = append(drift.NewForbiddenOccurrences[i].Occurrences, occurrence)
+		}
+	}
+
+	currentDebt := make(map[string]bool)
+	for _, debt := range bfc.TechnicalDebt {
+		currentDebt[debtKey(debt)] = true
+	}
+	listedDebt := make(map[string]bool)
+	for _, debt := range stored.TechnicalDebt {
+		listedDebt[debtKey(debt)] = true
+		if !currentDebt[debtKey(debt)] {
+			drift.ResolvedDebt = append(drift.ResolvedDebt, debt)
+		}
+	}
+	for _, debt := range bfc.TechnicalDebt {
+		if !listedDebt[debtKey(debt)] {
+			drift.IntroducedDebt = append(drift.IntroducedDebt, debt)
+			listedDebt[debtKey(debt)] = true
+		}
+	}
+
+	return drift
+}
+
+// HasDrift reports whether the stored context differs from the code at all
+func (d *ContextDrift) HasDrift() bool {
+	return len(d.RemovedIntegrationPoints) > 0 || len(d.AddedIntegrationPoints) > 0 ||
+		len(d.NewForbiddenOccurrences) > 0 || len(d.ResolvedDebt) > 0 || len(d.IntroducedDebt) > 0
+}
+
+// integrationKey identifies an integration point across analyses
+func integrationKey(point IntegrationPoint) string {
+	return point.Name + "\x00" + point.Type
+}
+
+// debtKey identifies a debt item across analyses by its issue and the files
+// it affects
+func debtKey(debt TechnicalDebtItem) string {
+	files := append([]string(nil), debt.Files...)
+	sort.Strings(files)
+	return debt.Issue + "\x00" + strings.Join(files, "\x00")
+}
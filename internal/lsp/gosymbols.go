@@ -0,0 +1,127 @@
+package lsp
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+)
+
+// parseGoFileSymbols extracts functions, methods, types and package-level
+// variables and constants from Go source using the AST
+func parseGoFileSymbols(content []byte, file string) ([]Symbol, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, content, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []Symbol
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			sym := Symbol{
+				Name:       d.Name.Name,
+				Kind:       "function",
+				File:       file,
+				Line:       fset.Position(d.Name.Pos()).Line,
+				Signature:  goSignature(fset, d),
+				DocComment: docText(d.Doc),
+				Exported:   d.Name.IsExported(),
+			}
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				sym.Kind = "method"
+				sym.Parent = receiverTypeName(d.Recv.List[0].Type)
+			}
+			symbols = append(symbols, sym)
+
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					doc := s.Doc
+					if doc == nil {
+						doc = d.Doc
+					}
+					symbols = append(symbols, Symbol{
+						Name:       s.Name.Name,
+						Kind:       goTypeKind(s.Type),
+						File:       file,
+						Line:       fset.Position(s.Name.Pos()).Line,
+						DocComment: docText(doc),
+						Exported:   s.Name.IsExported(),
+					})
+				case *ast.ValueSpec:
+					kind := "variable"
+					if d.Tok == token.CONST {
+						kind = "constant"
+					}
+					for _, name := range s.Names {
+						if name.Name == "_" {
+							continue
+						}
+						symbols = append(symbols, Symbol{
+							Name:     name.Name,
+							Kind:     kind,
+							File:     file,
+							Line:     fset.Position(name.Pos()).Line,
+							Exported: name.IsExported(),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return symbols, nil
+}
+
+// goSignature renders a function declaration without its body
+func goSignature(fset *token.FileSet, fn *ast.FuncDecl) string {
+	decl := *fn
+	decl.Body = nil
+	decl.Doc = nil
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, &decl); err != nil {
+		return fn.Name.Name
+	}
+	return buf.String()
+}
+
+// receiverTypeName returns the type name of a method receiver, without
+// pointer or type parameters
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// goTypeKind classifies a type declaration
+func goTypeKind(expr ast.Expr) string {
+	switch expr.(type) {
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface"
+	default:
+		return "type"
+	}
+}
+
+func docText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	return strings.TrimSpace(doc.Text())
+}
@@ -0,0 +1,268 @@
+package lsp
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// jsExtensions are tried, in order, when resolving a relative JS/TS import
+var jsExtensions = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"}
+
+// buildDependencyGraph fills Dependencies with the project-internal import
+// graph: Go files are grouped into packages (their directory) and JS/TS
+// files are nodes of their own. Imports of external modules are left out.
+func (cc *CodebaseContext) buildDependencyGraph() {
+	cc.Dependencies = make(map[string][]string)
+
+	modulePath := goModulePath(cc.RootPath)
+
+	files := make(map[string]bool, len(cc.Files))
+	for _, file := range cc.Files {
+		files[file.Path] = true
+	}
+
+	edges := make(map[string]map[string]bool)
+	addEdge := func(from, to string) {
+		if edges[from] == nil {
+			edges[from] = make(map[string]bool)
+		}
+		if to != "" && to != from {
+			edges[from][to] = true
+			if edges[to] == nil {
+				edges[to] = make(map[string]bool)
+			}
+		}
+	}
+
+	for _, file := range cc.Files {
+		switch file.Type {
+		case FileTypeGo:
+			if strings.HasSuffix(file.Path, "_test.go") {
+				continue
+			}
+			from := path.Dir(filepath.ToSlash(file.Path))
+			addEdge(from, "")
+			if modulePath == "" {
+				continue
+			}
+			for _, imp := range file.Imports {
+				if imp == modulePath {
+					addEdge(from, ".")
+				} else if pkg, ok := strings.CutPrefix(imp, modulePath+"/"); ok {
+					addEdge(from, pkg)
+				}
+			}
+		case FileTypeTypeScript, FileTypeJavaScript:
+			from := filepath.ToSlash(file.Path)
+			addEdge(from, "")
+			for _, imp := range file.Imports {
+				if strings.HasPrefix(imp, ".") {
+					addEdge(from, resolveJSImport(from, imp, files))
+				}
+			}
+		}
+	}
+
+	for from, targets := range edges {
+		deps := make([]string, 0, len(targets))
+		for to := range targets {
+			deps = append(deps, to)
+		}
+		sort.Strings(deps)
+		cc.Dependencies[from] = deps
+	}
+}
+
+// goModulePath returns the module path declared in the project's go.mod
+func goModulePath(rootPath string) string {
+	file, err := os.Open(filepath.Join(rootPath, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`)
+		}
+	}
+	return ""
+}
+
+// resolveJSImport maps a relative import to the project file it refers to,
+// or "" when no analyzed file matches
+func resolveJSImport(from, imp string, files map[string]bool) string {
+	target := path.Join(path.Dir(from), imp)
+	if files[target] {
+		return target
+	}
+	for _, ext := range jsExtensions {
+		if files[target+ext] {
+			return target + ext
+		}
+	}
+	for _, ext := range jsExtensions {
+		if index := path.Join(target, "index"+ext); files[index] {
+			return index
+		}
+	}
+	return ""
+}
+
+// DependencyCycles returns the groups of packages or files that import each
+// other, directly or transitively, each sorted and in a stable order
+func (cc *CodebaseContext) DependencyCycles() [][]string {
+	nodes := make([]string, 0, len(cc.Dependencies))
+	for node := range cc.Dependencies {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	// Tarjan's strongly connected components
+	index := 0
+	indices := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(node string)
+	visit = func(node string) {
+		indices[node] = index
+		lowlink[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, dep := range cc.Dependencies[node] {
+			if _, seen := indices[dep]; !seen {
+				visit(dep)
+				lowlink[node] = min(lowlink[node], lowlink[dep])
+			} else if onStack[dep] {
+				lowlink[node] = min(lowlink[node], indices[dep])
+			}
+		}
+
+		if lowlink[node] != indices[node] {
+			return
+		}
+
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == node {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, seen := indices[node]; !seen {
+			visit(node)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// GenerateDependencyGraph renders the dependency graph as Graphviz DOT
+// ("dot") or a Mermaid flowchart ("mermaid"). Nodes and edges that are part
+// of an import cycle are drawn in red.
+func (cc *CodebaseContext) GenerateDependencyGraph(format string) (string, error) {
+	cycleOf := make(map[string]int)
+	for i, cycle := range cc.DependencyCycles() {
+		for _, node := range cycle {
+			cycleOf[node] = i + 1
+		}
+	}
+	inCycle := func(from, to string) bool {
+		return cycleOf[from] != 0 && cycleOf[from] == cycleOf[to]
+	}
+
+	nodes := make([]string, 0, len(cc.Dependencies))
+	for node := range cc.Dependencies {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	var graph strings.Builder
+
+	switch strings.ToLower(format) {
+	case "dot":
+		graph.WriteString("digraph dependencies {\n")
+		graph.WriteString("  rankdir=LR;\n")
+		graph.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+		for _, node := range nodes {
+			if cycleOf[node] != 0 {
+				graph.WriteString(fmt.Sprintf("  %q [color=red, fontcolor=red];\n", node))
+			} else {
+				graph.WriteString(fmt.Sprintf("  %q;\n", node))
+			}
+		}
+		for _, from := range nodes {
+			for _, to := range cc.Dependencies[from] {
+				if inCycle(from, to) {
+					graph.WriteString(fmt.Sprintf("  %q -> %q [color=red, penwidth=2];\n", from, to))
+				} else {
+					graph.WriteString(fmt.Sprintf("  %q -> %q;\n", from, to))
+				}
+			}
+		}
+		graph.WriteString("}\n")
+
+	case "mermaid":
+		ids := make(map[string]string, len(nodes))
+		for i, node := range nodes {
+			ids[node] = fmt.Sprintf("n%d", i)
+		}
+
+		graph.WriteString("graph LR\n")
+		for _, node := range nodes {
+			graph.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", ids[node], strings.ReplaceAll(node, `"`, "#quot;")))
+		}
+
+		// Mermaid styles links by their position in the diagram
+		var cycleLinks []string
+		link := 0
+		for _, from := range nodes {
+			for _, to := range cc.Dependencies[from] {
+				graph.WriteString(fmt.Sprintf("  %s --> %s\n", ids[from], ids[to]))
+				if inCycle(from, to) {
+					cycleLinks = append(cycleLinks, fmt.Sprint(link))
+				}
+				link++
+			}
+		}
+
+		var cycleNodes []string
+		for _, node := range nodes {
+			if cycleOf[node] != 0 {
+				cycleNodes = append(cycleNodes, ids[node])
+			}
+		}
+		if len(cycleNodes) > 0 {
+			graph.WriteString("  classDef cycle fill:#fdd,stroke:#d00,stroke-width:2px,color:#900\n")
+			graph.WriteString(fmt.Sprintf("  class %s cycle\n", strings.Join(cycleNodes, ",")))
+			graph.WriteString(fmt.Sprintf("  linkStyle %s stroke:#d00,stroke-width:2px\n", strings.Join(cycleLinks, ",")))
+		}
+
+	default:
+		return "", fmt.Errorf("unsupported graph format '%s' (use dot or mermaid)", format)
+	}
+
+	return graph.String(), nil
+}
@@ -2,6 +2,7 @@ package lsp
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,30 +13,31 @@ import (
 
 // Symbol represents a code symbol (function, class, etc.)
 type Symbol struct {
-	Name       string
-	Kind       string // "function", "class", "method", "variable", "type"
-	File       string
-	Line       int
-	Signature  string
-	DocComment string
-	Parent     string // Parent class/interface for methods
+	Name       string `json:"name"`
+	Kind       string `json:"kind"` // "function", "class", "method", "variable", "type"
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Signature  string `json:"signature,omitempty"`
+	DocComment string `json:"doc_comment,omitempty"`
+	Parent     string `json:"parent,omitempty"` // Parent class/interface for methods
+	Exported   bool   `json:"exported,omitempty"`
 }
 
 // FileIndex represents indexed data for a single file
 type FileIndex struct {
-	Path     string
-	Language string
-	Symbols  []Symbol
-	Imports  []string
-	Size     int64
-	Modified int64
+	Path     string   `json:"path"`
+	Language string   `json:"language"`
+	Symbols  []Symbol `json:"symbols"`
+	Imports  []string `json:"imports,omitempty"`
+	Size     int64    `json:"size"`
+	Modified int64    `json:"modified"`
 }
 
 // ProjectIndex represents the entire project index
 type ProjectIndex struct {
-	Root       string
-	Files      map[string]*FileIndex
-	SymbolMap  map[string][]Symbol // symbol name -> locations
+	Root       string                `json:"root"`
+	Files      map[string]*FileIndex `json:"files"`
+	SymbolMap  map[string][]Symbol   `json:"-"` // symbol name -> locations, rebuilt on load
 	mu         sync.RWMutex
 }
 
@@ -113,7 +115,12 @@ func (i *Indexer) indexFile(path, lang string) error {
 	// Parse symbols based on language
 	switch lang {
 	case "go":
-		fileIndex.Symbols = parseGoSymbols(string(content), relPath)
+		symbols, err := parseGoFileSymbols(content, relPath)
+		if err != nil {
+			// Fall back to line matching for files that don't parse
+			symbols = parseGoSymbols(string(content), relPath)
+		}
+		fileIndex.Symbols = symbols
 		fileIndex.Imports = parseGoImports(string(content))
 	case "javascript", "typescript":
 		fileIndex.Symbols = parseJSSymbols(string(content), relPath)
@@ -136,6 +143,76 @@ func (i *Indexer) indexFile(path, lang string) error {
 	return nil
 }
 
+// IndexPath returns the location of the saved project index
+func IndexPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "index.json")
+}
+
+// Save writes the index to .sdd/index.json
+func (i *Indexer) Save() error {
+	i.index.mu.RLock()
+	data, err := json.MarshalIndent(i.index, "", "  ")
+	i.index.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+
+	path := IndexPath(i.projectRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+
+	return nil
+}
+
+// LoadIndex loads a previously saved index for the project
+func LoadIndex(projectRoot string) (*Indexer, error) {
+	data, err := os.ReadFile(IndexPath(projectRoot))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	indexer := NewIndexer(projectRoot)
+	if err := json.Unmarshal(data, indexer.index); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+	if indexer.index.Files == nil {
+		indexer.index.Files = make(map[string]*FileIndex)
+	}
+
+	for _, file := range indexer.index.Files {
+		for _, sym := range file.Symbols {
+			indexer.index.SymbolMap[sym.Name] = append(indexer.index.SymbolMap[sym.Name], sym)
+		}
+	}
+
+	return indexer, nil
+}
+
+// FindSymbol returns the definitions of a symbol. Methods can be qualified
+// with their receiver type, as in "AgentService.Initialize".
+func (i *Indexer) FindSymbol(name string) []Symbol {
+	i.index.mu.RLock()
+	defer i.index.mu.RUnlock()
+
+	parent := ""
+	if dot := strings.LastIndex(name, "."); dot > 0 {
+		parent, name = name[:dot], name[dot+1:]
+	}
+
+	var results []Symbol
+	for _, sym := range i.index.SymbolMap[name] {
+		if parent == "" || sym.Parent == parent {
+			results = append(results, sym)
+		}
+	}
+
+	return results
+}
+
 // Search finds symbols matching the query
 func (i *Indexer) Search(query string) []Symbol {
 	i.index.mu.RLock()
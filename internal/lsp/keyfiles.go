@@ -0,0 +1,134 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Defaults for the key files listed in the specify context
+const (
+	DefaultKeyFileMaxSize = 5000 // bytes; larger files are left out
+	DefaultMaxKeyFiles    = 20
+)
+
+// ContextConfig holds project-specific settings for the codebase context
+// given to agents, loaded from .sdd/codebase.json:
+//
+//	{"key_files": {"max_size": 8000, "max_files": 30}}
+type ContextConfig struct {
+	KeyFiles KeyFileLimits `json:"key_files"`
+}
+
+// KeyFileLimits bound the files listed as key files to consider; values
+// below 1 use the defaults
+type KeyFileLimits struct {
+	MaxSize  int `json:"max_size"`  // bytes
+	MaxFiles int `json:"max_files"` // files listed
+}
+
+// ContextConfigPath returns the location of the codebase context
+// configuration
+func ContextConfigPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "codebase.json")
+}
+
+// LoadContextConfig reads the codebase context configuration, returning an
+// empty configuration when none exists
+func LoadContextConfig(projectRoot string) (*ContextConfig, error) {
+	config := &ContextConfig{}
+
+	data, err := os.ReadFile(ContextConfigPath(projectRoot))
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read codebase config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse codebase config: %w", err)
+	}
+	return config, nil
+}
+
+// SetKeyFileLimits bounds the size and number of the key files listed in
+// the specify context
+func (cc *CodebaseContext) SetKeyFileLimits(limits KeyFileLimits) {
+	cc.keyFileLimits = limits
+}
+
+// KeyFiles returns the files small enough to list as key files, most
+// relevant and then largest first, at most the configured number. It also returns how
+// many qualifying files were left out.
+func (cc *CodebaseContext) KeyFiles() ([]FileInfo, int) {
+	maxSize, maxFiles := cc.keyFileLimits.MaxSize, cc.keyFileLimits.MaxFiles
+	if maxSize < 1 {
+		maxSize = DefaultKeyFileMaxSize
+	}
+	if maxFiles < 1 {
+		maxFiles = DefaultMaxKeyFiles
+	}
+
+	var candidates []FileInfo
+	for _, file := range cc.Files {
+		// The framework's own files are not part of the codebase
+		if isFrameworkFile(file.Path) {
+			continue
+		}
+		if len(file.Content) < maxSize {
+			candidates = append(candidates, file)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := keyFileRelevance(candidates[i]), keyFileRelevance(candidates[j])
+		if ri != rj {
+			return ri > rj
+		}
+		// Among equally relevant files, tiny ones say the least
+		if si, sj := len(candidates[i].Content), len(candidates[j].Content); si != sj {
+			return si > sj
+		}
+		return candidates[i].Path < candidates[j].Path
+	})
+
+	if len(candidates) <= maxFiles {
+		return candidates, 0
+	}
+	return candidates[:maxFiles], len(candidates) - maxFiles
+}
+
+// keyFileRelevance ranks a file for the key files list: entry points, then
+// configuration, then documentation and source, shallower paths before
+// deeper ones and tests last
+func keyFileRelevance(file FileInfo) int {
+	score := 0
+	switch {
+	case isEntryPoint(file.Path, file.Type):
+		score += 100
+	case isConfigFile(file.Path):
+		score += 80
+	case file.Type == FileTypeDoc:
+		score += 40
+	case file.Type != FileTypeConfig:
+		score += 30
+	}
+
+	path := filepath.ToSlash(file.Path)
+	score -= 5 * strings.Count(strings.TrimPrefix(path, "./"), "/")
+	if strings.Contains(path, "_test.") || strings.Contains(path, ".spec.") || strings.Contains(path, ".test.") ||
+		strings.Contains(path, "/test/") || strings.Contains(path, "/tests/") || strings.Contains(path, "testdata/") {
+		score -= 50
+	}
+	return score
+}
+
+// isFrameworkFile reports whether a path is under .sdd or .agents
+func isFrameworkFile(path string) bool {
+	first := strings.SplitN(strings.TrimPrefix(filepath.ToSlash(path), "./"), "/", 2)[0]
+	return first == ".sdd" || first == ".agents"
+}
@@ -0,0 +1,143 @@
+package lsp
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GoModule is a Go module of the project, with the structure and tech
+// stack of the files it owns
+type GoModule struct {
+	Root      string // directory of the go.mod, relative to the project root
+	Path      string // module path declared in go.mod
+	GoVersion string
+	Files     int
+	Structure ProjectStructure
+	TechStack map[string][]string
+}
+
+// moduleRoot returns the directory of a go.mod file in slash form, "." for
+// the project root
+func moduleRoot(goModPath string) string {
+	return path.Dir(filepath.ToSlash(goModPath))
+}
+
+// parseGoMod reads the module path and Go version of a go.mod file
+func parseGoMod(content string) (modulePath, goVersion string) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			modulePath = strings.Trim(fields[1], `"`)
+		case "go":
+			goVersion = fields[1]
+		}
+	}
+	return modulePath, goVersion
+}
+
+// ModuleOf returns the root of the Go module owning a project-relative file,
+// the deepest module whose directory contains it, or "" when no module does
+func (cc *CodebaseContext) ModuleOf(file string) string {
+	return owningModule(cc.moduleRoots(), file)
+}
+
+// FilesByModule groups the analyzed files by the root of their owning Go
+// module; files outside every module are grouped under ""
+func (cc *CodebaseContext) FilesByModule() map[string][]FileInfo {
+	roots := cc.moduleRoots()
+	groups := make(map[string][]FileInfo)
+	for _, file := range cc.Files {
+		root := owningModule(roots, file.Path)
+		groups[root] = append(groups[root], file)
+	}
+	return groups
+}
+
+// moduleRoots returns the directory of every go.mod among the analyzed files
+func (cc *CodebaseContext) moduleRoots() []string {
+	var roots []string
+	for _, file := range cc.Files {
+		if path.Base(filepath.ToSlash(file.Path)) == "go.mod" {
+			roots = append(roots, moduleRoot(file.Path))
+		}
+	}
+	return roots
+}
+
+// owningModule returns the deepest of roots containing file, "" if none does
+func owningModule(roots []string, file string) string {
+	file = filepath.ToSlash(file)
+	owner, depth := "", -1
+	for _, root := range roots {
+		rootDepth := 0
+		if root != "." {
+			if !strings.HasPrefix(file, root+"/") {
+				continue
+			}
+			rootDepth = strings.Count(root, "/") + 1
+		}
+		if rootDepth > depth {
+			owner, depth = root, rootDepth
+		}
+	}
+	return owner
+}
+
+// detectModules describes every Go module of the project, ordered by root
+func (cc *CodebaseContext) detectModules() []GoModule {
+	groups := cc.FilesByModule()
+
+	var modules []GoModule
+	for _, file := range cc.Files {
+		if path.Base(filepath.ToSlash(file.Path)) != "go.mod" {
+			continue
+		}
+		root := moduleRoot(file.Path)
+		modulePath, goVersion := parseGoMod(file.Content)
+		owned := groups[root]
+		modules = append(modules, GoModule{
+			Root:      root,
+			Path:      modulePath,
+			GoVersion: goVersion,
+			Files:     len(owned),
+			Structure: structureOf(owned),
+			TechStack: techStackOf(owned),
+		})
+	}
+
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Root < modules[j].Root })
+	return modules
+}
+
+// ModulesSummary lists the Go modules of a monorepo as markdown, or returns
+// "" for projects with at most one module
+func (cc *CodebaseContext) ModulesSummary() string {
+	if len(cc.Structure.Modules) < 2 {
+		return ""
+	}
+
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("**Go Modules (%d):**\n", len(cc.Structure.Modules)))
+	for _, module := range cc.Structure.Modules {
+		summary.WriteString(fmt.Sprintf("- `%s` (%s): %d files", module.Root, module.Path, module.Files))
+		if module.Structure.Framework != "" {
+			summary.WriteString(", " + module.Structure.Framework)
+		}
+		var stack []string
+		for _, category := range []string{"Frameworks", "Databases"} {
+			stack = append(stack, module.TechStack[category]...)
+		}
+		if len(stack) > 0 {
+			summary.WriteString(" — " + strings.Join(stack, ", "))
+		}
+		summary.WriteString("\n")
+	}
+	return summary.String()
+}
@@ -0,0 +1,166 @@
+package lsp
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Reference is a location where a symbol is defined or used
+type Reference struct {
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Definition bool   `json:"definition"`
+	Context    string `json:"context"` // the source line, trimmed
+}
+
+// FindReferences returns every definition and use of a symbol across the
+// indexed files, definitions first. Go files are matched on identifiers in
+// the AST, so comments and strings are ignored; other languages fall back to
+// whole-word text matching. Methods can be qualified with their receiver
+// type, as in "AgentService.Initialize", which narrows definitions but not
+// uses since receivers are not type-checked.
+func (i *Indexer) FindReferences(symbol string) []Reference {
+	name := symbol
+	if dot := strings.LastIndex(symbol, "."); dot > 0 {
+		name = symbol[dot+1:]
+	}
+
+	definitions := make(map[string]bool)
+	for _, sym := range i.FindSymbol(symbol) {
+		definitions[referenceKey(sym.File, sym.Line)] = true
+	}
+
+	i.index.mu.RLock()
+	files := make([]*FileIndex, 0, len(i.index.Files))
+	for _, file := range i.index.Files {
+		files = append(files, file)
+	}
+	i.index.mu.RUnlock()
+
+	var refs []Reference
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(i.projectRoot, file.Path))
+		if err != nil {
+			continue
+		}
+
+		var found []Reference
+		if file.Language == "go" {
+			found, err = goReferences(content, file.Path, name)
+		}
+		if file.Language != "go" || err != nil {
+			found = textReferences(string(content), file.Path, name)
+		}
+
+		for _, ref := range found {
+			ref.Definition = ref.Definition && definitions[referenceKey(ref.File, ref.Line)]
+			refs = append(refs, ref)
+		}
+	}
+
+	sort.Slice(refs, func(a, b int) bool {
+		if refs[a].Definition != refs[b].Definition {
+			return refs[a].Definition
+		}
+		if refs[a].File != refs[b].File {
+			return refs[a].File < refs[b].File
+		}
+		if refs[a].Line != refs[b].Line {
+			return refs[a].Line < refs[b].Line
+		}
+		return refs[a].Column < refs[b].Column
+	})
+
+	return refs
+}
+
+func referenceKey(file string, line int) string {
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// goReferences finds identifiers named name in Go source. Identifiers that
+// declare a package-level function, method, type, variable or constant are
+// marked as candidate definitions.
+func goReferences(content []byte, file, name string) ([]Reference, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, content, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	declaring := make(map[*ast.Ident]bool)
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			declaring[d.Name] = true
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					declaring[s.Name] = true
+				case *ast.ValueSpec:
+					for _, ident := range s.Names {
+						declaring[ident] = true
+					}
+				}
+			}
+		}
+	}
+
+	lines := strings.Split(string(content), "\n")
+	var refs []Reference
+	ast.Inspect(f, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || ident.Name != name {
+			return true
+		}
+
+		pos := fset.Position(ident.Pos())
+		refs = append(refs, Reference{
+			File:       file,
+			Line:       pos.Line,
+			Column:     pos.Column,
+			Definition: declaring[ident],
+			Context:    lineAt(lines, pos.Line),
+		})
+		return true
+	})
+
+	return refs, nil
+}
+
+// textReferences finds whole-word occurrences of name in source text
+func textReferences(content, file, name string) []Reference {
+	pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+	lines := strings.Split(content, "\n")
+
+	var refs []Reference
+	for lineNum, line := range lines {
+		for _, match := range pattern.FindAllStringIndex(line, -1) {
+			refs = append(refs, Reference{
+				File:       file,
+				Line:       lineNum + 1,
+				Column:     match[0] + 1,
+				Definition: true, // confirmed against the index by the caller
+				Context:    strings.TrimSpace(line),
+			})
+		}
+	}
+
+	return refs
+}
+
+func lineAt(lines []string, line int) string {
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
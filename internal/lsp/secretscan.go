@@ -0,0 +1,133 @@
+package lsp
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"ultimate-sdd-framework/internal/secrets"
+)
+
+// SecretFinding is a likely credential committed to a git-tracked file. The
+// value itself is never kept.
+type SecretFinding struct {
+	File string
+	Line int
+	Kind string
+}
+
+// String returns the finding as file:line (kind)
+func (sf SecretFinding) String() string {
+	return fmt.Sprintf("%s:%d (%s)", sf.File, sf.Line, sf.Kind)
+}
+
+var (
+	// envSecretEntry matches NAME=value entries of env files whose name
+	// suggests a credential
+	envSecretEntry = regexp.MustCompile(`(?i)^\s*(?:export\s+)?([A-Z0-9_]*(?:SECRET|TOKEN|PASSWORD|PASSWD|API_?KEY|ACCESS_?KEY|PRIVATE_?KEY)[A-Z0-9_]*)\s*=\s*["']?([^"'\s#]+)`)
+	// envPlaceholder matches values that stand in for a credential
+	envPlaceholder = regexp.MustCompile(`(?i)^(\$\{?|<|\{\{|%)|change.?me|your|example|placeholder|dummy|redacted|^x+$|^\*+$`)
+)
+
+// isEnvFile reports whether a file holds environment variables, such as
+// .env or .env.production but not the .env.example template
+func isEnvFile(path string) bool {
+	name := filepath.Base(path)
+	if name != ".env" && !strings.HasPrefix(name, ".env.") {
+		return false
+	}
+	for _, template := range []string{".example", ".sample", ".template", ".dist"} {
+		if strings.HasSuffix(name, template) {
+			return false
+		}
+	}
+	return true
+}
+
+// realLookingSecret reports whether an env value looks like a real
+// credential: long, mixing letters and digits, and not a placeholder
+func realLookingSecret(value string) bool {
+	if len(value) < 16 || envPlaceholder.MatchString(value) {
+		return false
+	}
+	return strings.ContainsAny(value, "0123456789") &&
+		strings.ContainsAny(strings.ToLower(value), "abcdefghijklmnopqrstuvwxyz")
+}
+
+// scanSecrets reports the high-confidence secrets in the content of a file:
+// well-known credential formats anywhere, and credential entries of env
+// files with real-looking values
+func scanSecrets(path, content string) []SecretFinding {
+	var findings []SecretFinding
+	found := make(map[int]bool)
+	for _, line := range secrets.TokenLines(content) {
+		findings = append(findings, SecretFinding{File: path, Line: line, Kind: "credential format"})
+		found[line] = true
+	}
+
+	if isEnvFile(path) {
+		for i, line := range strings.Split(content, "\n") {
+			match := envSecretEntry.FindStringSubmatch(line)
+			if match == nil || found[i+1] || !realLookingSecret(match[2]) {
+				continue
+			}
+			findings = append(findings, SecretFinding{File: path, Line: i + 1, Kind: match[1]})
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	return findings
+}
+
+// scanTrackedSecrets scans the files git tracks under the project root,
+// within the path filter, for committed secrets. Outside a git repository
+// it finds nothing.
+func (bfc *BrownfieldContext) scanTrackedSecrets() []SecretFinding {
+	out, err := exec.Command("git", "-C", bfc.RootPath, "ls-files", "-z", "--", ".").Output()
+	if err != nil {
+		return nil
+	}
+
+	var findings []SecretFinding
+	for _, rel := range strings.Split(string(out), "\x00") {
+		if rel == "" || !bfc.pathFilter.Matches(rel) {
+			continue
+		}
+		path := filepath.Join(bfc.RootPath, rel)
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > MaxAnalyzedFileSize {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(content, 0) >= 0 {
+			continue // unreadable or binary
+		}
+		findings = append(findings, scanSecrets(filepath.ToSlash(rel), string(content))...)
+	}
+	return findings
+}
+
+// checkCommittedSecrets reports the secrets found in git-tracked files as a
+// forbidden pattern, one occurrence per file:line
+func (bfc *BrownfieldContext) checkCommittedSecrets() []ForbiddenPattern {
+	bfc.Secrets = bfc.scanTrackedSecrets()
+	if len(bfc.Secrets) == 0 {
+		return nil
+	}
+
+	occurrences := make([]string, 0, len(bfc.Secrets))
+	for _, finding := range bfc.Secrets {
+		occurrences = append(occurrences, finding.String())
+	}
+	return []ForbiddenPattern{{
+		Pattern:     "Committed Secrets",
+		Description: "Credentials are committed to files tracked by git",
+		Severity:    "Critical",
+		Occurrences: occurrences,
+		Recommended: "Revoke each credential, remove it from the repository history and load it from the environment or a secret manager",
+	}}
+}
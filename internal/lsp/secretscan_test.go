@@ -0,0 +1,65 @@
+package lsp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanSecrets(t *testing.T) {
+	// Assembled at run time so this file does not trip secret scanners
+	awsKey := "AKIA" + strings.Repeat("Z", 16)
+	realValue := "s3cr3t" + strings.Repeat("Q7", 8)
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    []SecretFinding
+	}{
+		{
+			name:    "token in source",
+			path:    "main.go",
+			content: "package main\n\nconst key = \"" + awsKey + "\"\n",
+			want:    []SecretFinding{{File: "main.go", Line: 3, Kind: "credential format"}},
+		},
+		{
+			name:    "env entry with a real-looking value",
+			path:    "config/.env",
+			content: "DEBUG=true\nexport API_KEY=" + realValue + "\n",
+			want:    []SecretFinding{{File: "config/.env", Line: 2, Kind: "API_KEY"}},
+		},
+		{
+			name:    "env entries in source files are ignored",
+			path:    "settings.py",
+			content: "API_KEY=" + realValue + "\n",
+		},
+		{
+			name:    "env templates are ignored",
+			path:    ".env.example",
+			content: "API_KEY=" + realValue + "\n",
+		},
+		{
+			name:    "placeholders are ignored",
+			path:    ".env",
+			content: "API_KEY=your-api-key-goes-here-123\nDB_PASSWORD=${DB_PASSWORD}\nSECRET_TOKEN=changeme12345678901\nGITHUB_TOKEN=short1\n",
+		},
+		{
+			name:    "env token reported once, in line order",
+			path:    ".env.production",
+			content: "AWS_ACCESS_KEY=" + awsKey + "\nSTRIPE_SECRET=" + realValue + "\n",
+			want: []SecretFinding{
+				{File: ".env.production", Line: 1, Kind: "credential format"},
+				{File: ".env.production", Line: 2, Kind: "STRIPE_SECRET"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scanSecrets(tt.path, tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanSecrets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
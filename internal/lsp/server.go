@@ -0,0 +1,294 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Diagnostic severities, as defined by the Language Server Protocol
+const (
+	SeverityError       = 1
+	SeverityWarning     = 2
+	SeverityInformation = 3
+	SeverityHint        = 4
+)
+
+// Position is a zero-based line and character offset in a document
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is the span of a document a diagnostic applies to
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Diagnostic is a problem reported to the editor for a document
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Code     string `json:"code,omitempty"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+// JSON-RPC error codes used by the server
+const (
+	errParseError     = -32700
+	errInvalidRequest = -32600
+	errMethodNotFound = -32601
+	errInvalidParams  = -32602
+	errInternalError  = -32603
+)
+
+// Server speaks a minimal subset of the Language Server Protocol: JSON-RPC
+// messages framed by Content-Length headers. It supports initialize,
+// shutdown and exit, publishes diagnostics when a document is saved, and
+// answers the custom viki/context request.
+type Server struct {
+	in  *bufio.Reader
+	out io.Writer
+	mu  sync.Mutex
+
+	// Review returns the diagnostics for a saved file
+	Review func(path string) ([]Diagnostic, error)
+	// Context returns the workflow context for a file, for viki/context
+	Context func(path string) (interface{}, error)
+
+	shutdown bool
+}
+
+// rpcMessage is an incoming request or notification
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcError is the error of a failed request
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// textDocumentParams holds the document most notifications refer to
+type textDocumentParams struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+}
+
+// NewServer creates a server reading requests from in and writing
+// responses and notifications to out
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+// Serve handles messages until the client sends exit or closes the input
+func (s *Server) Serve() error {
+	for {
+		body, err := s.readMessage()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var msg rpcMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.respondError(nil, errParseError, err.Error())
+			continue
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		s.handle(msg)
+	}
+}
+
+// handle dispatches one message; requests, which carry an ID, always get a
+// response
+func (s *Server) handle(msg rpcMessage) {
+	isRequest := len(msg.ID) > 0
+
+	if s.shutdown && isRequest {
+		s.respondError(msg.ID, errInvalidRequest, "server is shutting down")
+		return
+	}
+
+	switch msg.Method {
+	case "initialize":
+		s.respond(msg.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync": map[string]interface{}{
+					"openClose": true,
+					"save":      map[string]bool{"includeText": false},
+				},
+			},
+			"serverInfo": map[string]string{"name": "viki"},
+		})
+
+	case "shutdown":
+		s.shutdown = true
+		s.respond(msg.ID, nil)
+
+	case "textDocument/didSave":
+		var params textDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return
+		}
+		s.publishReview(params.TextDocument.URI)
+
+	case "textDocument/didClose":
+		var params textDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return
+		}
+		s.publishDiagnostics(params.TextDocument.URI, []Diagnostic{})
+
+	case "viki/context":
+		var params textDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			s.respondError(msg.ID, errInvalidParams, err.Error())
+			return
+		}
+		if s.Context == nil {
+			s.respondError(msg.ID, errMethodNotFound, "viki/context is not available")
+			return
+		}
+		result, err := s.Context(PathFromURI(params.TextDocument.URI))
+		if err != nil {
+			s.respondError(msg.ID, errInternalError, err.Error())
+			return
+		}
+		s.respond(msg.ID, result)
+
+	default:
+		// Unknown notifications, such as initialized or didChange, are ignored
+		if isRequest {
+			s.respondError(msg.ID, errMethodNotFound, fmt.Sprintf("method not found: %s", msg.Method))
+		}
+	}
+}
+
+// publishReview reviews a saved document and sends its diagnostics, or
+// shows the error when the review fails
+func (s *Server) publishReview(uri string) {
+	if s.Review == nil {
+		return
+	}
+	diagnostics, err := s.Review(PathFromURI(uri))
+	if err != nil {
+		s.notify("window/showMessage", map[string]interface{}{
+			"type":    1,
+			"message": fmt.Sprintf("viki review failed: %v", err),
+		})
+		return
+	}
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	s.publishDiagnostics(uri, diagnostics)
+}
+
+// publishDiagnostics replaces the diagnostics the editor shows for a
+// document
+func (s *Server) publishDiagnostics(uri string, diagnostics []Diagnostic) {
+	s.notify("textDocument/publishDiagnostics", map[string]interface{}{
+		"uri":         uri,
+		"diagnostics": diagnostics,
+	})
+}
+
+// respond sends the result of a request
+func (s *Server) respond(id json.RawMessage, result interface{}) {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		s.respondError(id, errInternalError, err.Error())
+		return
+	}
+	s.write(map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": json.RawMessage(resultJSON)})
+}
+
+// respondError sends the error of a request; a nil id is sent as null
+func (s *Server) respondError(id json.RawMessage, code int, message string) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	s.write(map[string]interface{}{"jsonrpc": "2.0", "id": id, "error": rpcError{Code: code, Message: message}})
+}
+
+// notify sends a notification to the client
+func (s *Server) notify(method string, params interface{}) {
+	s.write(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+// write frames and sends one message
+func (s *Server) write(message interface{}) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(body))
+	s.out.Write(body)
+}
+
+// readMessage reads the headers and body of one message
+func (s *Server) readMessage() ([]byte, error) {
+	length := -1
+	for {
+		line, err := s.in.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && length == -1 {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to read message header: %w", err)
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if length < 0 {
+				return nil, fmt.Errorf("message without Content-Length header")
+			}
+			break
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, fmt.Errorf("failed to read message body: %w", err)
+	}
+	return body, nil
+}
+
+// PathFromURI converts a file:// URI to a local path; anything else is
+// returned unchanged
+func PathFromURI(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(parsed.Path)
+}
@@ -0,0 +1,76 @@
+package lsp
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// MaxAnalyzedFileSize is the size above which a file is skipped rather than
+// analyzed
+const MaxAnalyzedFileSize = 1 << 20
+
+// SkipReason is why discovery left a file out of the analysis
+type SkipReason string
+
+const (
+	SkipParseError  SkipReason = "parse error"
+	SkipTooLarge    SkipReason = "too large"
+	SkipUnreadable  SkipReason = "unreadable"
+	SkipUnsupported SkipReason = "unsupported type"
+)
+
+// skipReasons orders the reasons of the skipped summary
+var skipReasons = []SkipReason{SkipParseError, SkipTooLarge, SkipUnreadable, SkipUnsupported}
+
+// SkippedFile is a file discovery found but left out of the analysis
+type SkippedFile struct {
+	Path   string
+	Reason SkipReason
+	Detail string // e.g. the parse error
+}
+
+// skip records that a file was left out of the analysis
+func (cc *CodebaseContext) skip(path string, reason SkipReason, detail string) {
+	cc.Skipped = append(cc.Skipped, SkippedFile{
+		Path:   strings.TrimPrefix(path, cc.RootPath+"/"),
+		Reason: reason,
+		Detail: detail,
+	})
+}
+
+// SkippedSummary counts the skipped files by reason, e.g. "Skipped 3 files
+// (2 parse errors, 1 too large)", or returns "" when none were skipped
+func (cc *CodebaseContext) SkippedSummary() string {
+	if len(cc.Skipped) == 0 {
+		return ""
+	}
+
+	counts := make(map[SkipReason]int)
+	for _, file := range cc.Skipped {
+		counts[file.Reason]++
+	}
+
+	var parts []string
+	for _, reason := range skipReasons {
+		switch n := counts[reason]; {
+		case n == 0:
+		case reason == SkipParseError && n > 1:
+			parts = append(parts, fmt.Sprintf("%d parse errors", n))
+		default:
+			parts = append(parts, fmt.Sprintf("%d %s", n, reason))
+		}
+	}
+	return fmt.Sprintf("Skipped %d files (%s)", len(cc.Skipped), strings.Join(parts, ", "))
+}
+
+// goParseError returns the first syntax error of Go source, or "" when it
+// parses
+func goParseError(path string, content []byte) string {
+	_, err := parser.ParseFile(token.NewFileSet(), path, content, parser.SkipObjectResolution)
+	if err == nil {
+		return ""
+	}
+	return strings.SplitN(err.Error(), "\n", 2)[0]
+}
@@ -0,0 +1,243 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DependencyVersion is a framework or major dependency whose version a
+// manifest declares
+type DependencyVersion struct {
+	Technology string // as reported in the tech stack, e.g. "react"
+	Package    string // as named in the manifest, e.g. "github.com/jackc/pgx/v5"
+	Name       string // as shown, e.g. "pgx"
+	Version    string // without range operators or a leading "v"
+	Manifest   string // path of the declaring manifest
+}
+
+// knownDependency names the technology a manifest package provides and how
+// the package is shown
+type knownDependency struct {
+	technology string
+	label      string
+}
+
+// knownDependencies are the manifest packages whose versions are reported,
+// Go modules without their /vN suffix
+var knownDependencies = map[string]knownDependency{
+	// Go
+	"github.com/gin-gonic/gin":       {"gin", "Gin"},
+	"github.com/labstack/echo":       {"echo", "Echo"},
+	"github.com/gofiber/fiber":       {"fiber", "Fiber"},
+	"github.com/go-chi/chi":          {"chi", "Chi"},
+	"gorm.io/gorm":                   {"gorm", "GORM"},
+	"github.com/lib/pq":              {"postgresql", "lib/pq"},
+	"github.com/jackc/pgx":           {"postgresql", "pgx"},
+	"github.com/go-sql-driver/mysql": {"mysql", "go-sql-driver/mysql"},
+	"go.mongodb.org/mongo-driver":    {"mongodb", "mongo-driver"},
+	"github.com/redis/go-redis":      {"redis", "go-redis"},
+	"github.com/go-redis/redis":      {"redis", "go-redis"},
+	// JavaScript and TypeScript
+	"react":         {"react", "React"},
+	"vue":           {"vue", "Vue"},
+	"express":       {"express", "Express"},
+	"next":          {"next", "Next.js"},
+	"@angular/core": {"angular", "Angular"},
+	"typescript":    {"typescript", "TypeScript"},
+	"pg":            {"postgresql", "pg"},
+	"mysql2":        {"mysql", "mysql2"},
+	"mongoose":      {"mongodb", "Mongoose"},
+	"mongodb":       {"mongodb", "mongodb"},
+	"redis":         {"redis", "redis"},
+	"ioredis":       {"redis", "ioredis"},
+	// Python
+	"django":          {"django", "Django"},
+	"flask":           {"flask", "Flask"},
+	"fastapi":         {"fastapi", "FastAPI"},
+	"sqlalchemy":      {"sqlalchemy", "SQLAlchemy"},
+	"psycopg2":        {"postgresql", "psycopg2"},
+	"psycopg2-binary": {"postgresql", "psycopg2"},
+	"psycopg":         {"postgresql", "psycopg"},
+	"pymongo":         {"mongodb", "PyMongo"},
+}
+
+// databaseLabels name the databases whose clients are reported
+var databaseLabels = map[string]string{
+	"postgresql": "PostgreSQL",
+	"mysql":      "MySQL",
+	"mongodb":    "MongoDB",
+	"redis":      "Redis",
+}
+
+var (
+	goMajorSuffix      = regexp.MustCompile(`/v\d+$`)
+	requirementLine    = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(?:\[[^\]]*\])?\s*(?:===?|~=|>=)\s*([0-9][^\s,;#]*)`)
+	pyprojectPoetryDep = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*=\s*(?:"([^"]+)"|\{[^}]*version\s*=\s*"([^"]+)")`)
+	pyprojectPEP621Dep = regexp.MustCompile(`"([A-Za-z0-9][A-Za-z0-9._-]*)(?:\[[^\]]*\])?\s*(?:===?|~=|>=)\s*([0-9][^",;\s]*)`)
+	versionOperators   = regexp.MustCompile(`^[\^~>=<!v\s]+`)
+)
+
+// dependencyVersions reads the versions of the known frameworks and major
+// dependencies from the go.mod, package.json, requirements.txt and
+// pyproject.toml manifests among files, plus the Go version of go.mod. The
+// result is sorted by technology and package; a package declared by several
+// manifests is reported once, from the shallowest.
+func dependencyVersions(files []FileInfo) []DependencyVersion {
+	manifests := make([]FileInfo, 0)
+	for _, file := range files {
+		switch filepath.Base(file.Path) {
+		case "go.mod", "package.json", "requirements.txt", "pyproject.toml":
+			// Vendored packages declare their own dependencies
+			if !isFrameworkFile(file.Path) && !strings.Contains(filepath.ToSlash(file.Path), "node_modules/") {
+				manifests = append(manifests, file)
+			}
+		}
+	}
+	sort.SliceStable(manifests, func(i, j int) bool {
+		return strings.Count(filepath.ToSlash(manifests[i].Path), "/") < strings.Count(filepath.ToSlash(manifests[j].Path), "/")
+	})
+
+	seen := make(map[string]bool)
+	var versions []DependencyVersion
+	add := func(known knownDependency, pkg, version, manifest string) {
+		version = versionOperators.ReplaceAllString(strings.TrimSpace(version), "")
+		if version == "" || seen[known.technology+"\x00"+known.label] {
+			return
+		}
+		seen[known.technology+"\x00"+known.label] = true
+		versions = append(versions, DependencyVersion{known.technology, pkg, known.label, version, manifest})
+	}
+
+	for _, manifest := range manifests {
+		var declared map[string]string
+		switch filepath.Base(manifest.Path) {
+		case "go.mod":
+			if _, goVersion := parseGoMod(manifest.Content); goVersion != "" {
+				add(knownDependency{"go", "Go"}, "go", goVersion, manifest.Path)
+			}
+			declared = goModRequires(manifest.Content)
+		case "package.json":
+			declared = packageJSONDependencies(manifest.Content)
+		case "requirements.txt":
+			declared = requirementsDependencies(manifest.Content)
+		case "pyproject.toml":
+			declared = pyprojectDependencies(manifest.Content)
+		}
+
+		for pkg, version := range declared {
+			name := pkg
+			if filepath.Base(manifest.Path) == "go.mod" {
+				name = goMajorSuffix.ReplaceAllString(pkg, "")
+			} else if filepath.Base(manifest.Path) != "package.json" {
+				name = strings.ToLower(strings.ReplaceAll(pkg, "_", "-"))
+			}
+			if known, ok := knownDependencies[name]; ok {
+				add(known, pkg, version, manifest.Path)
+			}
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].Technology != versions[j].Technology {
+			return versions[i].Technology < versions[j].Technology
+		}
+		return versions[i].Package < versions[j].Package
+	})
+	return versions
+}
+
+// Label shows a dependency as "React 18.2.0", or "PostgreSQL (pgx 5.5.0)"
+// for a driver or client of a database
+func (d DependencyVersion) Label() string {
+	if label, ok := databaseLabels[d.Technology]; ok {
+		return fmt.Sprintf("%s (%s %s)", label, d.Name, d.Version)
+	}
+	return fmt.Sprintf("%s %s", d.Name, d.Version)
+}
+
+// goModRequires returns the required modules of a go.mod with their
+// versions
+func goModRequires(content string) map[string]string {
+	requires := make(map[string]string)
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+		case inBlock && len(fields) >= 2:
+			requires[fields[0]] = fields[1]
+		case fields[0] == "require" && len(fields) >= 2 && fields[1] == "(":
+			inBlock = true
+		case fields[0] == "require" && len(fields) >= 3:
+			requires[fields[1]] = fields[2]
+		}
+	}
+	return requires
+}
+
+// packageJSONDependencies returns the dependencies and dev dependencies of
+// a package.json with their version ranges
+func packageJSONDependencies(content string) map[string]string {
+	var manifest struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return nil
+	}
+
+	dependencies := make(map[string]string)
+	for name, version := range manifest.DevDependencies {
+		dependencies[name] = version
+	}
+	for name, version := range manifest.Dependencies {
+		dependencies[name] = version
+	}
+	return dependencies
+}
+
+// requirementsDependencies returns the packages of a requirements.txt that
+// are pinned or have a lower bound
+func requirementsDependencies(content string) map[string]string {
+	dependencies := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		if match := requirementLine.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			dependencies[match[1]] = match[2]
+		}
+	}
+	return dependencies
+}
+
+// pyprojectDependencies returns the versioned dependencies of a
+// pyproject.toml, from Poetry dependency tables or PEP 621 dependency lists
+func pyprojectDependencies(content string) map[string]string {
+	dependencies := make(map[string]string)
+	inPoetry := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inPoetry = strings.HasPrefix(line, "[tool.poetry") && strings.Contains(line, "dependencies")
+			continue
+		}
+		if inPoetry {
+			if match := pyprojectPoetryDep.FindStringSubmatch(line); match != nil && match[1] != "python" {
+				dependencies[match[1]] = match[2] + match[3]
+			}
+			continue
+		}
+		for _, match := range pyprojectPEP621Dep.FindAllStringSubmatch(line, -1) {
+			dependencies[match[1]] = match[2]
+		}
+	}
+	return dependencies
+}
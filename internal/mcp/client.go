@@ -3,11 +3,14 @@ package mcp
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"time"
+
+	"ultimate-sdd-framework/internal/metrics"
 )
 
 // ModelProvider represents different AI model providers
@@ -28,6 +31,7 @@ type ModelClient struct {
 	BaseURL    string
 	Model      string
 	httpClient *http.Client
+	metrics    *metrics.Store // nil when calls are not recorded
 }
 
 // Message represents a chat message
@@ -61,6 +65,35 @@ type ChatResponse struct {
 	} `json:"usage"`
 }
 
+// APIError is returned when a provider answers with a non-200 status
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsRetryableError reports whether a chat failure is worth retrying on
+// another provider. Malformed requests are not; outages, rate limits,
+// auth failures and network errors are.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+			return false
+		}
+	}
+
+	return true
+}
+
 // NewModelClient creates a new AI model client
 func NewModelClient(provider ModelProvider, apiKey, model string) *ModelClient {
 	client := &ModelClient{
@@ -80,6 +113,8 @@ func NewModelClient(provider ModelProvider, apiKey, model string) *ModelClient {
 		client.BaseURL = "https://generativelanguage.googleapis.com/v1beta"
 	case ProviderOllama:
 		client.BaseURL = "http://localhost:11434"
+		// Local models can take minutes to load and generate
+		client.httpClient.Timeout = 5 * time.Minute
 	case ProviderAzure:
 		// Azure OpenAI requires custom base URL
 		client.BaseURL = ""
@@ -93,8 +128,46 @@ func (mc *ModelClient) SetBaseURL(url string) {
 	mc.BaseURL = url
 }
 
-// Chat sends a chat request to the AI model
+// SetMetrics records the client's calls and token usage in a project's
+// metrics store
+func (mc *ModelClient) SetMetrics(store *metrics.Store) {
+	mc.metrics = store
+}
+
+// record increments a counter of the client's metrics store, if it has
+// one. Metrics are best-effort, so failures are ignored.
+func (mc *ModelClient) record(name string, labels metrics.Labels, value float64) {
+	if mc.metrics != nil {
+		mc.metrics.Add(name, labels, value)
+	}
+}
+
+// Chat sends a chat request to the AI model and records the call and its
+// token usage in the project metrics
 func (mc *ModelClient) Chat(messages []Message, options map[string]interface{}) (*ChatResponse, error) {
+	response, err := mc.chat(messages, options)
+
+	provider := string(mc.Provider)
+	if err != nil {
+		mc.record(metrics.AICalls, metrics.Labels{"provider": provider, "status": "error"}, 1)
+		return nil, err
+	}
+
+	mc.record(metrics.AICalls, metrics.Labels{"provider": provider, "status": "ok"}, 1)
+	if response.Usage.PromptTokens > 0 {
+		mc.record(metrics.Tokens, metrics.Labels{"provider": provider, "type": "prompt"}, float64(response.Usage.PromptTokens))
+	}
+	if response.Usage.CompletionTokens > 0 {
+		mc.record(metrics.Tokens, metrics.Labels{"provider": provider, "type": "completion"}, float64(response.Usage.CompletionTokens))
+	}
+	if response.Usage.PromptTokens+response.Usage.CompletionTokens == 0 && response.Usage.TotalTokens > 0 {
+		mc.record(metrics.Tokens, metrics.Labels{"provider": provider, "type": "total"}, float64(response.Usage.TotalTokens))
+	}
+
+	return response, nil
+}
+
+func (mc *ModelClient) chat(messages []Message, options map[string]interface{}) (*ChatResponse, error) {
 	var request ChatRequest
 	var endpoint string
 	var headers map[string]string
@@ -156,16 +229,18 @@ func (mc *ModelClient) Chat(messages []Message, options map[string]interface{})
 		return mc.sendGoogleRequest(requestBody, endpoint, headers)
 
 	case ProviderOllama:
-		request = ChatRequest{
-			Messages: messages,
-			Model:    mc.Model,
-			Stream:   false,
+		// Ollama takes sampling settings under "options" and needs no API key
+		requestBody := map[string]interface{}{
+			"model":    mc.Model,
+			"messages": messages,
+			"stream":   false,
 		}
-		endpoint = "/api/chat"
-		headers = map[string]string{
-			"Content-Type": "application/json",
+		if ollamaOptions := ollamaOptions(options); len(ollamaOptions) > 0 {
+			requestBody["options"] = ollamaOptions
 		}
 
+		return mc.sendOllamaRequest(requestBody)
+
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", mc.Provider)
 	}
@@ -207,7 +282,7 @@ func (mc *ModelClient) sendRequest(request interface{}, endpoint string, headers
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var response ChatResponse
@@ -243,7 +318,7 @@ func (mc *ModelClient) sendAnthropicRequest(requestBody map[string]interface{},
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	// Parse Anthropic response format
@@ -314,7 +389,7 @@ func (mc *ModelClient) sendGoogleRequest(requestBody map[string]interface{}, end
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var geminiResp struct {
@@ -355,6 +430,105 @@ func (mc *ModelClient) sendGoogleRequest(requestBody map[string]interface{}, end
 	return response, nil
 }
 
+// ollamaOptions maps generic chat options onto Ollama's option names
+func ollamaOptions(options map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if temp, ok := options["temperature"].(float64); ok {
+		result["temperature"] = temp
+	}
+	if maxTokens, ok := options["max_tokens"].(int); ok {
+		result["num_predict"] = maxTokens
+	}
+	return result
+}
+
+// sendOllamaRequest handles Ollama's /api/chat format. The server may answer
+// with a single JSON object or, if streaming is forced, newline-delimited
+// chunks; both are folded into one response.
+func (mc *ModelClient) sendOllamaRequest(requestBody map[string]interface{}) (*ChatResponse, error) {
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := mc.BaseURL + "/api/chat"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := mc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request (is Ollama running at %s?): %w", mc.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var content strings.Builder
+	var promptTokens, completionTokens int
+	finishReason := "stop"
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+			Done            bool   `json:"done"`
+			DoneReason      string `json:"done_reason"`
+			PromptEvalCount int    `json:"prompt_eval_count"`
+			EvalCount       int    `json:"eval_count"`
+			Error           string `json:"error"`
+		}
+
+		if err := decoder.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		if chunk.Error != "" {
+			return nil, fmt.Errorf("ollama error: %s", chunk.Error)
+		}
+
+		content.WriteString(chunk.Message.Content)
+		if chunk.Done {
+			promptTokens = chunk.PromptEvalCount
+			completionTokens = chunk.EvalCount
+			if chunk.DoneReason != "" {
+				finishReason = chunk.DoneReason
+			}
+			break
+		}
+	}
+
+	response := &ChatResponse{
+		Choices: []struct {
+			Message      Message `json:"message"`
+			FinishReason string  `json:"finish_reason"`
+		}{
+			{
+				Message: Message{
+					Role:    "assistant",
+					Content: content.String(),
+				},
+				FinishReason: finishReason,
+			},
+		},
+	}
+	response.Usage.PromptTokens = promptTokens
+	response.Usage.CompletionTokens = completionTokens
+	response.Usage.TotalTokens = promptTokens + completionTokens
+
+	return response, nil
+}
+
 // ValidateConnection tests the API key and connection
 func (mc *ModelClient) ValidateConnection() error {
 	// Send a simple test message
@@ -371,39 +545,11 @@ func (mc *ModelClient) ValidateConnection() error {
 	return err
 }
 
-// GetAvailableModels returns available models for the provider
+// GetAvailableModels returns the maintained list of models of the provider
 func (mc *ModelClient) GetAvailableModels() ([]string, error) {
 	switch mc.Provider {
-	case ProviderOpenAI, ProviderAzure:
-		return []string{
-			"gpt-4",
-			"gpt-4-turbo",
-			"gpt-4-turbo-preview",
-			"gpt-3.5-turbo",
-			"gpt-3.5-turbo-16k",
-		}, nil
-	case ProviderAnthropic:
-		return []string{
-			"claude-3-opus-20240229",
-			"claude-3-sonnet-20240229",
-			"claude-3-haiku-20240307",
-			"claude-2.1",
-			"claude-2",
-		}, nil
-	case ProviderGoogle:
-		return []string{
-			"gemini-2.5-flash",
-			"gemini-2.5-pro",
-			"gemini-2.0-flash-exp",
-		}, nil
-	case ProviderOllama:
-		// For Ollama, we'd need to query the local instance
-		return []string{
-			"llama2",
-			"codellama",
-			"mistral",
-			"vicuna",
-		}, nil
+	case ProviderOpenAI, ProviderAzure, ProviderAnthropic, ProviderGoogle, ProviderOllama:
+		return KnownModels(mc.Provider), nil
 	default:
 		return []string{}, fmt.Errorf("unsupported provider: %s", mc.Provider)
 	}
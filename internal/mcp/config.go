@@ -5,21 +5,29 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"ultimate-sdd-framework/internal/config"
+	"ultimate-sdd-framework/internal/metrics"
+	"ultimate-sdd-framework/internal/secrets"
 )
 
 // MCPConfig represents the Model Context Protocol configuration
 type MCPConfig struct {
-	Providers       map[string]ProviderConfig `json:"providers"`
-	DefaultProvider string                    `json:"default_provider"`
+	Providers         map[string]ProviderConfig `json:"providers"`
+	DefaultProvider   string                    `json:"default_provider"`
+	FallbackProviders []string                  `json:"fallback_providers,omitempty"`
 }
 
 // ProviderConfig represents configuration for a specific AI provider
 type ProviderConfig struct {
 	Provider ModelProvider `json:"provider"`
-	APIKey   string        `json:"api_key"`
+	APIKey   string        `json:"api_key"` // empty to resolve from the secrets backend at runtime
 	BaseURL  string        `json:"base_url,omitempty"`
 	Model    string        `json:"model"`
 	Enabled  bool          `json:"enabled"`
+	// Rate limits AI-assisted reviews keep to; zero uses the defaults
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+	MaxConcurrent     int `json:"max_concurrent,omitempty"`
 }
 
 // MCPManager manages MCP connections and configurations
@@ -27,6 +35,10 @@ type MCPManager struct {
 	configPath string
 	config     *MCPConfig
 	clients    map[string]*ModelClient
+	profile    *config.Profile // active config profile, nil if none
+	metrics    *metrics.Store  // where the clients record their calls
+
+	keyWarnings []string // providers whose API key could not be resolved
 }
 
 // NewMCPManager creates a new MCP manager
@@ -36,6 +48,7 @@ func NewMCPManager(projectRoot string) *MCPManager {
 	return &MCPManager{
 		configPath: configPath,
 		clients:    make(map[string]*ModelClient),
+		metrics:    metrics.NewStore(projectRoot),
 	}
 }
 
@@ -78,16 +91,35 @@ func (m *MCPManager) LoadConfig() error {
 			Providers:       make(map[string]ProviderConfig),
 			DefaultProvider: "",
 		}
-		return m.SaveConfig()
+		if err := m.SaveConfig(); err != nil {
+			return err
+		}
+	}
+
+	// Apply the active config profile without saving it into mcp.json
+	profileName, profile, err := config.ActiveProfile()
+	if err != nil {
+		return err
+	}
+	if profile != nil {
+		if _, ok := m.config.Providers[profile.Provider]; profile.Provider != "" && !ok {
+			return fmt.Errorf("profile '%s' uses provider '%s', which is not configured", profileName, profile.Provider)
+		}
+		m.profile = profile
 	}
 
 	// Initialize clients for enabled providers
 	for name, provider := range m.config.Providers {
 		if provider.Enabled {
-			client := NewModelClient(provider.Provider, provider.APIKey, provider.Model)
+			model := provider.Model
+			if m.profile != nil && m.profile.Model != "" && name == m.defaultProvider() {
+				model = m.profile.Model
+			}
+			client := NewModelClient(provider.Provider, m.resolveAPIKey(name, provider), model)
 			if provider.BaseURL != "" {
 				client.SetBaseURL(provider.BaseURL)
 			}
+			client.SetMetrics(m.metrics)
 			m.clients[name] = client
 		}
 	}
@@ -95,6 +127,38 @@ func (m *MCPManager) LoadConfig() error {
 	return nil
 }
 
+// resolveAPIKey returns the provider's configured key or, when none is
+// stored in the config, looks it up in the secrets backend under the
+// provider's name and then its type, noting a key it cannot find in
+// KeyWarnings
+func (m *MCPManager) resolveAPIKey(name string, provider ProviderConfig) string {
+	if provider.APIKey != "" || !RequiresAPIKey(provider.Provider) {
+		return provider.APIKey
+	}
+
+	sm, err := secrets.NewSecretsManager()
+	if err != nil {
+		m.keyWarnings = append(m.keyWarnings, fmt.Sprintf("Could not open secrets backend for '%s': %v", name, err))
+		return ""
+	}
+
+	for _, key := range []string{name, string(provider.Provider)} {
+		if apiKey, err := sm.GetAPIKey(key); err == nil && apiKey != "" {
+			return apiKey
+		}
+	}
+
+	m.keyWarnings = append(m.keyWarnings, fmt.Sprintf("No API key for '%s' in the %s secrets backend", name, sm.Backend()))
+	return ""
+}
+
+// KeyWarnings returns why API keys of configured providers could not be
+// resolved. They are reported by the commands inspecting providers rather
+// than on every load.
+func (m *MCPManager) KeyWarnings() []string {
+	return m.keyWarnings
+}
+
 // SaveConfig saves the MCP configuration to disk
 func (m *MCPManager) SaveConfig() error {
 	data, err := json.MarshalIndent(m.config, "", "  ")
@@ -128,14 +192,21 @@ func (m *MCPManager) AddProvider(name string, provider ModelProvider, apiKey, mo
 	if baseURL, ok := options["base_url"].(string); ok {
 		config.BaseURL = baseURL
 	}
+	if rpm, ok := options["requests_per_minute"].(int); ok {
+		config.RequestsPerMinute = rpm
+	}
+	if concurrent, ok := options["max_concurrent"].(int); ok {
+		config.MaxConcurrent = concurrent
+	}
 
 	m.config.Providers[name] = config
 
 	// Create client
-	client := NewModelClient(provider, apiKey, model)
+	client := NewModelClient(provider, m.resolveAPIKey(name, config), model)
 	if config.BaseURL != "" {
 		client.SetBaseURL(config.BaseURL)
 	}
+	client.SetMetrics(m.metrics)
 	m.clients[name] = client
 
 	// Set as default if it's the first provider
@@ -155,6 +226,14 @@ func (m *MCPManager) RemoveProvider(name string) error {
 	delete(m.config.Providers, name)
 	delete(m.clients, name)
 
+	var fallbacks []string
+	for _, fallback := range m.config.FallbackProviders {
+		if fallback != name {
+			fallbacks = append(fallbacks, fallback)
+		}
+	}
+	m.config.FallbackProviders = fallbacks
+
 	// Update default provider if necessary
 	if m.config.DefaultProvider == name {
 		m.config.DefaultProvider = ""
@@ -178,13 +257,45 @@ func (m *MCPManager) SetDefaultProvider(name string) error {
 }
 
 func (m *MCPManager) GetDefaultProvider() string {
+	return m.defaultProvider()
+}
+
+// defaultProvider returns the active profile's provider, if it names one,
+// else the configured default
+func (m *MCPManager) defaultProvider() string {
+	if m.profile != nil && m.profile.Provider != "" {
+		return m.profile.Provider
+	}
 	return m.config.DefaultProvider
 }
 
+// DefaultTemperatureOption is a request option holding the temperature to
+// use when neither the request nor the active profile sets one
+const DefaultTemperatureOption = "default_temperature"
+
+// profileOptions resolves the temperature of a request: its own, else the
+// active profile's, else its DefaultTemperatureOption
+func (m *MCPManager) profileOptions(options map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(options)+1)
+	for key, value := range options {
+		merged[key] = value
+	}
+	delete(merged, DefaultTemperatureOption)
+
+	if _, ok := options["temperature"]; !ok {
+		if m.profile != nil && m.profile.Temperature != nil {
+			merged["temperature"] = *m.profile.Temperature
+		} else if fallback, ok := options[DefaultTemperatureOption]; ok {
+			merged["temperature"] = fallback
+		}
+	}
+	return merged
+}
+
 // GetClient returns a model client for the specified provider
 func (m *MCPManager) GetClient(providerName string) (*ModelClient, error) {
 	if providerName == "" {
-		providerName = m.config.DefaultProvider
+		providerName = m.defaultProvider()
 	}
 
 	client, exists := m.clients[providerName]
@@ -195,6 +306,73 @@ func (m *MCPManager) GetClient(providerName string) (*ModelClient, error) {
 	return client, nil
 }
 
+// SetFallbackProviders sets the ordered providers to try when the default fails
+func (m *MCPManager) SetFallbackProviders(names []string) error {
+	for _, name := range names {
+		if _, exists := m.config.Providers[name]; !exists {
+			return fmt.Errorf("provider '%s' not found", name)
+		}
+	}
+
+	m.config.FallbackProviders = names
+	return m.SaveConfig()
+}
+
+// GetFallbackProviders returns the configured fallback order
+func (m *MCPManager) GetFallbackProviders() []string {
+	return m.config.FallbackProviders
+}
+
+// ProviderChain returns the default provider followed by its fallbacks,
+// skipping duplicates and providers that are disabled
+func (m *MCPManager) ProviderChain() []string {
+	var chain []string
+	seen := make(map[string]bool)
+
+	for _, name := range append([]string{m.defaultProvider()}, m.config.FallbackProviders...) {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, ok := m.clients[name]; ok {
+			chain = append(chain, name)
+		}
+	}
+
+	return chain
+}
+
+// ChatWithFallback sends a chat request to the default provider and, on a
+// retryable failure, to each fallback in turn. It returns the name of the
+// provider that served the response. onFallback, if set, is called each time
+// a provider is skipped.
+func (m *MCPManager) ChatWithFallback(messages []Message, options map[string]interface{}, onFallback func(failed string, err error, next string)) (*ChatResponse, string, error) {
+	chain := m.ProviderChain()
+	if len(chain) == 0 {
+		return nil, "", fmt.Errorf("provider '%s' not configured or disabled", m.defaultProvider())
+	}
+
+	options = m.profileOptions(options)
+
+	var lastErr error
+	for i, name := range chain {
+		response, err := m.clients[name].Chat(messages, options)
+		if err == nil {
+			return response, name, nil
+		}
+
+		lastErr = fmt.Errorf("%s: %w", name, err)
+		if !IsRetryableError(err) || i == len(chain)-1 {
+			break
+		}
+		if onFallback != nil {
+			onFallback(name, err, chain[i+1])
+		}
+	}
+
+	return nil, "", lastErr
+}
+
 // ListProviders returns a list of configured providers
 func (m *MCPManager) ListProviders() map[string]ProviderConfig {
 	return m.config.Providers
@@ -217,7 +395,7 @@ func (m *MCPManager) ChatWithProvider(providerName string, messages []Message, o
 		return nil, err
 	}
 
-	return client.Chat(messages, options)
+	return client.Chat(messages, m.profileOptions(options))
 }
 
 // Chat sends a chat request to the default provider
@@ -254,6 +432,11 @@ func GetProviderDisplayName(provider ModelProvider) string {
 	}
 }
 
+// RequiresAPIKey reports whether a provider needs an API key to be used
+func RequiresAPIKey(provider ModelProvider) bool {
+	return provider != ProviderOllama
+}
+
 // GetDefaultModelForProvider returns the default model for a provider
 func GetDefaultModelForProvider(provider ModelProvider) string {
 	switch provider {
@@ -264,7 +447,7 @@ func GetDefaultModelForProvider(provider ModelProvider) string {
 	case ProviderGoogle:
 		return "gemini-2.5-flash"
 	case ProviderOllama:
-		return "llama2"
+		return "llama3"
 	case ProviderAzure:
 		return "gpt-4"
 	default:
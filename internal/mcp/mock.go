@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+)
+
+// MockProvider is the provider name mock responses are reported under
+const MockProvider = "mock"
+
+// MockClient answers chat requests with canned responses keyed by workflow
+// phase, without network access, so the orchestration can be exercised
+// deterministically in tests and demos
+type MockClient struct {
+	path      string
+	responses map[string][]string
+	mu        sync.Mutex
+	calls     map[string]int
+}
+
+// LoadMockClient reads canned responses from a YAML file mapping each phase
+// to a response, or to a list answered in order with the last one repeated:
+//
+//	specify: |
+//	  # PRD ...
+//	execute:
+//	  - "first task"
+//	  - "second task"
+func LoadMockClient(path string) (*MockClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock responses: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse mock responses %s: %w", path, err)
+	}
+
+	responses := make(map[string][]string)
+	for phase, value := range raw {
+		switch value := value.(type) {
+		case string:
+			responses[phase] = []string{value}
+		case []interface{}:
+			for i, item := range value {
+				text, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("mock responses %s: response %d of phase '%s' is not text", path, i+1, phase)
+				}
+				responses[phase] = append(responses[phase], text)
+			}
+		default:
+			return nil, fmt.Errorf("mock responses %s: phase '%s' needs a response or a list of responses", path, phase)
+		}
+		if len(responses[phase]) == 0 {
+			return nil, fmt.Errorf("mock responses %s: phase '%s' has no responses", path, phase)
+		}
+	}
+
+	return &MockClient{path: path, responses: responses, calls: make(map[string]int)}, nil
+}
+
+// Chat returns the next canned response of phase, reported as served by
+// MockProvider
+func (mc *MockClient) Chat(phase string, messages []Message, options map[string]interface{}) (*ChatResponse, string, error) {
+	responses, ok := mc.responses[phase]
+	if !ok {
+		phases := make([]string, 0, len(mc.responses))
+		for known := range mc.responses {
+			phases = append(phases, known)
+		}
+		sort.Strings(phases)
+		return nil, MockProvider, fmt.Errorf("no mock response for phase '%s' in %s (phases: %s)", phase, mc.path, strings.Join(phases, ", "))
+	}
+
+	mc.mu.Lock()
+	call := mc.calls[phase]
+	mc.calls[phase]++
+	mc.mu.Unlock()
+
+	content := responses[min(call, len(responses)-1)]
+	response := &ChatResponse{
+		Choices: []struct {
+			Message      Message `json:"message"`
+			FinishReason string  `json:"finish_reason"`
+		}{
+			{Message: Message{Role: "assistant", Content: content}, FinishReason: "stop"},
+		},
+	}
+	return response, MockProvider, nil
+}
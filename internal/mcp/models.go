@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// modelListTimeout bounds a query of a provider's models endpoint
+const modelListTimeout = 10 * time.Second
+
+// KnownModels returns the maintained list of models of a provider, or nil
+// for providers whose models are chosen by the user (Ollama pulls, Azure
+// deployments)
+func KnownModels(provider ModelProvider) []string {
+	switch provider {
+	case ProviderOpenAI:
+		return []string{
+			"gpt-4.1",
+			"gpt-4.1-mini",
+			"gpt-4.1-nano",
+			"gpt-4o",
+			"gpt-4o-mini",
+			"o1",
+			"o3-mini",
+			"gpt-4",
+			"gpt-4-turbo",
+			"gpt-4-turbo-preview",
+			"gpt-3.5-turbo",
+			"gpt-3.5-turbo-16k",
+		}
+	case ProviderAnthropic:
+		return []string{
+			"claude-3-5-sonnet-20241022",
+			"claude-3-5-haiku-20241022",
+			"claude-3-opus-20240229",
+			"claude-3-sonnet-20240229",
+			"claude-3-haiku-20240307",
+			"claude-2.1",
+			"claude-2",
+		}
+	case ProviderGoogle:
+		return []string{
+			"gemini-2.5-pro",
+			"gemini-2.5-flash",
+			"gemini-2.0-flash",
+			"gemini-2.0-flash-exp",
+			"gemini-1.5-pro",
+			"gemini-1.5-flash",
+		}
+	default:
+		return nil
+	}
+}
+
+// ListModels queries the provider's models endpoint for the models the
+// client's key can use
+func (mc *ModelClient) ListModels() ([]string, error) {
+	var endpoint string
+	headers := map[string]string{}
+
+	switch mc.Provider {
+	case ProviderOpenAI:
+		endpoint = "/models"
+		headers["Authorization"] = "Bearer " + mc.APIKey
+	case ProviderAnthropic:
+		endpoint = "/models?limit=1000"
+		headers["x-api-key"] = mc.APIKey
+		headers["anthropic-version"] = "2023-06-01"
+	case ProviderGoogle:
+		endpoint = "/models?pageSize=1000"
+		headers["x-goog-api-key"] = mc.APIKey
+	case ProviderOllama:
+		endpoint = "/api/tags"
+	default:
+		return nil, fmt.Errorf("listing models is not supported for %s", GetProviderDisplayName(mc.Provider))
+	}
+
+	req, err := http.NewRequest("GET", mc.BaseURL+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	client := &http.Client{Timeout: modelListTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	// OpenAI and Anthropic list {data: [{id}]}, Google {models: [{name}]}
+	// and Ollama {models: [{name}]}
+	var listing struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	var models []string
+	for _, model := range listing.Data {
+		models = append(models, model.ID)
+	}
+	for _, model := range listing.Models {
+		models = append(models, strings.TrimPrefix(model.Name, "models/"))
+	}
+	sort.Strings(models)
+	return models, nil
+}
+
+// AvailableModels returns the models of the client's provider, queried
+// live when possible and otherwise from the maintained list. live reports
+// which; models is nil when neither source knows the provider's models.
+func (mc *ModelClient) AvailableModels() (models []string, live bool, err error) {
+	if mc.APIKey != "" || !RequiresAPIKey(mc.Provider) {
+		models, err = mc.ListModels()
+		if err == nil {
+			return models, true, nil
+		}
+	}
+	return KnownModels(mc.Provider), false, err
+}
+
+// HasModel reports whether model is among models. An Ollama model without a
+// tag matches its ":latest" tag.
+func HasModel(models []string, model string) bool {
+	return slices.Contains(models, model) || slices.Contains(models, model+":latest")
+}
+
+// CheckModel returns an error listing the valid models when model is not
+// one of models; an empty list cannot be checked against and passes
+func CheckModel(provider ModelProvider, models []string, model string) error {
+	if len(models) == 0 || HasModel(models, model) {
+		return nil
+	}
+	return fmt.Errorf("unknown %s model '%s'. Valid models: %s", GetProviderDisplayName(provider), model, strings.Join(models, ", "))
+}
+
+// UnknownModels checks the model every enabled provider runs with against
+// the maintained lists, without network access, and describes each that is
+// not a known model
+func (m *MCPManager) UnknownModels() []string {
+	names := make([]string, 0, len(m.clients))
+	for name := range m.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var unknown []string
+	for _, name := range names {
+		client := m.clients[name]
+		if err := CheckModel(client.Provider, KnownModels(client.Provider), client.Model); err != nil {
+			unknown = append(unknown, fmt.Sprintf("provider '%s': %v", name, err))
+		}
+	}
+	return unknown
+}
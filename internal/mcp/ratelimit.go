@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limits of a provider without configured ones
+const (
+	DefaultRequestsPerMinute = 60
+	DefaultMaxConcurrent     = 4
+	// maxLimiterInterval bounds how far backoff slows a limiter down
+	maxLimiterInterval = time.Minute
+)
+
+// RateLimiter bounds the calls made to a provider: at most maxConcurrent at
+// once, started no closer together than the requests-per-minute limit allows
+type RateLimiter struct {
+	interval time.Duration
+	slots    chan struct{}
+	mu       sync.Mutex
+	next     time.Time // earliest start of the next call
+	// pausedUntil is the end of the current backoff pause
+	pausedUntil time.Time
+}
+
+// NewRateLimiter creates a limiter; values below 1 use the defaults
+func NewRateLimiter(requestsPerMinute, maxConcurrent int) *RateLimiter {
+	if requestsPerMinute < 1 {
+		requestsPerMinute = DefaultRequestsPerMinute
+	}
+	if maxConcurrent < 1 {
+		maxConcurrent = DefaultMaxConcurrent
+	}
+	return &RateLimiter{
+		interval: time.Minute / time.Duration(requestsPerMinute),
+		slots:    make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Acquire waits until a call may start and returns the function that ends it
+func (rl *RateLimiter) Acquire() (release func()) {
+	rl.slots <- struct{}{}
+
+	rl.mu.Lock()
+	now := time.Now()
+	start := rl.next
+	if start.Before(now) {
+		start = now
+	}
+	rl.next = start.Add(rl.interval)
+	rl.mu.Unlock()
+
+	time.Sleep(time.Until(start))
+	return func() { <-rl.slots }
+}
+
+// Backoff is called when the provider reports its rate limit exceeded
+// anyway, i.e. the configured limit is too high. It halves the rate of
+// later calls, holds every call back for a pause that doubles with each
+// retry of a call (attempt counts from 1) and returns that pause.
+func (rl *RateLimiter) Backoff(attempt int) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	// Calls rejected in the same burst slow the limiter down only once
+	now := time.Now()
+	if now.After(rl.pausedUntil) && rl.interval < maxLimiterInterval/2 {
+		rl.interval *= 2
+	}
+	wait := max(rl.interval, time.Second) << (attempt - 1)
+	if resume := now.Add(wait); rl.next.Before(resume) {
+		rl.next = resume
+		rl.pausedUntil = resume
+	}
+	return wait
+}
+
+// IsRateLimitError reports whether a call failed because the provider's rate
+// limit was exceeded
+func IsRateLimitError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// Limits returns the requests per minute and concurrent calls allowed for
+// the default provider, the defaults where none are configured
+func (m *MCPManager) Limits() (requestsPerMinute, maxConcurrent int) {
+	requestsPerMinute, maxConcurrent = DefaultRequestsPerMinute, DefaultMaxConcurrent
+	if m.config == nil {
+		return
+	}
+	if provider, ok := m.config.Providers[m.defaultProvider()]; ok {
+		if provider.RequestsPerMinute > 0 {
+			requestsPerMinute = provider.RequestsPerMinute
+		}
+		if provider.MaxConcurrent > 0 {
+			maxConcurrent = provider.MaxConcurrent
+		}
+	}
+	return
+}
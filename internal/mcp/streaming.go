@@ -74,7 +74,7 @@ func (mc *ModelClient) streamOpenAI(messages []Message, options map[string]inter
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	reader := bufio.NewReader(resp.Body)
@@ -170,7 +170,7 @@ func (mc *ModelClient) streamAnthropic(messages []Message, options map[string]in
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	reader := bufio.NewReader(resp.Body)
@@ -246,7 +246,7 @@ func (mc *ModelClient) streamGoogle(messages []Message, options map[string]inter
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	// Gemini returns line-delimited JSON
@@ -291,6 +291,9 @@ func (mc *ModelClient) streamOllama(messages []Message, options map[string]inter
 		"messages": messages,
 		"stream":   true,
 	}
+	if ollamaOptions := ollamaOptions(options); len(ollamaOptions) > 0 {
+		request["options"] = ollamaOptions
+	}
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
@@ -313,7 +316,7 @@ func (mc *ModelClient) streamOllama(messages []Message, options map[string]inter
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	reader := bufio.NewReader(resp.Body)
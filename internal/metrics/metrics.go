@@ -0,0 +1,226 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Counter names exported on the dashboard's /metrics endpoint
+const (
+	AICalls         = "viki_ai_calls_total"
+	Tokens          = "viki_tokens_total"
+	PhasesCompleted = "viki_phases_completed_total"
+	GateRejections  = "viki_gate_rejections_total"
+)
+
+// counters lists every counter with its help text, in exposition order
+var counters = []struct {
+	name string
+	help string
+}{
+	{AICalls, "AI provider requests, by provider and status."},
+	{Tokens, "Tokens used by AI provider requests, by provider and type."},
+	{PhasesCompleted, "Workflow phases completed, by phase."},
+	{GateRejections, "Gate artifacts rejected, by artifact."},
+}
+
+// Labels are the label pairs of a counter series
+type Labels map[string]string
+
+// Store keeps counters in .sdd/metrics.json so that every viki command run
+// in a project adds to the same totals. Updates hold a lockfile, so
+// concurrent viki processes do not lose each other's increments.
+type Store struct {
+	projectRoot string
+	mu          sync.Mutex
+}
+
+// lockWait is how long an update waits for another process to release the
+// metrics lock before giving up
+const lockWait = 5 * time.Second
+
+// staleLockAge is how old a lockfile must be to be taken over, e.g. after
+// the process holding it was killed
+const staleLockAge = 30 * time.Second
+
+// metricsFile is the on-disk form of the store
+type metricsFile struct {
+	Series map[string]float64 `json:"series"` // series key -> value
+}
+
+// NewStore creates a counter store for the given project root
+func NewStore(projectRoot string) *Store {
+	return &Store{projectRoot: projectRoot}
+}
+
+func (s *Store) path() string {
+	return filepath.Join(s.projectRoot, ".sdd", "metrics.json")
+}
+
+// lock takes the lockfile of the store, waiting up to lockWait for another
+// process to release it. The returned function releases it.
+func (s *Store) lock() (func(), error) {
+	path := s.path() + ".lock"
+	deadline := time.Now().Add(lockWait)
+	for {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock metrics: %w", err)
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			// Only remove the stale file, not one another waiter just created
+			if current, err := os.Stat(path); err == nil && os.SameFile(info, current) {
+				os.Remove(path)
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("metrics are locked by another viki process; remove %s if none is running", path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// Add increments a counter series by value. Nothing is recorded outside an
+// initialized project.
+func (s *Store) Add(name string, labels Labels, value float64) error {
+	if _, err := os.Stat(filepath.Join(s.projectRoot, ".sdd")); err != nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	series, err := s.Load()
+	if err != nil {
+		return err
+	}
+	series[seriesKey(name, labels)] += value
+
+	data, err := json.MarshalIndent(metricsFile{Series: series}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+
+	// Write atomically so a concurrent scrape never sees a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(s.path()), "metrics-*.json.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path())
+}
+
+// Load returns every recorded series and its value
+func (s *Store) Load() (map[string]float64, error) {
+	file := metricsFile{Series: make(map[string]float64)}
+
+	data, err := os.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return file.Series, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+	if file.Series == nil {
+		file.Series = make(map[string]float64)
+	}
+	return file.Series, nil
+}
+
+// WritePrometheus writes the counters in the Prometheus text exposition
+// format. Counters with no recorded series are reported as zero.
+func (s *Store) WritePrometheus(w io.Writer) error {
+	series, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, counter := range counters {
+		fmt.Fprintf(w, "# HELP %s %s\n", counter.name, counter.help)
+		fmt.Fprintf(w, "# TYPE %s counter\n", counter.name)
+
+		found := false
+		for _, key := range keys {
+			if key == counter.name || strings.HasPrefix(key, counter.name+"{") {
+				fmt.Fprintf(w, "%s %g\n", key, series[key])
+				found = true
+			}
+		}
+		if !found {
+			fmt.Fprintf(w, "%s 0\n", counter.name)
+		}
+	}
+
+	return nil
+}
+
+// seriesKey renders a series as it appears in the exposition format, with
+// labels sorted by name
+func seriesKey(name string, labels Labels) string {
+	if len(labels) == 0 {
+		return name
+	}
+
+	names := make([]string, 0, len(labels))
+	for label := range labels {
+		names = append(names, label)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, label := range names {
+		pairs[i] = fmt.Sprintf("%s=%q", label, escapeLabelValue(labels[label]))
+	}
+	return fmt.Sprintf("%s{%s}", name, strings.Join(pairs, ","))
+}
+
+// escapeLabelValue leaves only characters that %q renders the way the
+// exposition format expects
+func escapeLabelValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\n' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+}
@@ -14,7 +14,7 @@ type PairSession struct {
 	ID          string                 `json:"id"`
 	StartTime   time.Time              `json:"start_time"`
 	ActiveFile  string                 `json:"active_file"`
-	Context     *lsp.CodebaseContext   `json:"context"`
+	Context     *lsp.CodebaseContext   `json:"-"` // re-analyzed when a stored session is restored
 	Agent       *agents.Agent          `json:"agent"`
 	SessionLog  []SessionEntry         `json:"session_log"`
 	Stats       PairingStats           `json:"stats"`
@@ -30,6 +30,7 @@ type SessionEntry struct {
 	Line         int       `json:"line"`
 	UserAction  string    `json:"user_action"` // accepted, rejected, modified, ignored
 	Duration    int       `json:"duration_ms"` // milliseconds spent on this interaction
+	Prompt      string    `json:"prompt,omitempty"` // what was sent to the AI for a suggestion
 }
 
 // PairingStats tracks session statistics
@@ -38,6 +39,8 @@ type PairingStats struct {
 	AcceptedSuggestions  int           `json:"accepted_suggestions"`
 	RejectedSuggestions  int           `json:"rejected_suggestions"`
 	TimeSpent            time.Duration `json:"time_spent"`
+	SuggestionTime       time.Duration `json:"suggestion_time"` // total time spent waiting for suggestions
+	Suggestions          int           `json:"suggestions"`
 	FilesTouched         []string      `json:"files_touched"`
 	ProductivityScore    float64       `json:"productivity_score"`
 	LearningOpportunities int          `json:"learning_opportunities"`
@@ -58,10 +61,15 @@ func NewPairProgrammer(projectRoot string) (*PairProgrammer, error) {
 		return nil, fmt.Errorf("failed to initialize agent service: %w", err)
 	}
 
-	return &PairProgrammer{
+	pp := &PairProgrammer{
 		projectRoot: projectRoot,
 		agentSvc:    agentSvc,
-	}, nil
+	}
+	if err := pp.loadActiveSession(); err != nil {
+		return nil, fmt.Errorf("failed to restore pair session: %w", err)
+	}
+
+	return pp, nil
 }
 
 // StartSession begins a new pair programming session
@@ -97,7 +105,11 @@ func (pp *PairProgrammer) StartSession(agentRole string, focusArea string) (*Pai
 	pp.activeSession = session
 
 	// Add initial session entry
-	pp.logSessionEntry("session_start", fmt.Sprintf("Started pair programming session with %s agent focusing on %s", agentRole, focusArea), "", 0, "")
+	pp.logSessionEntry("session_start", fmt.Sprintf("Started pair programming session with %s agent focusing on %s", agentRole, focusArea), "", 0, "", 0)
+
+	if err := pp.saveActiveSession(); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
 
 	return session, nil
 }
@@ -116,20 +128,36 @@ func (pp *PairProgrammer) EndSession() (*PairSession, error) {
 	session.Stats = pp.calculateSessionStats(session)
 
 	// Add final log entry
-	pp.logSessionEntry("session_end", fmt.Sprintf("Ended session after %v", session.Stats.TimeSpent.Round(time.Second)), "", 0, "")
+	pp.logSessionEntry("session_end", fmt.Sprintf("Ended session after %v", session.Stats.TimeSpent.Round(time.Second)), "", 0, "", 0)
 
 	// Move to history
 	pp.sessionHistory = append(pp.sessionHistory, *session)
 	pp.activeSession = nil
 
+	if err := pp.saveActiveSession(); err != nil {
+		return nil, fmt.Errorf("failed to clear saved session: %w", err)
+	}
+
 	return session, nil
 }
 
-// GetSuggestion requests AI assistance for current context
-func (pp *PairProgrammer) GetSuggestion(filePath string, cursorLine int, context string, requestType string) (*PairSuggestion, error) {
+// GetSuggestion requests AI assistance for current context. errorOutput is
+// the error message or stack trace being diagnosed by a debug request.
+func (pp *PairProgrammer) GetSuggestion(filePath string, cursorLine int, context string, requestType string, errorOutput string) (*PairSuggestion, error) {
 	if pp.activeSession == nil {
 		return nil, fmt.Errorf("no active pair programming session")
 	}
+	if requestType == "debug" && strings.TrimSpace(errorOutput) == "" {
+		return nil, fmt.Errorf("a debug request needs the error message or stack trace")
+	}
+
+	// A restored session has no codebase analysis yet
+	if pp.activeSession.Context == nil {
+		codebase := lsp.NewCodebaseContext(pp.projectRoot)
+		if err := codebase.AnalyzeProject(); err == nil {
+			pp.activeSession.Context = codebase
+		}
+	}
 
 	startTime := time.Now()
 
@@ -137,16 +165,21 @@ func (pp *PairProgrammer) GetSuggestion(filePath string, cursorLine int, context
 	pp.activeSession.ActiveFile = filePath
 
 	// Get context-aware suggestion
-	suggestion, err := pp.generateSuggestion(filePath, cursorLine, context, requestType)
+	suggestion, err := pp.generateSuggestion(filePath, cursorLine, context, requestType, errorOutput)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate suggestion: %w", err)
 	}
 
-	duration := int(time.Since(startTime).Milliseconds())
-	_ = duration // Reserve for future use in logging
+	duration := time.Since(startTime)
 
 	// Log the interaction
-	pp.logSessionEntry("suggestion", suggestion.Content, filePath, cursorLine, "")
+	pp.logSessionEntry("suggestion", suggestion.Content, filePath, cursorLine, "", duration)
+	log := pp.activeSession.SessionLog
+	log[len(log)-1].Prompt = suggestion.Prompt
+
+	if err := pp.saveActiveSession(); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
 
 	return suggestion, nil
 }
@@ -174,7 +207,7 @@ func (pp *PairProgrammer) RecordUserAction(action string, suggestionID string) e
 		}
 	}
 
-	return nil
+	return pp.saveActiveSession()
 }
 
 // PairSuggestion represents an AI-generated suggestion
@@ -187,10 +220,11 @@ type PairSuggestion struct {
 	Alternatives []string `json:"alternatives"`
 	File        string   `json:"file"`
 	Line         int      `json:"line"`
+	Prompt      string   `json:"prompt"`
 }
 
 // generateSuggestion creates context-aware suggestions
-func (pp *PairProgrammer) generateSuggestion(filePath string, cursorLine int, context string, requestType string) (*PairSuggestion, error) {
+func (pp *PairProgrammer) generateSuggestion(filePath string, cursorLine int, context string, requestType string, errorOutput string) (*PairSuggestion, error) {
 	suggestion := &PairSuggestion{
 		ID:         generateSuggestionID(),
 		File:       filePath,
@@ -200,10 +234,17 @@ func (pp *PairProgrammer) generateSuggestion(filePath string, cursorLine int, co
 	}
 
 	// Build context-aware prompt
-	prompt := pp.buildSuggestionPrompt(filePath, cursorLine, context, requestType)
+	prompt := pp.buildSuggestionPrompt(filePath, cursorLine, context, requestType, errorOutput)
+	suggestion.Prompt = prompt
+
+	// Errors are diagnosed by the debugger whoever the session is with
+	agentRole := pp.activeSession.Agent.Role
+	if requestType == "debug" {
+		agentRole = "debugger"
+	}
 
 	// Get AI response
-	response, err := pp.agentSvc.GetAgentResponse(pp.activeSession.Agent.Role, "execute", prompt, "", "")
+	response, err := pp.agentSvc.GetAgentResponse(agentRole, "execute", prompt, "", "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get AI response: %w", err)
 	}
@@ -221,14 +262,28 @@ func (pp *PairProgrammer) generateSuggestion(filePath string, cursorLine int, co
 }
 
 // buildSuggestionPrompt creates a detailed prompt for AI suggestions
-func (pp *PairProgrammer) buildSuggestionPrompt(filePath string, cursorLine int, context string, requestType string) string {
+func (pp *PairProgrammer) buildSuggestionPrompt(filePath string, cursorLine int, context string, requestType string, errorOutput string) string {
 	var prompt strings.Builder
 
 	prompt.WriteString(fmt.Sprintf("You are pair programming with a developer. Current context:\n"))
 	prompt.WriteString(fmt.Sprintf("- File: %s (line %d)\n", filePath, cursorLine))
 	prompt.WriteString(fmt.Sprintf("- Request type: %s\n", requestType))
+
+	// Mask secrets in the code before it leaves the process
+	context, redactions := pp.agentSvc.RedactContent(context)
+	if redactions > 0 {
+		fmt.Printf("🔒 Redacted %d likely secret(s) from the code context\n", redactions)
+	}
 	prompt.WriteString(fmt.Sprintf("- Code context:\n```\n%s\n```\n\n", context))
 
+	if errorOutput != "" {
+		errorOutput, redactions := pp.agentSvc.RedactContent(errorOutput)
+		if redactions > 0 {
+			fmt.Printf("🔒 Redacted %d likely secret(s) from the error output\n", redactions)
+		}
+		prompt.WriteString(fmt.Sprintf("- Error output:\n```\n%s\n```\n\n", errorOutput))
+	}
+
 	// Add project context
 	if pp.activeSession.Context != nil {
 		prompt.WriteString("Project context:\n")
@@ -275,6 +330,14 @@ func (pp *PairProgrammer) buildSuggestionPrompt(filePath string, cursorLine int,
 		prompt.WriteString("- Best practices and alternatives\n")
 		prompt.WriteString("- Performance and security considerations\n\n")
 
+	case "debug":
+		prompt.WriteString("Diagnose the error above. Focus on:\n")
+		prompt.WriteString("- The root cause, not just the symptom\n")
+		prompt.WriteString("- The frame of the trace where things first go wrong\n")
+		prompt.WriteString("- A minimal fix to the code shown\n")
+		prompt.WriteString("- How to keep this class of error from coming back\n\n")
+		prompt.WriteString("Format: Start with a one-line diagnosis, then the root cause, then the fix as code.\n")
+
 	default:
 		prompt.WriteString("Provide helpful assistance for the developer's current task.\n")
 	}
@@ -370,6 +433,10 @@ func (pp *PairProgrammer) calculateSessionStats(session *PairSession) PairingSta
 		if entry.File != "" {
 			fileSet[entry.File] = true
 		}
+		if entry.Type == "suggestion" {
+			stats.Suggestions++
+			stats.SuggestionTime += time.Duration(entry.Duration) * time.Millisecond
+		}
 	}
 
 	// Convert set to slice
@@ -397,7 +464,7 @@ func (pp *PairProgrammer) calculateSessionStats(session *PairSession) PairingSta
 }
 
 // logSessionEntry adds an entry to the session log
-func (pp *PairProgrammer) logSessionEntry(entryType, content, file string, line int, userAction string) {
+func (pp *PairProgrammer) logSessionEntry(entryType, content, file string, line int, userAction string, duration time.Duration) {
 	if pp.activeSession == nil {
 		return
 	}
@@ -409,6 +476,7 @@ func (pp *PairProgrammer) logSessionEntry(entryType, content, file string, line
 		File:       file,
 		Line:        line,
 		UserAction: userAction,
+		Duration:   int(duration.Milliseconds()),
 	}
 
 	pp.activeSession.SessionLog = append(pp.activeSession.SessionLog, entry)
@@ -430,7 +498,13 @@ func (pp *PairProgrammer) GetSessionReport(session *PairSession) string {
 	report.WriteString(fmt.Sprintf("- **Suggestions Accepted:** %d\n", session.Stats.AcceptedSuggestions))
 	report.WriteString(fmt.Sprintf("- **Suggestions Rejected:** %d\n", session.Stats.RejectedSuggestions))
 	report.WriteString(fmt.Sprintf("- **Productivity Score:** %.1f/10\n", session.Stats.ProductivityScore))
-	report.WriteString(fmt.Sprintf("- **Learning Opportunities:** %d\n\n", session.Stats.LearningOpportunities))
+	report.WriteString(fmt.Sprintf("- **Learning Opportunities:** %d\n", session.Stats.LearningOpportunities))
+	if session.Stats.Suggestions > 0 {
+		average := session.Stats.SuggestionTime / time.Duration(session.Stats.Suggestions)
+		report.WriteString(fmt.Sprintf("- **Total Suggestion Latency:** %v\n", session.Stats.SuggestionTime.Round(time.Millisecond)))
+		report.WriteString(fmt.Sprintf("- **Average Suggestion Latency:** %v\n", average.Round(time.Millisecond)))
+	}
+	report.WriteString("\n")
 
 	// Acceptance rate
 	totalSuggestions := session.Stats.AcceptedSuggestions + session.Stats.RejectedSuggestions
@@ -472,6 +546,10 @@ func (pp *PairProgrammer) GetSessionReport(session *PairSession) string {
 		}
 		report.WriteString(fmt.Sprintf("   %s\n", content))
 
+		if entry.Duration > 0 {
+			report.WriteString(fmt.Sprintf("   Latency: %v\n", time.Duration(entry.Duration)*time.Millisecond))
+		}
+
 		if entry.UserAction != "" {
 			report.WriteString(fmt.Sprintf("   *User action: %s*\n", entry.UserAction))
 		}
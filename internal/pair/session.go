@@ -0,0 +1,56 @@
+package pair
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ActiveSessionPath returns where the active session is kept between
+// commands
+func ActiveSessionPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "pair_session.json")
+}
+
+// loadActiveSession restores the session started by an earlier command, if
+// any
+func (pp *PairProgrammer) loadActiveSession() error {
+	data, err := os.ReadFile(ActiveSessionPath(pp.projectRoot))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var session PairSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return fmt.Errorf("invalid %s: %w", ActiveSessionPath(pp.projectRoot), err)
+	}
+	if session.IsActive {
+		pp.activeSession = &session
+	}
+	return nil
+}
+
+// saveActiveSession stores the active session for the next command, or
+// removes the stored one when the session has ended
+func (pp *PairProgrammer) saveActiveSession() error {
+	path := ActiveSessionPath(pp.projectRoot)
+	if pp.activeSession == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(pp.activeSession, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,75 @@
+package pair
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TranscriptFormats lists the formats ExportTranscript accepts
+var TranscriptFormats = []string{"markdown", "json"}
+
+// ExportTranscript renders a session as a chronological log of every prompt
+// and AI response in full, in markdown or json
+func (pp *PairProgrammer) ExportTranscript(session *PairSession, format string) (string, error) {
+	switch format {
+	case "markdown", "md":
+		return markdownTranscript(session), nil
+	case "json":
+		data, err := json.MarshalIndent(session, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unknown transcript format %q (use %s)", format, strings.Join(TranscriptFormats, " or "))
+	}
+}
+
+// markdownTranscript writes each interaction as a heading followed by the
+// prompt and response
+func markdownTranscript(session *PairSession) string {
+	var transcript strings.Builder
+
+	transcript.WriteString("# 👥 Pair Programming Transcript\n\n")
+	transcript.WriteString(fmt.Sprintf("**Session ID:** %s\n", session.ID))
+	if session.Agent != nil {
+		transcript.WriteString(fmt.Sprintf("**Agent:** %s\n", session.Agent.Role))
+	}
+	transcript.WriteString(fmt.Sprintf("**Started:** %s\n\n", session.StartTime.Format("2006-01-02 15:04")))
+
+	for _, entry := range session.SessionLog {
+		transcript.WriteString("---\n\n")
+
+		if entry.Type != "suggestion" {
+			transcript.WriteString(fmt.Sprintf("*%s — %s*\n\n", entry.Timestamp.Format("15:04:05"), entry.Content))
+			continue
+		}
+
+		location := entry.File
+		if entry.Line > 0 {
+			location = fmt.Sprintf("%s:%d", entry.File, entry.Line)
+		}
+		transcript.WriteString(fmt.Sprintf("## %s — %s\n\n", entry.Timestamp.Format("15:04:05"), location))
+
+		if entry.Prompt != "" {
+			// Four backticks, so code blocks inside the prompt stay intact
+			transcript.WriteString("### 🧑 Prompt\n\n")
+			transcript.WriteString(fmt.Sprintf("````\n%s\n````\n\n", strings.TrimSpace(entry.Prompt)))
+		}
+
+		transcript.WriteString("### 🤖 Response")
+		if entry.Duration > 0 {
+			transcript.WriteString(fmt.Sprintf(" (%v)", time.Duration(entry.Duration)*time.Millisecond))
+		}
+		transcript.WriteString("\n\n")
+		transcript.WriteString(strings.TrimSpace(entry.Content) + "\n\n")
+
+		if entry.UserAction != "" {
+			transcript.WriteString(fmt.Sprintf("*User action: %s*\n\n", entry.UserAction))
+		}
+	}
+
+	return transcript.String()
+}
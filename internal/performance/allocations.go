@@ -0,0 +1,72 @@
+package performance
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+// findLoopAllocations reports make/new calls that run on every iteration of
+// an enclosing for or range loop. Allocations in a loop's init, condition or
+// post statement, or inside a closure defined in the loop, are not reported.
+func findLoopAllocations(file *ast.File, fset *token.FileSet, filePath string) []AllocationPattern {
+	var patterns []AllocationPattern
+	var stack []ast.Node
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok || (ident.Name != "make" && ident.Name != "new") {
+			return true
+		}
+
+		loop := enclosingLoopBody(stack, call.Pos())
+		if loop == nil {
+			return true
+		}
+
+		patterns = append(patterns, AllocationPattern{
+			Pattern:    "allocation inside loop",
+			Frequency:  1,
+			Location:   fmt.Sprintf("%s:%d", filePath, fset.Position(call.Pos()).Line),
+			Impact:     fmt.Sprintf("%s() allocates on every iteration of the loop at line %d", ident.Name, fset.Position(loop.Pos()).Line),
+			Suggestion: "Hoist the allocation before the loop and reuse it (reset with s = s[:0] or clear(m))",
+		})
+		return true
+	})
+
+	return patterns
+}
+
+// enclosingLoopBody returns the innermost loop whose body contains pos,
+// stopping at function boundaries
+func enclosingLoopBody(stack []ast.Node, pos token.Pos) ast.Node {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch node := stack[i].(type) {
+		case *ast.FuncLit, *ast.FuncDecl:
+			return nil
+		case *ast.ForStmt:
+			if inBlock(node.Body, pos) {
+				return node
+			}
+		case *ast.RangeStmt:
+			if inBlock(node.Body, pos) {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+func inBlock(block *ast.BlockStmt, pos token.Pos) bool {
+	return block != nil && pos > block.Lbrace && pos < block.Rbrace
+}
@@ -0,0 +1,213 @@
+package performance
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MetricDelta is the change of one metric from a baseline to the current tree
+type MetricDelta struct {
+	Name          string
+	Baseline      float64
+	Current       float64
+	HigherIsWorse bool
+}
+
+// Delta returns the current value minus the baseline value
+func (md MetricDelta) Delta() float64 {
+	return md.Current - md.Baseline
+}
+
+// Regressed reports whether the metric moved in the wrong direction
+func (md MetricDelta) Regressed() bool {
+	const epsilon = 0.05
+	if md.HigherIsWorse {
+		return md.Delta() > epsilon
+	}
+	return md.Delta() < -epsilon
+}
+
+// FunctionDelta is the change in complexity of one function
+type FunctionDelta struct {
+	Name     string
+	File     string
+	Baseline int // 0 when the function is new
+	Current  int
+}
+
+// Comparison is the profile of the current tree against a git ref
+type Comparison struct {
+	Ref            string
+	Metrics        []MetricDelta
+	MoreComplex    []FunctionDelta // functions that got more complex, largest increase first
+	NewBottlenecks []Bottleneck    // bottlenecks not present at the ref
+}
+
+// Regressions returns the metrics that got worse
+func (c *Comparison) Regressions() []MetricDelta {
+	var regressed []MetricDelta
+	for _, metric := range c.Metrics {
+		if metric.Regressed() {
+			regressed = append(regressed, metric)
+		}
+	}
+	return regressed
+}
+
+// CompareWithRef profiles the tree as of a git ref in a temporary worktree,
+// profiles the current tree, and reports how each metric changed. The
+// current complexity ignore list and path filter apply to both. The worktree is removed
+// afterwards.
+func (pp *PerformanceProfiler) CompareWithRef(ref string) (*Comparison, error) {
+	root, err := filepath.Abs(pp.projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, err := exec.Command("git", "-C", root, "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s is not inside a git repository: %w", root, err)
+	}
+	if err := exec.Command("git", "-C", root, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Run(); err != nil {
+		return nil, fmt.Errorf("unknown git ref %q", ref)
+	}
+
+	worktree, err := os.MkdirTemp("", "viki-compare-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	defer func() {
+		exec.Command("git", "-C", root, "worktree", "remove", "--force", worktree).Run()
+		os.RemoveAll(worktree)
+		exec.Command("git", "-C", root, "worktree", "prune").Run()
+	}()
+
+	add := exec.Command("git", "-C", root, "worktree", "add", "--detach", "--quiet", worktree, ref)
+	if output, err := add.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to check out %s: %w\n%s", ref, err, strings.TrimSpace(string(output)))
+	}
+	baselineRoot := filepath.Join(worktree, strings.TrimSpace(string(prefix)))
+
+	// Both trees are measured with the current ignore list and scoring formula
+	for _, configPath := range []func(string) string{ComplexityIgnorePath, ScoringWeightsPath} {
+		if config, err := os.ReadFile(configPath(root)); err == nil {
+			os.MkdirAll(filepath.Dir(configPath(baselineRoot)), 0755)
+			if err := os.WriteFile(configPath(baselineRoot), config, 0644); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	baselineProfiler := NewPerformanceProfiler(baselineRoot)
+	baselineProfiler.SetIncludeReceiver(pp.includeReceiver)
+	baselineProfiler.SetPathFilter(pp.pathFilter)
+	baseline, err := baselineProfiler.AnalyzeProject()
+	if err != nil {
+		return nil, fmt.Errorf("failed to profile %s: %w", ref, err)
+	}
+
+	current, err := pp.AnalyzeProject()
+	if err != nil {
+		return nil, fmt.Errorf("failed to profile the current tree: %w", err)
+	}
+
+	comparison := &Comparison{
+		Ref: ref,
+		Metrics: []MetricDelta{
+			{Name: "Average complexity (all functions)", Baseline: averageComplexity(baseline), Current: averageComplexity(current), HigherIsWorse: true},
+			{Name: "Average complexity (complex functions)", Baseline: baseline.ComplexityAnalysis.CyclomaticComplexity, Current: current.ComplexityAnalysis.CyclomaticComplexity, HigherIsWorse: true},
+			{Name: "Complex functions", Baseline: float64(len(baseline.ComplexityAnalysis.ComplexFunctions)), Current: float64(len(current.ComplexityAnalysis.ComplexFunctions)), HigherIsWorse: true},
+			{Name: "Average maintainability index", Baseline: baseline.ComplexityAnalysis.MaintainabilityIndex, Current: current.ComplexityAnalysis.MaintainabilityIndex},
+			{Name: "Bottlenecks", Baseline: float64(len(baseline.Bottlenecks)), Current: float64(len(current.Bottlenecks)), HigherIsWorse: true},
+			{Name: "Duplicated code (%)", Baseline: baseline.Duplication, Current: current.Duplication, HigherIsWorse: true},
+			{Name: "Overall score", Baseline: baseline.OverallScore, Current: current.OverallScore},
+		},
+	}
+
+	// Functions are matched by file and name, both relative to the project
+	before := functionComplexity(baselineRoot, baseline.ComplexityAnalysis.Functions)
+	for key, complexity := range functionComplexity(root, current.ComplexityAnalysis.Functions) {
+		if previous := before[key]; complexity > previous {
+			file, name, _ := strings.Cut(key, "\x00")
+			comparison.MoreComplex = append(comparison.MoreComplex, FunctionDelta{
+				Name: name, File: file, Baseline: previous, Current: complexity,
+			})
+		}
+	}
+	sort.Slice(comparison.MoreComplex, func(i, j int) bool {
+		a, b := comparison.MoreComplex[i], comparison.MoreComplex[j]
+		if a.Current-a.Baseline != b.Current-b.Baseline {
+			return a.Current-a.Baseline > b.Current-b.Baseline
+		}
+		return a.File+a.Name < b.File+b.Name
+	})
+
+	known := make(map[string]bool)
+	for _, bottleneck := range baseline.Bottlenecks {
+		known[bottleneckKey(baselineRoot, bottleneck)] = true
+	}
+	for _, bottleneck := range current.Bottlenecks {
+		if !known[bottleneckKey(root, bottleneck)] {
+			comparison.NewBottlenecks = append(comparison.NewBottlenecks, bottleneck)
+		}
+	}
+
+	return comparison, nil
+}
+
+// averageComplexity averages the complexity of every analyzed function
+func averageComplexity(report *PerformanceReport) float64 {
+	functions := report.ComplexityAnalysis.Functions
+	if len(functions) == 0 {
+		return 0
+	}
+	total := 0
+	for _, fn := range functions {
+		total += fn.Complexity
+	}
+	return float64(total) / float64(len(functions))
+}
+
+// functionComplexity maps file and function name to complexity, keeping the
+// highest when methods of one file share a name
+func functionComplexity(root string, functions []FunctionMetrics) map[string]int {
+	complexity := make(map[string]int)
+	for _, fn := range functions {
+		key := relativePath(root, fn.File) + "\x00" + fn.Name
+		complexity[key] = max(complexity[key], fn.Complexity)
+	}
+	return complexity
+}
+
+// bottleneckKey identifies a bottleneck independently of the tree it was
+// found in
+func bottleneckKey(root string, bottleneck Bottleneck) string {
+	location := bottleneck.Location
+	if file, rest, ok := strings.Cut(location, ":"); ok {
+		// Line numbers shift as unrelated code changes
+		if strings.Trim(rest, "0123456789") == "" {
+			rest = ""
+		}
+		location = relativePath(root, file) + ":" + rest
+	}
+	return bottleneck.Type + "|" + location + "|" + bottleneck.Description
+}
+
+// relativePath returns path relative to root, in slash form
+func relativePath(root, path string) string {
+	if !filepath.IsAbs(path) {
+		absolute, err := filepath.Abs(path)
+		if err != nil {
+			return filepath.ToSlash(path)
+		}
+		path = absolute
+	}
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
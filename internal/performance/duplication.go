@@ -0,0 +1,107 @@
+package performance
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ultimate-sdd-framework/internal/analysis"
+)
+
+// duplicationWindow is how many consecutive statement lines make a clone
+const duplicationWindow = 6
+
+// MeasureDuplication returns the percentage of statement lines in the
+// project's Go files the filter selects, tests excluded, that belong to a block of at least
+// duplicationWindow lines repeated elsewhere. Blank lines, comments and
+// lone closing brackets are not statement lines.
+func MeasureDuplication(projectRoot string, filter *analysis.PathFilter) (float64, error) {
+	type location struct {
+		file  int
+		start int
+	}
+
+	var files [][]string
+	err := filepath.Walk(projectRoot, filter.Filtered(projectRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if name := info.Name(); path != projectRoot && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, statementLines(string(content)))
+		return nil
+	}))
+	if err != nil {
+		return 0, err
+	}
+
+	windows := make(map[string][]location)
+	total := 0
+	for f, lines := range files {
+		total += len(lines)
+		for start := 0; start+duplicationWindow <= len(lines); start++ {
+			key := strings.Join(lines[start:start+duplicationWindow], "\n")
+			windows[key] = append(windows[key], location{f, start})
+		}
+	}
+	if total == 0 {
+		return 0, nil
+	}
+
+	duplicated := make([]map[int]bool, len(files))
+	for _, locations := range windows {
+		if len(locations) < 2 {
+			continue
+		}
+		for _, loc := range locations {
+			if duplicated[loc.file] == nil {
+				duplicated[loc.file] = make(map[int]bool)
+			}
+			for line := loc.start; line < loc.start+duplicationWindow; line++ {
+				duplicated[loc.file][line] = true
+			}
+		}
+	}
+
+	count := 0
+	for _, lines := range duplicated {
+		count += len(lines)
+	}
+	return float64(count) / float64(total) * 100, nil
+}
+
+// statementLines returns a file's lines without indentation, leaving out
+// the package clause, imports, blank lines, comments and lines of closing
+// brackets only
+func statementLines(content string) []string {
+	var lines []string
+	inImports := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case inImports:
+			inImports = line != ")"
+			continue
+		case line == "import (":
+			inImports = true
+			continue
+		case line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "package ") ||
+			strings.HasPrefix(line, "import ") || strings.Trim(line, "})],;") == "":
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,185 @@
+package performance
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// findGoroutineLeaks reports go statements whose surrounding function has no
+// visible way of waiting for or stopping the goroutine: a WaitGroup (or
+// errgroup) Add/Go + Wait pairing, a cancellable context the goroutine
+// observes, or a channel the goroutine signals and the function receives from.
+func findGoroutineLeaks(file *ast.File, fset *token.FileSet, filePath string) []LeakDetection {
+	var leaks []LeakDetection
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			goStmt, ok := n.(*ast.GoStmt)
+			if !ok {
+				return true
+			}
+
+			if !hasWaitGroupPairing(fn.Body) &&
+				!observesCancellableContext(fn.Body, goStmt) &&
+				!signalsCompletion(fn.Body, goStmt) {
+				leaks = append(leaks, LeakDetection{
+					Type:        "goroutine",
+					Location:    fmt.Sprintf("%s:%d", filePath, fset.Position(goStmt.Pos()).Line),
+					Description: fmt.Sprintf("Goroutine in %s is never waited for or cancelled", fn.Name.Name),
+					Severity:    "medium",
+				})
+			}
+			return true
+		})
+	}
+
+	return leaks
+}
+
+// methodCalls maps receiver expressions to the set of methods called on them
+func methodCalls(node ast.Node) map[string]map[string]bool {
+	calls := make(map[string]map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		recv := types.ExprString(sel.X)
+		if calls[recv] == nil {
+			calls[recv] = make(map[string]bool)
+		}
+		calls[recv][sel.Sel.Name] = true
+		return true
+	})
+	return calls
+}
+
+// hasWaitGroupPairing reports whether the function both schedules work on and
+// waits for the same WaitGroup-like value
+func hasWaitGroupPairing(body *ast.BlockStmt) bool {
+	for _, methods := range methodCalls(body) {
+		if methods["Wait"] && (methods["Add"] || methods["Go"]) {
+			return true
+		}
+	}
+	return false
+}
+
+// observesCancellableContext reports whether the goroutine listens on a
+// context's Done channel, or uses a context the function made cancellable
+func observesCancellableContext(body *ast.BlockStmt, goStmt *ast.GoStmt) bool {
+	observesDone := false
+	ast.Inspect(goStmt, func(n ast.Node) bool {
+		if unary, ok := n.(*ast.UnaryExpr); ok && unary.Op == token.ARROW {
+			if call, ok := unary.X.(*ast.CallExpr); ok {
+				if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Done" {
+					observesDone = true
+				}
+			}
+		}
+		return !observesDone
+	})
+	if observesDone {
+		return true
+	}
+
+	cancellable := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 || len(assign.Lhs) == 0 {
+			return true
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		switch types.ExprString(call.Fun) {
+		case "context.WithCancel", "context.WithTimeout", "context.WithDeadline", "context.WithCancelCause":
+			cancellable[types.ExprString(assign.Lhs[0])] = true
+		}
+		return true
+	})
+
+	return referencesAny(goStmt, cancellable)
+}
+
+// signalsCompletion reports whether the goroutine sends on or closes a channel
+// that the surrounding function receives from, ranges over or returns
+func signalsCompletion(body *ast.BlockStmt, goStmt *ast.GoStmt) bool {
+	signalled := make(map[string]bool)
+	ast.Inspect(goStmt, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SendStmt:
+			signalled[types.ExprString(node.Chan)] = true
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "close" && len(node.Args) == 1 {
+				signalled[types.ExprString(node.Args[0])] = true
+			}
+		}
+		return true
+	})
+
+	// Channels handed to a named function may be signalled inside it
+	if _, isLiteral := goStmt.Call.Fun.(*ast.FuncLit); !isLiteral {
+		for _, arg := range goStmt.Call.Args {
+			signalled[types.ExprString(arg)] = true
+		}
+	}
+
+	if len(signalled) == 0 {
+		return false
+	}
+
+	received := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == goStmt {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW && signalled[types.ExprString(node.X)] {
+				received = true
+			}
+		case *ast.RangeStmt:
+			if signalled[types.ExprString(node.X)] {
+				received = true
+			}
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				if signalled[types.ExprString(result)] {
+					received = true
+				}
+			}
+		}
+		return !received
+	})
+
+	return received
+}
+
+// referencesAny reports whether node mentions any of the given expressions
+func referencesAny(node ast.Node, names map[string]bool) bool {
+	if len(names) == 0 {
+		return false
+	}
+
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if expr, ok := n.(ast.Expr); ok && names[types.ExprString(expr)] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,116 @@
+package performance
+
+import (
+	"fmt"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"ultimate-sdd-framework/internal/analysis"
+)
+
+// IgnoreComplexityMarker, in the comment above a function, keeps it out of
+// the complex function list
+const IgnoreComplexityMarker = "//viki:ignore-complexity"
+
+// ComplexityIgnore lists the files and functions never flagged as complex.
+// It is read from .sdd/perf-ignore.yaml:
+//
+//	files:
+//	  - "**/*_gen.go"
+//	  - internal/cli/dispatch.go
+//	functions:
+//	  - dispatchCommand
+//	  - "Parser.parse*"
+type ComplexityIgnore struct {
+	Files     []string `yaml:"files,omitempty"`     // globs of project paths; without a "/" they match base names
+	Functions []string `yaml:"functions,omitempty"` // globs of function names, methods as Type.Method
+}
+
+// ComplexityIgnorePath returns the location of the project's ignore list
+func ComplexityIgnorePath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "perf-ignore.yaml")
+}
+
+// LoadComplexityIgnore reads the project's ignore list, which is empty when
+// the file does not exist
+func LoadComplexityIgnore(projectRoot string) (*ComplexityIgnore, error) {
+	ignore := &ComplexityIgnore{}
+
+	data, err := os.ReadFile(ComplexityIgnorePath(projectRoot))
+	if os.IsNotExist(err) {
+		return ignore, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, ignore); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ComplexityIgnorePath(projectRoot), err)
+	}
+	return ignore, nil
+}
+
+// IgnoresFile reports whether a project-relative path is ignored
+func (ci *ComplexityIgnore) IgnoresFile(path string) bool {
+	path = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./")
+	for _, pattern := range ci.Files {
+		target := path
+		if !strings.Contains(pattern, "/") {
+			target = filepath.Base(path)
+		}
+		if analysis.MatchGlob(pattern, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// IgnoresFunction reports whether a function is ignored, by its name or its
+// qualified Type.Method name, or by the marker in its doc comment
+func (ci *ComplexityIgnore) IgnoresFunction(fn *ast.FuncDecl) bool {
+	if fn.Doc != nil {
+		for _, comment := range fn.Doc.List {
+			if strings.HasPrefix(strings.ReplaceAll(comment.Text, "// ", "//"), IgnoreComplexityMarker) {
+				return true
+			}
+		}
+	}
+
+	names := []string{fn.Name.Name}
+	if receiver := receiverTypeName(fn); receiver != "" {
+		names = append(names, receiver+"."+fn.Name.Name)
+	}
+	for _, pattern := range ci.Functions {
+		for _, name := range names {
+			if analysis.MatchGlob(pattern, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// receiverTypeName returns the type a method is declared on, or "" for a
+// function
+func receiverTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
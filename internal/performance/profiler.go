@@ -5,9 +5,11 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"ultimate-sdd-framework/internal/analysis"
@@ -15,7 +17,10 @@ import (
 
 // PerformanceProfiler analyzes code performance characteristics
 type PerformanceProfiler struct {
-	analyzer *analysis.CodeAnalyzer
+	analyzer        *analysis.CodeAnalyzer
+	projectRoot     string
+	includeReceiver bool
+	pathFilter      *analysis.PathFilter
 }
 
 // PerformanceReport contains comprehensive performance analysis
@@ -26,6 +31,7 @@ type PerformanceReport struct {
 	ComplexityAnalysis ComplexityMetrics   `json:"complexity_analysis"`
 	MemoryAnalysis  MemoryMetrics        `json:"memory_analysis"`
 	RuntimeAnalysis RuntimeMetrics       `json:"runtime_analysis"`
+	Duplication     float64              `json:"duplication"` // percent of statement lines in repeated blocks
 	Recommendations []string             `json:"recommendations"`
 }
 
@@ -52,13 +58,31 @@ type Optimization struct {
 
 // ComplexityMetrics contains code complexity analysis
 type ComplexityMetrics struct {
-	CyclomaticComplexity float64           `json:"cyclomatic_complexity"`
-	CognitiveComplexity  float64           `json:"cognitive_complexity"`
-	NestingDepth         float64           `json:"nesting_depth"`
-	FunctionLength       float64           `json:"function_length"`
-	ComplexFunctions     []FunctionMetrics `json:"complex_functions"`
+	CyclomaticComplexity float64             `json:"cyclomatic_complexity"`
+	CognitiveComplexity  float64             `json:"cognitive_complexity"`
+	NestingDepth         float64             `json:"nesting_depth"`
+	FunctionLength       float64             `json:"function_length"`
+	MaintainabilityIndex float64             `json:"maintainability_index"`
+	ComplexFunctions     []FunctionMetrics   `json:"complex_functions"`
+	Packages             []PackageComplexity `json:"packages"` // most complex first
+	Functions            []FunctionMetrics   `json:"-"`        // every function analyzed
 }
 
+// PackageComplexity aggregates the functions of one directory
+type PackageComplexity struct {
+	Package                string  `json:"package"`
+	Functions              int     `json:"functions"`
+	ComplexFunctions       int     `json:"complex_functions"`
+	AverageComplexity      float64 `json:"average_complexity"`
+	MaxComplexity          int     `json:"max_complexity"`
+	MaxFunction            string  `json:"max_function"`
+	AverageLines           float64 `json:"average_lines"`
+	AverageMaintainability float64 `json:"average_maintainability"`
+}
+
+// LowMaintainabilityThreshold is the MI below which a function is hard to maintain
+const LowMaintainabilityThreshold = 65.0
+
 // FunctionMetrics contains metrics for individual functions
 type FunctionMetrics struct {
 	Name              string  `json:"name"`
@@ -69,6 +93,7 @@ type FunctionMetrics struct {
 	NestedDepth       int     `json:"nested_depth"`
 	CognitiveLoad     int     `json:"cognitive_load"`
 	Performance       float64 `json:"performance_score"`
+	Maintainability   float64 `json:"maintainability_index"`
 }
 
 // MemoryMetrics contains memory usage analysis
@@ -175,10 +200,22 @@ type ParallelizationOpp struct {
 // NewPerformanceProfiler creates a new performance profiler
 func NewPerformanceProfiler(projectRoot string) *PerformanceProfiler {
 	return &PerformanceProfiler{
-		analyzer: analysis.NewCodeAnalyzer(projectRoot),
+		analyzer:    analysis.NewCodeAnalyzer(projectRoot),
+		projectRoot: projectRoot,
 	}
 }
 
+// SetIncludeReceiver controls whether a method's receiver counts as a parameter
+func (pp *PerformanceProfiler) SetIncludeReceiver(include bool) {
+	pp.includeReceiver = include
+}
+
+// SetPathFilter limits profiling to the files the filter selects
+func (pp *PerformanceProfiler) SetPathFilter(filter *analysis.PathFilter) {
+	pp.pathFilter = filter
+	pp.analyzer.Filter = filter
+}
+
 // AnalyzeProject performs comprehensive performance analysis
 func (pp *PerformanceProfiler) AnalyzeProject() (*PerformanceReport, error) {
 	// Create performance report
@@ -196,6 +233,11 @@ func (pp *PerformanceProfiler) AnalyzeProject() (*PerformanceReport, error) {
 	}
 	perfReport.ComplexityAnalysis = *complexityMetrics
 
+	// Measure duplicated code
+	if perfReport.Duplication, err = MeasureDuplication(pp.projectRoot, pp.pathFilter); err != nil {
+		return nil, fmt.Errorf("duplication analysis failed: %w", err)
+	}
+
 	// Analyze memory patterns
 	memoryMetrics, err := pp.analyzeMemoryPatterns()
 	if err != nil {
@@ -235,8 +277,18 @@ func (pp *PerformanceProfiler) analyzeComplexity() (*ComplexityMetrics, error) {
 		ComplexFunctions:     []FunctionMetrics{},
 	}
 
+	ignore, err := LoadComplexityIgnore(pp.projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	scoring, err := LoadScoringWeights(pp.projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
 	// Walk through Go files
-	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+	var functions []FunctionMetrics
+	err = filepath.Walk(pp.projectRoot, pp.pathFilter.Filtered(pp.projectRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -244,9 +296,14 @@ func (pp *PerformanceProfiler) analyzeComplexity() (*ComplexityMetrics, error) {
 		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
 			return nil
 		}
+		if rel, err := filepath.Rel(pp.projectRoot, path); err == nil && ignore.IgnoresFile(rel) {
+			return nil
+		}
 
-		return pp.analyzeGoFileComplexity(path, metrics)
-	})
+		analyzed, err := pp.analyzeGoFileComplexity(path, metrics, ignore, scoring)
+		functions = append(functions, analyzed...)
+		return err
+	}))
 
 	if err != nil {
 		return nil, err
@@ -268,28 +325,45 @@ func (pp *PerformanceProfiler) analyzeComplexity() (*ComplexityMetrics, error) {
 		metrics.FunctionLength = float64(totalLines) / float64(len(metrics.ComplexFunctions))
 		metrics.NestingDepth = float64(totalNesting) / float64(len(metrics.ComplexFunctions))
 	}
+	// The project's maintainability is that of all its functions, not just
+	// the complex ones
+	if len(functions) > 0 {
+		totalMaintainability := 0.0
+		for _, fn := range functions {
+			totalMaintainability += fn.Maintainability
+		}
+		metrics.MaintainabilityIndex = totalMaintainability / float64(len(functions))
+	}
+	metrics.Functions = functions
+	metrics.Packages = packageComplexity(functions)
 
 	return metrics, nil
 }
 
-// analyzeGoFileComplexity analyzes a single Go file for complexity
-func (pp *PerformanceProfiler) analyzeGoFileComplexity(filePath string, complexityMetrics *ComplexityMetrics) error {
+// analyzeGoFileComplexity analyzes a single Go file for complexity,
+// returning the metrics of every function it does not ignore
+func (pp *PerformanceProfiler) analyzeGoFileComplexity(filePath string, complexityMetrics *ComplexityMetrics, ignore *ComplexityIgnore, scoring *ScoringWeights) ([]FunctionMetrics, error) {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
 	if err != nil {
-		return err // Skip files that don't parse
+		return nil, err // Skip files that don't parse
 	}
 
+	var functions []FunctionMetrics
 	ast.Inspect(file, func(n ast.Node) bool {
 		switch fn := n.(type) {
 		case *ast.FuncDecl:
-			metrics := pp.calculateFunctionMetrics(fn, fset, filePath)
-			if metrics.Complexity > 5 || metrics.Lines > 50 || metrics.NestedDepth > 3 {
+			if ignore.IgnoresFunction(fn) {
+				return true
+			}
+			metrics := pp.calculateFunctionMetrics(fn, fset, filePath, scoring)
+			functions = append(functions, metrics)
+			if isComplexFunction(metrics) {
 				complexityMetrics.ComplexFunctions = append(
 					complexityMetrics.ComplexFunctions, metrics)
 			}
@@ -297,16 +371,87 @@ func (pp *PerformanceProfiler) analyzeGoFileComplexity(filePath string, complexi
 		return true
 	})
 
-	return nil
+	return functions, nil
+}
+
+// isComplexFunction reports whether a function should be flagged as complex
+func isComplexFunction(metrics FunctionMetrics) bool {
+	return metrics.Complexity > 5 || metrics.Lines > 50 || metrics.NestedDepth > 3 || metrics.Parameters > 7
+}
+
+// packageComplexity aggregates function metrics by directory, most complex
+// package first
+func packageComplexity(functions []FunctionMetrics) []PackageComplexity {
+	byDir := make(map[string]*PackageComplexity)
+	var order []string
+	for _, fn := range functions {
+		dir := filepath.ToSlash(filepath.Dir(fn.File))
+		pkg, ok := byDir[dir]
+		if !ok {
+			pkg = &PackageComplexity{Package: dir}
+			byDir[dir] = pkg
+			order = append(order, dir)
+		}
+
+		pkg.Functions++
+		if isComplexFunction(fn) {
+			pkg.ComplexFunctions++
+		}
+		pkg.AverageComplexity += float64(fn.Complexity)
+		pkg.AverageLines += float64(fn.Lines)
+		pkg.AverageMaintainability += fn.Maintainability
+		if fn.Complexity > pkg.MaxComplexity {
+			pkg.MaxComplexity = fn.Complexity
+			pkg.MaxFunction = fn.Name
+		}
+	}
+
+	packages := make([]PackageComplexity, 0, len(order))
+	for _, dir := range order {
+		pkg := byDir[dir]
+		pkg.AverageComplexity /= float64(pkg.Functions)
+		pkg.AverageLines /= float64(pkg.Functions)
+		pkg.AverageMaintainability /= float64(pkg.Functions)
+		packages = append(packages, *pkg)
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		return packages[i].AverageComplexity > packages[j].AverageComplexity
+	})
+	return packages
+}
+
+// FileFunctionMetrics returns the metrics of every function in a Go file
+func (pp *PerformanceProfiler) FileFunctionMetrics(filePath string) ([]FunctionMetrics, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	scoring, err := LoadScoringWeights(pp.projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var functions []FunctionMetrics
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			functions = append(functions, pp.calculateFunctionMetrics(fn, fset, filePath, scoring))
+		}
+	}
+	return functions, nil
 }
 
 // calculateFunctionMetrics calculates metrics for a function
-func (pp *PerformanceProfiler) calculateFunctionMetrics(fn *ast.FuncDecl, fset *token.FileSet, filePath string) FunctionMetrics {
+func (pp *PerformanceProfiler) calculateFunctionMetrics(fn *ast.FuncDecl, fset *token.FileSet, filePath string, scoring *ScoringWeights) FunctionMetrics {
 	metrics := FunctionMetrics{
 		Name:   fn.Name.Name,
 		File:   filePath,
 		Lines:  pp.calculateFunctionLines(fn, fset),
-		Parameters: len(fn.Type.Params.List),
+		Parameters: countFields(fn.Type.Params),
+	}
+	if pp.includeReceiver {
+		metrics.Parameters += countFields(fn.Recv)
 	}
 
 	// Calculate complexity
@@ -347,24 +492,112 @@ func (pp *PerformanceProfiler) calculateFunctionMetrics(fn *ast.FuncDecl, fset *
 	metrics.NestedDepth = maxNesting
 	metrics.CognitiveLoad = complexity + maxNesting + metrics.Parameters
 
-	// Calculate performance score (simplified)
-	performanceScore := 100.0
-	if complexity > 10 {
-		performanceScore -= 20
+	metrics.Performance = scoring.Score(metrics)
+	metrics.Maintainability = maintainabilityIndex(halsteadVolume(fn), complexity, metrics.Lines)
+
+	return metrics
+}
+
+// maintainabilityIndex computes the classic Maintainability Index
+// (171 - 5.2 ln V - 0.23 CC - 16.2 ln LOC). Above 85 is highly
+// maintainable; below 65 is hard to maintain.
+func maintainabilityIndex(volume float64, complexity, lines int) float64 {
+	if volume < 1 {
+		volume = 1
+	}
+	if lines < 1 {
+		lines = 1
+	}
+
+	mi := 171 - 5.2*math.Log(volume) - 0.23*float64(complexity) - 16.2*math.Log(float64(lines))
+
+	return math.Max(0, mi)
+}
+
+// halsteadVolume approximates Halstead volume (N log2 n) for a function by
+// treating identifiers and literals as operands and operators, calls,
+// selectors, indexing and control keywords as operators
+func halsteadVolume(fn *ast.FuncDecl) float64 {
+	if fn.Body == nil {
+		return 0
 	}
-	if metrics.Lines > 100 {
-		performanceScore -= 15
+
+	operators := make(map[string]int)
+	operands := make(map[string]int)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.Ident:
+			operands[node.Name]++
+		case *ast.BasicLit:
+			operands[node.Value]++
+		case *ast.BinaryExpr:
+			operators[node.Op.String()]++
+		case *ast.UnaryExpr:
+			operators[node.Op.String()]++
+		case *ast.AssignStmt:
+			operators[node.Tok.String()]++
+		case *ast.IncDecStmt:
+			operators[node.Tok.String()]++
+		case *ast.CallExpr:
+			operators["()"]++
+		case *ast.IndexExpr:
+			operators["[]"]++
+		case *ast.SelectorExpr:
+			operators["."]++
+		case *ast.StarExpr:
+			operators["*"]++
+		case *ast.IfStmt:
+			operators["if"]++
+		case *ast.ForStmt:
+			operators["for"]++
+		case *ast.RangeStmt:
+			operators["range"]++
+		case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+			operators["switch"]++
+		case *ast.ReturnStmt:
+			operators["return"]++
+		case *ast.GoStmt:
+			operators["go"]++
+		case *ast.DeferStmt:
+			operators["defer"]++
+		}
+		return true
+	})
+
+	total := 0
+	for _, count := range operators {
+		total += count
 	}
-	if maxNesting > 5 {
-		performanceScore -= 10
+	for _, count := range operands {
+		total += count
 	}
-	if metrics.Parameters > 7 {
-		performanceScore -= 5
+
+	vocabulary := len(operators) + len(operands)
+	if vocabulary < 2 {
+		return float64(total)
 	}
 
-	metrics.Performance = performanceScore
+	return float64(total) * math.Log2(float64(vocabulary))
+}
 
-	return metrics
+// countFields counts individual parameters in a field list. Grouped names
+// such as (a, b int) count once per name; unnamed and variadic parameters
+// count as one each.
+func countFields(fields *ast.FieldList) int {
+	if fields == nil {
+		return 0
+	}
+
+	count := 0
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			count++
+		} else {
+			count += len(field.Names)
+		}
+	}
+	return count
 }
 
 // calculateFunctionLines calculates the number of lines in a function
@@ -383,13 +616,13 @@ func (pp *PerformanceProfiler) analyzeMemoryPatterns() (*MemoryMetrics, error) {
 	}
 
 	// Analyze Go files for memory patterns
-	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(pp.projectRoot, pp.pathFilter.Filtered(pp.projectRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
 			return err
 		}
 
 		return pp.analyzeFileMemoryPatterns(path, metrics)
-	})
+	}))
 
 	if err != nil {
 		return nil, err
@@ -414,35 +647,17 @@ func (pp *PerformanceProfiler) analyzeFileMemoryPatterns(filePath string, metric
 		return err
 	}
 
-	contentStr := string(content)
-	lines := strings.Split(contentStr, "\n")
-
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, content, 0)
+	if err != nil {
+		return nil // Skip files that don't parse
+	}
 
-		// Check for potential memory leaks
-		if strings.Contains(line, "new(") || strings.Contains(line, "make(") {
-			if strings.Contains(line, "[]") || strings.Contains(line, "map[") {
-				// Large allocations
-				metrics.AllocationPatterns = append(metrics.AllocationPatterns, AllocationPattern{
-					Pattern:    "Large slice/map allocation",
-					Location:   fmt.Sprintf("%s:%d", filePath, i+1),
-					Impact:     "High memory usage",
-					Suggestion: "Consider pre-allocating with known capacity",
-				})
-			}
-		}
+	// Check for allocations repeated on every loop iteration
+	metrics.AllocationPatterns = append(metrics.AllocationPatterns, findLoopAllocations(file, fset, filePath)...)
 
-		// Check for goroutine leaks
-		if strings.Contains(line, "go func") && !strings.Contains(contentStr, "wg.Wait()") {
-			metrics.MemoryLeaks = append(metrics.MemoryLeaks, LeakDetection{
-				Type:        "goroutine",
-				Location:    fmt.Sprintf("%s:%d", filePath, i+1),
-				Description: "Potential goroutine leak without wait group",
-				Severity:    "medium",
-			})
-		}
-	}
+	// Check for goroutine leaks
+	metrics.MemoryLeaks = append(metrics.MemoryLeaks, findGoroutineLeaks(file, fset, filePath)...)
 
 	return nil
 }
@@ -484,7 +699,7 @@ func (pp *PerformanceProfiler) analyzeRuntimePatterns() (*RuntimeMetrics, error)
 func (pp *PerformanceProfiler) analyzeConcurrencyIssues() ([]ConcurrencyIssue, error) {
 	issues := []ConcurrencyIssue{}
 
-	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(pp.projectRoot, pp.pathFilter.Filtered(pp.projectRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil || !strings.HasSuffix(path, ".go") {
 			return err
 		}
@@ -511,7 +726,7 @@ func (pp *PerformanceProfiler) analyzeConcurrencyIssues() ([]ConcurrencyIssue, e
 		}
 
 		return nil
-	})
+	}))
 
 	return issues, err
 }
@@ -520,7 +735,7 @@ func (pp *PerformanceProfiler) analyzeConcurrencyIssues() ([]ConcurrencyIssue, e
 func (pp *PerformanceProfiler) analyzeIOPatterns() ([]IOPattern, error) {
 	patterns := []IOPattern{}
 
-	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(pp.projectRoot, pp.pathFilter.Filtered(pp.projectRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil || !strings.HasSuffix(path, ".go") {
 			return err
 		}
@@ -544,7 +759,7 @@ func (pp *PerformanceProfiler) analyzeIOPatterns() ([]IOPattern, error) {
 		}
 
 		return nil
-	})
+	}))
 
 	return patterns, err
 }
@@ -557,7 +772,7 @@ func (pp *PerformanceProfiler) analyzeCPUUsage() CPUAnalysis {
 
 	// Analyze algorithmic complexity
 	complexityIssues := []ComplexityIssue{}
-	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(pp.projectRoot, pp.pathFilter.Filtered(pp.projectRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil || !strings.HasSuffix(path, ".go") {
 			return err
 		}
@@ -581,7 +796,7 @@ func (pp *PerformanceProfiler) analyzeCPUUsage() CPUAnalysis {
 		}
 
 		return nil
-	})
+	}))
 
 	if err == nil {
 		analysis.AlgorithmicComplexity = complexityIssues
@@ -768,7 +983,29 @@ func (report *PerformanceReport) GetPerformanceSummary() string {
 	summary.WriteString(fmt.Sprintf("- **Average Cyclomatic Complexity:** %.1f\n", report.ComplexityAnalysis.CyclomaticComplexity))
 	summary.WriteString(fmt.Sprintf("- **Average Function Length:** %.1f lines\n", report.ComplexityAnalysis.FunctionLength))
 	summary.WriteString(fmt.Sprintf("- **Average Nesting Depth:** %.1f\n", report.ComplexityAnalysis.NestingDepth))
-	summary.WriteString(fmt.Sprintf("- **Complex Functions:** %d\n\n", len(report.ComplexityAnalysis.ComplexFunctions)))
+	summary.WriteString(fmt.Sprintf("- **Average Maintainability Index:** %.1f\n", report.ComplexityAnalysis.MaintainabilityIndex))
+	summary.WriteString(fmt.Sprintf("- **Complex Functions:** %d\n", len(report.ComplexityAnalysis.ComplexFunctions)))
+	summary.WriteString(fmt.Sprintf("- **Duplicated Code:** %.1f%%\n", report.Duplication))
+	if packages := report.ComplexityAnalysis.Packages; len(packages) > 0 {
+		summary.WriteString(fmt.Sprintf("- **Most Complex Package:** %s has avg complexity %.1f across %d functions\n",
+			packages[0].Package, packages[0].AverageComplexity, packages[0].Functions))
+	}
+	summary.WriteString("\n")
+
+	var hardToMaintain []FunctionMetrics
+	for _, fn := range report.ComplexityAnalysis.Functions {
+		if fn.Maintainability < LowMaintainabilityThreshold {
+			hardToMaintain = append(hardToMaintain, fn)
+		}
+	}
+	if len(hardToMaintain) > 0 {
+		summary.WriteString(fmt.Sprintf("### ⚠️ Hard to Maintain (MI < %.0f)\n\n", LowMaintainabilityThreshold))
+		for _, fn := range hardToMaintain {
+			summary.WriteString(fmt.Sprintf("- `%s` (%s) - MI: %.1f, Complexity: %d, Lines: %d\n",
+				fn.Name, fn.File, fn.Maintainability, fn.Complexity, fn.Lines))
+		}
+		summary.WriteString("\n")
+	}
 
 	// Bottlenecks
 	if len(report.Bottlenecks) > 0 {
@@ -0,0 +1,85 @@
+package performance
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ScoreRule deducts Penalty points from a function's performance score when
+// a metric is above Threshold
+type ScoreRule struct {
+	Threshold int     `yaml:"threshold"`
+	Penalty   float64 `yaml:"penalty"` // points deducted; the sign is ignored
+}
+
+// ScoringWeights is the formula of the per-function performance score: 100
+// minus the penalty of every rule whose threshold the function exceeds. It
+// is read from .sdd/perf-scoring.yaml, where omitted fields keep these
+// defaults:
+//
+//	complexity: {threshold: 10, penalty: 20}  # cyclomatic complexity
+//	lines:      {threshold: 100, penalty: 15} # lines of code
+//	nesting:    {threshold: 5, penalty: 10}   # deepest nesting of blocks
+//	parameters: {threshold: 7, penalty: 5}    # parameters, with --include-receiver the receiver too
+type ScoringWeights struct {
+	Complexity ScoreRule `yaml:"complexity"`
+	Lines      ScoreRule `yaml:"lines"`
+	Nesting    ScoreRule `yaml:"nesting"`
+	Parameters ScoreRule `yaml:"parameters"`
+}
+
+// DefaultScoringWeights returns the formula used when a project does not
+// configure one
+func DefaultScoringWeights() *ScoringWeights {
+	return &ScoringWeights{
+		Complexity: ScoreRule{Threshold: 10, Penalty: 20},
+		Lines:      ScoreRule{Threshold: 100, Penalty: 15},
+		Nesting:    ScoreRule{Threshold: 5, Penalty: 10},
+		Parameters: ScoreRule{Threshold: 7, Penalty: 5},
+	}
+}
+
+// ScoringWeightsPath returns the location of the project's scoring formula
+func ScoringWeightsPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "perf-scoring.yaml")
+}
+
+// LoadScoringWeights reads the project's scoring formula over the defaults
+func LoadScoringWeights(projectRoot string) (*ScoringWeights, error) {
+	weights := DefaultScoringWeights()
+
+	data, err := os.ReadFile(ScoringWeightsPath(projectRoot))
+	if os.IsNotExist(err) {
+		return weights, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, weights); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ScoringWeightsPath(projectRoot), err)
+	}
+	return weights, nil
+}
+
+// Score returns the performance score of a function's metrics
+func (sw *ScoringWeights) Score(metrics FunctionMetrics) float64 {
+	score := 100.0
+	for _, check := range []struct {
+		value int
+		rule  ScoreRule
+	}{
+		{metrics.Complexity, sw.Complexity},
+		{metrics.Lines, sw.Lines},
+		{metrics.NestedDepth, sw.Nesting},
+		{metrics.Parameters, sw.Parameters},
+	} {
+		if check.value > check.rule.Threshold {
+			score -= math.Abs(check.rule.Penalty)
+		}
+	}
+	return score
+}
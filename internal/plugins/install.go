@@ -0,0 +1,219 @@
+package plugins
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// pluginNamePattern restricts plugin and command names to safe identifiers
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// InstallRecord remembers where an installed plugin came from
+type InstallRecord struct {
+	Source      string    `json:"source"`
+	Commit      string    `json:"commit,omitempty"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// ProjectPluginsDir returns the directory holding a project's plugins
+func ProjectPluginsDir(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "plugins")
+}
+
+// GlobalPluginsDir returns the directory holding the user's plugins
+func GlobalPluginsDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".viki", "plugins")
+}
+
+// registryPath returns the file recording installed plugins
+func (pm *PluginManager) registryPath() string {
+	return filepath.Join(pm.pluginsDir, "registry.json")
+}
+
+// Registry returns the install records of plugins installed from a source
+func (pm *PluginManager) Registry() (map[string]InstallRecord, error) {
+	registry := make(map[string]InstallRecord)
+
+	data, err := os.ReadFile(pm.registryPath())
+	if os.IsNotExist(err) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin registry: %w", err)
+	}
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin registry: %w", err)
+	}
+	return registry, nil
+}
+
+func (pm *PluginManager) saveRegistry(registry map[string]InstallRecord) error {
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(pm.registryPath(), data, 0644)
+}
+
+// Install clones a plugin repository, from a git URL or a local repository
+// path, verifies its manifest and registers it. It returns the manifest of
+// the installed plugin.
+func (pm *PluginManager) Install(source string) (*PluginManifest, error) {
+	if err := os.MkdirAll(pm.pluginsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create plugins directory: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(pm.pluginsDir, ".install-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	// A source read as an option, such as --upload-pack=..., would make git
+	// run arbitrary commands
+	if strings.HasPrefix(source, "-") {
+		return nil, fmt.Errorf("invalid plugin source %q", source)
+	}
+	clone := exec.Command("git", "clone", "--depth", "1", "--quiet", "--", source, staging)
+	if output, err := clone.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to clone %s: %w\n%s", source, err, strings.TrimSpace(string(output)))
+	}
+
+	manifest, err := pm.loadManifest(filepath.Join(staging, "plugin.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a Viki plugin: no valid plugin.yaml: %w", source, err)
+	}
+	if err := ValidatePlugin(staging, manifest); err != nil {
+		return nil, err
+	}
+
+	target := filepath.Join(pm.pluginsDir, manifest.Name)
+	if _, err := os.Stat(target); err == nil {
+		return nil, fmt.Errorf("plugin %s is already installed (remove it first)", manifest.Name)
+	}
+
+	commit := ""
+	if output, err := exec.Command("git", "-C", staging, "rev-parse", "HEAD").Output(); err == nil {
+		commit = strings.TrimSpace(string(output))
+	}
+
+	// The plugin's git history is not needed to run it
+	os.RemoveAll(filepath.Join(staging, ".git"))
+	if err := os.Rename(staging, target); err != nil {
+		return nil, fmt.Errorf("failed to install plugin: %w", err)
+	}
+
+	registry, err := pm.Registry()
+	if err != nil {
+		return nil, err
+	}
+	registry[manifest.Name] = InstallRecord{Source: source, Commit: commit, InstalledAt: time.Now()}
+	if err := pm.saveRegistry(registry); err != nil {
+		return nil, fmt.Errorf("failed to register plugin: %w", err)
+	}
+
+	pm.plugins[manifest.Name] = &PluginInfo{Manifest: *manifest, Path: target}
+	return manifest, nil
+}
+
+// checkPluginSymbol reports whether the shared object at path exports the
+// Plugin symbol of a Go plugin, without loading it
+func checkPluginSymbol(path string) error {
+	var symbols []string
+	if file, err := elf.Open(path); err == nil {
+		defer file.Close()
+		dynamic, _ := file.DynamicSymbols()
+		static, _ := file.Symbols()
+		for _, sym := range append(dynamic, static...) {
+			symbols = append(symbols, sym.Name)
+		}
+	} else if file, err := macho.Open(path); err == nil {
+		defer file.Close()
+		if file.Symtab != nil {
+			for _, sym := range file.Symtab.Syms {
+				symbols = append(symbols, sym.Name)
+			}
+		}
+	} else {
+		return fmt.Errorf("%s is not a shared object", filepath.Base(path))
+	}
+
+	for _, name := range symbols {
+		if strings.HasSuffix(name, ".Plugin") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s does not export a 'Plugin' symbol", filepath.Base(path))
+}
+
+// ValidatePlugin checks that a plugin in dir provides what its manifest
+// declares: a known type, its entry point and its agent files
+func ValidatePlugin(dir string, manifest *PluginManifest) error {
+	if !pluginNamePattern.MatchString(manifest.Name) {
+		return fmt.Errorf("invalid plugin name %q in plugin.yaml", manifest.Name)
+	}
+	for _, command := range manifest.Commands {
+		if !pluginNamePattern.MatchString(command) {
+			return fmt.Errorf("plugin %s declares invalid command name %q", manifest.Name, command)
+		}
+	}
+
+	entry := filepath.Join(dir, manifest.Entry)
+	if manifest.Type == "go" || manifest.Type == "script" {
+		if manifest.Entry == "" || !strings.HasPrefix(entry, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("plugin %s has no valid entry point", manifest.Name)
+		}
+		if _, err := os.Stat(entry); err != nil {
+			return fmt.Errorf("plugin %s entry point %s not found", manifest.Name, manifest.Entry)
+		}
+	}
+
+	switch manifest.Type {
+	case "go":
+		// Opening the plugin would run its init code before the user has
+		// trusted it, so only its symbol table is inspected
+		if err := checkPluginSymbol(entry); err != nil {
+			return fmt.Errorf("plugin %s: %w", manifest.Name, err)
+		}
+	case "script":
+		if _, err := scriptRunner(manifest.Entry); err != nil {
+			return fmt.Errorf("plugin %s: %w", manifest.Name, err)
+		}
+	case "config":
+		for _, agent := range manifest.Agents {
+			if _, err := os.Stat(filepath.Join(dir, "agents", agent+".md")); err != nil {
+				return fmt.Errorf("plugin %s agent %s not found in agents/", manifest.Name, agent)
+			}
+		}
+	default:
+		return fmt.Errorf("plugin %s has unknown type %q (want go, script or config)", manifest.Name, manifest.Type)
+	}
+
+	return nil
+}
+
+// Remove uninstalls a plugin and drops it from the registry
+func (pm *PluginManager) Remove(name string) error {
+	if err := pm.Uninstall(name); err != nil {
+		return err
+	}
+
+	registry, err := pm.Registry()
+	if err != nil {
+		return err
+	}
+	if _, ok := registry[name]; !ok {
+		return nil
+	}
+	delete(registry, name)
+	return pm.saveRegistry(registry)
+}
@@ -3,6 +3,7 @@ package plugins
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"plugin"
 	"strings"
@@ -52,7 +53,7 @@ type PluginManifest struct {
 	Author      string   `yaml:"author"`
 	Entry       string   `yaml:"entry"`    // Entry point file
 	Type        string   `yaml:"type"`     // "go", "script", "config"
-	Commands    []string `yaml:"commands"` // Commands this plugin adds
+	Commands    []string `yaml:"commands"` // Commands this plugin adds; the command name is passed as the first argument
 	Hooks       []string `yaml:"hooks"`    // Hooks this plugin listens to
 	Agents      []string `yaml:"agents"`   // Custom agents this plugin provides
 }
@@ -97,7 +98,8 @@ func (pm *PluginManager) Discover() error {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		// Skip files and interrupted installs
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
@@ -228,25 +230,34 @@ func (pm *PluginManager) Execute(name string, args []string) error {
 func (pm *PluginManager) executeScript(info *PluginInfo, args []string) error {
 	scriptPath := filepath.Join(info.Path, info.Manifest.Entry)
 
-	// Determine script runner
-	ext := filepath.Ext(info.Manifest.Entry)
-	var runner string
-	switch ext {
+	runner, err := scriptRunner(info.Manifest.Entry)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(runner, append([]string{scriptPath}, args...)...)
+	cmd.Dir = pm.context.WorkDir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(),
+		"VIKI_PLUGIN_DIR="+info.Path,
+		"VIKI_WORK_DIR="+pm.context.WorkDir,
+		"VIKI_SDD_DIR="+pm.context.SDDDir,
+	)
+	return cmd.Run()
+}
+
+// scriptRunner returns the interpreter for a script plugin's entry point
+func scriptRunner(entry string) (string, error) {
+	switch ext := filepath.Ext(entry); ext {
 	case ".sh":
-		runner = "bash"
+		return "bash", nil
 	case ".py":
-		runner = "python3"
+		return "python3", nil
 	case ".js":
-		runner = "node"
+		return "node", nil
 	default:
-		return fmt.Errorf("unsupported script type: %s", ext)
+		return "", fmt.Errorf("unsupported script type: %s", ext)
 	}
-
-	// Execute script
-	cmd := fmt.Sprintf("%s %s %s", runner, scriptPath, strings.Join(args, " "))
-	fmt.Printf("Executing: %s\n", cmd)
-	// In real implementation, use os/exec
-	return nil
 }
 
 // List returns all discovered plugins
@@ -280,16 +291,6 @@ func (pm *PluginManager) GetAgents() map[string]string {
 	return agents
 }
 
-// Install installs a plugin from a URL or local path
-func (pm *PluginManager) Install(source string) error {
-	// Placeholder for plugin installation
-	// Would handle:
-	// - GitHub URLs
-	// - Local paths
-	// - ZIP archives
-	return fmt.Errorf("plugin installation not yet implemented")
-}
-
 // Uninstall removes a plugin
 func (pm *PluginManager) Uninstall(name string) error {
 	info, ok := pm.plugins[name]
@@ -0,0 +1,155 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"ultimate-sdd-framework/internal/mcp"
+)
+
+const (
+	// aiReviewRetries is how often a rate-limited file review is retried
+	aiReviewRetries = 3
+	// aiReviewMaxLines bounds the lines of a file sent to the agent
+	aiReviewMaxLines = 1500
+)
+
+// aiReviewPrompt asks the QA agent for findings the reviewer can parse
+const aiReviewPrompt = `Review the file %s for bugs, security problems, performance problems and maintainability issues that simple pattern checks miss. Lines are numbered "N| ".
+
+Answer with only a JSON array, empty when the file is fine:
+[{"line": 12, "severity": "low|medium|high|critical", "category": "security|performance|maintainability|concurrency|style", "message": "what is wrong", "suggestion": "how to fix it"}]`
+
+// aiFinding is an issue reported by the QA agent
+type aiFinding struct {
+	Line       int    `json:"line"`
+	Severity   string `json:"severity"`
+	Category   string `json:"category"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// EnableAIReview has the QA agent review every file as well. Its calls are
+// throttled to the rate limits of the default provider and retried with
+// backoff when the provider still reports its limit exceeded.
+func (cr *CodeReviewer) EnableAIReview() {
+	cr.aiReview = true
+}
+
+// aiReviewFiles adds the QA agent's findings to the reviewed files
+func (cr *CodeReviewer) aiReviewFiles(agentName string, fileReviews []FileReview) {
+	limiter := mcp.NewRateLimiter(cr.agentSvc.ProviderLimits())
+	fmt.Printf("🧠 AI review of %d files, throttled to the provider's rate limits...\n", len(fileReviews))
+
+	var wg sync.WaitGroup
+	for i := range fileReviews {
+		wg.Add(1)
+		go func(fileReview *FileReview) {
+			defer wg.Done()
+
+			issues, err := cr.aiReviewFile(limiter, agentName, fileReview.Path)
+			if err != nil {
+				fmt.Printf("Warning: AI review of %s failed: %v\n", fileReview.Path, err)
+				return
+			}
+			issues = cr.focusIssues(issues)
+			if len(issues) == 0 {
+				return
+			}
+
+			fileReview.Issues = append(fileReview.Issues, issues...)
+			fileReview.Comments = append(fileReview.Comments, cr.generateCommentsFromIssues(issues)...)
+			fileReview.Score = cr.calculateFileScore(fileReview.Issues, fileReview.Comments)
+			fileReview.Status = cr.determineFileStatus(fileReview.Issues)
+		}(&fileReviews[i])
+	}
+	wg.Wait()
+}
+
+// aiReviewFile asks the QA agent to review one file, waiting for the rate
+// limiter before each call
+func (cr *CodeReviewer) aiReviewFile(limiter *mcp.RateLimiter, agentName, filePath string) ([]CodeIssue, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	context := cr.agentSvc.UntrustedContext(filePath, numberLines(string(content)))
+	prompt := fmt.Sprintf(aiReviewPrompt, filePath)
+
+	for attempt := 0; ; attempt++ {
+		release := limiter.Acquire()
+		response, err := cr.agentSvc.GetAgentResponse(agentName, "review", prompt, context, "")
+		release()
+
+		if err == nil {
+			return parseAIFindings(response)
+		}
+		if !mcp.IsRateLimitError(err) || attempt == aiReviewRetries {
+			return nil, err
+		}
+		wait := limiter.Backoff(attempt + 1)
+		fmt.Printf("⏳ Rate limited reviewing %s, retrying in %s\n", filePath, wait)
+		time.Sleep(wait)
+	}
+}
+
+// numberLines prefixes each line with its number so findings cite lines
+// accurately, cutting off very long files
+func numberLines(content string) string {
+	lines := strings.Split(content, "\n")
+	truncated := len(lines) > aiReviewMaxLines
+	if truncated {
+		lines = lines[:aiReviewMaxLines]
+	}
+
+	var numbered strings.Builder
+	for i, line := range lines {
+		numbered.WriteString(fmt.Sprintf("%d| %s\n", i+1, line))
+	}
+	if truncated {
+		numbered.WriteString(fmt.Sprintf("... (truncated after %d lines)\n", aiReviewMaxLines))
+	}
+	return numbered.String()
+}
+
+// parseAIFindings converts the agent's JSON findings to issues
+func parseAIFindings(response string) ([]CodeIssue, error) {
+	// Agents sometimes wrap the JSON in a code fence or prose
+	start, end := strings.Index(response, "["), strings.LastIndex(response, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("the agent's answer contains no findings JSON")
+	}
+
+	var findings []aiFinding
+	if err := json.Unmarshal([]byte(response[start:end+1]), &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse the agent's findings: %w", err)
+	}
+
+	issues := make([]CodeIssue, 0, len(findings))
+	for _, finding := range findings {
+		if finding.Message == "" {
+			continue
+		}
+		severity := strings.ToLower(finding.Severity)
+		if _, ok := severityPoints[severity]; !ok {
+			severity = "medium"
+		}
+		category := strings.ToLower(finding.Category)
+		if category == "" {
+			category = "maintainability"
+		}
+		issues = append(issues, CodeIssue{
+			Type:       "ai-review",
+			Severity:   severity,
+			Message:    finding.Message,
+			Line:       finding.Line,
+			Suggestion: finding.Suggestion,
+			Category:   category,
+		})
+	}
+	return issues, nil
+}
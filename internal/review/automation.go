@@ -2,12 +2,15 @@ package review
 
 import (
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
 	"ultimate-sdd-framework/internal/agents"
 	"ultimate-sdd-framework/internal/analysis"
+	"ultimate-sdd-framework/internal/secrets"
 )
 
 // CodeReview represents an automated code review
@@ -17,6 +20,7 @@ type CodeReview struct {
 	Files      []FileReview              `json:"files"`
 	Summary    ReviewSummary             `json:"summary"`
 	Agent      *agents.Agent            `json:"agent"`
+	Focus      string                    `json:"focus,omitempty"` // the area the review was limited to
 }
 
 // FileReview represents review of a single file
@@ -46,6 +50,8 @@ type CodeIssue struct {
 	Line         int    `json:"line"`
 	Suggestion  string `json:"suggestion"`
 	Category    string `json:"category"`
+	Patch       string `json:"patch,omitempty"`    // unified diff fixing a mechanical issue
+	SafeFix     bool   `json:"safe_fix,omitempty"` // the patch keeps behavior and can be applied unreviewed
 }
 
 // ReviewSummary provides overall review assessment
@@ -53,6 +59,7 @@ type ReviewSummary struct {
 	OverallScore     int               `json:"overall_score"`     // 1-10
 	ApprovalStatus   string            `json:"approval_status"`   // approved, requested_changes, blocked
 	RiskLevel        string            `json:"risk_level"`        // low, medium, high, critical
+	RiskScore        float64           `json:"risk_score"`        // 0-9, weighted severity of the worst issue
 	IssuesByCategory map[string]int    `json:"issues_by_category"`
 	KeyFindings      []string          `json:"key_findings"`
 	Recommendations  []string          `json:"recommendations"`
@@ -62,7 +69,10 @@ type ReviewSummary struct {
 type CodeReviewer struct {
 	agentSvc    *agents.AgentService
 	analyzer    *analysis.CodeAnalyzer
+	config      *ReviewConfig
 	projectRoot string
+	aiReview    bool   // the QA agent reviews each file too
+	focus       string // the one area reviewed, or "" / FocusAll for every issue
 }
 
 // NewCodeReviewer creates a new code reviewer
@@ -74,9 +84,15 @@ func NewCodeReviewer(projectRoot string) (*CodeReviewer, error) {
 
 	analyzer := analysis.NewCodeAnalyzer(projectRoot)
 
+	config, err := LoadReviewConfig(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
 	return &CodeReviewer{
 		agentSvc:    agentSvc,
 		analyzer:    analyzer,
+		config:      config,
 		projectRoot: projectRoot,
 	}, nil
 }
@@ -95,6 +111,9 @@ func (cr *CodeReviewer) ReviewPullRequest(prNumber int, changedFiles []string) (
 		return nil, fmt.Errorf("failed to get QA agent: %w", err)
 	}
 	review.Agent = qaAgent
+	if cr.focused() {
+		review.Focus = cr.focus
+	}
 
 	// Analyze each changed file
 	for _, filePath := range changedFiles {
@@ -107,6 +126,14 @@ func (cr *CodeReviewer) ReviewPullRequest(prNumber int, changedFiles []string) (
 		review.Files = append(review.Files, *fileReview)
 	}
 
+	if cr.aiReview && len(review.Files) > 0 {
+		agentName, err := cr.agentSvc.AgentNameForPhase("review")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get QA agent: %w", err)
+		}
+		cr.aiReviewFiles(agentName, review.Files)
+	}
+
 	// Generate overall summary
 	review.Summary = cr.generateSummary(review.Files)
 
@@ -121,6 +148,12 @@ func (cr *CodeReviewer) reviewFile(filePath string) (*FileReview, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
+	return cr.ReviewContent(filePath, string(content))
+}
+
+// ReviewContent runs the per-file analysis on in-memory content, such as an
+// editor's unsaved buffer. path is used only to pick the language rules.
+func (cr *CodeReviewer) ReviewContent(filePath, content string) (*FileReview, error) {
 	fileReview := &FileReview{
 		Path:     filePath,
 		Status:   "approved", // Default to approved
@@ -130,17 +163,18 @@ func (cr *CodeReviewer) reviewFile(filePath string) (*FileReview, error) {
 		Issues:   []CodeIssue{},
 	}
 
-	// Perform automated analysis
-	issues := cr.analyzeFileIssues(filePath, string(content))
+	// Perform automated analysis, keeping the issues in focus
+	issues := cr.focusIssues(cr.analyzeFileIssues(filePath, content))
 	fileReview.Issues = issues
 
 	// Generate comments from issues
 	comments := cr.generateCommentsFromIssues(issues)
 	fileReview.Comments = comments
 
-	// Generate suggestions
-	suggestions := cr.generateSuggestions(filePath, string(content))
-	fileReview.Suggestions = suggestions
+	// Generate suggestions; they are general upkeep, outside any focus area
+	if !cr.focused() {
+		fileReview.Suggestions = cr.generateSuggestions(filePath, content)
+	}
 
 	// Calculate file score
 	fileReview.Score = cr.calculateFileScore(issues, comments)
@@ -163,13 +197,23 @@ func (cr *CodeReviewer) analyzeFileIssues(filePath, content string) []CodeIssue
 	} else if strings.HasSuffix(filePath, ".ts") || strings.HasSuffix(filePath, ".tsx") ||
 	          strings.HasSuffix(filePath, ".js") || strings.HasSuffix(filePath, ".jsx") {
 		issues = append(issues, cr.analyzeJSIssues(filePath, lines)...)
+	} else if strings.HasSuffix(filePath, ".py") {
+		issues = append(issues, cr.analyzePythonIssues(filePath, lines)...)
+	} else if strings.HasSuffix(filePath, ".rs") {
+		issues = append(issues, cr.analyzeRustIssues(filePath, lines)...)
+	} else if isDockerfile(filePath) {
+		issues = append(issues, cr.analyzeDockerfileIssues(lines)...)
+	} else if isConfigFile(filePath) {
+		issues = append(issues, cr.analyzeConfigIssues(lines)...)
 	}
 
 	// Check for security issues
 	issues = append(issues, cr.analyzeSecurityIssues(content)...)
 
-	// Check for performance issues
-	issues = append(issues, cr.analyzePerformanceIssues(content)...)
+	// Check for performance issues; the patterns only make sense for code
+	if !isDockerfile(filePath) && !isConfigFile(filePath) {
+		issues = append(issues, cr.analyzePerformanceIssues(content)...)
+	}
 
 	return issues
 }
@@ -181,14 +225,17 @@ func (cr *CodeReviewer) analyzeGoIssues(filePath string, lines []string) []CodeI
 	for i, line := range lines {
 		// Check for panic usage
 		if strings.Contains(line, "panic(") {
-			issues = append(issues, CodeIssue{
+			issue := CodeIssue{
 				Type:       "error-handling",
 				Severity:   "medium",
 				Message:    "Use of panic() detected - consider proper error handling",
 				Line:        i + 1,
 				Suggestion: "Return errors instead of panicking",
 				Category:   "maintainability",
-			})
+			}
+			// A stub to complete by hand, never applied by --fix
+			issue.Patch, _ = panicFix(filePath, lines, i+1)
+			issues = append(issues, issue)
 		}
 
 		// Check for TODO comments
@@ -202,17 +249,31 @@ func (cr *CodeReviewer) analyzeGoIssues(filePath string, lines []string) []CodeI
 				Category:   "maintainability",
 			})
 		}
+	}
 
-		// Check for long lines
-		if len(line) > 120 {
-			issues = append(issues, CodeIssue{
+	issues = append(issues, cr.analyzeLineLength(filePath, "go", lines)...)
+
+	return issues
+}
+
+// analyzeLineLength flags lines longer than the configured limit for the
+// language; a trailing comment that makes a line too long is moved above it
+func (cr *CodeReviewer) analyzeLineLength(filePath, language string, lines []string) []CodeIssue {
+	issues := []CodeIssue{}
+	limit := cr.config.MaxLineLength(language)
+
+	for i, line := range lines {
+		if len(line) > limit {
+			issue := CodeIssue{
 				Type:       "style",
 				Severity:   "low",
-				Message:    fmt.Sprintf("Line too long (%d characters)", len(line)),
-				Line:        i + 1,
-				Suggestion: "Break long lines for better readability",
+				Message:    "Line too long",
+				Line:       i + 1,
+				Suggestion: fmt.Sprintf("Break lines longer than %d characters for better readability", limit),
 				Category:   "style",
-			})
+			}
+			issue.Patch, issue.SafeFix = longLineFix(filePath, language, lines, i+1, limit)
+			issues = append(issues, issue)
 		}
 	}
 
@@ -225,15 +286,17 @@ func (cr *CodeReviewer) analyzeJSIssues(filePath string, lines []string) []CodeI
 
 	for i, line := range lines {
 		// Check for console.log in production code
-		if strings.Contains(line, "console.log") && !strings.Contains(filePath, "test") {
-			issues = append(issues, CodeIssue{
+		if strings.Contains(line, "console.log") && !isTestFile(filePath) {
+			issue := CodeIssue{
 				Type:       "logging",
 				Severity:   "low",
 				Message:    "console.log found in production code",
 				Line:        i + 1,
 				Suggestion: "Use proper logging library instead",
 				Category:   "maintainability",
-			})
+			}
+			issue.Patch, issue.SafeFix = consoleLogFix(filePath, lines, i+1)
+			issues = append(issues, issue)
 		}
 
 		// Check for any type usage in TypeScript
@@ -249,6 +312,118 @@ func (cr *CodeReviewer) analyzeJSIssues(filePath string, lines []string) []CodeI
 		}
 	}
 
+	language := "javascript"
+	if strings.HasSuffix(filePath, ".ts") || strings.HasSuffix(filePath, ".tsx") {
+		language = "typescript"
+	}
+	issues = append(issues, cr.analyzeLineLength(filePath, language, lines)...)
+
+	return issues
+}
+
+var (
+	pythonBareExcept     = regexp.MustCompile(`^\s*except\s*:`)
+	pythonMutableDefault = regexp.MustCompile(`^\s*(?:async\s+)?def\s+\w+\s*\(.*=\s*(?:\[|\{|list\(\)|dict\(\)|set\(\))`)
+	pythonPrint          = regexp.MustCompile(`^\s*print\s*\(`)
+	rustUnwrap           = regexp.MustCompile(`\.(unwrap|expect)\(`)
+	rustUnsafe           = regexp.MustCompile(`\bunsafe\s*(?:fn\b|impl\b|\{)`)
+	testFilePath         = regexp.MustCompile(`_test\.|(?:^|/)test_|(?:^|/)tests?/`)
+)
+
+// isTestFile reports whether a path names test code: foo_test.go,
+// test_foo.py or a file under a test/ or tests/ directory
+func isTestFile(filePath string) bool {
+	return testFilePath.MatchString(filepath.ToSlash(filePath))
+}
+
+// analyzePythonIssues checks for Python specific issues
+func (cr *CodeReviewer) analyzePythonIssues(filePath string, lines []string) []CodeIssue {
+	issues := []CodeIssue{}
+	isTest := isTestFile(filePath)
+
+	for i, line := range lines {
+		// Check for bare except clauses
+		if pythonBareExcept.MatchString(line) {
+			issues = append(issues, CodeIssue{
+				Type:       "error-handling",
+				Severity:   "medium",
+				Message:    "Bare except: catches every exception, including KeyboardInterrupt and SystemExit",
+				Line:       i + 1,
+				Suggestion: "Catch specific exceptions, or use 'except Exception:' at the very least",
+				Category:   "maintainability",
+			})
+		}
+
+		// Check for mutable default arguments
+		if pythonMutableDefault.MatchString(line) {
+			issues = append(issues, CodeIssue{
+				Type:       "correctness",
+				Severity:   "medium",
+				Message:    "Mutable default argument is shared between calls",
+				Line:       i + 1,
+				Suggestion: "Default to None and create the list/dict inside the function",
+				Category:   "maintainability",
+			})
+		}
+
+		// Check for print statements in production code
+		if !isTest && pythonPrint.MatchString(line) {
+			issues = append(issues, CodeIssue{
+				Type:       "logging",
+				Severity:   "low",
+				Message:    "print() found in production code",
+				Line:       i + 1,
+				Suggestion: "Use the logging module instead",
+				Category:   "maintainability",
+			})
+		}
+	}
+
+	issues = append(issues, cr.analyzeLineLength(filePath, "python", lines)...)
+
+	return issues
+}
+
+// analyzeRustIssues checks for Rust specific issues
+func (cr *CodeReviewer) analyzeRustIssues(filePath string, lines []string) []CodeIssue {
+	issues := []CodeIssue{}
+	isTest := isTestFile(filePath)
+
+	for i, line := range lines {
+		// Everything after a #[cfg(test)] module is test code
+		if strings.Contains(line, "#[cfg(test)]") {
+			isTest = true
+		}
+
+		// Check for unwrap()/expect() in production code
+		if !isTest {
+			if match := rustUnwrap.FindStringSubmatch(line); match != nil {
+				issues = append(issues, CodeIssue{
+					Type:       "error-handling",
+					Severity:   "medium",
+					Message:    fmt.Sprintf("Use of %s() panics on failure", match[1]),
+					Line:       i + 1,
+					Suggestion: "Propagate the error with ? or handle the None/Err case explicitly",
+					Category:   "maintainability",
+				})
+			}
+		}
+
+		// Check for unsafe code
+		if rustUnsafe.MatchString(line) {
+			issues = append(issues, CodeIssue{
+				Type:       "unsafe",
+				Severity:   "high",
+				Message:    "unsafe code bypasses the borrow checker's guarantees",
+				Line:       i + 1,
+				Suggestion: "Document the invariants with a // SAFETY: comment or use a safe abstraction",
+				Category:   "security",
+			})
+		}
+	}
+
+	issues = append(issues, cr.analyzeLineLength(filePath, "rust", lines)...)
+
 	return issues
 }
 
@@ -257,19 +432,13 @@ func (cr *CodeReviewer) analyzeSecurityIssues(content string) []CodeIssue {
 	issues := []CodeIssue{}
 
 	// Check for hardcoded secrets
-	secretPatterns := []string{
-		`password\s*=\s*["'][^"']*["']`,
-		`secret\s*=\s*["'][^"']*["']`,
-		`token\s*=\s*["'][^"']*["']`,
-		`key\s*=\s*["'][^"']*["']`,
-	}
-
-	for _, pattern := range secretPatterns {
+	for _, pattern := range secrets.SecretPatterns {
 		re := regexp.MustCompile("(?i)" + pattern)
 		if re.MatchString(content) {
+			// A leaked credential is exploitable as soon as the code is shared
 			issues = append(issues, CodeIssue{
 				Type:       "security",
-				Severity:   "high",
+				Severity:   "critical",
 				Message:    "Potential hardcoded secret detected",
 				Suggestion: "Use environment variables or secure credential storage",
 				Category:   "security",
@@ -381,24 +550,58 @@ func (cr *CodeReviewer) generateSuggestions(filePath, content string) []string {
 	return suggestions
 }
 
+// categoryWeights scale an issue's severity by how much damage its category
+// can do; unlisted categories weigh 1
+var categoryWeights = map[string]float64{
+	"security":        3,
+	"concurrency":     3,
+	"performance":     1.5,
+	"maintainability": 1,
+	"style":           0.5,
+}
+
+// severityPoints is the base penalty for each issue severity
+var severityPoints = map[string]float64{
+	"critical": 3,
+	"high":     2,
+	"medium":   1,
+	"low":      0,
+}
+
+// issueRisk returns the category-weighted severity of an issue
+func issueRisk(issue CodeIssue) float64 {
+	weight, ok := categoryWeights[issue.Category]
+	if !ok {
+		weight = 1
+	}
+	return severityPoints[issue.Severity] * weight
+}
+
+// riskLevelFor maps a weighted risk score to a risk level
+func riskLevelFor(risk float64) string {
+	switch {
+	case risk >= 9:
+		return "critical"
+	case risk >= 4:
+		return "high"
+	case risk >= 2:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
 // calculateFileScore computes a quality score for the file
 func (cr *CodeReviewer) calculateFileScore(issues []CodeIssue, comments []ReviewComment) int {
-	score := 10 // Start with perfect score
+	penalty := 0.0
 
-	// Deduct points for issues
+	// Deduct points for issues, weighted by category
 	for _, issue := range issues {
-		switch issue.Severity {
-		case "critical":
-			score -= 3
-		case "high":
-			score -= 2
-		case "medium":
-			score -= 1
-		case "low":
-			score -= 0
-		}
+		penalty += issueRisk(issue)
 	}
 
+	score := 10 - int(math.Round(penalty)) // Start with perfect score
+
 	// Ensure score stays within bounds
 	if score < 1 {
 		score = 1
@@ -454,29 +657,50 @@ func (cr *CodeReviewer) generateSummary(fileReviews []FileReview) ReviewSummary
 			} else if issue.Severity == "high" {
 				highIssues++
 			}
+
+			if risk := issueRisk(issue); risk > summary.RiskScore {
+				summary.RiskScore = risk
+			}
 		}
 	}
 
-	// Calculate overall score
+	// Calculate overall score; the worst weighted issue caps it so a single
+	// serious flaw is not averaged away by clean files
 	if totalFiles > 0 {
 		summary.OverallScore = totalScore / totalFiles
 	} else {
 		summary.OverallScore = 10
 	}
+	if ceiling := 10 - int(math.Round(summary.RiskScore)); ceiling < summary.OverallScore {
+		summary.OverallScore = ceiling
+	}
+	if summary.OverallScore < 1 {
+		summary.OverallScore = 1
+	}
+
+	// Determine risk level from the worst weighted issue, falling back to the
+	// overall score for codebases with many small problems
+	summary.RiskLevel = riskLevelFor(summary.RiskScore)
+	if summary.RiskLevel == "low" || summary.RiskLevel == "medium" {
+		if summary.OverallScore < 7 {
+			summary.RiskLevel = "high"
+		} else if summary.OverallScore < 8 {
+			summary.RiskLevel = "medium"
+		}
+	}
+	// A critical issue blocks the review, so it is never reported as low or
+	// medium risk whatever its category
+	if criticalIssues > 0 && (summary.RiskLevel == "low" || summary.RiskLevel == "medium") {
+		summary.RiskLevel = "high"
+	}
 
-	// Determine approval status and risk level
+	// Determine approval status
 	if criticalIssues > 0 {
 		summary.ApprovalStatus = "blocked"
-		summary.RiskLevel = "critical"
-	} else if highIssues > 0 || summary.OverallScore < 7 {
+	} else if highIssues > 0 || summary.RiskLevel == "high" || summary.RiskLevel == "critical" {
 		summary.ApprovalStatus = "requested_changes"
-		summary.RiskLevel = "high"
-	} else if summary.OverallScore < 8 {
-		summary.ApprovalStatus = "approved"
-		summary.RiskLevel = "medium"
 	} else {
 		summary.ApprovalStatus = "approved"
-		summary.RiskLevel = "low"
 	}
 
 	// Generate key findings
@@ -526,13 +750,16 @@ func (cr *CodeReviewer) GetReviewReport(review *CodeReview) string {
 	report.WriteString(fmt.Sprintf("**Repository:** %s\n", review.Repository))
 	report.WriteString(fmt.Sprintf("**Branch:** %s\n", review.Branch))
 	report.WriteString(fmt.Sprintf("**Agent:** %s\n", review.Agent.Role))
+	if review.Focus != "" {
+		report.WriteString(fmt.Sprintf("**Focus:** %s (only %s and critical issues are reported and scored)\n", review.Focus, review.Focus))
+	}
 	report.WriteString(fmt.Sprintf("**Files Reviewed:** %d\n\n", len(review.Files)))
 
 	// Summary section
 	report.WriteString("## 📊 Review Summary\n\n")
 	report.WriteString(fmt.Sprintf("**Overall Score:** %d/10\n", review.Summary.OverallScore))
 	report.WriteString(fmt.Sprintf("**Status:** %s\n", review.Summary.ApprovalStatus))
-	report.WriteString(fmt.Sprintf("**Risk Level:** %s\n\n", review.Summary.RiskLevel))
+	report.WriteString(fmt.Sprintf("**Risk Level:** %s (score %.1f/9)\n\n", review.Summary.RiskLevel, review.Summary.RiskScore))
 
 	// Issues by category
 	if len(review.Summary.IssuesByCategory) > 0 {
@@ -569,16 +796,27 @@ func (cr *CodeReviewer) GetReviewReport(review *CodeReview) string {
 		report.WriteString(fmt.Sprintf("**Status:** %s\n", file.Status))
 		report.WriteString(fmt.Sprintf("**Score:** %d/10\n", file.Score))
 
+		// Identical issues are listed once; all of them still count above
 		if len(file.Issues) > 0 {
 			report.WriteString("\n**Issues:**\n")
-			for _, issue := range file.Issues {
-				report.WriteString(fmt.Sprintf("- **%s** (%s): %s\n",
+			for _, issue := range GroupIssues(file.Issues) {
+				report.WriteString(fmt.Sprintf("- **%s** (%s): %s",
 					issue.Type, issue.Severity, issue.Message))
+				if issue.Occurrences > 1 {
+					report.WriteString(fmt.Sprintf(" (%d occurrences)", issue.Occurrences))
+				}
+				if len(issue.Lines) > 0 {
+					report.WriteString(fmt.Sprintf(" — %s", formatLines(issue.Lines)))
+				}
+				report.WriteString("\n")
 				if issue.Suggestion != "" {
 					report.WriteString(fmt.Sprintf("  *Suggestion:* %s\n", issue.Suggestion))
 				}
 			}
 		}
+		if fixes := len(file.SafeFixes()); fixes > 0 {
+			report.WriteString(fmt.Sprintf("\n*%d issue(s) can be fixed automatically; run with --fix to apply them.*\n", fixes))
+		}
 
 		if len(file.Suggestions) > 0 {
 			report.WriteString("\n**Suggestions:**\n")
@@ -589,8 +827,9 @@ func (cr *CodeReviewer) GetReviewReport(review *CodeReview) string {
 
 		if len(file.Comments) > 0 {
 			report.WriteString("\n**Comments:**\n")
-			for _, comment := range file.Comments {
-				report.WriteString(fmt.Sprintf("- Line %d: %s\n", comment.Line, comment.Message))
+			messages, lines := commentLines(file.Comments)
+			for _, message := range messages {
+				report.WriteString(fmt.Sprintf("- %s: %s\n", formatLines(lines[message]), message))
 			}
 		}
 
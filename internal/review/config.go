@@ -0,0 +1,58 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultLineLength is the long-line threshold used when a language has no
+// configured limit
+const DefaultLineLength = 120
+
+// ReviewConfig holds project-specific settings for automated reviews,
+// loaded from .sdd/review.json
+type ReviewConfig struct {
+	// LineLength maps a language (go, javascript, typescript, python, rust)
+	// to its maximum line length
+	LineLength map[string]int `json:"line_length"`
+}
+
+// ReviewConfigPath returns the location of the review configuration
+func ReviewConfigPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".sdd", "review.json")
+}
+
+// LoadReviewConfig reads the review configuration, returning an empty
+// configuration when none exists
+func LoadReviewConfig(projectRoot string) (*ReviewConfig, error) {
+	config := &ReviewConfig{LineLength: make(map[string]int)}
+
+	data, err := os.ReadFile(ReviewConfigPath(projectRoot))
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse review config: %w", err)
+	}
+	if config.LineLength == nil {
+		config.LineLength = make(map[string]int)
+	}
+
+	return config, nil
+}
+
+// MaxLineLength returns the line-length limit for a language
+func (c *ReviewConfig) MaxLineLength(language string) int {
+	if c != nil {
+		if limit, ok := c.LineLength[language]; ok && limit > 0 {
+			return limit
+		}
+	}
+	return DefaultLineLength
+}
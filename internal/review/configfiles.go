@@ -0,0 +1,294 @@
+package review
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// isDockerfile reports whether a path is a Dockerfile, e.g. Dockerfile,
+// Dockerfile.prod, api.dockerfile or Containerfile
+func isDockerfile(filePath string) bool {
+	name := strings.ToLower(filepath.Base(filePath))
+	return name == "dockerfile" || name == "containerfile" ||
+		strings.HasPrefix(name, "dockerfile.") || strings.HasSuffix(name, ".dockerfile")
+}
+
+// isConfigFile reports whether a path is a YAML or JSON configuration file
+func isConfigFile(filePath string) bool {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+var (
+	dockerInstruction = regexp.MustCompile(`^\s*([A-Za-z]+)\s+(.*)$`)
+	// dockerStageName captures the stage an image is built as, so later
+	// FROM lines referring to it are not treated as base images
+	dockerStageName = regexp.MustCompile(`(?i)\s+as\s+(\S+)\s*$`)
+)
+
+// analyzeDockerfileIssues checks a Dockerfile for containers running as
+// root, unpinned base images and a missing HEALTHCHECK
+func (cr *CodeReviewer) analyzeDockerfileIssues(lines []string) []CodeIssue {
+	issues := []CodeIssue{}
+	stages := make(map[string]bool)
+	hasFrom, finalStageUser := false, false
+	hasHealthcheck := false
+
+	for i, line := range lines {
+		match := dockerInstruction.FindStringSubmatch(line)
+		if match == nil || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		args := strings.TrimSpace(match[2])
+
+		switch strings.ToUpper(match[1]) {
+		case "FROM":
+			// Each stage starts as the image's default user again
+			hasFrom, finalStageUser = true, false
+			if stage := dockerStageName.FindStringSubmatch(args); stage != nil {
+				stages[strings.ToLower(stage[1])] = true
+				args = strings.TrimSpace(args[:len(args)-len(stage[0])])
+			}
+			if issue, ok := unpinnedBaseImage(args, stages); ok {
+				issue.Line = i + 1
+				issues = append(issues, issue)
+			}
+		case "USER":
+			finalStageUser = true
+			if user := strings.SplitN(args, ":", 2)[0]; user == "root" || user == "0" {
+				issues = append(issues, CodeIssue{
+					Type:       "container-root",
+					Severity:   "high",
+					Message:    "Container runs as root (USER " + args + ")",
+					Line:       i + 1,
+					Suggestion: "Create an unprivileged user and switch to it with USER before the entrypoint",
+					Category:   "security",
+				})
+			}
+		case "HEALTHCHECK":
+			hasHealthcheck = true
+		}
+	}
+
+	if hasFrom && !finalStageUser {
+		issues = append(issues, CodeIssue{
+			Type:       "container-root",
+			Severity:   "medium",
+			Message:    "No USER instruction in the final stage - the container runs as root by default",
+			Suggestion: "Create an unprivileged user and switch to it with USER before the entrypoint",
+			Category:   "security",
+		})
+	}
+
+	if !hasHealthcheck {
+		issues = append(issues, CodeIssue{
+			Type:       "container-healthcheck",
+			Severity:   "low",
+			Message:    "Missing HEALTHCHECK - the orchestrator can't tell when the container is unhealthy",
+			Suggestion: "Add a HEALTHCHECK that probes the service, e.g. HEALTHCHECK CMD curl -f http://localhost/health || exit 1",
+			Category:   "reliability",
+		})
+	}
+
+	return issues
+}
+
+// unpinnedBaseImage returns an issue when a FROM image uses the latest tag
+// or no tag at all. Earlier stages, scratch and digests are pinned.
+func unpinnedBaseImage(args string, stages map[string]bool) (CodeIssue, bool) {
+	fields := strings.Fields(args)
+	image := ""
+	for _, field := range fields {
+		if !strings.HasPrefix(field, "--") {
+			image = field
+			break
+		}
+	}
+	lower := strings.ToLower(image)
+	if image == "" || lower == "scratch" || stages[lower] || strings.Contains(image, "@") || strings.Contains(image, "$") {
+		return CodeIssue{}, false
+	}
+
+	// A colon after the last slash is a tag; one before it is a registry port
+	tag := ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+
+	switch tag {
+	case "latest":
+		return CodeIssue{
+			Type:       "container-image",
+			Severity:   "medium",
+			Message:    fmt.Sprintf("Base image %s uses the mutable 'latest' tag", image),
+			Suggestion: "Pin the base image to a version tag or digest so builds are reproducible",
+			Category:   "security",
+		}, true
+	case "":
+		return CodeIssue{
+			Type:       "container-image",
+			Severity:   "medium",
+			Message:    fmt.Sprintf("Base image %s has no tag and resolves to 'latest'", image),
+			Suggestion: "Pin the base image to a version tag or digest so builds are reproducible",
+			Category:   "security",
+		}, true
+	}
+	return CodeIssue{}, false
+}
+
+var (
+	// configEntry matches a key/value line of YAML ("key: value") or JSON
+	// ("\"key\": value,")
+	configEntry = regexp.MustCompile(`^\s*(?:-\s+)?["']?([\w.\-]+)["']?\s*:\s*(.*?)\s*,?\s*$`)
+	// configSecretKey matches keys whose values are credentials
+	configSecretKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|api[_\-]?key|access[_\-]?key|private[_\-]?key|credentials?)$`)
+	// configReference matches values taken from the environment or a
+	// template rather than written into the file
+	configReference = regexp.MustCompile(`^(\$\{?|\{\{|<|%|ENC\[|vault:|secretref:|!)`)
+)
+
+// permissiveSetting is a configuration value that opens up more than it
+// should
+type permissiveSetting struct {
+	key        *regexp.Regexp
+	value      *regexp.Regexp
+	severity   string
+	message    string
+	suggestion string
+}
+
+// permissiveSettings are the overly permissive values flagged in YAML and
+// JSON, matched against lower-cased keys and unquoted values
+var permissiveSettings = []permissiveSetting{
+	{
+		key: regexp.MustCompile(`^privileged$`), value: regexp.MustCompile(`^true$`), severity: "high",
+		message:    "Privileged container has full access to the host",
+		suggestion: "Drop privileged mode and grant only the capabilities the container needs",
+	},
+	{
+		key: regexp.MustCompile(`^allowprivilegeescalation$`), value: regexp.MustCompile(`^true$`), severity: "medium",
+		message:    "Privilege escalation is allowed",
+		suggestion: "Set allowPrivilegeEscalation: false",
+	},
+	{
+		key: regexp.MustCompile(`^runasuser$`), value: regexp.MustCompile(`^0$`), severity: "high",
+		message:    "Workload runs as root",
+		suggestion: "Run as an unprivileged user and set runAsNonRoot: true",
+	},
+	{
+		key: regexp.MustCompile(`^runasnonroot$`), value: regexp.MustCompile(`^false$`), severity: "medium",
+		message:    "Workload is allowed to run as root",
+		suggestion: "Set runAsNonRoot: true",
+	},
+	{
+		key: regexp.MustCompile(`^host(network|pid|ipc)$`), value: regexp.MustCompile(`^true$`), severity: "high",
+		message:    "Workload shares the host's namespaces",
+		suggestion: "Remove hostNetwork/hostPID/hostIPC unless the workload must manage the host",
+	},
+	{
+		key: regexp.MustCompile(`(allow[_\-]?origins?|allowedorigins|access-control-allow-origin|cors[_\-]?origins?)$`), value: regexp.MustCompile(`^\[?\s*["']?\*["']?\s*\]?$`), severity: "medium",
+		message:    "CORS allows requests from any origin",
+		suggestion: "List the origins that may call the service",
+	},
+	{
+		key: regexp.MustCompile(`(cidr|cidr_blocks|source_ranges|ingress|allowed_ips|sourceip)`), value: regexp.MustCompile(`0\.0\.0\.0/0|::/0`), severity: "high",
+		message:    "Network rule is open to the whole internet",
+		suggestion: "Restrict the rule to the address ranges that need access",
+	},
+	{
+		key: regexp.MustCompile(`(insecure[_\-]?skip[_\-]?verify|skip[_\-]?tls[_\-]?verify|insecure)$`), value: regexp.MustCompile(`^true$`), severity: "high",
+		message:    "TLS certificate verification is disabled",
+		suggestion: "Verify certificates; trust a private CA instead of skipping verification",
+	},
+	{
+		key: regexp.MustCompile(`(verify[_\-]?ssl|ssl[_\-]?verify|tls[_\-]?verify|verify)$`), value: regexp.MustCompile(`^false$`), severity: "high",
+		message:    "TLS certificate verification is disabled",
+		suggestion: "Verify certificates; trust a private CA instead of skipping verification",
+	},
+	{
+		key: regexp.MustCompile(`^sslmode$`), value: regexp.MustCompile(`^disable$`), severity: "medium",
+		message:    "Database connection is not encrypted",
+		suggestion: "Use sslmode: require or verify-full",
+	},
+	{
+		key: regexp.MustCompile(`^debug$`), value: regexp.MustCompile(`^true$`), severity: "low",
+		message:    "Debug mode is enabled",
+		suggestion: "Disable debug mode outside development; it can leak internals in error pages",
+	},
+	{
+		key: regexp.MustCompile(`(mode|permissions|chmod)$`), value: regexp.MustCompile(`^0?777$`), severity: "medium",
+		message:    "World-writable file permissions",
+		suggestion: "Grant write access only to the owner, e.g. 0644 or 0755",
+	},
+	{
+		key: regexp.MustCompile(`^(action|actions)$`), value: regexp.MustCompile(`^\[?\s*["']?\*["']?\s*\]?$`), severity: "high",
+		message:    "Policy grants every action",
+		suggestion: "Grant only the actions the principal needs",
+	},
+}
+
+// analyzeConfigIssues checks YAML and JSON configuration for hardcoded
+// secrets and overly permissive values
+func (cr *CodeReviewer) analyzeConfigIssues(lines []string) []CodeIssue {
+	issues := []CodeIssue{}
+
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		match := configEntry.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		key := strings.ToLower(match[1])
+		value := strings.Trim(match[2], `"'`)
+
+		if configSecretKey.MatchString(key) && isLiteralSecret(value) {
+			issues = append(issues, CodeIssue{
+				Type:       "hardcoded-secret",
+				Severity:   "critical",
+				Message:    fmt.Sprintf("Hardcoded secret in '%s'", match[1]),
+				Line:       i + 1,
+				Suggestion: "Reference an environment variable or secret store instead of committing the value",
+				Category:   "security",
+			})
+			continue
+		}
+
+		for _, setting := range permissiveSettings {
+			if setting.key.MatchString(key) && setting.value.MatchString(strings.ToLower(value)) {
+				issues = append(issues, CodeIssue{
+					Type:       "permissive-config",
+					Severity:   setting.severity,
+					Message:    fmt.Sprintf("%s (%s: %s)", setting.message, match[1], value),
+					Line:       i + 1,
+					Suggestion: setting.suggestion,
+					Category:   "security",
+				})
+				break
+			}
+		}
+	}
+
+	return issues
+}
+
+// isLiteralSecret reports whether a secret key's value is written into the
+// file rather than empty, a placeholder or a reference
+func isLiteralSecret(value string) bool {
+	lower := strings.ToLower(value)
+	switch lower {
+	case "", "null", "~", "true", "false", "{", "[", "|", ">", "changeme", "change-me", "xxx", "todo", "none", "redacted":
+		return false
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return false
+	}
+	return !configReference.MatchString(value) && !strings.HasPrefix(lower, "your")
+}
@@ -0,0 +1,70 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxListedLines is how many line numbers a grouped issue lists
+const maxListedLines = 10
+
+// GroupedIssue is an issue raised identically on one or more lines of a file
+type GroupedIssue struct {
+	CodeIssue
+	Occurrences int
+	Lines       []int
+}
+
+// GroupIssues collapses issues with the same type, message and suggestion
+// into one entry, in the order each was first raised
+func GroupIssues(issues []CodeIssue) []GroupedIssue {
+	var groups []GroupedIssue
+	index := make(map[string]int)
+
+	for _, issue := range issues {
+		key := issue.Type + "\x00" + issue.Message + "\x00" + issue.Suggestion
+		i, seen := index[key]
+		if !seen {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, GroupedIssue{CodeIssue: issue})
+		}
+		groups[i].Occurrences++
+		if issue.Line > 0 {
+			groups[i].Lines = append(groups[i].Lines, issue.Line)
+		}
+	}
+	return groups
+}
+
+// commentLines groups comments by message, returning the messages in the
+// order first seen and the lines each was made on
+func commentLines(comments []ReviewComment) ([]string, map[string][]int) {
+	var messages []string
+	lines := make(map[string][]int)
+	for _, comment := range comments {
+		if _, seen := lines[comment.Message]; !seen {
+			messages = append(messages, comment.Message)
+		}
+		lines[comment.Message] = append(lines[comment.Message], comment.Line)
+	}
+	return messages, lines
+}
+
+// formatLines lists line numbers after "Line" or "Lines", abbreviating
+// long lists
+func formatLines(lines []int) string {
+	listed := make([]string, 0, min(len(lines), maxListedLines))
+	for _, line := range lines[:min(len(lines), maxListedLines)] {
+		listed = append(listed, fmt.Sprint(line))
+	}
+
+	text := "Line " + strings.Join(listed, ", ")
+	if len(lines) > 1 {
+		text = "Lines " + strings.Join(listed, ", ")
+	}
+	if len(lines) > maxListedLines {
+		text += fmt.Sprintf(" and %d more", len(lines)-maxListedLines)
+	}
+	return text
+}
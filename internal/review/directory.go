@@ -0,0 +1,93 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"ultimate-sdd-framework/internal/analysis"
+)
+
+// reviewableExtensions are the source and configuration files the reviewer
+// has rules for; Dockerfiles are recognized by name
+var reviewableExtensions = []string{".go", ".ts", ".tsx", ".js", ".jsx", ".py", ".rs", ".yaml", ".yml", ".json"}
+
+// unreviewedFiles are generated files with reviewable extensions
+var unreviewedFiles = []string{"package-lock.json", "composer.lock", "pnpm-lock.yaml"}
+
+// SourceFiles lists the reviewable source files under dir, skipping hidden,
+// vendor and node_modules directories and anything the filter rules out.
+// Filter globs are relative to the project root.
+func (cr *CodeReviewer) SourceFiles(dir string, filter *analysis.PathFilter) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, relErr := filepath.Rel(cr.projectRoot, path)
+		if relErr != nil {
+			rel = path
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			if path != dir && filter.SkipsDir(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if isReviewable(path) && filter.Matches(rel) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// ReviewDirectory reviews every source file under dir as one review, with a
+// summary and risk level covering all of them
+func (cr *CodeReviewer) ReviewDirectory(dir string, filter *analysis.PathFilter) (*CodeReview, error) {
+	files, err := cr.SourceFiles(dir, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files under %s: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no reviewable files under %s", dir)
+	}
+
+	review, err := cr.ReviewPullRequest(0, files)
+	if err != nil {
+		return nil, err
+	}
+	review.Repository = dir
+	return review, nil
+}
+
+// isReviewable reports whether the reviewer has rules for a file
+func isReviewable(path string) bool {
+	if slices.Contains(unreviewedFiles, filepath.Base(path)) {
+		return false
+	}
+	if isDockerfile(path) {
+		return true
+	}
+	return slices.Contains(reviewableExtensions, strings.ToLower(filepath.Ext(path)))
+}
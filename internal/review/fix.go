@@ -0,0 +1,249 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// patchContext is how many unchanged lines surround a fix in its patch
+const patchContext = 3
+
+var (
+	hunkHeader       = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+\d+(?:,\d+)? @@`)
+	goPanicStatement = regexp.MustCompile(`^(\s*)panic\((.+)\)\s*$`)
+)
+
+// linePatch returns a unified diff that replaces line n (1-based) of a file
+// with replacement, which may be empty to delete the line
+func linePatch(path string, lines []string, n int, replacement []string) string {
+	start := max(n-1-patchContext, 0)
+	end := min(n+patchContext, len(lines))
+
+	var patch strings.Builder
+	patch.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", path, path))
+	oldCount := end - start
+	newCount := oldCount - 1 + len(replacement)
+	patch.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", start+1, oldCount, start+1, newCount))
+	for i := start; i < end; i++ {
+		if i == n-1 {
+			patch.WriteString("-" + lines[i] + "\n")
+			for _, line := range replacement {
+				patch.WriteString("+" + line + "\n")
+			}
+			continue
+		}
+		patch.WriteString(" " + lines[i] + "\n")
+	}
+	return patch.String()
+}
+
+// ApplyPatch applies a unified diff to content. The lines a hunk removes
+// must still be there; its context lines are taken as they now are, so fixes
+// to neighbouring lines can be applied one after another, bottom up.
+func ApplyPatch(content, patch string) (string, error) {
+	lines := strings.Split(content, "\n")
+	var result []string
+	pos := 0
+
+	inHunk := false
+	for _, line := range strings.Split(strings.TrimSuffix(patch, "\n"), "\n") {
+		if match := hunkHeader.FindStringSubmatch(line); match != nil {
+			start, _ := strconv.Atoi(match[1])
+			if match[2] != "0" {
+				start--
+			}
+			if start < pos || start > len(lines) {
+				return "", fmt.Errorf("hunk at line %d is out of range", start+1)
+			}
+			result = append(result, lines[pos:start]...)
+			pos = start
+			inHunk = true
+			continue
+		}
+		if !inHunk || line == "" {
+			continue
+		}
+
+		switch line[0] {
+		case ' ':
+			if pos < len(lines) {
+				result = append(result, lines[pos])
+				pos++
+			}
+		case '-':
+			if pos >= len(lines) || lines[pos] != line[1:] {
+				return "", fmt.Errorf("line %d no longer matches the patch", pos+1)
+			}
+			pos++
+		case '+':
+			result = append(result, line[1:])
+		}
+	}
+	if !inHunk {
+		return "", fmt.Errorf("patch has no hunks")
+	}
+
+	return strings.Join(append(result, lines[pos:]...), "\n"), nil
+}
+
+// SafeFixes returns the issues of a file that carry a patch safe to apply
+// without review, in line order
+func (fr *FileReview) SafeFixes() []CodeIssue {
+	var fixes []CodeIssue
+	for _, issue := range fr.Issues {
+		if issue.SafeFix && issue.Patch != "" {
+			fixes = append(fixes, issue)
+		}
+	}
+	sort.SliceStable(fixes, func(i, j int) bool { return fixes[i].Line < fixes[j].Line })
+	return fixes
+}
+
+// ApplyFixes applies the patches of issues found in the file at path,
+// bottom up so earlier lines keep their numbers, and writes the file back
+// once. It returns how many applied; a patch that no longer applies is
+// skipped and reported in the error.
+func ApplyFixes(path string, issues []CodeIssue) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	ordered := append([]CodeIssue(nil), issues...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Line > ordered[j].Line })
+
+	fixed := string(content)
+	applied := 0
+	var failed []string
+	for _, issue := range ordered {
+		patched, err := ApplyPatch(fixed, issue.Patch)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("line %d: %v", issue.Line, err))
+			continue
+		}
+		fixed = patched
+		applied++
+	}
+
+	if applied > 0 {
+		if err := os.WriteFile(path, []byte(fixed), 0644); err != nil {
+			return 0, fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+	if len(failed) > 0 {
+		return applied, fmt.Errorf("%d fix(es) no longer apply (%s); review again", len(failed), strings.Join(failed, "; "))
+	}
+	return applied, nil
+}
+
+// consoleLogFix deletes a line holding nothing but a console.log call
+func consoleLogFix(filePath string, lines []string, n int) (string, bool) {
+	line := strings.TrimSpace(lines[n-1])
+	if !strings.HasPrefix(line, "console.log(") {
+		return "", false
+	}
+	// The call must close at the end of the line, so nothing else goes
+	end := closingParen(line, len("console.log"))
+	if end < 0 || strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line[end+1:]), ";")) != "" {
+		return "", false
+	}
+	return linePatch(filePath, lines, n, nil), true
+}
+
+// longLineFix moves the trailing comment of a long line onto its own line
+// above, when both then fit within limit
+func longLineFix(filePath, language string, lines []string, n, limit int) (string, bool) {
+	marker := "//"
+	if language == "python" {
+		marker = "#"
+	}
+
+	line := lines[n-1]
+	at := commentStart(line, marker)
+	if at < 0 {
+		return "", false
+	}
+	code := strings.TrimRight(line[:at], " \t")
+	if strings.TrimSpace(code) == "" {
+		return "", false
+	}
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	comment := indent + strings.TrimSpace(line[at:])
+	if len(code) > limit || len(comment) > limit {
+		return "", false
+	}
+	return linePatch(filePath, lines, n, []string{comment, code}), true
+}
+
+// panicFix replaces a Go panic statement with an error return stub. It is
+// not safe: the function may not return an error and the stub needs review.
+func panicFix(filePath string, lines []string, n int) (string, bool) {
+	match := goPanicStatement.FindStringSubmatch(lines[n-1])
+	if match == nil || closingParen(strings.TrimSpace(lines[n-1]), len("panic")) != len(strings.TrimSpace(lines[n-1]))-1 {
+		return "", false
+	}
+
+	indent, arg := match[1], strings.TrimSpace(match[2])
+	replacement := fmt.Sprintf("%sreturn fmt.Errorf(\"%%v\", %s)", indent, arg)
+	if arg == "err" {
+		replacement = indent + "return err"
+	}
+	return linePatch(filePath, lines, n, []string{replacement}), true
+}
+
+// closingParen returns the index of the parenthesis closing the one at
+// open, skipping string literals, or -1 when the line does not close it
+func closingParen(line string, open int) int {
+	if open >= len(line) || line[open] != '(' {
+		return -1
+	}
+	depth := 0
+	var quote byte
+	for i := open; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// commentStart returns where a line comment begins outside string literals,
+// or -1
+func commentStart(line, marker string) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case strings.HasPrefix(line[i:], marker):
+			return i
+		}
+	}
+	return -1
+}
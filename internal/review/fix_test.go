@@ -0,0 +1,125 @@
+package review
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	content := "a\nb\nc\nd\ne\n"
+
+	tests := []struct {
+		name    string
+		content string
+		patch   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "replaces a line",
+			content: content,
+			patch:   "--- a/f\n+++ b/f\n@@ -2,3 +2,3 @@\n b\n-c\n+C\n d\n",
+			want:    "a\nb\nC\nd\ne\n",
+		},
+		{
+			name:    "deletes a line",
+			content: content,
+			patch:   "--- a/f\n+++ b/f\n@@ -1,3 +1,2 @@\n a\n-b\n c\n",
+			want:    "a\nc\nd\ne\n",
+		},
+		{
+			name:    "inserts lines",
+			content: content,
+			patch:   "--- a/f\n+++ b/f\n@@ -4,1 +4,3 @@\n-d\n+// d\n+d\n",
+			want:    "a\nb\nc\n// d\nd\ne\n",
+		},
+		{
+			name:    "inserts into an empty file",
+			content: "",
+			patch:   "--- a/f\n+++ b/f\n@@ -0,0 +1,1 @@\n+x\n",
+			want:    "x\n",
+		},
+		{
+			name:    "takes context lines as they now are",
+			content: "a\nB\nc\n",
+			patch:   "--- a/f\n+++ b/f\n@@ -1,3 +1,3 @@\n a\n b\n-c\n+C\n",
+			want:    "a\nB\nC\n",
+		},
+		{
+			name:    "applies several hunks",
+			content: content,
+			patch:   "--- a/f\n+++ b/f\n@@ -1,1 +1,1 @@\n-a\n+A\n@@ -5,1 +5,1 @@\n-e\n+E\n",
+			want:    "A\nb\nc\nd\nE\n",
+		},
+		{
+			name:    "removed line changed",
+			content: content,
+			patch:   "--- a/f\n+++ b/f\n@@ -2,1 +2,1 @@\n-x\n+y\n",
+			wantErr: "line 2 no longer matches",
+		},
+		{
+			name:    "hunk past the end",
+			content: content,
+			patch:   "--- a/f\n+++ b/f\n@@ -20,1 +20,1 @@\n-x\n+y\n",
+			wantErr: "out of range",
+		},
+		{
+			name:    "hunks out of order",
+			content: content,
+			patch:   "--- a/f\n+++ b/f\n@@ -4,1 +4,1 @@\n-d\n+D\n@@ -1,1 +1,1 @@\n-a\n+A\n",
+			wantErr: "out of range",
+		},
+		{
+			name:    "no hunks",
+			content: content,
+			patch:   "--- a/f\n+++ b/f\n",
+			wantErr: "no hunks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyPatch(tt.content, tt.patch)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ApplyPatch() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyPatch() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ApplyPatch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinePatchRoundTrip(t *testing.T) {
+	lines := []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+	content := strings.Join(lines, "\n")
+
+	tests := []struct {
+		name        string
+		line        int
+		replacement []string
+		want        string
+	}{
+		{"first line", 1, []string{"ONE"}, "ONE\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine"},
+		{"middle line deleted", 5, nil, "one\ntwo\nthree\nfour\nsix\nseven\neight\nnine"},
+		{"last line split", 9, []string{"// nine", "nine"}, "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n// nine\nnine"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyPatch(content, linePatch("f", lines, tt.line, tt.replacement))
+			if err != nil {
+				t.Fatalf("ApplyPatch() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ApplyPatch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
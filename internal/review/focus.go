@@ -0,0 +1,48 @@
+package review
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FocusAll reviews every issue; the other focus areas are issue categories
+const FocusAll = "all"
+
+// FocusAreas are the values of the review's --focus
+var FocusAreas = []string{FocusAll, "security", "performance", "style"}
+
+// SetFocus limits the review to one area: only issues of that category, and
+// critical issues of any category, are reported and count toward scores.
+// Detection is unchanged.
+func (cr *CodeReviewer) SetFocus(focus string) error {
+	if !slices.Contains(FocusAreas, focus) {
+		return fmt.Errorf("invalid focus %q: use %s", focus, strings.Join(FocusAreas, ", "))
+	}
+	cr.focus = focus
+	return nil
+}
+
+// focused reports whether the review is limited to one area
+func (cr *CodeReviewer) focused() bool {
+	return cr.focus != "" && cr.focus != FocusAll
+}
+
+// inFocus reports whether an issue belongs to the review's focus area
+func (cr *CodeReviewer) inFocus(issue CodeIssue) bool {
+	return !cr.focused() || issue.Category == cr.focus || issue.Severity == "critical"
+}
+
+// focusIssues keeps the issues in the review's focus area
+func (cr *CodeReviewer) focusIssues(issues []CodeIssue) []CodeIssue {
+	if !cr.focused() {
+		return issues
+	}
+	focused := []CodeIssue{}
+	for _, issue := range issues {
+		if cr.inFocus(issue) {
+			focused = append(focused, issue)
+		}
+	}
+	return focused
+}
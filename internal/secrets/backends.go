@@ -0,0 +1,311 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Secret backends selectable in the secrets config
+const (
+	BackendAuto     = "auto"     // OS keychain when available, else the encrypted file
+	BackendEnv      = "env"      // read-only, from VIKI_* environment variables
+	BackendKeychain = "keychain" // macOS Keychain, libsecret or Windows Credential Manager
+	BackendFile     = "file"     // encrypted file under ~/.viki/secrets
+	BackendVault    = "vault"    // HashiCorp Vault KV v2 over HTTP
+)
+
+// Backends lists the supported secret backends
+var Backends = []string{BackendAuto, BackendEnv, BackendKeychain, BackendFile, BackendVault}
+
+// Config selects and configures the secret backend
+type Config struct {
+	Backend string      `json:"backend"`
+	Vault   VaultConfig `json:"vault,omitempty"`
+}
+
+// VaultConfig locates the Vault KV v2 secret holding Viki's keys. The token
+// is never stored; it is read from VAULT_TOKEN at runtime.
+type VaultConfig struct {
+	Address string `json:"address,omitempty"`
+	Mount   string `json:"mount,omitempty"`
+	Path    string `json:"path,omitempty"`
+}
+
+// secretsDir returns the directory holding Viki's secrets configuration
+func secretsDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".viki", "secrets")
+}
+
+// ConfigPath returns the location of the secrets backend configuration
+func ConfigPath() string {
+	return filepath.Join(secretsDir(), "config.json")
+}
+
+// LoadConfig reads the secrets backend configuration. VIKI_SECRETS_BACKEND
+// and VAULT_ADDR override the file.
+func LoadConfig() (*Config, error) {
+	config := &Config{Backend: BackendAuto}
+
+	data, err := os.ReadFile(ConfigPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read secrets config: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse secrets config: %w", err)
+		}
+	}
+
+	if backend := os.Getenv("VIKI_SECRETS_BACKEND"); backend != "" {
+		config.Backend = backend
+	}
+	if addr := os.Getenv("VAULT_ADDR"); addr != "" {
+		config.Vault.Address = addr
+	}
+	if config.Backend == "" {
+		config.Backend = BackendAuto
+	}
+	if config.Vault.Mount == "" {
+		config.Vault.Mount = "secret"
+	}
+	if config.Vault.Path == "" {
+		config.Vault.Path = "viki"
+	}
+
+	return config, nil
+}
+
+// SaveConfig writes the secrets backend configuration
+func SaveConfig(config *Config) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets config: %w", err)
+	}
+
+	if err := os.MkdirAll(secretsDir(), 0700); err != nil {
+		return fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+
+	return os.WriteFile(ConfigPath(), data, 0600)
+}
+
+// EnvStore reads secrets from environment variables. API keys stored as
+// apikey_<provider> map to VIKI_<PROVIDER>_API_KEY; other keys map to
+// VIKI_SECRET_<KEY>.
+type EnvStore struct{}
+
+// NewEnvStore creates a new environment store
+func NewEnvStore() *EnvStore {
+	return &EnvStore{}
+}
+
+func envVarFor(key string) string {
+	name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+	if provider, ok := strings.CutPrefix(name, "APIKEY_"); ok {
+		return fmt.Sprintf("VIKI_%s_API_KEY", provider)
+	}
+	return "VIKI_SECRET_" + name
+}
+
+func (e *EnvStore) Set(key, value string) error {
+	return fmt.Errorf("the env backend is read-only; export %s instead", envVarFor(key))
+}
+
+func (e *EnvStore) Get(key string) (string, error) {
+	if value := os.Getenv(envVarFor(key)); value != "" {
+		return value, nil
+	}
+	return "", fmt.Errorf("secret not found: %s (set %s)", key, envVarFor(key))
+}
+
+func (e *EnvStore) Delete(key string) error {
+	return fmt.Errorf("the env backend is read-only; unset %s instead", envVarFor(key))
+}
+
+func (e *EnvStore) List() ([]string, error) {
+	var keys []string
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		if provider, ok := strings.CutPrefix(name, "VIKI_"); ok {
+			if provider, ok = strings.CutSuffix(provider, "_API_KEY"); ok {
+				keys = append(keys, "apikey_"+strings.ToLower(provider))
+			}
+		}
+	}
+	return keys, nil
+}
+
+// VaultStore keeps secrets as fields of a single HashiCorp Vault KV v2 secret
+type VaultStore struct {
+	address string
+	token   string
+	mount   string
+	path    string
+	client  *http.Client
+}
+
+// NewVaultStore creates a Vault store authenticated with VAULT_TOKEN
+func NewVaultStore(config VaultConfig) (*VaultStore, error) {
+	if config.Address == "" {
+		return nil, fmt.Errorf("vault address not configured (set VAULT_ADDR or run 'viki secrets backend vault --address ...')")
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("VAULT_TOKEN is not set")
+	}
+
+	return &VaultStore{
+		address: strings.TrimRight(config.Address, "/"),
+		token:   token,
+		mount:   strings.Trim(config.Mount, "/"),
+		path:    strings.Trim(config.Path, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (v *VaultStore) dataURL() string {
+	return fmt.Sprintf("%s/v1/%s/data/%s", v.address, v.mount, v.path)
+}
+
+// read fetches every field of the Viki secret; a missing secret is empty
+func (v *VaultStore) read() (map[string]string, error) {
+	req, err := http.NewRequest(http.MethodGet, v.dataURL(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return make(map[string]string), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	if result.Data.Data == nil {
+		result.Data.Data = make(map[string]string)
+	}
+
+	return result.Data.Data, nil
+}
+
+// write replaces the Viki secret with a new version holding data
+func (v *VaultStore) write(data map[string]string) error {
+	body, err := json.Marshal(map[string]interface{}{"data": data})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, v.dataURL(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (v *VaultStore) Set(key, value string) error {
+	data, err := v.read()
+	if err != nil {
+		return err
+	}
+	data[key] = value
+	return v.write(data)
+}
+
+func (v *VaultStore) Get(key string) (string, error) {
+	data, err := v.read()
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("secret not found: %s", key)
+	}
+	return value, nil
+}
+
+func (v *VaultStore) Delete(key string) error {
+	data, err := v.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := data[key]; !ok {
+		return nil
+	}
+	delete(data, key)
+	return v.write(data)
+}
+
+func (v *VaultStore) List() ([]string, error) {
+	data, err := v.read()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// newStore creates the secret store selected by config
+func newStore(config *Config) (SecretStore, error) {
+	switch config.Backend {
+	case BackendEnv:
+		return NewEnvStore(), nil
+	case BackendKeychain:
+		if !isKeychainAvailable() {
+			return nil, fmt.Errorf("no OS keychain available on this system")
+		}
+		return NewKeychainStore("viki"), nil
+	case BackendFile:
+		return NewFileStore(FileStorePath())
+	case BackendVault:
+		return NewVaultStore(config.Vault)
+	case BackendAuto:
+		// Try to use keychain first, fall back to encrypted file
+		if isKeychainAvailable() {
+			return NewKeychainStore("viki"), nil
+		}
+		return NewFileStore(FileStorePath())
+	default:
+		return nil, fmt.Errorf("unknown secrets backend '%s' (valid: %s)", config.Backend, strings.Join(Backends, ", "))
+	}
+}
@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces secret values removed from content sent to providers
+const Redacted = "[REDACTED]"
+
+// SecretPatterns match hardcoded credential assignments such as
+// password = "..." (matched case-insensitively)
+var SecretPatterns = []string{
+	`password\s*=\s*["'][^"']*["']`,
+	`secret\s*=\s*["'][^"']*["']`,
+	`token\s*=\s*["'][^"']*["']`,
+	`key\s*=\s*["'][^"']*["']`,
+}
+
+// TokenPatterns match well-known credential formats regardless of context
+var TokenPatterns = []string{
+	`AKIA[0-9A-Z]{16}`,                      // AWS access key ID
+	`sk-(?:proj-|ant-)?[A-Za-z0-9_\-]{20,}`, // OpenAI / Anthropic keys
+	`AIza[0-9A-Za-z_\-]{35}`,                // Google API key
+	`gh[pousr]_[A-Za-z0-9]{36,}`,            // GitHub tokens
+	`xox[baprs]-[A-Za-z0-9\-]{10,}`,         // Slack tokens
+	`[sr]k_live_[0-9A-Za-z]{24,}`,           // Stripe live keys
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+}
+
+var (
+	assignmentRegexps = compileAll(SecretPatterns, "(?i)")
+	tokenRegexps      = compileAll(TokenPatterns, "")
+	quotedValue       = regexp.MustCompile(`(["'])[^"']+(["'])`)
+)
+
+func compileAll(patterns []string, flags string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled = append(compiled, regexp.MustCompile(flags+pattern))
+	}
+	return compiled
+}
+
+// TokenLines returns the 1-based numbers of the lines of content where a
+// well-known credential format starts, in order
+func TokenLines(content string) []int {
+	var lines []int
+	seen := make(map[int]bool)
+	for _, re := range tokenRegexps {
+		for _, match := range re.FindAllStringIndex(content, -1) {
+			line := strings.Count(content[:match[0]], "\n") + 1
+			if !seen[line] {
+				seen[line] = true
+				lines = append(lines, line)
+			}
+		}
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// Redact masks likely secrets in content and returns the redacted text
+// along with the number of values that were replaced
+func Redact(content string) (string, int) {
+	count := 0
+
+	for _, re := range tokenRegexps {
+		content = re.ReplaceAllStringFunc(content, func(string) string {
+			count++
+			return Redacted
+		})
+	}
+
+	// Only the quoted value of an assignment is masked so the prompt keeps
+	// the variable name for context
+	for _, re := range assignmentRegexps {
+		content = re.ReplaceAllStringFunc(content, func(match string) string {
+			if strings.Contains(match, Redacted) || !quotedValue.MatchString(match) {
+				return match
+			}
+			count++
+			return quotedValue.ReplaceAllString(match, "${1}"+Redacted+"${2}")
+		})
+	}
+
+	return content, count
+}
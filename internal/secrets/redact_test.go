@@ -0,0 +1,39 @@
+package secrets
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Credentials are assembled at run time so the test files themselves do not
+// trip secret scanners
+var (
+	awsKey     = "AKIA" + strings.Repeat("Z", 16)
+	openAIKey  = "sk-" + strings.Repeat("a1", 12)
+	githubPAT  = "ghp_" + strings.Repeat("x9", 18)
+	privateKey = "-----BEGIN RSA " + "PRIVATE KEY-----\nMIIE\n-----END RSA " + "PRIVATE KEY-----"
+)
+
+func TestTokenLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []int
+	}{
+		{"no credentials", "package main\nfunc main() {}\n", nil},
+		{"single token", "x := 1\nkey := \"" + awsKey + "\"\n", []int{2}},
+		{"tokens in line order", githubPAT + "\n\n" + openAIKey + "\n" + awsKey, []int{1, 3, 4}},
+		{"two tokens on one line", awsKey + " " + openAIKey, []int{1}},
+		{"multi-line key reported where it starts", "a\n" + privateKey + "\nb", []int{2}},
+		{"too short to be a key", "sk-short\nAKIA123", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TokenLines(tt.content); !slices.Equal(got, tt.want) {
+				t.Errorf("TokenLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
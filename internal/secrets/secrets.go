@@ -3,17 +3,20 @@ package secrets
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/pbkdf2"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"runtime"
 	"strings"
+
+	"golang.org/x/crypto/argon2"
 )
 
 // SecretStore interface for secret storage backends
@@ -29,47 +32,63 @@ type KeychainStore struct {
 	service string
 }
 
-// FileStore uses an encrypted file for secret storage
+// FileStore uses an encrypted file for secret storage. Secrets are only
+// ever decrypted in memory.
 type FileStore struct {
-	filePath   string
-	passphrase string
-	secrets    map[string]string
+	filePath string
+	mode     string // modePassphrase or modeMachine
+	kdf      string // kdfArgon2id, or kdfPBKDF2 for vaults not yet rekeyed
+	salt     []byte
+	key      []byte
+	secrets  map[string]string
 }
 
 // SecretsManager manages API keys and other secrets
 type SecretsManager struct {
 	store    SecretStore
+	backend  string
 	cacheDir string
 }
 
-// NewSecretsManager creates a new secrets manager
+// NewSecretsManager creates a new secrets manager using the backend
+// selected in the secrets config
 func NewSecretsManager() (*SecretsManager, error) {
-	homeDir, _ := os.UserHomeDir()
-	cacheDir := filepath.Join(homeDir, ".viki", "secrets")
+	cacheDir := secretsDir()
 
 	if err := os.MkdirAll(cacheDir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create secrets directory: %w", err)
 	}
 
-	// Try to use keychain first, fall back to encrypted file
-	var store SecretStore
-	var err error
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
 
-	if isKeychainAvailable() {
-		store = NewKeychainStore("viki")
-	} else {
-		store, err = NewFileStore(filepath.Join(cacheDir, "vault.enc"))
-		if err != nil {
-			return nil, err
-		}
+	store, err := newStore(config)
+	if err != nil {
+		return nil, err
 	}
 
 	return &SecretsManager{
 		store:    store,
+		backend:  config.Backend,
 		cacheDir: cacheDir,
 	}, nil
 }
 
+// Backend returns the name of the backend secrets are stored in
+func (sm *SecretsManager) Backend() string {
+	return sm.backend
+}
+
+// Obfuscated reports whether secrets are kept in a file store keyed from
+// machine details rather than a passphrase, which anyone able to read the
+// file can decrypt
+func (sm *SecretsManager) Obfuscated() bool {
+	fs, ok := sm.store.(*FileStore)
+	return ok && fs.mode == modeMachine
+}
+
 // SetAPIKey stores an API key for a provider
 func (sm *SecretsManager) SetAPIKey(provider, apiKey string) error {
 	key := fmt.Sprintf("apikey_%s", provider)
@@ -260,27 +279,173 @@ func (k *KeychainStore) deleteWindows(key string) error {
 
 // File-based encrypted storage
 
-// NewFileStore creates a new encrypted file store
+const (
+	// modePassphrase vaults are keyed from a user passphrase and must be
+	// unlocked before use
+	modePassphrase = "passphrase"
+	// modeMachine vaults are keyed from the hostname and home directory and
+	// open without a passphrase. Anyone who can read the vault can derive
+	// that key, so this mode only obfuscates secrets; it does not protect
+	// them from other local users or malware.
+	modeMachine = "machine"
+
+	// kdfArgon2id is the key derivation of new vaults; kdfPBKDF2 vaults are
+	// still read and rekeyed with argon2id when the passphrase is known
+	kdfArgon2id = "argon2id"
+	kdfPBKDF2   = "pbkdf2-sha256"
+
+	argon2Time     = 3
+	argon2Memory   = 64 * 1024 // KiB
+	argon2Threads  = 4
+	pbkdf2Rounds   = 600000
+	vaultVersion   = 2
+	saltSize       = 16
+	derivedKeySize = 32
+)
+
+// ErrLocked is returned when a passphrase-protected store has not been unlocked
+var ErrLocked = errors.New("secrets store is locked; run 'viki secrets unlock' or set VIKI_VAULT_PASSPHRASE")
+
+// vaultFile is the on-disk envelope of an encrypted file store
+type vaultFile struct {
+	Version    int    `json:"version"`
+	Mode       string `json:"mode"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations,omitempty"` // pbkdf2 only
+	Salt       string `json:"salt"`
+	Data       string `json:"data"` // base64 nonce + AES-GCM ciphertext
+}
+
+// NewFileStore opens an encrypted file store. Passphrase-protected stores
+// use VIKI_VAULT_PASSPHRASE or the key cached by 'viki secrets unlock' and
+// return ErrLocked when neither is available.
 func NewFileStore(filePath string) (*FileStore, error) {
 	fs := &FileStore{
 		filePath: filePath,
 		secrets:  make(map[string]string),
 	}
 
-	// Get passphrase from environment or generate one
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return fs, fs.initKey(os.Getenv("VIKI_VAULT_PASSPHRASE"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var vault vaultFile
+	if json.Unmarshal(data, &vault) != nil || vault.Version < vaultVersion {
+		// Stores written before key derivation was added are re-encrypted
+		// on the next save
+		if err := fs.loadLegacy(data); err != nil {
+			return nil, err
+		}
+		return fs, fs.initKey(os.Getenv("VIKI_VAULT_PASSPHRASE"))
+	}
+
+	if err := fs.openVault(&vault, os.Getenv("VIKI_VAULT_PASSPHRASE")); err != nil {
+		return nil, err
+	}
+
+	return fs, nil
+}
+
+// initKey sets up a fresh salt and key, protected by passphrase if given
+func (fs *FileStore) initKey(passphrase string) error {
+	fs.salt = make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, fs.salt); err != nil {
+		return err
+	}
+
+	fs.mode = modeMachine
+	if passphrase != "" {
+		fs.mode = modePassphrase
+	} else {
+		passphrase = getMachineKey()
+	}
+
+	fs.kdf = kdfArgon2id
+	key, err := deriveKey(kdfArgon2id, passphrase, fs.salt)
+	if err != nil {
+		return err
+	}
+	fs.key = key
+	return nil
+}
+
+// openVault derives the vault's key and decrypts its secrets
+func (fs *FileStore) openVault(vault *vaultFile, passphrase string) error {
+	salt, err := base64.StdEncoding.DecodeString(vault.Salt)
+	if err != nil {
+		return fmt.Errorf("failed to decode vault salt: %w", err)
+	}
+	fs.salt = salt
+	fs.mode = vault.Mode
+	fs.kdf = vault.KDF
+	if fs.kdf == "" {
+		fs.kdf = kdfPBKDF2
+	}
+
+	if vault.Mode == modeMachine {
+		passphrase = getMachineKey()
+	}
+	if passphrase != "" {
+		fs.key, err = deriveKey(fs.kdf, passphrase, salt)
+	} else {
+		fs.key = loadSessionKey(salt)
+		if fs.key == nil {
+			return ErrLocked
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := fs.decryptSecrets(vault.Data); err != nil {
+		return err
+	}
+
+	// Vaults keyed with PBKDF2 move to argon2id on the next save
+	if fs.kdf != kdfArgon2id && passphrase != "" {
+		fs.kdf = kdfArgon2id
+		fs.key, err = deriveKey(kdfArgon2id, passphrase, salt)
+	}
+	return err
+}
+
+func (fs *FileStore) decryptSecrets(data string) error {
+	ciphertext, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode vault: %w", err)
+	}
+
+	plaintext, err := decrypt(ciphertext, fs.key)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt vault (wrong passphrase?): %w", err)
+	}
+
+	return json.Unmarshal(plaintext, &fs.secrets)
+}
+
+// loadLegacy reads a store encrypted with an unsalted SHA-256 key
+func (fs *FileStore) loadLegacy(data []byte) error {
 	passphrase := os.Getenv("VIKI_VAULT_PASSPHRASE")
 	if passphrase == "" {
-		// Use machine-specific key
 		passphrase = getMachineKey()
 	}
-	fs.passphrase = passphrase
+	hash := sha256.Sum256([]byte(passphrase))
 
-	// Load existing secrets
-	if err := fs.load(); err != nil && !os.IsNotExist(err) {
-		return nil, err
+	ciphertext, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode vault: %w", err)
 	}
 
-	return fs, nil
+	plaintext, err := decrypt(ciphertext, hash[:])
+	if err != nil {
+		return fmt.Errorf("failed to decrypt vault: %w", err)
+	}
+
+	return json.Unmarshal(plaintext, &fs.secrets)
 }
 
 func (fs *FileStore) Set(key, value string) error {
@@ -309,39 +474,39 @@ func (fs *FileStore) List() ([]string, error) {
 	return keys, nil
 }
 
-func (fs *FileStore) load() error {
-	data, err := os.ReadFile(fs.filePath)
+func (fs *FileStore) save() error {
+	data, err := json.Marshal(fs.secrets)
 	if err != nil {
 		return err
 	}
 
-	decrypted, err := decrypt(data, fs.passphrase)
+	ciphertext, err := encrypt(data, fs.key)
 	if err != nil {
-		return fmt.Errorf("failed to decrypt vault: %w", err)
+		return fmt.Errorf("failed to encrypt vault: %w", err)
 	}
 
-	return json.Unmarshal(decrypted, &fs.secrets)
-}
-
-func (fs *FileStore) save() error {
-	data, err := json.Marshal(fs.secrets)
-	if err != nil {
-		return err
+	envelope := vaultFile{
+		Version: vaultVersion,
+		Mode:    fs.mode,
+		KDF:     fs.kdf,
+		Salt:    base64.StdEncoding.EncodeToString(fs.salt),
+		Data:    base64.StdEncoding.EncodeToString(ciphertext),
+	}
+	if fs.kdf == kdfPBKDF2 {
+		envelope.Iterations = pbkdf2Rounds
 	}
 
-	encrypted, err := encrypt(data, fs.passphrase)
+	vault, err := json.MarshalIndent(envelope, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to encrypt vault: %w", err)
+		return err
 	}
 
-	return os.WriteFile(fs.filePath, encrypted, 0600)
+	return os.WriteFile(fs.filePath, vault, 0600)
 }
 
 // Encryption helpers
 
-func encrypt(data []byte, passphrase string) ([]byte, error) {
-	key := deriveKey(passphrase)
-
+func encrypt(data, key []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -357,18 +522,10 @@ func encrypt(data []byte, passphrase string) ([]byte, error) {
 		return nil, err
 	}
 
-	ciphertext := gcm.Seal(nonce, nonce, data, nil)
-	return []byte(base64.StdEncoding.EncodeToString(ciphertext)), nil
+	return gcm.Seal(nonce, nonce, data, nil), nil
 }
 
-func decrypt(data []byte, passphrase string) ([]byte, error) {
-	key := deriveKey(passphrase)
-
-	decoded, err := base64.StdEncoding.DecodeString(string(data))
-	if err != nil {
-		return nil, err
-	}
-
+func decrypt(data, key []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -380,21 +537,30 @@ func decrypt(data []byte, passphrase string) ([]byte, error) {
 	}
 
 	nonceSize := gcm.NonceSize()
-	if len(decoded) < nonceSize {
+	if len(data) < nonceSize {
 		return nil, fmt.Errorf("ciphertext too short")
 	}
 
-	nonce, ciphertext := decoded[:nonceSize], decoded[nonceSize:]
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
 	return gcm.Open(nil, nonce, ciphertext, nil)
 }
 
-func deriveKey(passphrase string) []byte {
-	hash := sha256.Sum256([]byte(passphrase))
-	return hash[:]
+// deriveKey stretches a passphrase into an AES-256 key with the named key
+// derivation function
+func deriveKey(kdf, passphrase string, salt []byte) ([]byte, error) {
+	switch kdf {
+	case kdfArgon2id:
+		return argon2.IDKey([]byte(passphrase), salt, argon2Time, argon2Memory, argon2Threads, derivedKeySize), nil
+	case kdfPBKDF2:
+		return pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Rounds, derivedKeySize)
+	default:
+		return nil, fmt.Errorf("unsupported vault key derivation: %s", kdf)
+	}
 }
 
+// getMachineKey returns the key material of machine-mode vaults. It is not
+// secret: it only obfuscates the vault from casual reading.
 func getMachineKey() string {
-	// Generate a machine-specific key
 	hostname, _ := os.Hostname()
 	homeDir, _ := os.UserHomeDir()
 	return fmt.Sprintf("%s:%s:viki-vault", hostname, homeDir)
@@ -0,0 +1,175 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// DefaultUnlockDuration is how long 'viki secrets unlock' keeps the store open
+const DefaultUnlockDuration = 8 * time.Hour
+
+// sessionService and sessionKey name the OS keyring entry holding the
+// session of an unlocked file store
+const (
+	sessionService = "viki-vault-session"
+	sessionKey     = "session"
+)
+
+// ErrNoKeyring is returned by Unlock when there is no OS keyring to keep the
+// session in
+var ErrNoKeyring = errors.New("no OS keyring available to keep the secrets unlocked; set VIKI_VAULT_PASSPHRASE instead")
+
+// session caches the derived key of an unlocked file store in the OS
+// keyring. It never holds the passphrase or any decrypted secret, and is
+// never written to disk by viki.
+type session struct {
+	Salt    string    `json:"salt"`
+	Key     string    `json:"key"`
+	Expires time.Time `json:"expires"`
+}
+
+// FileStorePath returns the location of the encrypted file store
+func FileStorePath() string {
+	return filepath.Join(secretsDir(), "vault.enc")
+}
+
+// legacySessionPath is where older versions cached the derived key on disk
+func legacySessionPath() string {
+	return filepath.Join(secretsDir(), "session.json")
+}
+
+// sessionKeyring returns the OS keyring holding the session, or nil when
+// there is none that viki can read back from
+func sessionKeyring() SecretStore {
+	if runtime.GOOS == "windows" || !isKeychainAvailable() {
+		return nil
+	}
+	return NewKeychainStore(sessionService)
+}
+
+// loadSessionKey returns the cached key for the vault with the given salt,
+// or nil if the store is not unlocked
+func loadSessionKey(salt []byte) []byte {
+	keyring := sessionKeyring()
+	if keyring == nil {
+		return nil
+	}
+	data, err := keyring.Get(sessionKey)
+	if err != nil {
+		return nil
+	}
+
+	var s session
+	if err := json.Unmarshal([]byte(data), &s); err != nil || time.Now().After(s.Expires) {
+		return nil
+	}
+
+	sessionSalt, err := base64.StdEncoding.DecodeString(s.Salt)
+	if err != nil || !bytes.Equal(sessionSalt, salt) {
+		return nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(s.Key)
+	if err != nil {
+		return nil
+	}
+	return key
+}
+
+// Unlock opens the file store with passphrase and caches its derived key
+// in the OS keyring for duration so later commands can decrypt it. A store
+// that is not yet passphrase-protected is re-encrypted under passphrase;
+// protected reports whether that happened. Without a keyring it returns
+// ErrNoKeyring, after protecting the store.
+func Unlock(passphrase string, duration time.Duration) (protected bool, err error) {
+	if passphrase == "" {
+		return false, fmt.Errorf("passphrase cannot be empty")
+	}
+
+	if err := os.MkdirAll(secretsDir(), 0700); err != nil {
+		return false, fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+	if err := os.Remove(legacySessionPath()); err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to remove old session: %w", err)
+	}
+
+	fs, err := openForUnlock(passphrase)
+	if err != nil {
+		return false, err
+	}
+
+	if fs.mode != modePassphrase {
+		if err := fs.initKey(passphrase); err != nil {
+			return false, err
+		}
+		protected = true
+	}
+	// Saving also writes a store opened with an older key derivation under
+	// its argon2id key, which is the key cached below
+	if err := fs.save(); err != nil {
+		return false, err
+	}
+
+	keyring := sessionKeyring()
+	if keyring == nil {
+		return protected, ErrNoKeyring
+	}
+
+	data, err := json.Marshal(session{
+		Salt:    base64.StdEncoding.EncodeToString(fs.salt),
+		Key:     base64.StdEncoding.EncodeToString(fs.key),
+		Expires: time.Now().Add(duration),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := keyring.Set(sessionKey, string(data)); err != nil {
+		return protected, fmt.Errorf("failed to store session in the OS keyring: %w", err)
+	}
+
+	return protected, nil
+}
+
+// openForUnlock loads the file store, using passphrase for a protected vault
+func openForUnlock(passphrase string) (*FileStore, error) {
+	data, err := os.ReadFile(FileStorePath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var vault vaultFile
+	if err == nil && json.Unmarshal(data, &vault) == nil && vault.Version >= vaultVersion {
+		fs := &FileStore{filePath: FileStorePath(), secrets: make(map[string]string)}
+		if err := fs.openVault(&vault, passphrase); err != nil {
+			return nil, err
+		}
+		return fs, nil
+	}
+
+	// Missing or legacy stores open without the passphrase
+	return NewFileStore(FileStorePath())
+}
+
+// Lock forgets the cached key so the file store needs unlocking again. It
+// also removes the on-disk key cache left by older versions.
+func Lock() error {
+	if err := os.Remove(legacySessionPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+	if keyring := sessionKeyring(); keyring != nil {
+		if _, err := keyring.Get(sessionKey); err == nil {
+			if err := keyring.Delete(sessionKey); err != nil {
+				return fmt.Errorf("failed to remove session from the OS keyring: %w", err)
+			}
+		}
+	}
+	return nil
+}
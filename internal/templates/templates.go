@@ -6,10 +6,16 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"text/template"
+
+	"github.com/goccy/go-yaml"
 )
 
+// ManifestFile is the name of a custom template's metadata file
+const ManifestFile = "template.yaml"
+
 //go:embed builtin/*
 var builtinTemplates embed.FS
 
@@ -64,7 +70,7 @@ func (tm *TemplateManager) LoadBuiltinTemplates() {
 		},
 		Variables: []TemplateVar{
 			{Name: "ProjectName", Description: "Project name", Default: "myapp", Required: true},
-			{Name: "ModulePath", Description: "Go module path", Default: "github.com/user/myapp", Required: true},
+			{Name: "ModulePath", Description: "Go module path", Default: "github.com/user/{{.ProjectName}}", Required: true},
 		},
 		PostCreate: []string{
 			"go mod tidy",
@@ -156,7 +162,7 @@ func (tm *TemplateManager) LoadBuiltinTemplates() {
 		},
 		Variables: []TemplateVar{
 			{Name: "ProjectName", Description: "CLI name", Default: "mycli", Required: true},
-			{Name: "ModulePath", Description: "Go module path", Default: "github.com/user/mycli", Required: true},
+			{Name: "ModulePath", Description: "Go module path", Default: "github.com/user/{{.ProjectName}}", Required: true},
 		},
 		PostCreate: []string{
 			"go mod tidy",
@@ -182,6 +188,30 @@ func (tm *TemplateManager) Get(name string) (*Template, error) {
 	return t, nil
 }
 
+// DefaultValue renders the variable's default, which may refer to other
+// variables such as {{.ProjectName}}
+func (v TemplateVar) DefaultValue(vars map[string]string) string {
+	value, err := processTemplate(v.Default, vars)
+	if err != nil {
+		return v.Default
+	}
+	return value
+}
+
+// ResolveVariables fills in defaults for the template's declared variables
+// that vars does not set and reports any required variable left empty
+func (t *Template) ResolveVariables(vars map[string]string) error {
+	for _, v := range t.Variables {
+		if vars[v.Name] == "" {
+			vars[v.Name] = v.DefaultValue(vars)
+		}
+		if v.Required && vars[v.Name] == "" {
+			return fmt.Errorf("template variable %s is required", v.Name)
+		}
+	}
+	return nil
+}
+
 // Create creates a new project from a template
 func (tm *TemplateManager) Create(templateName, targetDir string, vars map[string]string) error {
 	t, err := tm.Get(templateName)
@@ -189,14 +219,29 @@ func (tm *TemplateManager) Create(templateName, targetDir string, vars map[strin
 		return err
 	}
 
+	if err := t.ResolveVariables(vars); err != nil {
+		return err
+	}
+
 	// Create target directory
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	// Process each template file
-	for path, content := range t.Files {
-		targetPath := filepath.Join(targetDir, path)
+	for file, content := range t.Files {
+		// File paths may contain placeholders too, e.g. cmd/{{.ProjectName}}/main.go
+		if err := checkPathVariables(file, vars); err != nil {
+			return err
+		}
+		path, err := processTemplate(file, vars)
+		if err != nil {
+			return fmt.Errorf("failed to process path %s: %w", file, err)
+		}
+		if !filepath.IsLocal(filepath.FromSlash(path)) {
+			return fmt.Errorf("template path %s resolves to %s, outside the project directory", file, path)
+		}
+		targetPath := filepath.Join(targetDir, filepath.FromSlash(path))
 
 		// Create parent directories
 		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
@@ -226,6 +271,21 @@ func (tm *TemplateManager) Create(templateName, targetDir string, vars map[strin
 	return nil
 }
 
+// pathVariable matches a variable placeholder such as {{.ProjectName}}
+var pathVariable = regexp.MustCompile(`\{\{\s*\.(\w+)\s*\}\}`)
+
+// checkPathVariables rejects values of the variables a file path uses that
+// could move the file out of its directory: path separators and ".."
+func checkPathVariables(file string, vars map[string]string) error {
+	for _, match := range pathVariable.FindAllStringSubmatch(file, -1) {
+		value := vars[match[1]]
+		if strings.ContainsAny(value, `/\`) || strings.Contains(value, "..") {
+			return fmt.Errorf("template variable %s is used in the path %s and must not contain path separators or '..' (got '%s')", match[1], file, value)
+		}
+	}
+	return nil
+}
+
 // processTemplate processes a template string with variables
 func processTemplate(content string, vars map[string]string) (string, error) {
 	tmpl, err := template.New("file").Parse(content)
@@ -241,7 +301,10 @@ func processTemplate(content string, vars map[string]string) (string, error) {
 	return buf.String(), nil
 }
 
-// LoadCustomTemplates loads custom templates from a directory
+// LoadCustomTemplates loads custom templates from a directory. Each
+// subdirectory is a template: every file in it is rendered into the new
+// project, and an optional template.yaml describes the template and the
+// variables it uses. Custom templates replace built-in ones of the same name.
 func (tm *TemplateManager) LoadCustomTemplates() error {
 	if _, err := os.Stat(tm.templatesDir); os.IsNotExist(err) {
 		return nil
@@ -258,13 +321,68 @@ func (tm *TemplateManager) LoadCustomTemplates() error {
 		}
 
 		templateDir := filepath.Join(tm.templatesDir, entry.Name())
-		// Would load template.yaml and files here
-		_ = templateDir
+		t, err := loadCustomTemplate(templateDir, entry.Name())
+		if err != nil {
+			return fmt.Errorf("failed to load template %s: %w", entry.Name(), err)
+		}
+		tm.templates[t.Name] = t
 	}
 
 	return nil
 }
 
+// loadCustomTemplate reads a template's manifest and files from disk
+func loadCustomTemplate(templateDir, name string) (*Template, error) {
+	t := &Template{}
+
+	data, err := os.ReadFile(filepath.Join(templateDir, ManifestFile))
+	if err == nil {
+		if err := yaml.Unmarshal(data, t); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ManifestFile, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if t.Name == "" {
+		t.Name = name
+	}
+	if t.Description == "" {
+		t.Description = "Custom template"
+	}
+	if t.Files == nil {
+		t.Files = make(map[string]string)
+	}
+	if len(t.Variables) == 0 {
+		t.Variables = []TemplateVar{
+			{Name: "ProjectName", Description: "Project name", Default: name, Required: true},
+		}
+	}
+
+	err = filepath.WalkDir(templateDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(templateDir, path)
+		if err != nil || rel == ManifestFile {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		t.Files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template files: %w", err)
+	}
+
+	return t, nil
+}
+
 // Template content strings
 
 const goMainTemplate = `package main
@@ -0,0 +1,251 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/gates"
+)
+
+var (
+	approveTitleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+	approveSelectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("46"))
+	approveDimStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	approveOKStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+	approveRejectStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+type approveModel struct {
+	projectRoot string
+	approver    string
+	artifacts   []*gates.Artifact
+	decisions   map[int]string
+	cursor      int
+	viewing     bool
+	viewport    viewport.Model
+	width       int
+	height      int
+	message     string
+	err         error
+}
+
+// RunApproveTUI walks through all pending gate artifacts across tracks,
+// letting the user read each one and approve or reject it with a keypress.
+// Approvals are recorded as approver's.
+func RunApproveTUI(projectRoot, approver string) error {
+	pending, err := gates.ListPendingArtifacts(projectRoot)
+	if err != nil {
+		return fmt.Errorf("failed to list artifacts: %w", err)
+	}
+
+	if len(pending) == 0 {
+		fmt.Println("✅ No pending artifacts. All gates are decided.")
+		return nil
+	}
+
+	m := approveModel{
+		projectRoot: projectRoot,
+		approver:    approver,
+		artifacts:   pending,
+		decisions:   make(map[int]string),
+		viewport:    viewport.New(80, 20),
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
+		return err
+	}
+
+	if fm, ok := final.(approveModel); ok {
+		approved, rejected := fm.counts()
+		fmt.Printf("✅ Approved: %d  ❌ Rejected: %d  ⏳ Pending: %d\n",
+			approved, rejected, len(fm.artifacts)-approved-rejected)
+	}
+
+	return nil
+}
+
+func (m approveModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m approveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.viewport.Width = msg.Width - 4
+		m.viewport.Height = msg.Height - 8
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "q":
+			if !m.viewing {
+				return m, tea.Quit
+			}
+			m.viewing = false
+			return m, nil
+		case "esc":
+			m.viewing = false
+			return m, nil
+		case "a":
+			return m.decide(gates.ArtifactApproved), nil
+		case "r":
+			return m.decide(gates.ArtifactRejected), nil
+		}
+
+		if !m.viewing {
+			switch msg.String() {
+			case "up", "k":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+			case "down", "j":
+				if m.cursor < len(m.artifacts)-1 {
+					m.cursor++
+				}
+			case "enter", " ":
+				m.open(m.cursor)
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "n", "right":
+			if m.cursor < len(m.artifacts)-1 {
+				m.open(m.cursor + 1)
+			}
+			return m, nil
+		case "p", "left":
+			if m.cursor > 0 {
+				m.open(m.cursor - 1)
+			}
+			return m, nil
+		}
+	}
+
+	if m.viewing {
+		m.viewport, cmd = m.viewport.Update(msg)
+	}
+	return m, cmd
+}
+
+// open shows the artifact at index i in the viewport
+func (m *approveModel) open(i int) {
+	m.cursor = i
+	m.viewing = true
+	m.viewport.SetContent(m.artifacts[i].Body)
+	m.viewport.GotoTop()
+}
+
+// decide writes the status for the selected artifact and advances to the next undecided one
+func (m approveModel) decide(status string) approveModel {
+	artifact := m.artifacts[m.cursor]
+	message := fmt.Sprintf("%s/%s → %s", artifact.TrackID, artifact.Name, status)
+	if status == gates.ArtifactApproved {
+		approvals, required, err := agents.ApproveArtifact(m.projectRoot, artifact.TrackID, artifact.Name, m.approver)
+		if err != nil {
+			m.err = err
+			return m
+		}
+		if approvals < required {
+			message = fmt.Sprintf("%s/%s approved by %s (%d of %d approvals)", artifact.TrackID, artifact.Name, m.approver, approvals, required)
+		}
+	} else if err := gates.SetArtifactStatus(m.projectRoot, artifact.TrackID, artifact.Name, status); err != nil {
+		m.err = err
+		return m
+	}
+
+	m.err = nil
+	m.decisions[m.cursor] = status
+	m.message = message
+
+	for i := m.cursor + 1; i < len(m.artifacts); i++ {
+		if _, done := m.decisions[i]; !done {
+			if m.viewing {
+				m.open(i)
+			} else {
+				m.cursor = i
+			}
+			return m
+		}
+	}
+
+	m.viewing = false
+	return m
+}
+
+func (m approveModel) counts() (approved, rejected int) {
+	for _, status := range m.decisions {
+		switch status {
+		case gates.ArtifactApproved:
+			approved++
+		case gates.ArtifactRejected:
+			rejected++
+		}
+	}
+	return approved, rejected
+}
+
+func (m approveModel) View() string {
+	var b strings.Builder
+
+	approved, rejected := m.counts()
+	b.WriteString(approveTitleStyle.Render("🚦 Gate Approvals"))
+	b.WriteString(approveDimStyle.Render(fmt.Sprintf("  %d pending • %d approved • %d rejected",
+		len(m.artifacts)-approved-rejected, approved, rejected)))
+	b.WriteString("\n\n")
+
+	if m.viewing {
+		artifact := m.artifacts[m.cursor]
+		b.WriteString(approveSelectedStyle.Render(fmt.Sprintf("%s / %s", artifact.TrackID, artifact.Name)))
+		b.WriteString(approveDimStyle.Render(fmt.Sprintf("  (%d/%d, %3.f%%)", m.cursor+1, len(m.artifacts), m.viewport.ScrollPercent()*100)))
+		b.WriteString("\n\n")
+		b.WriteString(m.viewport.View())
+		b.WriteString("\n\n")
+	} else {
+		for i, artifact := range m.artifacts {
+			marker := "  "
+			line := fmt.Sprintf("%s / %s", artifact.TrackID, artifact.Name)
+			if i == m.cursor {
+				marker = "▶ "
+				line = approveSelectedStyle.Render(line)
+			}
+
+			status := approveDimStyle.Render("PENDING")
+			switch m.decisions[i] {
+			case gates.ArtifactApproved:
+				status = approveOKStyle.Render("APPROVED")
+			case gates.ArtifactRejected:
+				status = approveRejectStyle.Render("REJECTED")
+			}
+
+			b.WriteString(fmt.Sprintf("%s%s  %s\n", marker, line, status))
+		}
+		b.WriteString("\n")
+	}
+
+	if m.err != nil {
+		b.WriteString(approveRejectStyle.Render("Error: "+m.err.Error()) + "\n")
+	} else if m.message != "" {
+		b.WriteString(approveDimStyle.Render(m.message) + "\n")
+	}
+
+	if m.viewing {
+		b.WriteString(approveDimStyle.Render("a approve • r reject • n/p next/prev • ↑/↓ scroll • esc back"))
+	} else {
+		b.WriteString(approveDimStyle.Render("↑/↓ select • enter view • a approve • r reject • q quit"))
+	}
+
+	return b.String()
+}
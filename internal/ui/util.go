@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"ultimate-sdd-framework/internal/agents"
 )
 
 // GSDTask represents a single task in the GSD checklist
@@ -37,7 +39,7 @@ func (m *SDDModel) loadGSDTasks() {
 
 	// Try standard path
 	// Note: We need to handle the frontmatter if it's saved with SaveArtifact
-	path := filepath.Join(".sdd", "tracks", trackID, "gsd.json")
+	path := filepath.Join(".sdd", "tracks", trackID, agents.ArtifactName(".", "task"))
 
 	content, err := os.ReadFile(path)
 	if err != nil {
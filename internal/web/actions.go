@@ -0,0 +1,132 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"ultimate-sdd-framework/internal/agents"
+)
+
+// dashboardActions maps the agent actions the dashboard offers to the viki
+// command each runs. Actions marked withInput pass the text the user typed
+// as the command's argument.
+var dashboardActions = map[string]struct {
+	args      []string
+	withInput bool
+}{
+	"init":    {args: []string{"init"}, withInput: true},
+	"specify": {args: []string{"specify"}, withInput: true},
+	"plan":    {args: []string{"plan"}},
+	"task":    {args: []string{"task"}},
+	"execute": {args: []string{"execute"}},
+	"review":  {args: []string{"review", "track"}},
+}
+
+// errActionRunning is returned when a dashboard action is already running
+var errActionRunning = errors.New("another action is still running")
+
+// runAction starts the viki command behind a dashboard action in the
+// project directory and logs its outcome when it finishes. Only one action
+// runs at a time; the state it changes reaches clients through Watch.
+func (ds *DashboardServer) runAction(name, input string) error {
+	action, ok := dashboardActions[name]
+	if !ok {
+		return fmt.Errorf("unknown action: %s", name)
+	}
+	if !ds.actionMu.TryLock() {
+		return errActionRunning
+	}
+
+	args := append([]string{}, action.args...)
+	if input = strings.TrimSpace(input); action.withInput && input != "" {
+		args = append(args, input)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		ds.actionMu.Unlock()
+		return fmt.Errorf("failed to locate viki: %w", err)
+	}
+	cmd := exec.Command(executable, args...)
+	cmd.Dir = ds.projectDir
+
+	ds.addLog("info", "Running: viki "+strings.Join(args, " "), "dashboard")
+	go func() {
+		defer ds.actionMu.Unlock()
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			ds.addLog("error", fmt.Sprintf("viki %s failed: %v\n%s", name, err, lastLines(string(output), 10)), "dashboard")
+			return
+		}
+		ds.addLog("success", fmt.Sprintf("viki %s finished\n%s", name, lastLines(string(output), 10)), "dashboard")
+	}()
+	return nil
+}
+
+// handleChat answers a chat message from the dashboard with the chosen
+// agent, in the context of the project's current phase
+func (ds *DashboardServer) handleChat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Agent   string `json:"agent"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(request.Message) == "" {
+		http.Error(w, "message is required", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	reply, err := ds.askAgent(request.Agent, request.Message)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"agent": request.Agent, "message": reply})
+}
+
+// askAgent sends a chat message to an agent. The agent service is set up on
+// first use and kept for later messages once it initializes.
+func (ds *DashboardServer) askAgent(agent, message string) (string, error) {
+	ds.chatMu.Lock()
+	defer ds.chatMu.Unlock()
+
+	if ds.agentSvc == nil {
+		svc := agents.NewAgentService(ds.projectDir)
+		if err := svc.Initialize(); err != nil {
+			return "", err
+		}
+		ds.agentSvc = svc
+	}
+
+	ds.mu.RLock()
+	phase := ds.state.CurrentPhase
+	ds.mu.RUnlock()
+	if phase == "" {
+		phase = "chat"
+	}
+	return ds.agentSvc.GetAgentResponse(agent, phase, message, "", "")
+}
+
+// lastLines returns at most n trailing lines of command output
+func lastLines(output string, n int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
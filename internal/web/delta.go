@@ -0,0 +1,156 @@
+package web
+
+import (
+	"fmt"
+	"slices"
+
+	"ultimate-sdd-framework/internal/config"
+	"ultimate-sdd-framework/internal/gates"
+)
+
+// StateDelta is the part of the dashboard state that changed, sent to SSE
+// clients as a "patch" event. Unchanged fields are omitted; clients merge
+// phases by name.
+type StateDelta struct {
+	ProjectName   *string     `json:"projectName,omitempty"`
+	CurrentPhase  *string     `json:"currentPhase,omitempty"`
+	Phases        []PhaseInfo `json:"phases,omitempty"`        // changed or added phases
+	RemovedPhases []string    `json:"removedPhases,omitempty"` // names of phases no longer in the state
+	Providers     *[]Provider `json:"providers,omitempty"`     // the full list when any provider changed
+	RecentLogs    *[]LogEntry `json:"recentLogs,omitempty"`    // the full list when it was replaced
+	Stats         *Stats      `json:"stats,omitempty"`
+}
+
+// Empty reports whether the delta carries no change
+func (d *StateDelta) Empty() bool {
+	return d.ProjectName == nil && d.CurrentPhase == nil && len(d.Phases) == 0 &&
+		len(d.RemovedPhases) == 0 && d.Providers == nil && d.RecentLogs == nil && d.Stats == nil
+}
+
+// diffState computes the changes that turn old into new
+func diffState(old, new *DashboardState) *StateDelta {
+	delta := &StateDelta{}
+
+	// Copies, as the broadcaster encodes the delta after the state moves on
+	if old.ProjectName != new.ProjectName {
+		name := new.ProjectName
+		delta.ProjectName = &name
+	}
+	if old.CurrentPhase != new.CurrentPhase {
+		phase := new.CurrentPhase
+		delta.CurrentPhase = &phase
+	}
+
+	oldPhases := make(map[string]PhaseInfo, len(old.Phases))
+	for _, phase := range old.Phases {
+		oldPhases[phase.Name] = phase
+	}
+	newPhases := make(map[string]bool, len(new.Phases))
+	for _, phase := range new.Phases {
+		newPhases[phase.Name] = true
+		if prev, ok := oldPhases[phase.Name]; !ok || !samePhase(prev, phase) {
+			delta.Phases = append(delta.Phases, phase)
+		}
+	}
+	for _, phase := range old.Phases {
+		if !newPhases[phase.Name] {
+			delta.RemovedPhases = append(delta.RemovedPhases, phase.Name)
+		}
+	}
+
+	if !slices.Equal(old.Providers, new.Providers) {
+		providers := append([]Provider{}, new.Providers...)
+		delta.Providers = &providers
+	}
+	if !slices.EqualFunc(old.RecentLogs, new.RecentLogs, sameLog) {
+		logs := append([]LogEntry{}, new.RecentLogs...)
+		delta.RecentLogs = &logs
+	}
+	if old.Stats != new.Stats {
+		stats := new.Stats
+		delta.Stats = &stats
+	}
+
+	return delta
+}
+
+// mergeDelta folds a later delta into an earlier one, so coalesced updates
+// reach clients as a single patch
+func mergeDelta(into, later *StateDelta) {
+	if later.ProjectName != nil {
+		into.ProjectName = later.ProjectName
+	}
+	if later.CurrentPhase != nil {
+		into.CurrentPhase = later.CurrentPhase
+	}
+	for _, phase := range later.Phases {
+		into.RemovedPhases = slices.DeleteFunc(into.RemovedPhases, func(name string) bool { return name == phase.Name })
+		if i := slices.IndexFunc(into.Phases, func(p PhaseInfo) bool { return p.Name == phase.Name }); i >= 0 {
+			into.Phases[i] = phase
+		} else {
+			into.Phases = append(into.Phases, phase)
+		}
+	}
+	for _, name := range later.RemovedPhases {
+		into.Phases = slices.DeleteFunc(into.Phases, func(p PhaseInfo) bool { return p.Name == name })
+		if !slices.Contains(into.RemovedPhases, name) {
+			into.RemovedPhases = append(into.RemovedPhases, name)
+		}
+	}
+	if later.Providers != nil {
+		into.Providers = later.Providers
+	}
+	if later.RecentLogs != nil {
+		into.RecentLogs = later.RecentLogs
+	}
+	if later.Stats != nil {
+		into.Stats = later.Stats
+	}
+}
+
+// cloneState copies a state so it can be changed without affecting the
+// state clients were sent
+func cloneState(state *DashboardState) *DashboardState {
+	clone := *state
+	clone.Phases = slices.Clone(state.Phases)
+	clone.Providers = slices.Clone(state.Providers)
+	clone.RecentLogs = slices.Clone(state.RecentLogs)
+	return &clone
+}
+
+// approvePhase records the approval of the project's current phase in its
+// state, then sends clients the change computed from the reloaded state so
+// the next refresh agrees with it
+func (ds *DashboardServer) approvePhase() error {
+	ds.mu.RLock()
+	projectDir := ds.projectDir
+	ds.mu.RUnlock()
+
+	stateMgr := gates.NewStateManager(projectDir)
+	project, err := stateMgr.LoadState()
+	if err != nil {
+		return fmt.Errorf("project not initialized: %w", err)
+	}
+	if project.Phases[project.CurrentPhase].Status.IsComplete() {
+		return fmt.Errorf("phase %s is already approved", project.CurrentPhase)
+	}
+
+	approver := config.ResolveUser(projectDir)
+	if err := stateMgr.ApprovePhase(approver, "Approved in the dashboard"); err != nil {
+		return fmt.Errorf("failed to approve phase: %w", err)
+	}
+	ds.refreshState(projectDir)
+	ds.addLog("info", fmt.Sprintf("Phase %s approved by %s via dashboard", project.CurrentPhase, approver), "dashboard")
+	return nil
+}
+
+// samePhase compares phases, times by instant
+func samePhase(a, b PhaseInfo) bool {
+	return a.Name == b.Name && a.Status == b.Status && a.Agent == b.Agent &&
+		a.StartedAt.Equal(b.StartedAt) && a.CompletedAt.Equal(b.CompletedAt)
+}
+
+// sameLog compares log entries, times by instant
+func sameLog(a, b LogEntry) bool {
+	return a.Level == b.Level && a.Message == b.Message && a.Source == b.Source && a.Timestamp.Equal(b.Timestamp)
+}
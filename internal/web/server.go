@@ -1,15 +1,25 @@
 package web
 
 import (
+	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
 	"sync"
+	"syscall"
 	"time"
 
-	"github.com/goccy/go-yaml"
+	"ultimate-sdd-framework/internal/agents"
+	"ultimate-sdd-framework/internal/gates"
+	"ultimate-sdd-framework/internal/lsp"
+	"ultimate-sdd-framework/internal/metrics"
 )
 
 //go:embed static/*
@@ -19,11 +29,60 @@ var staticFiles embed.FS
 type DashboardServer struct {
 	port     int
 	state    *DashboardState
+	stateSeq uint64 // number of changes made to state, guarded by mu
 	mu       sync.RWMutex
-	clients  map[chan []byte]bool
+	clients  map[chan []byte]uint64 // client -> stateSeq of the snapshot it was sent
 	clientMu sync.Mutex
+	events   chan broadcastEvent
+	lastID   uint64     // ID of the most recent SSE event, guarded by clientMu
+	history  []sseFrame // recent SSE events for Last-Event-ID replay, guarded by clientMu
+	server   *http.Server
+	listener net.Listener
+	done     chan struct{}
+	stopOnce sync.Once
+	metrics  *metrics.Store // nil unless /metrics is enabled
+
+	projectDir string               // where actions and chat run
+	actionMu   sync.Mutex           // held while a dashboard action runs
+	chatMu     sync.Mutex           // guards agentSvc
+	agentSvc   *agents.AgentService // answers dashboard chat, set up on first use
+}
+
+// broadcastEvent is an update queued for delivery to SSE clients
+type broadcastEvent struct {
+	event string
+	data  interface{}
+	// seq is the stateSeq the change brings the state to, so clients whose
+	// snapshot already holds it can skip it; 0 for events outside the state.
+	// A "logs" event has the seq of each entry in seqs instead.
+	seq  uint64
+	seqs []uint64
 }
 
+// sseFrame is an encoded SSE event kept for replay to reconnecting clients
+type sseFrame struct {
+	id    uint64
+	frame []byte
+}
+
+const (
+	// eventHistorySize is the number of recent events kept for replay
+	eventHistorySize = 100
+	// broadcastQueueSize bounds the updates waiting for the broadcaster
+	broadcastQueueSize = 1024
+	// broadcastInterval is the minimum delay between pushes to clients;
+	// updates arriving within it are coalesced into one push
+	broadcastInterval = 100 * time.Millisecond
+	// clientBufferSize is the number of pushes buffered per SSE client
+	clientBufferSize = 10
+	// sseRetry is the reconnect delay suggested to browsers
+	sseRetry = 2 * time.Second
+	// shutdownTimeout bounds how long Ctrl+C waits for requests to drain
+	shutdownTimeout = 2 * time.Second
+	// stateRefreshInterval is how often Watch rereads the project state
+	stateRefreshInterval = 2 * time.Second
+)
+
 // DashboardState represents the current state for the dashboard
 type DashboardState struct {
 	ProjectName  string      `json:"projectName"`
@@ -68,45 +127,146 @@ type Stats struct {
 	TasksPending   int `json:"tasksPending"`
 }
 
-// NewDashboardServer creates a new dashboard server
+// NewDashboardServer creates a new dashboard server. A port of 0 picks a
+// free port when the server starts listening.
 func NewDashboardServer(port int) *DashboardServer {
-	return &DashboardServer{
+	ds := &DashboardServer{
 		port:    port,
 		state:   &DashboardState{},
-		clients: make(map[chan []byte]bool),
+		clients: make(map[chan []byte]uint64),
+		events:  make(chan broadcastEvent, broadcastQueueSize),
+		done:    make(chan struct{}),
 	}
+	go ds.runBroadcaster()
+	return ds
 }
 
-// LoadState loads the current project state
+// LoadState loads the current project state. Dashboard actions and chat
+// run in projectDir.
 func (ds *DashboardServer) LoadState(projectDir string) error {
 	ds.mu.Lock()
 	defer ds.mu.Unlock()
 
-	// Load state.yaml
-	stateFile := projectDir + "/.sdd/state.yaml"
-	data, err := fs.ReadFile(staticFiles, stateFile)
+	ds.projectDir = projectDir
+	loadProjectState(ds.state, projectDir)
+	return nil
+}
+
+// loadProjectState fills state from .sdd/state.yaml and the symbol index
+// 'viki index' writes to .sdd/index.json. What the project lacks is left
+// as is.
+func loadProjectState(state *DashboardState, projectDir string) {
+	if indexer, err := lsp.LoadIndex(projectDir); err == nil {
+		stats := indexer.GetStats()
+		state.Stats.FilesIndexed = stats["files"]
+		state.Stats.SymbolsFound = stats["symbols"]
+	}
+
+	project, err := gates.NewStateManager(projectDir).LoadState()
 	if err != nil {
-		// Try alternative read
-		return nil // Ignore for demo
+		return
 	}
 
-	var state map[string]interface{}
-	if err := yaml.Unmarshal(data, &state); err != nil {
-		return err
+	state.ProjectName = project.ProjectName
+	state.CurrentPhase = string(project.CurrentPhase)
+	state.Phases = phaseInfos(project)
+}
+
+// Watch rereads the project state every stateRefreshInterval until the
+// server shuts down, so changes made by viki commands reach clients as
+// patches of the fields that changed
+func (ds *DashboardServer) Watch(projectDir string) {
+	go func() {
+		ticker := time.NewTicker(stateRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ds.refreshState(projectDir)
+			case <-ds.done:
+				return
+			}
+		}
+	}()
+}
+
+// refreshState rereads the project state and sends clients what changed
+func (ds *DashboardServer) refreshState(projectDir string) {
+	ds.mu.Lock()
+	state := cloneState(ds.state)
+	loadProjectState(state, projectDir)
+	delta := diffState(ds.state, state)
+	ds.state = state
+	if !delta.Empty() {
+		ds.broadcastChange("patch", delta)
 	}
+	ds.mu.Unlock()
 
-	if name, ok := state["project_name"].(string); ok {
-		ds.state.ProjectName = name
+	if delta.Empty() {
+		return
 	}
-	if phase, ok := state["current_phase"].(string); ok {
-		ds.state.CurrentPhase = phase
+	if delta.CurrentPhase != nil {
+		ds.addLog("info", "Project moved to the "+*delta.CurrentPhase+" phase", "viki")
 	}
+}
 
-	return nil
+// dashboardPhases are the phases of the project state, in workflow order
+var dashboardPhases = []gates.Phase{
+	gates.PhaseInit, gates.PhaseSpecify, gates.PhasePlan, gates.PhaseTask,
+	gates.PhaseExecute, gates.PhaseReview, gates.PhaseComplete,
+}
+
+// phaseInfos lists the phases the project has started, in workflow order
+func phaseInfos(state *gates.ProjectState) []PhaseInfo {
+	var phases []PhaseInfo
+	for _, phase := range dashboardPhases {
+		phaseState, ok := state.Phases[phase]
+		if !ok {
+			continue
+		}
+		info := PhaseInfo{Name: string(phase), Status: string(phaseState.Status), Agent: phaseState.AgentUsed}
+		if phaseState.StartedAt != nil {
+			info.StartedAt = *phaseState.StartedAt
+		}
+		if phaseState.CompletedAt != nil {
+			info.CompletedAt = *phaseState.CompletedAt
+		}
+		phases = append(phases, info)
+	}
+	return phases
+}
+
+// EnableMetrics serves the project's counters in the Prometheus text format
+// on /metrics
+func (ds *DashboardServer) EnableMetrics(projectRoot string) {
+	ds.metrics = metrics.NewStore(projectRoot)
+}
+
+// Listen binds the dashboard's port without serving yet and returns the URL
+// it will be reachable at. Start calls it if it has not been called.
+func (ds *DashboardServer) Listen() (string, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.listener == nil {
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", ds.port))
+		if err != nil {
+			return "", fmt.Errorf("failed to listen on port %d: %w", ds.port, err)
+		}
+		ds.listener = listener
+		ds.port = listener.Addr().(*net.TCPAddr).Port
+	}
+
+	return fmt.Sprintf("http://localhost:%d", ds.port), nil
 }
 
 // Start starts the dashboard server
 func (ds *DashboardServer) Start() error {
+	url, err := ds.Listen()
+	if err != nil {
+		return err
+	}
+
 	mux := http.NewServeMux()
 
 	// API endpoints
@@ -114,20 +274,88 @@ func (ds *DashboardServer) Start() error {
 	mux.HandleFunc("/api/logs", ds.handleLogs)
 	mux.HandleFunc("/api/events", ds.handleSSE)
 	mux.HandleFunc("/api/action", ds.handleAction)
+	mux.HandleFunc("/api/chat", ds.handleChat)
 
-	// Static files
+	if ds.metrics != nil {
+		mux.HandleFunc("/metrics", ds.handleMetrics)
+	}
+
+	// Static files, or the embedded HTML when there is no static index page
 	staticFS, err := fs.Sub(staticFiles, "static")
+	if err == nil {
+		_, err = fs.Stat(staticFS, "index.html")
+	}
 	if err != nil {
-		// Serve embedded HTML if static dir not found
 		mux.HandleFunc("/", ds.handleIndex)
 	} else {
 		mux.Handle("/", http.FileServer(http.FS(staticFS)))
 	}
 
-	addr := fmt.Sprintf(":%d", ds.port)
-	fmt.Printf("🌐 Dashboard running at http://localhost%s\n", addr)
+	server := &http.Server{Handler: mux}
+	ds.mu.Lock()
+	ds.server = server
+	listener := ds.listener
+	ds.mu.Unlock()
 
-	return http.ListenAndServe(addr, mux)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+
+	fmt.Printf("🌐 Dashboard running at %s\n", url)
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("dashboard server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	fmt.Println("\n🛑 Shutting down dashboard...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return ds.Shutdown(shutdownCtx)
+}
+
+// Shutdown disconnects all SSE clients, stops the broadcaster and then
+// gracefully stops the HTTP server, waiting for requests until ctx expires
+func (ds *DashboardServer) Shutdown(ctx context.Context) error {
+	ds.stopOnce.Do(func() {
+		close(ds.done)
+	})
+
+	ds.clientMu.Lock()
+	for client := range ds.clients {
+		delete(ds.clients, client)
+		close(client)
+	}
+	ds.clientMu.Unlock()
+
+	ds.mu.RLock()
+	server := ds.server
+	ds.mu.RUnlock()
+	if server == nil {
+		return nil
+	}
+
+	if err := server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down dashboard: %w", err)
+	}
+	return nil
+}
+
+// handleMetrics exports the project counters for Prometheus to scrape
+func (ds *DashboardServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := ds.metrics.WritePrometheus(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
 }
 
 // handleIndex serves the main dashboard HTML
@@ -160,17 +388,25 @@ func (ds *DashboardServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 
-	// Create client channel
-	clientChan := make(chan []byte, 10)
+	// Reconnecting browsers send the ID of the last event they received
+	lastSeen, _ := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+
+	// Create client channel; registering and computing the resync together
+	// ensures no event falls between the snapshot and the live stream
+	clientChan := make(chan []byte, clientBufferSize)
 	ds.clientMu.Lock()
-	ds.clients[clientChan] = true
+	backlog, snapshotSeq := ds.resyncFrames(lastSeen)
+	ds.clients[clientChan] = snapshotSeq
 	ds.clientMu.Unlock()
 
 	defer func() {
 		ds.clientMu.Lock()
-		delete(ds.clients, clientChan)
+		// Shutdown may already have closed the channel
+		if _, ok := ds.clients[clientChan]; ok {
+			delete(ds.clients, clientChan)
+			close(clientChan)
+		}
 		ds.clientMu.Unlock()
-		close(clientChan)
 	}()
 
 	flusher, ok := w.(http.Flusher)
@@ -179,13 +415,25 @@ func (ds *DashboardServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Ask browsers to reconnect quickly, then bring the client up to date
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+	for _, frame := range backlog {
+		w.Write(frame)
+	}
+	flusher.Flush()
+
 	for {
 		select {
-		case msg := <-clientChan:
-			fmt.Fprintf(w, "data: %s\n\n", msg)
+		case msg, ok := <-clientChan:
+			if !ok {
+				return
+			}
+			w.Write(msg)
 			flusher.Flush()
 		case <-r.Context().Done():
 			return
+		case <-ds.done:
+			return
 		}
 	}
 }
@@ -210,45 +458,239 @@ func (ds *DashboardServer) handleAction(w http.ResponseWriter, r *http.Request)
 	// Handle actions
 	switch action.Type {
 	case "approve":
-		ds.addLog("info", "Phase approved via dashboard", "dashboard")
-	case "specify":
-		ds.addLog("info", "Specification created: "+action.Data, "dashboard")
-	case "plan":
-		ds.addLog("info", "Planning started", "dashboard")
+		if err := ds.approvePhase(); err != nil {
+			ds.addLog("warning", err.Error(), "dashboard")
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 	default:
-		ds.addLog("warning", "Unknown action: "+action.Type, "dashboard")
+		// Agent actions run the matching viki command in the background
+		if err := ds.runAction(action.Type, action.Data); errors.Is(err, errActionRunning) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
 
-// Broadcast sends an update to all connected clients
+// Broadcast queues an update for all connected clients. It never blocks;
+// if the broadcaster has fallen far behind the update is dropped.
 func (ds *DashboardServer) Broadcast(event string, data interface{}) {
-	msg, _ := json.Marshal(map[string]interface{}{
-		"event": event,
-		"data":  data,
-	})
+	ds.enqueue(broadcastEvent{event: event, data: data})
+}
+
+// broadcastChange queues an update carrying a change to the state, stamped
+// with the state's new sequence number. Callers must hold mu for writing,
+// so changes are queued in the order they were made.
+func (ds *DashboardServer) broadcastChange(event string, data interface{}) {
+	ds.stateSeq++
+	ds.enqueue(broadcastEvent{event: event, data: data, seq: ds.stateSeq})
+}
+
+// enqueue hands an update to the broadcaster without blocking
+func (ds *DashboardServer) enqueue(ev broadcastEvent) {
+	select {
+	case ds.events <- ev:
+	default:
+		// Queue full, drop the update
+	}
+}
+
+// runBroadcaster delivers queued updates to clients, at most once per
+// broadcastInterval
+func (ds *DashboardServer) runBroadcaster() {
+	var pending []broadcastEvent
+	var flush <-chan time.Time
+
+	for {
+		select {
+		case ev := <-ds.events:
+			pending = append(pending, ev)
+			if flush == nil {
+				flush = time.After(broadcastInterval)
+			}
+		case <-flush:
+			for _, ev := range coalesceEvents(pending) {
+				ds.send(ev)
+			}
+			pending = nil
+			flush = nil
+
+		case <-ds.done:
+			return
+		}
+	}
+}
+
+// coalesceEvents keeps only the latest state update, folds the patches
+// since it into one and merges log entries into a single "logs" event,
+// preserving the order of everything else
+func coalesceEvents(events []broadcastEvent) []broadcastEvent {
+	var result []broadcastEvent
+	var state *broadcastEvent
+	var patch *StateDelta
+	var patchSeq uint64
+	var logs []interface{}
+	var logSeqs []uint64
+
+	for i, ev := range events {
+		switch ev.event {
+		case "state":
+			// A full state supersedes the patches before it
+			state = &events[i]
+			patch = nil
+		case "patch":
+			delta := ev.data.(*StateDelta)
+			if patch == nil {
+				patch = &StateDelta{}
+			}
+			mergeDelta(patch, delta)
+			patchSeq = max(patchSeq, ev.seq)
+		case "log":
+			logs = append(logs, ev.data)
+			logSeqs = append(logSeqs, ev.seq)
+		default:
+			result = append(result, ev)
+		}
+	}
+
+	if state != nil {
+		result = append(result, *state)
+	}
+	if patch != nil {
+		result = append(result, broadcastEvent{event: "patch", data: patch, seq: patchSeq})
+	}
+	if len(logs) > 0 {
+		result = append(result, logsEvent(logs, logSeqs))
+	}
+
+	return result
+}
+
+// logsEvent returns log entries as a single "log" event, or a "logs" event
+// when there are several
+func logsEvent(logs []interface{}, seqs []uint64) broadcastEvent {
+	if len(logs) == 1 {
+		return broadcastEvent{event: "log", data: logs[0], seq: seqs[0]}
+	}
+	return broadcastEvent{event: "logs", data: logs, seqs: seqs}
+}
+
+// newerThan reports whether an event carries changes past stateSeq seen,
+// the point of the snapshot a client was sent
+func (ev broadcastEvent) newerThan(seen uint64) bool {
+	if ev.seqs != nil {
+		return ev.seqs[len(ev.seqs)-1] > seen
+	}
+	return ev.seq == 0 || ev.seq > seen
+}
 
+// logsAfter returns the entries of a "logs" event made after stateSeq seen
+func (ev broadcastEvent) logsAfter(seen uint64) broadcastEvent {
+	var logs []interface{}
+	var seqs []uint64
+	for i, seq := range ev.seqs {
+		if seq > seen {
+			logs = append(logs, ev.data.([]interface{})[i])
+			seqs = append(seqs, seq)
+		}
+	}
+	return logsEvent(logs, seqs)
+}
+
+// send pushes one event to every connected client, leaving out what a
+// client's snapshot already holds. A client whose buffer is full loses its
+// oldest undelivered message so it catches up on recent ones.
+func (ds *DashboardServer) send(ev broadcastEvent) {
 	ds.clientMu.Lock()
 	defer ds.clientMu.Unlock()
 
-	for client := range ds.clients {
+	ds.lastID++
+	msg := encodeSSE(ds.lastID, ev)
+	ds.history = append(ds.history, sseFrame{id: ds.lastID, frame: msg})
+	if len(ds.history) > eventHistorySize {
+		ds.history = ds.history[len(ds.history)-eventHistorySize:]
+	}
+
+	for client, seen := range ds.clients {
+		if !ev.newerThan(seen) {
+			continue
+		}
+		frame := msg
+		if ev.seqs != nil && ev.seqs[0] <= seen {
+			// The client's snapshot holds the earlier entries
+			frame = encodeSSE(ds.lastID, ev.logsAfter(seen))
+		}
+
+		select {
+		case client <- frame:
+			continue
+		default:
+		}
+
+		// Client buffer full, drop its oldest message and retry once
 		select {
-		case client <- msg:
+		case <-client:
 		default:
-			// Client buffer full, skip
+		}
+		select {
+		case client <- frame:
+		default:
+		}
+	}
+}
+
+// encodeSSE formats an event as an SSE frame carrying its ID
+func encodeSSE(id uint64, ev broadcastEvent) []byte {
+	data, _ := json.Marshal(map[string]interface{}{
+		"event": ev.event,
+		"data":  ev.data,
+	})
+	return []byte(fmt.Sprintf("id: %d\ndata: %s\n\n", id, data))
+}
+
+// resyncFrames returns the events a client that last saw lastSeen has
+// missed, if they are still in the history, followed by a full state
+// snapshot, and the stateSeq of the snapshot. Callers must hold clientMu.
+func (ds *DashboardServer) resyncFrames(lastSeen uint64) ([][]byte, uint64) {
+	var frames [][]byte
+
+	if lastSeen > 0 && lastSeen < ds.lastID && len(ds.history) > 0 && ds.history[0].id <= lastSeen+1 {
+		for _, frame := range ds.history {
+			if frame.id > lastSeen {
+				frames = append(frames, frame.frame)
+			}
 		}
 	}
+
+	ds.mu.RLock()
+	snapshot := encodeSSE(ds.lastID, broadcastEvent{event: "state", data: ds.state})
+	seq := ds.stateSeq
+	ds.mu.RUnlock()
+
+	return append(frames, snapshot), seq
 }
 
-// UpdateState updates the dashboard state
+// UpdateState updates the dashboard state, sending clients only the fields
+// that changed. A state changed in place since the last update can't be
+// compared and is sent whole.
 func (ds *DashboardServer) UpdateState(state *DashboardState) {
 	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if state == ds.state {
+		ds.broadcastChange("state", state)
+		return
+	}
+	delta := diffState(ds.state, state)
 	ds.state = state
-	ds.mu.Unlock()
-
-	ds.Broadcast("state", state)
+	if !delta.Empty() {
+		ds.broadcastChange("patch", delta)
+	}
 }
 
 // addLog adds a log entry
@@ -268,7 +710,7 @@ func (ds *DashboardServer) addLog(level, message, source string) {
 		ds.state.RecentLogs = ds.state.RecentLogs[:100]
 	}
 
-	go ds.Broadcast("log", entry)
+	ds.broadcastChange("log", entry)
 }
 
 // Embedded dashboard HTML
@@ -454,6 +896,43 @@ const dashboardHTML = `<!DOCTYPE html>
             background: var(--bg-tertiary);
             color: var(--text-primary);
         }
+        
+        input, textarea, select {
+            width: 100%;
+            padding: 10px;
+            background: var(--bg-tertiary);
+            color: var(--text-primary);
+            border: 1px solid var(--bg-tertiary);
+            border-radius: 8px;
+            font: inherit;
+        }
+        
+        .chat-messages {
+            height: 240px;
+            overflow-y: auto;
+            margin: 12px 0;
+            display: flex;
+            flex-direction: column;
+            gap: 8px;
+        }
+        
+        .chat-message {
+            padding: 10px 12px;
+            border-radius: 8px;
+            white-space: pre-wrap;
+            font-size: 14px;
+        }
+        
+        .chat-user { background: rgba(88, 166, 255, 0.2); align-self: flex-end; }
+        .chat-assistant { background: var(--bg-tertiary); }
+        .chat-error { color: var(--error); }
+        
+        .chat-input {
+            display: flex;
+            gap: 8px;
+        }
+        
+        .log-entry span:last-child { white-space: pre-wrap; }
     </style>
 </head>
 <body>
@@ -467,27 +946,27 @@ const dashboardHTML = `<!DOCTYPE html>
             <div class="card">
                 <h2>Workflow Progress</h2>
                 <div class="phases" id="phases">
-                    <div class="phase">
+                    <div class="phase" data-phase="init">
                         <div class="phase-icon done">✓</div>
                         <span>Initialize</span>
                     </div>
-                    <div class="phase">
+                    <div class="phase" data-phase="specify">
                         <div class="phase-icon active">●</div>
                         <span>Specify</span>
                     </div>
-                    <div class="phase">
+                    <div class="phase" data-phase="plan">
                         <div class="phase-icon pending"></div>
                         <span>Plan</span>
                     </div>
-                    <div class="phase">
+                    <div class="phase" data-phase="task">
                         <div class="phase-icon pending"></div>
                         <span>Task</span>
                     </div>
-                    <div class="phase">
+                    <div class="phase" data-phase="execute">
                         <div class="phase-icon pending"></div>
                         <span>Execute</span>
                     </div>
-                    <div class="phase">
+                    <div class="phase" data-phase="review">
                         <div class="phase-icon pending"></div>
                         <span>Review</span>
                     </div>
@@ -516,6 +995,24 @@ const dashboardHTML = `<!DOCTYPE html>
                 </div>
             </div>
             
+            <div class="card">
+                <h2>Agent Chat</h2>
+                <select id="agent">
+                    <option value="pm">🎯 Product Manager</option>
+                    <option value="architect">🏗️ System Architect</option>
+                    <option value="developer">💻 Developer</option>
+                    <option value="qa">🔍 QA Engineer</option>
+                    <option value="devops">⚙️ DevOps</option>
+                    <option value="security">🔒 Security</option>
+                    <option value="ux_designer">🎨 UX Designer</option>
+                </select>
+                <div class="chat-messages" id="chat-messages"></div>
+                <div class="chat-input">
+                    <textarea id="chat-input" rows="2" placeholder="Ask the agent anything..."></textarea>
+                    <button class="btn-primary" onclick="sendChat()">Send</button>
+                </div>
+            </div>
+            
             <div class="card" style="grid-column: span 2;">
                 <h2>Activity Log</h2>
                 <div class="logs" id="logs">
@@ -531,6 +1028,16 @@ const dashboardHTML = `<!DOCTYPE html>
             <button class="btn-primary" onclick="approve()">✓ Approve Current Phase</button>
             <button class="btn-secondary" onclick="refresh()">↻ Refresh</button>
         </div>
+        
+        <div class="actions">
+            <input id="action-input" placeholder="Project name or feature description">
+            <button class="btn-secondary" onclick="runAction('init')">Init</button>
+            <button class="btn-secondary" onclick="runAction('specify')">Specify</button>
+            <button class="btn-secondary" onclick="runAction('plan')">Plan</button>
+            <button class="btn-secondary" onclick="runAction('task')">Task</button>
+            <button class="btn-secondary" onclick="runAction('execute')">Execute</button>
+            <button class="btn-secondary" onclick="runAction('review')">Review</button>
+        </div>
     </div>
     
     <script>
@@ -541,24 +1048,109 @@ const dashboardHTML = `<!DOCTYPE html>
             const data = JSON.parse(e.data);
             if (data.event === 'log') {
                 addLog(data.data);
+            } else if (data.event === 'logs') {
+                data.data.forEach(addLog);
             } else if (data.event === 'state') {
                 updateState(data.data);
+            } else if (data.event === 'patch') {
+                applyPatch(data.data);
             }
         };
         
+        // The last full state, kept current by patches
+        let state = null;
+        
         function addLog(entry) {
             const logs = document.getElementById('logs');
             const div = document.createElement('div');
             div.className = 'log-entry';
-            div.innerHTML = '<span class="log-time">' + new Date(entry.timestamp).toLocaleTimeString() + '</span> ' +
-                           '<span class="log-' + entry.level + '">' + entry.message + '</span>';
+            const time = document.createElement('span');
+            time.className = 'log-time';
+            time.textContent = new Date(entry.timestamp).toLocaleTimeString() + ' ';
+            // Messages carry command output, so never treat them as HTML
+            const message = document.createElement('span');
+            message.className = 'log-' + entry.level;
+            message.textContent = entry.message;
+            div.append(time, message);
             logs.insertBefore(div, logs.firstChild);
         }
         
-        function updateState(state) {
+        function updateState(full) {
+            state = full;
+            renderCurrentPhase();
+            renderStats();
+            (state.phases || []).forEach(renderPhase);
+            renderLogs();
+        }
+        
+        // applyPatch merges the changed fields into the state and redraws
+        // only what they affect
+        function applyPatch(patch) {
+            if (!state) {
+                return;
+            }
+            if (patch.projectName !== undefined) {
+                state.projectName = patch.projectName;
+            }
+            if (patch.currentPhase !== undefined) {
+                state.currentPhase = patch.currentPhase;
+                renderCurrentPhase();
+            }
+            (patch.phases || []).forEach(function(phase) {
+                state.phases = (state.phases || []).filter(p => p.name !== phase.name).concat([phase]);
+                renderPhase(phase);
+            });
+            (patch.removedPhases || []).forEach(function(name) {
+                state.phases = (state.phases || []).filter(p => p.name !== name);
+                renderPhase({name: name, status: 'pending'});
+            });
+            if (patch.providers !== undefined) {
+                state.providers = patch.providers;
+            }
+            if (patch.stats !== undefined) {
+                state.stats = patch.stats;
+                renderStats();
+            }
+            if (patch.recentLogs !== undefined) {
+                state.recentLogs = patch.recentLogs;
+                renderLogs();
+            }
+        }
+        
+        function renderCurrentPhase() {
             document.getElementById('current-phase').textContent = state.currentPhase.toUpperCase();
+        }
+        
+        function renderStats() {
             document.getElementById('files-count').textContent = state.stats.filesIndexed;
             document.getElementById('symbols-count').textContent = state.stats.symbolsFound;
+            document.getElementById('tasks-done').textContent = state.stats.tasksCompleted;
+            document.getElementById('tasks-pending').textContent = state.stats.tasksPending;
+        }
+        
+        function renderPhase(phase) {
+            const row = document.querySelector('[data-phase="' + phase.name + '"]');
+            if (!row) {
+                return;
+            }
+            const icon = row.querySelector('.phase-icon');
+            if (phase.status === 'approved' || phase.status === 'completed') {
+                icon.className = 'phase-icon done';
+                icon.textContent = '✓';
+            } else if (phase.status === 'in_progress') {
+                icon.className = 'phase-icon active';
+                icon.textContent = '●';
+            } else {
+                icon.className = 'phase-icon pending';
+                icon.textContent = '';
+            }
+        }
+        
+        function renderLogs() {
+            if (state.recentLogs && state.recentLogs.length) {
+                document.getElementById('logs').innerHTML = '';
+                state.recentLogs.slice().reverse().forEach(addLog);
+            }
         }
         
         function approve() {
@@ -569,6 +1161,54 @@ const dashboardHTML = `<!DOCTYPE html>
             });
         }
         
+        // runAction runs the viki command behind an action; its progress
+        // arrives as log events
+        function runAction(type) {
+            fetch('/api/action', {
+                method: 'POST',
+                headers: {'Content-Type': 'application/json'},
+                body: JSON.stringify({type: type, data: document.getElementById('action-input').value})
+            }).then(function(r) {
+                if (!r.ok) {
+                    r.text().then(text => addLog({timestamp: new Date(), level: 'warning', message: text}));
+                }
+            });
+        }
+        
+        function addChatMessage(text, kind) {
+            const messages = document.getElementById('chat-messages');
+            const div = document.createElement('div');
+            div.className = 'chat-message chat-' + kind;
+            div.textContent = text;
+            messages.appendChild(div);
+            messages.scrollTop = messages.scrollHeight;
+        }
+        
+        function sendChat() {
+            const input = document.getElementById('chat-input');
+            const message = input.value.trim();
+            if (!message) {
+                return;
+            }
+            input.value = '';
+            addChatMessage(message, 'user');
+            fetch('/api/chat', {
+                method: 'POST',
+                headers: {'Content-Type': 'application/json'},
+                body: JSON.stringify({agent: document.getElementById('agent').value, message: message})
+            })
+                .then(r => r.json())
+                .then(reply => reply.error ? addChatMessage(reply.error, 'error') : addChatMessage(reply.message, 'assistant'))
+                .catch(err => addChatMessage(err.message, 'error'));
+        }
+        
+        document.getElementById('chat-input').addEventListener('keydown', function(e) {
+            if (e.key === 'Enter' && !e.shiftKey) {
+                e.preventDefault();
+                sendChat();
+            }
+        });
+        
         function refresh() {
             fetch('/api/state')
                 .then(r => r.json())